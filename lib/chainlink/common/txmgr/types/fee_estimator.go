@@ -14,6 +14,8 @@ type Opt int
 const (
 	// OptForceRefetch forces the estimator to bust a cache if necessary
 	OptForceRefetch Opt = iota
+	// OptBlobTx requests a fee suitable for an EIP-4844 blob transaction
+	OptBlobTx
 )
 
 type Fee fmt.Stringer
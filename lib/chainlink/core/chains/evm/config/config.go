@@ -2,6 +2,7 @@ package config
 
 import (
 	"math/big"
+	"net/url"
 	"time"
 
 	gethcommon "github.com/ethereum/go-ethereum/common"
@@ -19,12 +20,20 @@ type ChainScopedOnlyConfig interface {
 	BlockBackfillDepth() uint64
 	BlockBackfillSkip() bool
 	BlockEmissionIdleWarningThreshold() time.Duration
+	BlockHistoryEstimatorBaseFeeLookaheadBlocks() uint16
 	BlockHistoryEstimatorBatchSize() (size uint32)
 	BlockHistoryEstimatorBlockDelay() uint16
 	BlockHistoryEstimatorBlockHistorySize() uint16
 	BlockHistoryEstimatorCheckInclusionBlocks() uint16
 	BlockHistoryEstimatorCheckInclusionPercentile() uint16
 	BlockHistoryEstimatorEIP1559FeeCapBufferBlocks() uint16
+	BlockHistoryEstimatorExcludedTxTypes() []uint8
+	BlockHistoryEstimatorHistoryDuration() time.Duration
+	BlockHistoryEstimatorHistorySizeMax() uint16
+	BlockHistoryEstimatorHistorySizeMin() uint16
+	BlockHistoryEstimatorInclusionPercentiles() []string
+	BlockHistoryEstimatorMaxReorgDepth() uint16
+	BlockHistoryEstimatorTipCapTrimPercentile() uint16
 	BlockHistoryEstimatorTransactionPercentile() uint16
 	ChainID() *big.Int
 	EvmEIP1559DynamicFees() bool
@@ -32,13 +41,29 @@ type ChainScopedOnlyConfig interface {
 	EthTxReaperThreshold() time.Duration
 	EthTxResendAfterThreshold() time.Duration
 	EvmFinalityDepth() uint32
+	EvmGasBaseFeeBufferMultiplier() float32
+	EvmGasBatchTipIncrement() *assets.Wei
+	EvmGasBumpFeeCapFromBaseFee() bool
 	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
 	EvmGasBumpThreshold() uint64
 	EvmGasBumpTxDepth() uint32
 	EvmGasBumpWei() *assets.Wei
+	EvmGasDecisionLogAlways() []string
+	EvmGasDecisionLogSampleRate() uint32
+	EvmGasDrainTimeout() time.Duration
+	EvmGasEstimateAccessList() bool
+	EvmGasEstimateGasLimit() bool
+	EvmGasFeeAnomalyCooldown() time.Duration
+	EvmGasFeeAnomalyFactor() float32
+	EvmGasFeeAnomalyHalfLife() time.Duration
+	EvmGasFeeCacheTTL() time.Duration
 	EvmGasFeeCapDefault() *assets.Wei
+	EvmGasFeeCurrency() *gethcommon.Address
+	EvmGasFeeRounding() *assets.Wei
 	EvmGasLimitDefault() uint32
 	EvmGasLimitMax() uint32
+	EvmGasLimitMin() uint32
 	EvmGasLimitMultiplier() float32
 	EvmGasLimitTransfer() uint32
 	EvmGasLimitOCRJobType() *uint32
@@ -46,7 +71,17 @@ type ChainScopedOnlyConfig interface {
 	EvmGasLimitVRFJobType() *uint32
 	EvmGasLimitFMJobType() *uint32
 	EvmGasLimitKeeperJobType() *uint32
+	EvmGasMaxTxCost() *assets.Wei
+	EvmGasNodeMinPriceMethod() string
+	EvmGasNodeMinPriceSync() bool
 	EvmGasPriceDefault() *assets.Wei
+	EvmGasPriceStaleThreshold() time.Duration
+	EvmGasPriceUpdateThreshold() uint16
+	EvmGasRPCCallTimeout() time.Duration
+	EvmGasRPCRateLimit() uint32
+	EvmGasRPCRateLimitBurst() uint32
+	EvmGasSimulateBeforeBump() bool
+	EvmGasSuggestedPriceConnectivityCheck() bool
 	EvmGasTipCapDefault() *assets.Wei
 	EvmGasTipCapMinimum() *assets.Wei
 	EvmHeadTrackerHistoryDepth() uint32
@@ -55,6 +90,7 @@ type ChainScopedOnlyConfig interface {
 	EvmLogBackfillBatchSize() uint32
 	EvmLogKeepBlocksDepth() uint32
 	EvmLogPollInterval() time.Duration
+	EvmMaxBlobGasPriceWei() *assets.Wei
 	EvmMaxGasPriceWei() *assets.Wei
 	EvmMaxInFlightTransactions() uint32
 	EvmMaxQueuedTransactions() uint64
@@ -62,7 +98,18 @@ type ChainScopedOnlyConfig interface {
 	EvmNonceAutoSync() bool
 	EvmUseForwarders() bool
 	EvmRPCDefaultBatchSize() uint32
+	ExternalAPIEstimatorBaseFeePath() string
+	ExternalAPIEstimatorFastPath() string
+	ExternalAPIEstimatorPricesIncludeBaseFee() bool
+	ExternalAPIEstimatorSafeLowPath() string
+	ExternalAPIEstimatorSpeed() string
+	ExternalAPIEstimatorStandardPath() string
+	ExternalAPIEstimatorUnit() string
+	ExternalAPIEstimatorURL() *url.URL
+	FeeHistoryEstimatorBlockCount() uint16
+	FeeHistoryEstimatorRewardPercentile() uint16
 	FlagsContractAddress() string
+	GasEstimatorFallbackModes() []string
 	GasEstimatorMode() string
 	ChainType() config.ChainType
 	KeySpecificMaxGasPriceWei(addr gethcommon.Address) *assets.Wei
@@ -436,6 +436,20 @@ func (_m *ChainScopedConfig) BlockEmissionIdleWarningThreshold() time.Duration {
 	return r0
 }
 
+// BlockHistoryEstimatorBaseFeeLookaheadBlocks provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorBaseFeeLookaheadBlocks() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
 // BlockHistoryEstimatorBatchSize provides a mock function with given fields:
 func (_m *ChainScopedConfig) BlockHistoryEstimatorBatchSize() uint32 {
 	ret := _m.Called()
@@ -520,6 +534,108 @@ func (_m *ChainScopedConfig) BlockHistoryEstimatorEIP1559FeeCapBufferBlocks() ui
 	return r0
 }
 
+// BlockHistoryEstimatorExcludedTxTypes provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorExcludedTxTypes() []uint8 {
+	ret := _m.Called()
+
+	var r0 []uint8
+	if rf, ok := ret.Get(0).(func() []uint8); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint8)
+		}
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorHistoryDuration provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorHistoryDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorHistorySizeMax provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorHistorySizeMax() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorHistorySizeMin provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorHistorySizeMin() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorInclusionPercentiles provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorInclusionPercentiles() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorMaxReorgDepth provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorMaxReorgDepth() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorTipCapTrimPercentile provides a mock function with given fields:
+func (_m *ChainScopedConfig) BlockHistoryEstimatorTipCapTrimPercentile() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
 // BlockHistoryEstimatorTransactionPercentile provides a mock function with given fields:
 func (_m *ChainScopedConfig) BlockHistoryEstimatorTransactionPercentile() uint16 {
 	ret := _m.Called()
@@ -1022,6 +1138,50 @@ func (_m *ChainScopedConfig) EvmFinalityDepth() uint32 {
 	return r0
 }
 
+// EvmGasBaseFeeBufferMultiplier provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasBaseFeeBufferMultiplier() float32 {
+	ret := _m.Called()
+
+	var r0 float32
+	if rf, ok := ret.Get(0).(func() float32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(float32)
+	}
+
+	return r0
+}
+
+// EvmGasBatchTipIncrement provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasBatchTipIncrement() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasBumpFeeCapFromBaseFee provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasBumpFeeCapFromBaseFee() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // EvmGasBumpPercent provides a mock function with given fields:
 func (_m *ChainScopedConfig) EvmGasBumpPercent() uint16 {
 	ret := _m.Called()
@@ -1036,6 +1196,20 @@ func (_m *ChainScopedConfig) EvmGasBumpPercent() uint16 {
 	return r0
 }
 
+// EvmGasBumpStrategy provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasBumpStrategy() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // EvmGasBumpThreshold provides a mock function with given fields:
 func (_m *ChainScopedConfig) EvmGasBumpThreshold() uint64 {
 	ret := _m.Called()
@@ -1080,100 +1254,94 @@ func (_m *ChainScopedConfig) EvmGasBumpWei() *assets.Wei {
 	return r0
 }
 
-// EvmGasFeeCapDefault provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasFeeCapDefault() *assets.Wei {
+// EvmGasDecisionLogAlways provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasDecisionLogAlways() []string {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
 	return r0
 }
 
-// EvmGasLimitDRJobType provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitDRJobType() *uint32 {
+// EvmGasDecisionLogSampleRate provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasDecisionLogSampleRate() uint32 {
 	ret := _m.Called()
 
-	var r0 *uint32
-	if rf, ok := ret.Get(0).(func() *uint32); ok {
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*uint32)
-		}
+		r0 = ret.Get(0).(uint32)
 	}
 
 	return r0
 }
 
-// EvmGasLimitDefault provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitDefault() uint32 {
+// EvmGasDrainTimeout provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasDrainTimeout() time.Duration {
 	ret := _m.Called()
 
-	var r0 uint32
-	if rf, ok := ret.Get(0).(func() uint32); ok {
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(uint32)
+		r0 = ret.Get(0).(time.Duration)
 	}
 
 	return r0
 }
 
-// EvmGasLimitFMJobType provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitFMJobType() *uint32 {
+// EvmGasEstimateAccessList provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasEstimateAccessList() bool {
 	ret := _m.Called()
 
-	var r0 *uint32
-	if rf, ok := ret.Get(0).(func() *uint32); ok {
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*uint32)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
 	return r0
 }
 
-// EvmGasLimitKeeperJobType provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitKeeperJobType() *uint32 {
+// EvmGasEstimateGasLimit provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasEstimateGasLimit() bool {
 	ret := _m.Called()
 
-	var r0 *uint32
-	if rf, ok := ret.Get(0).(func() *uint32); ok {
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*uint32)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
 	return r0
 }
 
-// EvmGasLimitMax provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitMax() uint32 {
+// EvmGasFeeAnomalyCooldown provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeAnomalyCooldown() time.Duration {
 	ret := _m.Called()
 
-	var r0 uint32
-	if rf, ok := ret.Get(0).(func() uint32); ok {
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(uint32)
+		r0 = ret.Get(0).(time.Duration)
 	}
 
 	return r0
 }
 
-// EvmGasLimitMultiplier provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitMultiplier() float32 {
+// EvmGasFeeAnomalyFactor provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeAnomalyFactor() float32 {
 	ret := _m.Called()
 
 	var r0 float32
@@ -1186,70 +1354,68 @@ func (_m *ChainScopedConfig) EvmGasLimitMultiplier() float32 {
 	return r0
 }
 
-// EvmGasLimitOCRJobType provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitOCRJobType() *uint32 {
+// EvmGasFeeAnomalyHalfLife provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeAnomalyHalfLife() time.Duration {
 	ret := _m.Called()
 
-	var r0 *uint32
-	if rf, ok := ret.Get(0).(func() *uint32); ok {
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*uint32)
-		}
+		r0 = ret.Get(0).(time.Duration)
 	}
 
 	return r0
 }
 
-// EvmGasLimitTransfer provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitTransfer() uint32 {
+// EvmGasFeeCacheTTL provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeCacheTTL() time.Duration {
 	ret := _m.Called()
 
-	var r0 uint32
-	if rf, ok := ret.Get(0).(func() uint32); ok {
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
 		r0 = rf()
 	} else {
-		r0 = ret.Get(0).(uint32)
+		r0 = ret.Get(0).(time.Duration)
 	}
 
 	return r0
 }
 
-// EvmGasLimitVRFJobType provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasLimitVRFJobType() *uint32 {
+// EvmGasFeeCapDefault provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeCapDefault() *assets.Wei {
 	ret := _m.Called()
 
-	var r0 *uint32
-	if rf, ok := ret.Get(0).(func() *uint32); ok {
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*uint32)
+			r0 = ret.Get(0).(*assets.Wei)
 		}
 	}
 
 	return r0
 }
 
-// EvmGasPriceDefault provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasPriceDefault() *assets.Wei {
+// EvmGasFeeCurrency provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeCurrency() *common.Address {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 *common.Address
+	if rf, ok := ret.Get(0).(func() *common.Address); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
+			r0 = ret.Get(0).(*common.Address)
 		}
 	}
 
 	return r0
 }
 
-// EvmGasTipCapDefault provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasTipCapDefault() *assets.Wei {
+// EvmGasFeeRounding provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasFeeRounding() *assets.Wei {
 	ret := _m.Called()
 
 	var r0 *assets.Wei
@@ -1264,24 +1430,24 @@ func (_m *ChainScopedConfig) EvmGasTipCapDefault() *assets.Wei {
 	return r0
 }
 
-// EvmGasTipCapMinimum provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmGasTipCapMinimum() *assets.Wei {
+// EvmGasLimitDRJobType provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitDRJobType() *uint32 {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 *uint32
+	if rf, ok := ret.Get(0).(func() *uint32); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
+			r0 = ret.Get(0).(*uint32)
 		}
 	}
 
 	return r0
 }
 
-// EvmHeadTrackerHistoryDepth provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmHeadTrackerHistoryDepth() uint32 {
+// EvmGasLimitDefault provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitDefault() uint32 {
 	ret := _m.Called()
 
 	var r0 uint32
@@ -1294,8 +1460,332 @@ func (_m *ChainScopedConfig) EvmHeadTrackerHistoryDepth() uint32 {
 	return r0
 }
 
-// EvmHeadTrackerMaxBufferSize provides a mock function with given fields:
-func (_m *ChainScopedConfig) EvmHeadTrackerMaxBufferSize() uint32 {
+// EvmGasLimitFMJobType provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitFMJobType() *uint32 {
+	ret := _m.Called()
+
+	var r0 *uint32
+	if rf, ok := ret.Get(0).(func() *uint32); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*uint32)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasLimitKeeperJobType provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitKeeperJobType() *uint32 {
+	ret := _m.Called()
+
+	var r0 *uint32
+	if rf, ok := ret.Get(0).(func() *uint32); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*uint32)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasLimitMax provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitMax() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasLimitMin provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitMin() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasLimitMultiplier provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitMultiplier() float32 {
+	ret := _m.Called()
+
+	var r0 float32
+	if rf, ok := ret.Get(0).(func() float32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(float32)
+	}
+
+	return r0
+}
+
+// EvmGasLimitOCRJobType provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitOCRJobType() *uint32 {
+	ret := _m.Called()
+
+	var r0 *uint32
+	if rf, ok := ret.Get(0).(func() *uint32); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*uint32)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasLimitTransfer provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitTransfer() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasLimitVRFJobType provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasLimitVRFJobType() *uint32 {
+	ret := _m.Called()
+
+	var r0 *uint32
+	if rf, ok := ret.Get(0).(func() *uint32); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*uint32)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasMaxTxCost provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasMaxTxCost() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasNodeMinPriceMethod provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasNodeMinPriceMethod() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// EvmGasNodeMinPriceSync provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasNodeMinPriceSync() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasPriceDefault provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasPriceDefault() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasPriceStaleThreshold provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasPriceStaleThreshold() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasPriceUpdateThreshold provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasPriceUpdateThreshold() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// EvmGasRPCCallTimeout provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasRPCCallTimeout() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasRPCRateLimit provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasRPCRateLimit() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasRPCRateLimitBurst provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasRPCRateLimitBurst() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasSimulateBeforeBump provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasSimulateBeforeBump() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasSuggestedPriceConnectivityCheck provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasSuggestedPriceConnectivityCheck() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasTipCapDefault provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasTipCapDefault() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasTipCapMinimum provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmGasTipCapMinimum() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmHeadTrackerHistoryDepth provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmHeadTrackerHistoryDepth() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmHeadTrackerMaxBufferSize provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmHeadTrackerMaxBufferSize() uint32 {
 	ret := _m.Called()
 
 	var r0 uint32
@@ -1364,6 +1854,22 @@ func (_m *ChainScopedConfig) EvmLogPollInterval() time.Duration {
 	return r0
 }
 
+// EvmMaxBlobGasPriceWei provides a mock function with given fields:
+func (_m *ChainScopedConfig) EvmMaxBlobGasPriceWei() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
 // EvmMaxGasPriceWei provides a mock function with given fields:
 func (_m *ChainScopedConfig) EvmMaxGasPriceWei() *assets.Wei {
 	ret := _m.Called()
@@ -1510,6 +2016,120 @@ func (_m *ChainScopedConfig) ExplorerURL() *url.URL {
 	return r0
 }
 
+// ExternalAPIEstimatorBaseFeePath provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorBaseFeePath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorFastPath provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorFastPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorPricesIncludeBaseFee provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorPricesIncludeBaseFee() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorSafeLowPath provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorSafeLowPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorSpeed provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorSpeed() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorStandardPath provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorStandardPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorURL provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorURL() *url.URL {
+	ret := _m.Called()
+
+	var r0 *url.URL
+	if rf, ok := ret.Get(0).(func() *url.URL); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*url.URL)
+		}
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorUnit provides a mock function with given fields:
+func (_m *ChainScopedConfig) ExternalAPIEstimatorUnit() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // FMDefaultTransactionQueueDepth provides a mock function with given fields:
 func (_m *ChainScopedConfig) FMDefaultTransactionQueueDepth() uint32 {
 	ret := _m.Called()
@@ -1622,6 +2242,34 @@ func (_m *ChainScopedConfig) FeatureUICSAKeys() bool {
 	return r0
 }
 
+// FeeHistoryEstimatorBlockCount provides a mock function with given fields:
+func (_m *ChainScopedConfig) FeeHistoryEstimatorBlockCount() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// FeeHistoryEstimatorRewardPercentile provides a mock function with given fields:
+func (_m *ChainScopedConfig) FeeHistoryEstimatorRewardPercentile() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
 // FlagsContractAddress provides a mock function with given fields:
 func (_m *ChainScopedConfig) FlagsContractAddress() string {
 	ret := _m.Called()
@@ -1636,6 +2284,22 @@ func (_m *ChainScopedConfig) FlagsContractAddress() string {
 	return r0
 }
 
+// GasEstimatorFallbackModes provides a mock function with given fields:
+func (_m *ChainScopedConfig) GasEstimatorFallbackModes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
 // GasEstimatorMode provides a mock function with given fields:
 func (_m *ChainScopedConfig) GasEstimatorMode() string {
 	ret := _m.Called()
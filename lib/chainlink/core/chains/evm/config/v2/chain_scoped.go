@@ -2,6 +2,7 @@ package v2
 
 import (
 	"math/big"
+	"net/url"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -88,6 +89,69 @@ func (c *ChainScoped) BlockHistoryEstimatorBlockHistorySize() uint16 {
 	return *c.cfg.GasEstimator.BlockHistory.BlockHistorySize
 }
 
+func (c *ChainScoped) BlockHistoryEstimatorBaseFeeLookaheadBlocks() uint16 {
+	return *c.cfg.GasEstimator.BlockHistory.BaseFeeLookaheadBlocks
+}
+
+func (c *ChainScoped) BlockHistoryEstimatorMaxReorgDepth() uint16 {
+	return *c.cfg.GasEstimator.BlockHistory.MaxReorgDepth
+}
+
+func (c *ChainScoped) BlockHistoryEstimatorHistoryDuration() time.Duration {
+	return c.cfg.GasEstimator.BlockHistory.HistoryDuration.Duration()
+}
+
+func (c *ChainScoped) BlockHistoryEstimatorHistorySizeMin() uint16 {
+	return *c.cfg.GasEstimator.BlockHistory.HistorySizeMin
+}
+
+func (c *ChainScoped) BlockHistoryEstimatorHistorySizeMax() uint16 {
+	return *c.cfg.GasEstimator.BlockHistory.HistorySizeMax
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorURL() *url.URL {
+	if c.cfg.GasEstimator.ExternalAPI.URL == nil {
+		return nil
+	}
+	return c.cfg.GasEstimator.ExternalAPI.URL.URL()
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorSpeed() string {
+	return *c.cfg.GasEstimator.ExternalAPI.Speed
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorSafeLowPath() string {
+	return *c.cfg.GasEstimator.ExternalAPI.SafeLowPath
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorStandardPath() string {
+	return *c.cfg.GasEstimator.ExternalAPI.StandardPath
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorFastPath() string {
+	return *c.cfg.GasEstimator.ExternalAPI.FastPath
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorBaseFeePath() string {
+	return *c.cfg.GasEstimator.ExternalAPI.BaseFeePath
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorUnit() string {
+	return *c.cfg.GasEstimator.ExternalAPI.Unit
+}
+
+func (c *ChainScoped) ExternalAPIEstimatorPricesIncludeBaseFee() bool {
+	return *c.cfg.GasEstimator.ExternalAPI.PricesIncludeBaseFee
+}
+
+func (c *ChainScoped) FeeHistoryEstimatorBlockCount() uint16 {
+	return *c.cfg.GasEstimator.FeeHistory.BlockCount
+}
+
+func (c *ChainScoped) FeeHistoryEstimatorRewardPercentile() uint16 {
+	return *c.cfg.GasEstimator.FeeHistory.RewardPercentile
+}
+
 func (c *ChainScoped) BlockHistoryEstimatorCheckInclusionBlocks() uint16 {
 	return *c.cfg.GasEstimator.BlockHistory.CheckInclusionBlocks
 }
@@ -103,6 +167,18 @@ func (c *ChainScoped) BlockHistoryEstimatorEIP1559FeeCapBufferBlocks() uint16 {
 	return *c.cfg.GasEstimator.BlockHistory.EIP1559FeeCapBufferBlocks
 }
 
+func (c *ChainScoped) BlockHistoryEstimatorInclusionPercentiles() []string {
+	return *c.cfg.GasEstimator.BlockHistory.InclusionPercentiles
+}
+
+func (c *ChainScoped) BlockHistoryEstimatorExcludedTxTypes() []uint8 {
+	return *c.cfg.GasEstimator.BlockHistory.ExcludedTxTypes
+}
+
+func (c *ChainScoped) BlockHistoryEstimatorTipCapTrimPercentile() uint16 {
+	return *c.cfg.GasEstimator.BlockHistory.TipCapTrimPercentile
+}
+
 func (c *ChainScoped) BlockHistoryEstimatorTransactionPercentile() uint16 {
 	return *c.cfg.GasEstimator.BlockHistory.TransactionPercentile
 }
@@ -146,23 +222,76 @@ func (c *ChainScoped) EvmGasBumpWei() *assets.Wei {
 	return c.cfg.GasEstimator.BumpMin
 }
 
+func (c *ChainScoped) EvmGasBumpFeeCapFromBaseFee() bool {
+	return *c.cfg.GasEstimator.BumpFeeCapFromBaseFee
+}
+
+func (c *ChainScoped) EvmGasBatchTipIncrement() *assets.Wei {
+	return c.cfg.GasEstimator.BatchTipIncrement
+}
+
+func (c *ChainScoped) EvmGasBumpStrategy() string {
+	return *c.cfg.GasEstimator.BumpStrategy
+}
+
 func (c *ChainScoped) EvmGasFeeCapDefault() *assets.Wei {
 	return c.cfg.GasEstimator.FeeCapDefault
 }
 
+func (c *ChainScoped) EvmGasFeeCacheTTL() time.Duration {
+	return c.cfg.GasEstimator.FeeCacheTTL.Duration()
+}
+
 func (c *ChainScoped) EvmGasLimitDefault() uint32 {
 	return *c.cfg.GasEstimator.LimitDefault
 }
 
+func (c *ChainScoped) EvmGasEstimateGasLimit() bool {
+	return *c.cfg.GasEstimator.EstimateGasLimit
+}
+
+func (c *ChainScoped) EvmGasEstimateAccessList() bool {
+	return *c.cfg.GasEstimator.EstimateAccessList
+}
+
+func (c *ChainScoped) EvmGasSimulateBeforeBump() bool {
+	return *c.cfg.GasEstimator.SimulateBeforeBump
+}
+
+func (c *ChainScoped) EvmGasRPCRateLimit() uint32 {
+	return *c.cfg.GasEstimator.RPCRateLimit
+}
+
+func (c *ChainScoped) EvmGasRPCRateLimitBurst() uint32 {
+	return *c.cfg.GasEstimator.RPCRateLimitBurst
+}
+
+func (c *ChainScoped) EvmGasRPCCallTimeout() time.Duration {
+	return c.cfg.GasEstimator.RPCCallTimeout.Duration()
+}
+
+func (c *ChainScoped) EvmGasDrainTimeout() time.Duration {
+	return c.cfg.GasEstimator.DrainTimeout.Duration()
+}
+
 func (c *ChainScoped) EvmGasLimitMax() uint32 {
 	return *c.cfg.GasEstimator.LimitMax
 }
 
+func (c *ChainScoped) EvmGasLimitMin() uint32 {
+	return *c.cfg.GasEstimator.LimitMin
+}
+
 func (c *ChainScoped) EvmGasLimitMultiplier() float32 {
 	f, _ := c.cfg.GasEstimator.LimitMultiplier.BigFloat().Float32()
 	return f
 }
 
+func (c *ChainScoped) EvmGasBaseFeeBufferMultiplier() float32 {
+	f, _ := c.cfg.GasEstimator.BaseFeeBufferMultiplier.BigFloat().Float32()
+	return f
+}
+
 func (c *ChainScoped) EvmGasLimitTransfer() uint32 {
 	return *c.cfg.GasEstimator.LimitTransfer
 }
@@ -191,6 +320,18 @@ func (c *ChainScoped) EvmGasPriceDefault() *assets.Wei {
 	return c.cfg.GasEstimator.PriceDefault
 }
 
+func (c *ChainScoped) EvmGasPriceStaleThreshold() time.Duration {
+	return c.cfg.GasEstimator.PriceStaleThreshold.Duration()
+}
+
+func (c *ChainScoped) EvmGasPriceUpdateThreshold() uint16 {
+	return *c.cfg.GasEstimator.PriceUpdateThreshold
+}
+
+func (c *ChainScoped) EvmGasSuggestedPriceConnectivityCheck() bool {
+	return *c.cfg.GasEstimator.SuggestedPriceConnectivityCheck
+}
+
 func (c *ChainScoped) EvmMinGasPriceWei() *assets.Wei {
 	return c.cfg.GasEstimator.PriceMin
 }
@@ -199,6 +340,29 @@ func (c *ChainScoped) EvmMaxGasPriceWei() *assets.Wei {
 	return c.cfg.GasEstimator.PriceMax
 }
 
+func (c *ChainScoped) EvmGasMaxTxCost() *assets.Wei {
+	return c.cfg.GasEstimator.MaxTxCost
+}
+
+func (c *ChainScoped) EvmGasFeeRounding() *assets.Wei {
+	return c.cfg.GasEstimator.FeeRounding
+}
+
+func (c *ChainScoped) EvmGasFeeCurrency() *common.Address {
+	if c.cfg.GasEstimator.FeeCurrency == nil {
+		return nil
+	}
+	a := c.cfg.GasEstimator.FeeCurrency.Address()
+	return &a
+}
+
+func (c *ChainScoped) EvmMaxBlobGasPriceWei() *assets.Wei {
+	if c.cfg.GasEstimator.PriceMaxBlob == nil {
+		return c.cfg.GasEstimator.PriceMax
+	}
+	return c.cfg.GasEstimator.PriceMaxBlob
+}
+
 func (c *ChainScoped) EvmGasTipCapDefault() *assets.Wei {
 	return c.cfg.GasEstimator.TipCapDefault
 }
@@ -261,6 +425,11 @@ func (c *ChainScoped) FlagsContractAddress() string {
 func (c *ChainScoped) GasEstimatorMode() string {
 	return *c.cfg.GasEstimator.Mode
 }
+
+func (c *ChainScoped) GasEstimatorFallbackModes() []string {
+	return *c.cfg.GasEstimator.FallbackModes
+}
+
 func (c *ChainScoped) KeySpecificMaxGasPriceWei(addr common.Address) *assets.Wei {
 	var keySpecific *assets.Wei
 	for i := range c.cfg.KeySpecific {
@@ -340,3 +509,32 @@ func (c *ChainScoped) OCRDatabaseTimeout() time.Duration {
 func (c *ChainScoped) OCR2AutomationGasLimit() uint32 {
 	return *c.cfg.OCR2.Automation.GasLimit
 }
+
+func (c *ChainScoped) EvmGasFeeAnomalyFactor() float32 {
+	f, _ := c.cfg.GasEstimator.FeeAnomalyFactor.BigFloat().Float32()
+	return f
+}
+
+func (c *ChainScoped) EvmGasFeeAnomalyHalfLife() time.Duration {
+	return c.cfg.GasEstimator.FeeAnomalyHalfLife.Duration()
+}
+
+func (c *ChainScoped) EvmGasFeeAnomalyCooldown() time.Duration {
+	return c.cfg.GasEstimator.FeeAnomalyCooldown.Duration()
+}
+
+func (c *ChainScoped) EvmGasNodeMinPriceSync() bool {
+	return *c.cfg.GasEstimator.NodeMinPriceSync
+}
+
+func (c *ChainScoped) EvmGasNodeMinPriceMethod() string {
+	return *c.cfg.GasEstimator.NodeMinPriceMethod
+}
+
+func (c *ChainScoped) EvmGasDecisionLogSampleRate() uint32 {
+	return *c.cfg.GasEstimator.DecisionLogSampleRate
+}
+
+func (c *ChainScoped) EvmGasDecisionLogAlways() []string {
+	return *c.cfg.GasEstimator.DecisionLogAlways
+}
@@ -3,6 +3,9 @@ package v2
 import (
 	"fmt"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/pelletier/go-toml/v2"
@@ -468,11 +471,41 @@ type GasEstimator struct {
 
 	EIP1559DynamicFees *bool
 
-	FeeCapDefault *assets.Wei
-	TipCapDefault *assets.Wei
-	TipCapMin     *assets.Wei
+	FeeCapDefault                   *assets.Wei
+	TipCapDefault                   *assets.Wei
+	TipCapMin                       *assets.Wei
+	PriceMaxBlob                    *assets.Wei
+	PriceStaleThreshold             *models.Duration
+	SuggestedPriceConnectivityCheck *bool
+	FeeCacheTTL                     *models.Duration
+	EstimateGasLimit                *bool
+	LimitMin                        *uint32
+	BumpStrategy                    *string
+	SimulateBeforeBump              *bool
+	RPCRateLimit                    *uint32
+	RPCRateLimitBurst               *uint32
+	FallbackModes                   *[]string
+	BatchTipIncrement               *assets.Wei
+	EstimateAccessList              *bool
+	PriceUpdateThreshold            *uint16
+	MaxTxCost                       *assets.Wei
+	FeeAnomalyFactor                *decimal.Decimal
+	FeeAnomalyHalfLife              *models.Duration
+	FeeAnomalyCooldown              *models.Duration
+	NodeMinPriceSync                *bool
+	NodeMinPriceMethod              *string
+	BumpFeeCapFromBaseFee           *bool
+	RPCCallTimeout                  *models.Duration
+	FeeCurrency                     *ethkey.EIP55Address
+	DecisionLogSampleRate           *uint32
+	DecisionLogAlways               *[]string
+	DrainTimeout                    *models.Duration
+	FeeRounding                     *assets.Wei
+	BaseFeeBufferMultiplier         *decimal.Decimal
 
 	BlockHistory BlockHistoryEstimator `toml:",omitempty"`
+	ExternalAPI  ExternalAPIEstimator  `toml:",omitempty"`
+	FeeHistory   FeeHistoryEstimator   `toml:",omitempty"`
 }
 
 func (e *GasEstimator) ValidateConfig() (err error) {
@@ -480,6 +513,12 @@ func (e *GasEstimator) ValidateConfig() (err error) {
 		err = multierr.Append(err, v2.ErrInvalid{Name: "BumpPercent", Value: *e.BumpPercent,
 			Msg: fmt.Sprintf("may not be less than Geth's default of %d", txpool.DefaultConfig.PriceBump)})
 	}
+	switch *e.BumpStrategy {
+	case "Percent", "Additive", "Rebase":
+	default:
+		err = multierr.Append(err, v2.ErrInvalid{Name: "BumpStrategy", Value: *e.BumpStrategy,
+			Msg: "must be one of Percent, Additive or Rebase"})
+	}
 	if e.TipCapDefault.Cmp(e.TipCapMin) < 0 {
 		err = multierr.Append(err, v2.ErrInvalid{Name: "TipCapDefault", Value: e.TipCapDefault,
 			Msg: "must be greater than or equal to TipCapMinimum"})
@@ -505,14 +544,150 @@ func (e *GasEstimator) ValidateConfig() (err error) {
 		err = multierr.Append(err, v2.ErrInvalid{Name: "PriceMax", Value: e.PriceMin,
 			Msg: "must be greater than or equal to PriceDefault"})
 	}
+	if *e.LimitMin > *e.LimitMax {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "LimitMin", Value: *e.LimitMin,
+			Msg: "must be less than or equal to LimitMax"})
+	}
+	if *e.RPCRateLimit > 0 && *e.RPCRateLimitBurst == 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "RPCRateLimitBurst", Value: *e.RPCRateLimitBurst,
+			Msg: "must be greater than or equal to 1 with RPCRateLimit"})
+	}
+	if *e.Mode == "Fallback" && len(*e.FallbackModes) == 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FallbackModes", Value: *e.FallbackModes,
+			Msg: "must not be empty with Fallback Mode"})
+	}
+	for _, mode := range *e.FallbackModes {
+		if mode == "Fallback" {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "FallbackModes", Value: *e.FallbackModes,
+				Msg: "must not include Fallback"})
+			break
+		}
+	}
+	for _, mode := range *e.FallbackModes {
+		if mode != "Fallback" && !IsGasEstimatorMode(mode) {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "FallbackModes", Value: mode,
+				Msg: "must be a built-in or registered estimator mode"})
+		}
+	}
 	if *e.Mode == "BlockHistory" && *e.BlockHistory.BlockHistorySize <= 0 {
 		err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistory.BlockHistorySize", Value: *e.BlockHistory.BlockHistorySize,
 			Msg: "must be greater than or equal to 1 with BlockHistory Mode"})
 	}
+	if *e.Mode == "FeeHistory" && *e.BlockHistory.BlockHistorySize <= 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistory.BlockHistorySize", Value: *e.BlockHistory.BlockHistorySize,
+			Msg: "must be greater than or equal to 1 with FeeHistory Mode"})
+	}
+	if v := e.BlockHistory.TipCapTrimPercentile; v != nil && *v >= 50 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistory.TipCapTrimPercentile", Value: *v,
+			Msg: "must be less than 50"})
+	}
+	if d := e.BlockHistory.HistoryDuration; d != nil && d.Duration() > 0 {
+		if *e.BlockHistory.HistorySizeMin == 0 {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistory.HistorySizeMin", Value: *e.BlockHistory.HistorySizeMin,
+				Msg: "must be greater than or equal to 1 with HistoryDuration"})
+		}
+		if *e.BlockHistory.HistorySizeMin > *e.BlockHistory.HistorySizeMax {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistory.HistorySizeMin", Value: *e.BlockHistory.HistorySizeMin,
+				Msg: "must be less than or equal to HistorySizeMax"})
+		}
+	}
+	if v := e.BlockHistory.InclusionPercentiles; v != nil {
+		if msg := validateInclusionPercentiles(*v); msg != "" {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistory.InclusionPercentiles", Value: *v, Msg: msg})
+		}
+	}
+	if v := e.BaseFeeBufferMultiplier; v != nil && v.LessThan(decimal.NewFromInt(1)) {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "BaseFeeBufferMultiplier", Value: v,
+			Msg: "must be at least 1, so that fee caps cover the base fee"})
+	}
+	if v := e.FeeAnomalyFactor; v != nil && !v.IsZero() {
+		if v.LessThanOrEqual(decimal.NewFromInt(1)) {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "FeeAnomalyFactor", Value: v,
+				Msg: "must be greater than 1, or 0 to disable fee anomaly alerts"})
+		}
+		if h := e.FeeAnomalyHalfLife; h != nil && h.Duration() <= 0 {
+			err = multierr.Append(err, v2.ErrInvalid{Name: "FeeAnomalyHalfLife", Value: h,
+				Msg: "must be greater than 0 with FeeAnomalyFactor"})
+		}
+	}
+	if v := e.DecisionLogAlways; v != nil {
+		for _, cond := range *v {
+			switch cond {
+			case "Capped", "Bumped", "Anomaly":
+			default:
+				err = multierr.Append(err, v2.ErrInvalid{Name: "DecisionLogAlways", Value: cond,
+					Msg: "must be one of Capped, Bumped or Anomaly"})
+			}
+		}
+	}
+	if v := e.FeeRounding; v != nil && v.IsNegative() {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FeeRounding", Value: v,
+			Msg: "must not be negative, or 0 to disable fee rounding"})
+	}
+	if e.FeeCurrency != nil {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FeeCurrency", Value: e.FeeCurrency,
+			Msg: "is not supported yet, as transactions are sent paying fees in the native currency"})
+	}
+	if e.NodeMinPriceSync != nil && *e.NodeMinPriceSync && (e.NodeMinPriceMethod == nil || *e.NodeMinPriceMethod == "") {
+		err = multierr.Append(err, v2.ErrEmpty{Name: "NodeMinPriceMethod", Msg: "must be set with NodeMinPriceSync"})
+	}
+	if *e.Mode == "ExternalAPI" || slices.Contains(*e.FallbackModes, "ExternalAPI") {
+		err = multierr.Append(err, e.ExternalAPI.validate())
+	}
+	if *e.Mode == "FeeHistory" || slices.Contains(*e.FallbackModes, "FeeHistory") {
+		err = multierr.Append(err, e.FeeHistory.validate())
+	}
 
 	return
 }
 
+// GasEstimatorModes are the built-in values of GasEstimator.Mode
+var GasEstimatorModes = []string{"Arbitrum", "BlockHistory", "Celo", "ExternalAPI", "Fallback", "FeeHistory", "FixedPrice", "Optimism2", "L2Suggested", "ZkSync"}
+
+var customGasEstimatorModes sync.Map
+
+// RegisterGasEstimatorMode makes mode, the name of a custom estimator, a valid
+// GasEstimator.FallbackModes entry. gas.RegisterEstimator calls it.
+func RegisterGasEstimatorMode(mode string) {
+	customGasEstimatorModes.Store(mode, struct{}{})
+}
+
+// IsGasEstimatorMode returns true if mode is a built-in or registered
+// estimator mode
+func IsGasEstimatorMode(mode string) bool {
+	if slices.Contains(GasEstimatorModes, mode) {
+		return true
+	}
+	_, ok := customGasEstimatorModes.Load(mode)
+	return ok
+}
+
+// validateInclusionPercentiles checks that the entries are blocks:percentile
+// pairs with increasing blocks and non-increasing percentiles, and returns
+// what is wrong otherwise
+func validateInclusionPercentiles(entries []string) string {
+	var prevBlocks, prevPercentile uint64 = 0, 100
+	for _, entry := range entries {
+		b, p, ok := strings.Cut(entry, ":")
+		if !ok {
+			return fmt.Sprintf("entry %q must be of the form blocks:percentile", entry)
+		}
+		blocks, berr := strconv.ParseUint(b, 10, 32)
+		percentile, perr := strconv.ParseUint(p, 10, 16)
+		if berr != nil || perr != nil {
+			return fmt.Sprintf("entry %q must be of the form blocks:percentile", entry)
+		}
+		if blocks <= prevBlocks {
+			return "blocks must be at least 1 and increasing"
+		}
+		if percentile > prevPercentile {
+			return "percentiles must be at most 100 and must not increase with the blocks"
+		}
+		prevBlocks, prevPercentile = blocks, percentile
+	}
+	return ""
+}
+
 func (e *GasEstimator) setFrom(f *GasEstimator) {
 	if v := f.Mode; v != nil {
 		e.Mode = v
@@ -562,8 +737,94 @@ func (e *GasEstimator) setFrom(f *GasEstimator) {
 	if v := f.PriceMin; v != nil {
 		e.PriceMin = v
 	}
+	if v := f.PriceMaxBlob; v != nil {
+		e.PriceMaxBlob = v
+	}
+	if v := f.PriceStaleThreshold; v != nil {
+		e.PriceStaleThreshold = v
+	}
+	if v := f.SuggestedPriceConnectivityCheck; v != nil {
+		e.SuggestedPriceConnectivityCheck = v
+	}
+	if v := f.FeeCacheTTL; v != nil {
+		e.FeeCacheTTL = v
+	}
+	if v := f.EstimateGasLimit; v != nil {
+		e.EstimateGasLimit = v
+	}
+	if v := f.LimitMin; v != nil {
+		e.LimitMin = v
+	}
+	if v := f.BumpStrategy; v != nil {
+		e.BumpStrategy = v
+	}
+	if v := f.SimulateBeforeBump; v != nil {
+		e.SimulateBeforeBump = v
+	}
+	if v := f.RPCRateLimit; v != nil {
+		e.RPCRateLimit = v
+	}
+	if v := f.RPCRateLimitBurst; v != nil {
+		e.RPCRateLimitBurst = v
+	}
+	if v := f.FallbackModes; v != nil {
+		e.FallbackModes = v
+	}
+	if v := f.BatchTipIncrement; v != nil {
+		e.BatchTipIncrement = v
+	}
+	if v := f.EstimateAccessList; v != nil {
+		e.EstimateAccessList = v
+	}
+	if v := f.PriceUpdateThreshold; v != nil {
+		e.PriceUpdateThreshold = v
+	}
+	if v := f.MaxTxCost; v != nil {
+		e.MaxTxCost = v
+	}
+	if v := f.FeeAnomalyFactor; v != nil {
+		e.FeeAnomalyFactor = v
+	}
+	if v := f.FeeAnomalyHalfLife; v != nil {
+		e.FeeAnomalyHalfLife = v
+	}
+	if v := f.FeeAnomalyCooldown; v != nil {
+		e.FeeAnomalyCooldown = v
+	}
+	if v := f.NodeMinPriceSync; v != nil {
+		e.NodeMinPriceSync = v
+	}
+	if v := f.NodeMinPriceMethod; v != nil {
+		e.NodeMinPriceMethod = v
+	}
+	if v := f.BumpFeeCapFromBaseFee; v != nil {
+		e.BumpFeeCapFromBaseFee = v
+	}
+	if v := f.RPCCallTimeout; v != nil {
+		e.RPCCallTimeout = v
+	}
+	if v := f.FeeCurrency; v != nil {
+		e.FeeCurrency = v
+	}
+	if v := f.DecisionLogSampleRate; v != nil {
+		e.DecisionLogSampleRate = v
+	}
+	if v := f.DecisionLogAlways; v != nil {
+		e.DecisionLogAlways = v
+	}
+	if v := f.DrainTimeout; v != nil {
+		e.DrainTimeout = v
+	}
+	if v := f.FeeRounding; v != nil {
+		e.FeeRounding = v
+	}
+	if v := f.BaseFeeBufferMultiplier; v != nil {
+		e.BaseFeeBufferMultiplier = v
+	}
 	e.LimitJobType.setFrom(&f.LimitJobType)
 	e.BlockHistory.setFrom(&f.BlockHistory)
+	e.ExternalAPI.setFrom(&f.ExternalAPI)
+	e.FeeHistory.setFrom(&f.FeeHistory)
 }
 
 type GasLimitJobType struct {
@@ -599,6 +860,14 @@ type BlockHistoryEstimator struct {
 	CheckInclusionPercentile  *uint16
 	EIP1559FeeCapBufferBlocks *uint16
 	TransactionPercentile     *uint16
+	TipCapTrimPercentile      *uint16
+	BaseFeeLookaheadBlocks    *uint16
+	MaxReorgDepth             *uint16
+	InclusionPercentiles      *[]string
+	HistoryDuration           *models.Duration
+	HistorySizeMin            *uint16
+	HistorySizeMax            *uint16
+	ExcludedTxTypes           *[]uint8
 }
 
 func (e *BlockHistoryEstimator) setFrom(f *BlockHistoryEstimator) {
@@ -620,6 +889,132 @@ func (e *BlockHistoryEstimator) setFrom(f *BlockHistoryEstimator) {
 	if v := f.TransactionPercentile; v != nil {
 		e.TransactionPercentile = v
 	}
+	if v := f.TipCapTrimPercentile; v != nil {
+		e.TipCapTrimPercentile = v
+	}
+	if v := f.BaseFeeLookaheadBlocks; v != nil {
+		e.BaseFeeLookaheadBlocks = v
+	}
+	if v := f.MaxReorgDepth; v != nil {
+		e.MaxReorgDepth = v
+	}
+	if v := f.InclusionPercentiles; v != nil {
+		e.InclusionPercentiles = v
+	}
+	if v := f.HistoryDuration; v != nil {
+		e.HistoryDuration = v
+	}
+	if v := f.HistorySizeMin; v != nil {
+		e.HistorySizeMin = v
+	}
+	if v := f.HistorySizeMax; v != nil {
+		e.HistorySizeMax = v
+	}
+	if v := f.ExcludedTxTypes; v != nil {
+		e.ExcludedTxTypes = v
+	}
+}
+
+type ExternalAPIEstimator struct {
+	URL                  *models.URL
+	Speed                *string
+	SafeLowPath          *string
+	StandardPath         *string
+	FastPath             *string
+	BaseFeePath          *string
+	Unit                 *string
+	PricesIncludeBaseFee *bool
+}
+
+// validate checks the config of the ExternalAPI Mode, which is only required
+// if the mode is used
+func (e *ExternalAPIEstimator) validate() (err error) {
+	if e.URL == nil || e.URL.IsZero() {
+		err = multierr.Append(err, v2.ErrMissing{Name: "ExternalAPI.URL", Msg: "must be set with ExternalAPI Mode"})
+	} else if e.URL.Scheme != "https" {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "ExternalAPI.URL", Value: e.URL.String(),
+			Msg: "must be an https URL"})
+	}
+	var path *string
+	switch *e.Speed {
+	case "SafeLow":
+		path = e.SafeLowPath
+	case "Standard":
+		path = e.StandardPath
+	case "Fast":
+		path = e.FastPath
+	default:
+		err = multierr.Append(err, v2.ErrInvalid{Name: "ExternalAPI.Speed", Value: *e.Speed,
+			Msg: "must be one of SafeLow, Standard or Fast"})
+	}
+	if path != nil && *path == "" {
+		err = multierr.Append(err, v2.ErrEmpty{Name: fmt.Sprintf("ExternalAPI.%sPath", *e.Speed), Msg: "must be set for the ExternalAPI.Speed"})
+	}
+	if *e.BaseFeePath == "" {
+		err = multierr.Append(err, v2.ErrEmpty{Name: "ExternalAPI.BaseFeePath", Msg: "must be set with ExternalAPI Mode"})
+	}
+	switch *e.Unit {
+	case "wei", "gwei":
+	default:
+		err = multierr.Append(err, v2.ErrInvalid{Name: "ExternalAPI.Unit", Value: *e.Unit,
+			Msg: "must be one of wei or gwei"})
+	}
+	return
+}
+
+func (e *ExternalAPIEstimator) setFrom(f *ExternalAPIEstimator) {
+	if v := f.URL; v != nil {
+		e.URL = v
+	}
+	if v := f.Speed; v != nil {
+		e.Speed = v
+	}
+	if v := f.SafeLowPath; v != nil {
+		e.SafeLowPath = v
+	}
+	if v := f.StandardPath; v != nil {
+		e.StandardPath = v
+	}
+	if v := f.FastPath; v != nil {
+		e.FastPath = v
+	}
+	if v := f.BaseFeePath; v != nil {
+		e.BaseFeePath = v
+	}
+	if v := f.Unit; v != nil {
+		e.Unit = v
+	}
+	if v := f.PricesIncludeBaseFee; v != nil {
+		e.PricesIncludeBaseFee = v
+	}
+}
+
+type FeeHistoryEstimator struct {
+	BlockCount       *uint16
+	RewardPercentile *uint16
+}
+
+// validate checks the config of the FeeHistory Mode, which is only required
+// if the mode is used
+func (e *FeeHistoryEstimator) validate() (err error) {
+	if *e.BlockCount == 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FeeHistory.BlockCount", Value: *e.BlockCount,
+			Msg: "must be greater than or equal to 1 with FeeHistory Mode"})
+	}
+	if *e.RewardPercentile > 100 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FeeHistory.RewardPercentile", Value: *e.RewardPercentile,
+			Msg: "must be less than or equal to 100"})
+	}
+	return
+}
+
+func (e *FeeHistoryEstimator) setFrom(f *FeeHistoryEstimator) {
+	if v := f.BlockCount; v != nil {
+		e.BlockCount = v
+	}
+	if v := f.RewardPercentile; v != nil {
+		e.RewardPercentile = v
+	}
 }
 
 type KeySpecificConfig []KeySpecific
@@ -42,7 +42,11 @@ func newEvmTxm(
 
 	// build estimator from factory
 	if opts.GenGasEstimator == nil {
-		estimator = gas.NewEstimator(lggr, client, cfg)
+		var store gas.BlockHistoryStore
+		if db != nil {
+			store = gas.NewORM(db, lggr, cfg)
+		}
+		estimator = gas.NewEstimator(lggr, client, cfg, store)
 	} else {
 		estimator = opts.GenGasEstimator(chainID)
 	}
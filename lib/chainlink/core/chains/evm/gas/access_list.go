@@ -0,0 +1,91 @@
+package gas
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// ErrAccessListNotSupported is returned by CreateAccessList if the chain's
+// node does not support eth_createAccessList
+var ErrAccessListNotSupported = errors.New("eth_createAccessList is not supported by the node")
+
+// jsonRPCMethodNotFound is the JSON-RPC error code of unknown methods
+const jsonRPCMethodNotFound = -32601
+
+// AccessListEstimator creates EIP-2930 access lists with eth_createAccessList.
+// Calls that touch many storage slots need less gas with an access list, so the
+// node's gas used with the list makes for a lower gas limit.
+//
+// There is one AccessListEstimator per chain, like the estimator it is used
+// by. Once the chain's node reports that the method is not supported, it stops
+// calling it.
+type AccessListEstimator struct {
+	client      rpcClient
+	unsupported atomic.Bool
+	lggr        logger.Logger
+}
+
+// NewAccessListEstimator returns an AccessListEstimator for the chain of client
+func NewAccessListEstimator(lggr logger.Logger, client rpcClient) *AccessListEstimator {
+	return &AccessListEstimator{
+		client: client,
+		lggr:   lggr.Named("AccessListEstimator"),
+	}
+}
+
+// accessListResult is the result of eth_createAccessList
+type accessListResult struct {
+	AccessList types.AccessList `json:"accessList"`
+	GasUsed    hexutil.Uint64   `json:"gasUsed"`
+	Error      string           `json:"error,omitempty"`
+}
+
+// CreateAccessList returns the access list of the call at the latest block,
+// along with the gas the call uses with it. It returns
+// ErrAccessListNotSupported without calling the node once the node has
+// reported that it doesn't support the method.
+func (a *AccessListEstimator) CreateAccessList(ctx context.Context, call EstimateGasCall) (types.AccessList, uint64, error) {
+	if a.unsupported.Load() {
+		return nil, 0, ErrAccessListNotSupported
+	}
+	var res accessListResult
+	if err := a.client.CallContext(ctx, &res, "eth_createAccessList", call.args(), "latest"); err != nil {
+		if isMethodNotFound(err) {
+			if !a.unsupported.Swap(true) {
+				a.lggr.Infow("Node does not support eth_createAccessList, no longer creating access lists", "err", err)
+			}
+			return nil, 0, ErrAccessListNotSupported
+		}
+		return nil, 0, errors.Wrap(withDecodedRevert(err), "eth_createAccessList failed")
+	}
+	if res.Error != "" {
+		// the node reports calls that fail, e.g. because they revert, in the result
+		return nil, 0, errors.Errorf("eth_createAccessList failed: %s", res.Error)
+	}
+	return res.AccessList, uint64(res.GasUsed), nil
+}
+
+// isMethodNotFound returns true if err is the node's error for an unknown or
+// disabled JSON-RPC method
+func isMethodNotFound(err error) bool {
+	var rErr rpc.Error
+	if errors.As(err, &rErr) && rErr.ErrorCode() == jsonRPCMethodNotFound {
+		return true
+	}
+	if jErr := evmclient.ExtractRPCErrorOrNil(err); jErr != nil && jErr.Code == jsonRPCMethodNotFound {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "method not found") ||
+		strings.Contains(msg, "does not exist/is not available") ||
+		strings.Contains(msg, "unsupported method")
+}
@@ -0,0 +1,163 @@
+package gas_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_EstimateAccessList(t *testing.T) {
+	t.Parallel()
+
+	const feeLimit uint32 = 100_000
+	calldata := []byte{0x01, 0x02, 0x03}
+	call := gas.EstimateGasCall{From: testutils.NewAddress(), To: testutils.NewAddress()}
+	accessList := types.AccessList{{
+		Address:     testutils.NewAddress(),
+		StorageKeys: []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x02")},
+	}}
+
+	dynamicFee := gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(1)}
+	bumpedDynamicFee := gas.DynamicFee{FeeCap: assets.GWei(11), TipCap: assets.NewWeiI(1_100_000_000)}
+	newEstimator := func(t *testing.T, client *mocks.RPCClient, dynamic bool) gas.EvmFeeEstimator {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = dynamic
+		cfg.EvmGasEstimateGasLimitF = true
+		cfg.EvmGasEstimateAccessListF = true
+		cfg.EvmGasLimitMultiplierF = 1.5
+		cfg.EvmGasLimitMinF = 21_000
+		cfg.EvmGasLimitMaxF = 500_000
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, calldata, feeLimit, mock.Anything).Return(assets.GWei(1), feeLimit, nil).Maybe()
+		e.On("GetDynamicFee", mock.Anything, feeLimit, mock.Anything).Return(dynamicFee, feeLimit, nil).Maybe()
+		e.On("BumpDynamicFee", mock.Anything, dynamicFee, mock.Anything, mock.Anything, mock.Anything).Return(bumpedDynamicFee, uint32(120_000), nil).Maybe()
+		return gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, client)
+	}
+	mockCreateAccessList := func(t *testing.T, client *mocks.RPCClient, err error) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_createAccessList", mock.MatchedBy(func(args map[string]interface{}) bool {
+			return args["from"] == call.From && args["to"] == call.To && assert.ObjectsAreEqual(hexutil.Bytes(calldata), args["data"])
+		}), "latest").Run(func(args mock.Arguments) {
+			if err != nil {
+				return
+			}
+			require.NoError(t, setJSON(args.Get(1), map[string]interface{}{"accessList": accessList, "gasUsed": hexutil.Uint64(80_000)}))
+		}).Return(err)
+	}
+	mockEstimateGas := func(client *mocks.RPCClient, estimate uint64) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_estimateGas", mock.Anything).Run(func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = hexutil.Uint64(estimate)
+		}).Return(nil)
+	}
+
+	t.Run("returns the access list with the multiplied gas used", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCreateAccessList(t, client, nil).Once()
+		estimator := newEstimator(t, client, true)
+
+		fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(120_000), gasLimit)
+		assert.Equal(t, accessList, fee.AccessList)
+		assert.Equal(t, dynamicFee.FeeCap, fee.DynamicFeeCap)
+
+		// the bumped fee keeps the access list of the transaction
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), fee, gasLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bumpedDynamicFee.FeeCap, bumped.DynamicFeeCap)
+		assert.Equal(t, accessList, bumped.AccessList)
+	})
+
+	t.Run("estimates legacy fees without an access list, which they can't carry", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateGas(client, 100_000).Once()
+		estimator := newEstimator(t, client, false)
+
+		fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(150_000), gasLimit)
+		assert.Nil(t, fee.AccessList)
+		assert.Equal(t, assets.GWei(1), fee.Legacy)
+	})
+
+	t.Run("stops creating access lists if the node does not support it", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCreateAccessList(t, client, &evmclient.JsonError{Code: -32601, Message: "the method eth_createAccessList does not exist/is not available"}).Once()
+		mockEstimateGas(client, 100_000).Twice()
+		estimator := newEstimator(t, client, true)
+
+		for i := 0; i < 2; i++ {
+			fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(150_000), gasLimit)
+			assert.Nil(t, fee.AccessList)
+		}
+	})
+
+	t.Run("estimates without an access list if creating one fails", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCreateAccessList(t, client, errors.New("connection refused")).Twice()
+		mockEstimateGas(client, 100_000).Twice()
+		estimator := newEstimator(t, client, true)
+
+		// other errors don't stop the estimator from trying again
+		for i := 0; i < 2; i++ {
+			fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(150_000), gasLimit)
+			assert.Nil(t, fee.AccessList)
+		}
+	})
+}
+
+func TestAccessListEstimator_CreateAccessList(t *testing.T) {
+	t.Parallel()
+
+	call := gas.EstimateGasCall{From: testutils.NewAddress(), To: testutils.NewAddress(), Data: []byte{0x01}}
+
+	t.Run("reports failed calls", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_createAccessList", mock.Anything, "latest").Run(func(args mock.Arguments) {
+			require.NoError(t, setJSON(args.Get(1), map[string]interface{}{"accessList": types.AccessList{}, "gasUsed": "0x5208", "error": "execution reverted"}))
+		}).Return(nil).Once()
+
+		_, _, err := gas.NewAccessListEstimator(logger.TestLogger(t), client).CreateAccessList(testutils.Context(t), call)
+		assert.EqualError(t, err, "eth_createAccessList failed: execution reverted")
+	})
+
+	t.Run("recognizes method not found errors by message", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_createAccessList", mock.Anything, "latest").
+			Return(errors.New("Method not found")).Once()
+		e := gas.NewAccessListEstimator(logger.TestLogger(t), client)
+
+		_, _, err := e.CreateAccessList(testutils.Context(t), call)
+		assert.ErrorIs(t, err, gas.ErrAccessListNotSupported)
+		// without calling the node again
+		_, _, err = e.CreateAccessList(testutils.Context(t), call)
+		assert.ErrorIs(t, err, gas.ErrAccessListNotSupported)
+	})
+}
+
+// setJSON sets the result of a mocked CallContext to the JSON encoding of v
+func setJSON(result interface{}, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, result)
+}
@@ -0,0 +1,150 @@
+package gas
+
+import (
+	"context"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+var promGasEstimatorFeeAnomalyCount = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "gas_estimator_fee_anomaly_count",
+	Help: "Counter is incremented every time an estimated fee exceeds the moving average of recent fees by EVM.GasEstimator.FeeAnomalyFactor",
+},
+	[]string{"evmChainID", "estimator"},
+)
+
+// AnomalyEvent describes an estimated fee that exceeded the moving average of
+// recent estimates by EVM.GasEstimator.FeeAnomalyFactor, a sign of either a
+// gas war or a broken estimate
+type AnomalyEvent struct {
+	// Mode is the estimator mode, as in EVM.GasEstimator.Mode
+	Mode string
+	// Dynamic is true if Value is an EIP-1559 tip cap, false if it is a
+	// legacy gas price
+	Dynamic bool
+	// Value is the estimated tip cap or gas price
+	Value *assets.Wei
+	// EWMA is the exponentially weighted moving average of the previous
+	// estimates, with a half-life of EVM.GasEstimator.FeeAnomalyHalfLife
+	EWMA *assets.Wei
+}
+
+// AnomalyHook is called by the WrappedEvmEstimator when an estimated fee is an
+// anomaly, at most once per EVM.GasEstimator.FeeAnomalyCooldown. It is called
+// while estimating the fee, so it must not block.
+type AnomalyHook interface {
+	OnFeeAnomaly(ctx context.Context, event AnomalyEvent)
+}
+
+type loggingAnomalyHook struct {
+	chainID string
+	lggr    logger.SugaredLogger
+}
+
+// NewLoggingAnomalyHook returns the default AnomalyHook, which logs the
+// anomaly at warn level and increments gas_estimator_fee_anomaly_count
+func NewLoggingAnomalyHook(lggr logger.Logger, chainID *big.Int) AnomalyHook {
+	return &loggingAnomalyHook{chainID: chainID.String(), lggr: logger.Sugared(lggr.Named("FeeAnomaly"))}
+}
+
+func (h *loggingAnomalyHook) OnFeeAnomaly(_ context.Context, event AnomalyEvent) {
+	promGasEstimatorFeeAnomalyCount.WithLabelValues(h.chainID, event.Mode).Inc()
+	fee := "gas price"
+	if event.Dynamic {
+		fee = "tip cap"
+	}
+	h.lggr.Warnw("Estimated "+fee+" is far above the average of recent estimates, this may be a gas war or a broken estimate",
+		"mode", event.Mode, "value", event.Value, "ewma", event.EWMA)
+}
+
+// ewma is an exponentially weighted moving average of samples taken at
+// irregular times. A sample's weight halves every halfLife.
+type ewma struct {
+	value float64
+	at    time.Time
+}
+
+// add adds the sample v taken at now
+func (a *ewma) add(v float64, now time.Time, halfLife time.Duration) {
+	if a.at.IsZero() {
+		a.value, a.at = v, now
+		return
+	}
+	dt := now.Sub(a.at)
+	if dt <= 0 {
+		return
+	}
+	alpha := 1 - math.Exp2(-float64(dt)/float64(halfLife))
+	a.value += alpha * (v - a.value)
+	a.at = now
+}
+
+// feeAnomalyDetector calls the hook when an estimated fee exceeds factor times
+// the EWMA of the previous estimates, EVM.GasEstimator.FeeAnomalyFactor. Gas
+// prices and tip caps are averaged separately.
+type feeAnomalyDetector struct {
+	mode     string
+	factor   float64
+	halfLife time.Duration
+	cooldown time.Duration
+	hook     AnomalyHook
+	now      func() time.Time
+
+	mu        sync.Mutex
+	legacy    ewma
+	tipCap    ewma
+	lastAlert time.Time
+}
+
+func newFeeAnomalyDetector(cfg Config, hook AnomalyHook) *feeAnomalyDetector {
+	return &feeAnomalyDetector{
+		mode:     cfg.GasEstimatorMode(),
+		factor:   float64(cfg.EvmGasFeeAnomalyFactor()),
+		halfLife: cfg.EvmGasFeeAnomalyHalfLife(),
+		cooldown: cfg.EvmGasFeeAnomalyCooldown(),
+		hook:     hook,
+		now:      time.Now,
+	}
+}
+
+// observe adds the estimated fee to the average and calls the hook if it is
+// an anomaly, returning whether it was
+func (d *feeAnomalyDetector) observe(ctx context.Context, fee EvmFee) (anomaly bool) {
+	value, dynamic := fee.Legacy, false
+	if fee.ValidDynamic() {
+		value, dynamic = fee.DynamicTipCap, true
+	}
+	if value == nil {
+		return false
+	}
+	v, _ := new(big.Float).SetInt(value.ToInt()).Float64()
+
+	d.mu.Lock()
+	avg := &d.legacy
+	if dynamic {
+		avg = &d.tipCap
+	}
+	now := d.now()
+	prev := avg.value
+	anomaly = !avg.at.IsZero() && prev > 0 && v > d.factor*prev &&
+		(d.lastAlert.IsZero() || now.Sub(d.lastAlert) >= d.cooldown)
+	if anomaly {
+		d.lastAlert = now
+	}
+	avg.add(v, now, d.halfLife)
+	d.mu.Unlock()
+
+	if anomaly {
+		ewmaInt, _ := big.NewFloat(prev).Int(nil)
+		d.hook.OnFeeAnomaly(ctx, AnomalyEvent{Mode: d.mode, Dynamic: dynamic, Value: value, EWMA: assets.NewWei(ewmaInt)})
+	}
+	return anomaly
+}
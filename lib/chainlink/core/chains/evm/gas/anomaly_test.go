@@ -0,0 +1,127 @@
+package gas_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+type anomalyRecorder struct {
+	mu     sync.Mutex
+	events []gas.AnomalyEvent
+}
+
+func (r *anomalyRecorder) OnFeeAnomaly(_ context.Context, event gas.AnomalyEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestWrappedEvmEstimator_FeeAnomaly(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21_000
+	newConfig := func(factor float32) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = "BlockHistory"
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(5000)
+		cfg.EvmGasFeeAnomalyFactorF = factor
+		cfg.EvmGasFeeAnomalyHalfLifeF = time.Minute
+		cfg.EvmGasFeeAnomalyCooldownF = 10 * time.Minute
+		return cfg
+	}
+	// newEstimator returns an estimator that estimates the prices in order, one
+	// second apart
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, hook gas.AnomalyHook, prices ...*assets.Wei) (gas.EvmFeeEstimator, *time.Time) {
+		e := mocks.NewEvmEstimator(t)
+		for _, price := range prices {
+			e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(price, gasLimit, nil).Once()
+		}
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
+		estimator.(*gas.WrappedEvmEstimator).SetAnomalyHook(hook)
+		now := time.Now()
+		if cfg.EvmGasFeeAnomalyFactorF > 0 {
+			gas.SetAnomalyClock(estimator, func() time.Time { return now })
+		}
+		return estimator, &now
+	}
+	estimate := func(t *testing.T, estimator gas.EvmFeeEstimator, now *time.Time, n int) {
+		for i := 0; i < n; i++ {
+			*now = now.Add(time.Second)
+			_, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+			require.NoError(t, err)
+		}
+	}
+	repeat := func(price *assets.Wei, n int) (prices []*assets.Wei) {
+		for i := 0; i < n; i++ {
+			prices = append(prices, price)
+		}
+		return
+	}
+
+	t.Run("calls the hook once within the cooldown", func(t *testing.T) {
+		hook := new(anomalyRecorder)
+		prices := append(repeat(assets.GWei(10), 20), repeat(assets.GWei(100), 10)...)
+		prices = append(prices, assets.GWei(1000))
+		estimator, now := newEstimator(t, newConfig(5), hook, prices...)
+
+		estimate(t, estimator, now, 20)
+		assert.Empty(t, hook.events)
+		estimate(t, estimator, now, 10)
+		require.Len(t, hook.events, 1)
+		assert.Equal(t, gas.AnomalyEvent{Mode: "BlockHistory", Value: assets.GWei(100), EWMA: assets.GWei(10)}, hook.events[0])
+
+		// the next anomaly after the cooldown calls the hook again
+		*now = now.Add(10 * time.Minute)
+		estimate(t, estimator, now, 1)
+		require.Len(t, hook.events, 2)
+		assert.Equal(t, assets.GWei(1000), hook.events[1].Value)
+	})
+
+	t.Run("averages tip caps", func(t *testing.T) {
+		cfg := newConfig(5)
+		cfg.EvmEIP1559DynamicFeesF = true
+		hook := new(anomalyRecorder)
+		e := mocks.NewEvmEstimator(t)
+		for _, tipCap := range []int64{1, 1, 1, 10} {
+			e.On("GetDynamicFee", mock.Anything, gasLimit, mock.Anything).Return(gas.DynamicFee{FeeCap: assets.GWei(100), TipCap: assets.GWei(tipCap)}, gasLimit, nil).Once()
+		}
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
+		estimator.(*gas.WrappedEvmEstimator).SetAnomalyHook(hook)
+		now := time.Now()
+		gas.SetAnomalyClock(estimator, func() time.Time { return now })
+
+		estimate(t, estimator, &now, 4)
+		require.Len(t, hook.events, 1)
+		assert.Equal(t, gas.AnomalyEvent{Mode: "BlockHistory", Dynamic: true, Value: assets.GWei(10), EWMA: assets.GWei(1)}, hook.events[0])
+	})
+
+	t.Run("is disabled with a factor of 0", func(t *testing.T) {
+		hook := new(anomalyRecorder)
+		estimator, now := newEstimator(t, newConfig(0), hook, assets.GWei(10), assets.GWei(1000))
+
+		estimate(t, estimator, now, 2)
+		assert.Empty(t, hook.events)
+	})
+
+	t.Run("the logging hook counts anomalies", func(t *testing.T) {
+		chainID := testutils.NewRandomEVMChainID()
+		hook := gas.NewLoggingAnomalyHook(logger.TestLogger(t), chainID)
+		estimator, now := newEstimator(t, newConfig(5), hook, assets.GWei(10), assets.GWei(10), assets.GWei(100))
+
+		estimate(t, estimator, now, 3)
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(gas.PromGasEstimatorFeeAnomalyCount.WithLabelValues(chainID.String(), "BlockHistory")))
+	})
+}
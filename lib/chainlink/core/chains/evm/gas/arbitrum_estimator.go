@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/maps"
 	"golang.org/x/exp/slices"
 
 	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
@@ -21,6 +25,7 @@ import (
 )
 
 type ArbConfig interface {
+	L2SuggestedPriceConfig
 	EvmGasLimitMax() uint32
 }
 
@@ -29,15 +34,19 @@ type ethClient interface {
 	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
 }
 
-// arbitrumEstimator is an Estimator which extends l2SuggestedPriceEstimator to use getPricesInArbGas() for gas limit estimation.
+// arbitrumEstimator is an Estimator which extends l2SuggestedPriceEstimator to use gasEstimateComponents() and
+// getPricesInArbGas() for gas limit estimation.
 type arbitrumEstimator struct {
 	cfg ArbConfig
 
 	EvmEstimator // *l2SuggestedPriceEstimator
 
+	rpcClient  rpcClient
 	client     ethClient
 	pollPeriod time.Duration
 	logger     logger.Logger
+	chainID    big.Int
+	metrics    *estimatorMetrics
 
 	getPricesInArbGasMu sync.RWMutex
 	perL2Tx             uint32
@@ -51,14 +60,17 @@ type arbitrumEstimator struct {
 	utils.StartStopOnce
 }
 
-func NewArbitrumEstimator(lggr logger.Logger, cfg ArbConfig, rpcClient rpcClient, ethClient ethClient) EvmEstimator {
+func NewArbitrumEstimator(lggr logger.Logger, cfg ArbConfig, rpcClient rpcClient, ethClient ethClient, chainID big.Int) EvmEstimator {
 	lggr = lggr.Named("ArbitrumEstimator")
 	return &arbitrumEstimator{
 		cfg:            cfg,
-		EvmEstimator:   NewL2SuggestedPriceEstimator(lggr, rpcClient),
+		EvmEstimator:   newL2SuggestedPriceEstimator(lggr, cfg, singleClient(rpcClient), chainID, "Arbitrum"),
+		rpcClient:      rpcClient,
 		client:         ethClient,
 		pollPeriod:     10 * time.Second,
 		logger:         lggr,
+		chainID:        chainID,
+		metrics:        newEstimatorMetrics(chainID, "Arbitrum"),
 		chForceRefetch: make(chan (chan struct{})),
 		chInitialised:  make(chan struct{}),
 		chStop:         make(chan struct{}),
@@ -91,21 +103,32 @@ func (a *arbitrumEstimator) Close() error {
 
 func (a *arbitrumEstimator) Ready() error { return a.StartStopOnce.Ready() }
 
+// HealthReport includes the report of the embedded l2SuggestedPriceEstimator,
+// which polls the L2 gas price
 func (a *arbitrumEstimator) HealthReport() map[string]error {
-	return map[string]error{a.Name(): a.StartStopOnce.Healthy()}
+	report := map[string]error{a.Name(): a.StartStopOnce.Healthy()}
+	maps.Copy(report, a.EvmEstimator.HealthReport())
+	return report
 }
 
 // GetLegacyGas estimates both the gas price and the gas limit.
 //   - Price is delegated to the embedded l2SuggestedPriceEstimator.
-//   - Limit is computed from the dynamic values perL2Tx and perL1CalldataUnit, provided by the getPricesInArbGas() method
-//     of the precompilie contract at ArbGasInfoAddress. perL2Tx is a constant amount of gas, and perL1CalldataUnit is
-//     multiplied by the length of the tx calldata. The sum of these two values plus the original l2GasLimit is returned.
+//   - Limit is the original l2GasLimit plus the L1 gas needed to post the calldata, as returned for this specific
+//     payload by the gasEstimateComponents() method of the virtual contract at NodeInterfaceAddress.
+//   - If that call fails, the limit is computed from the dynamic values perL2Tx and perL1CalldataUnit, provided by the
+//     getPricesInArbGas() method of the precompilie contract at ArbGasInfoAddress. perL2Tx is a constant amount of gas,
+//     and perL1CalldataUnit is multiplied by the length of the tx calldata. The sum of these two values plus the
+//     original l2GasLimit is returned.
+//
+// In both cases the limit is capped by EvmGasLimitMax.
 func (a *arbitrumEstimator) GetLegacyGas(ctx context.Context, calldata []byte, l2GasLimit uint32, maxGasPriceWei *assets.Wei, opts ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &a.chainID, "Arbitrum") }()
 	gasPrice, _, err = a.EvmEstimator.GetLegacyGas(ctx, calldata, l2GasLimit, maxGasPriceWei, opts...)
 	if err != nil {
 		return
 	}
 	gasPrice = a.gasPriceWithBuffer(gasPrice, maxGasPriceWei)
+	var limit uint64
 	ok := a.IfStarted(func() {
 		if slices.Contains(opts, txmgrtypes.OptForceRefetch) {
 			ch := make(chan struct{})
@@ -128,23 +151,38 @@ func (a *arbitrumEstimator) GetLegacyGas(ctx context.Context, calldata []byte, l
 				return
 			}
 		}
+		gasEstimateForL1, gerr := a.callGasEstimateComponents(ctx, calldata)
+		if gerr == nil {
+			limit = uint64(l2GasLimit) + gasEstimateForL1
+			a.logger.Debugw("GetLegacyGas", "l2GasLimit", l2GasLimit, "calldataLen", len(calldata), "gasEstimateForL1", gasEstimateForL1,
+				"chainSpecificGasLimit", limit)
+			return
+		}
+		a.logger.Warnw("Failed to call gasEstimateComponents, falling back to getPricesInArbGas", "err", gerr)
 		perL2Tx, perL1CalldataUnit := a.getPricesInArbGas()
-		chainSpecificGasLimit = l2GasLimit + perL2Tx + uint32(len(calldata))*perL1CalldataUnit
+		limit = uint64(l2GasLimit) + uint64(perL2Tx) + uint64(len(calldata))*uint64(perL1CalldataUnit)
 		a.logger.Debugw("GetLegacyGas", "l2GasLimit", l2GasLimit, "calldataLen", len(calldata), "perL2Tx", perL2Tx,
-			"perL1CalldataUnit", perL1CalldataUnit, "chainSpecificGasLimit", chainSpecificGasLimit)
+			"perL1CalldataUnit", perL1CalldataUnit, "chainSpecificGasLimit", limit)
 	})
 	if !ok {
 		return nil, 0, errors.New("estimator is not started")
 	} else if err != nil {
 		return
 	}
-	if max := a.cfg.EvmGasLimitMax(); chainSpecificGasLimit > max {
-		err = fmt.Errorf("estimated gas limit: %d is greater than the maximum gas limit configured: %d", chainSpecificGasLimit, max)
+	if max := a.cfg.EvmGasLimitMax(); limit > uint64(max) {
+		err = fmt.Errorf("estimated gas limit: %d is greater than the maximum gas limit configured: %d", limit, max)
 		return
 	}
+	chainSpecificGasLimit = uint32(limit)
 	return
 }
 
+// BumpLegacyGas is not supported, since the gas limit returned by GetLegacyGas
+// depends on the calldata, which is not known when bumping
+func (a *arbitrumEstimator) BumpLegacyGas(_ context.Context, _ *assets.Wei, _ uint32, _ *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	return nil, 0, annotateError(errors.New("bump gas is not supported for this l2"), &a.chainID, "Arbitrum")
+}
+
 // During network congestion Arbitrum's suggested gas price can be extremely volatile, making gas estimations less accurate. For any transaction, Arbitrum will only charge
 // the block's base fee. If the base fee increases rapidly there is a chance the suggested gas price will fall under that value, resulting in a fee too low error.
 // We use gasPriceWithBuffer to increase the estimated gas price by some percentage to avoid fee too low errors. Eventually, only the base fee will be paid, regardless of the price.
@@ -155,6 +193,7 @@ func (a *arbitrumEstimator) gasPriceWithBuffer(gasPrice *assets.Wei, maxGasPrice
 	if gasPrice.Cmp(maxGasPriceWei) > 0 {
 		a.logger.Warnw("Updated gasPrice with buffer is higher than the max gas price limit. Falling back to max gas price", "gasPriceWithBuffer", gasPrice, "maxGasPriceWei", maxGasPriceWei)
 		gasPrice = maxGasPriceWei
+		a.metrics.maxPriceCapped.Inc()
 	}
 	a.logger.Debugw("gasPriceWithBuffer", "updatedGasPrice", gasPrice)
 	return gasPrice
@@ -231,7 +270,8 @@ func (a *arbitrumEstimator) callGetPricesInArbGas() (perL2Tx uint32, perL1Callda
 		Data: common.Hex2Bytes(ArbGasInfo_getPricesInArbGas),
 	}, big.NewInt(-1))
 	if err != nil {
-		return 0, 0, err
+		a.metrics.rpcErrors.Inc()
+		return 0, 0, withDecodedRevert(err)
 	}
 
 	if len(b) != 3*32 { // returns (uint256, uint256, uint256);
@@ -256,3 +296,57 @@ func (a *arbitrumEstimator) callGetPricesInArbGas() (perL2Tx uint32, perL1Callda
 	perL1CalldataUnit = uint32(perL1CalldataUnitU64)
 	return
 }
+
+const (
+	// NodeInterfaceAddress is the address of the NodeInterface "virtual contract", which is only available on Arbitrum
+	// nodes through eth_call and eth_estimateGas.
+	// https://github.com/OffchainLabs/nitro/blob/f7645453cfc77bf3e3644ea1ac031eff629df325/contracts/src/node-interface/NodeInterface.sol
+	NodeInterfaceAddress = "0x00000000000000000000000000000000000000C8"
+	// NodeInterface_gasEstimateComponents is the hex encoded method ID of:
+	// `function gasEstimateComponents(address to, bool contractCreation, bytes calldata data) external payable returns (uint64, uint64, uint256, uint256);`
+	NodeInterface_gasEstimateComponents = "c94e6eeb"
+)
+
+const nodeInterfaceABIJSON = `[{"inputs":[{"internalType":"address","name":"to","type":"address"},{"internalType":"bool","name":"contractCreation","type":"bool"},{"internalType":"bytes","name":"data","type":"bytes"}],"name":"gasEstimateComponents","outputs":[{"internalType":"uint64","name":"gasEstimate","type":"uint64"},{"internalType":"uint64","name":"gasEstimateForL1","type":"uint64"},{"internalType":"uint256","name":"baseFee","type":"uint256"},{"internalType":"uint256","name":"l1BaseFeeEstimate","type":"uint256"}],"stateMutability":"payable","type":"function"}]`
+
+var nodeInterfaceABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(nodeInterfaceABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// callGasEstimateComponents calls NodeInterface.gasEstimateComponents() for the given calldata on the virtual contract
+// NodeInterfaceAddress, and returns the amount of gas needed to post it to L1.
+//
+// @return (gasEstimate, gasEstimateForL1, baseFee, l1BaseFeeEstimate)
+// function gasEstimateComponents(address to, bool contractCreation, bytes calldata data) external payable returns (uint64, uint64, uint256, uint256);
+//
+// https://github.com/OffchainLabs/nitro/blob/f7645453cfc77bf3e3644ea1ac031eff629df325/contracts/src/node-interface/NodeInterface.sol#L87
+func (a *arbitrumEstimator) callGasEstimateComponents(ctx context.Context, calldata []byte) (gasEstimateForL1 uint64, err error) {
+	data, err := nodeInterfaceABI.Pack("gasEstimateComponents", common.Address{}, false, calldata)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode gasEstimateComponents call")
+	}
+
+	var b hexutil.Bytes
+	err = a.rpcClient.CallContext(ctx, &b, "eth_call", map[string]interface{}{
+		"to":   NodeInterfaceAddress,
+		"data": hexutil.Bytes(data),
+	}, "latest")
+	if err != nil {
+		a.metrics.recordRPCError(err)
+		return 0, errors.Wrap(withDecodedRevert(err), "gasEstimateComponents call failed")
+	}
+
+	out, err := nodeInterfaceABI.Unpack("gasEstimateComponents", b)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to decode gasEstimateComponents result")
+	}
+	gasEstimateForL1, ok := out[1].(uint64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected gasEstimateForL1 type %T", out[1])
+	}
+	return gasEstimateForL1, nil
+}
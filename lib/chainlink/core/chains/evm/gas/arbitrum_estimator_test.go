@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -34,7 +35,7 @@ func TestArbitrumEstimator(t *testing.T) {
 		config := mocks.NewConfig(t)
 		rpcClient := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
 		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
 		assert.EqualError(t, err, "estimator is not started")
 	})
@@ -43,14 +44,36 @@ func TestArbitrumEstimator(t *testing.T) {
 	zeros.Write(common.BigToHash(big.NewInt(0)).Bytes())
 	zeros.Write(common.BigToHash(big.NewInt(0)).Bytes())
 	zeros.Write(common.BigToHash(big.NewInt(123455)).Bytes())
+
+	// gasEstimateComponents ABI encodes a NodeInterface.gasEstimateComponents() result
+	gasEstimateComponents := func(gasEstimateForL1 int64) hexutil.Bytes {
+		var b bytes.Buffer
+		b.Write(common.BigToHash(big.NewInt(gasEstimateForL1 + 21_000)).Bytes())
+		b.Write(common.BigToHash(big.NewInt(gasEstimateForL1)).Bytes())
+		b.Write(common.BigToHash(big.NewInt(100_000_000)).Bytes())
+		b.Write(common.BigToHash(big.NewInt(20_000_000_000)).Bytes())
+		return b.Bytes()
+	}
+	mockGasEstimateComponents := func(t *testing.T, rpcClient *mocks.RPCClient, result hexutil.Bytes, err error) {
+		rpcClient.On("CallContext", mock.Anything, mock.IsType(&hexutil.Bytes{}), "eth_call", mock.Anything, "latest").Run(func(args mock.Arguments) {
+			callArgs := args.Get(3).(map[string]interface{})
+			assert.Equal(t, gas.NodeInterfaceAddress, callArgs["to"])
+			data := callArgs["data"].(hexutil.Bytes)
+			assert.Equal(t, gas.NodeInterface_gasEstimateComponents, fmt.Sprintf("%x", []byte(data[:4])))
+			assert.True(t, bytes.Contains(data[4:], calldata))
+			*args.Get(1).(*hexutil.Bytes) = result
+		}).Return(err)
+	}
+
 	t.Run("calling GetLegacyGas on started estimator returns estimates", func(t *testing.T) {
 		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
 		config.On("EvmGasLimitMax").Return(maxGasLimit)
 		rpcClient := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
 		rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(42)
+			gas.SetRPCPrice(args.Get(1), 42)
 		})
 		ethClient.On("CallContract", mock.Anything, mock.IsType(ethereum.CallMsg{}), mock.IsType(&big.Int{})).Run(func(args mock.Arguments) {
 			callMsg := args.Get(1).(ethereum.CallMsg)
@@ -59,8 +82,9 @@ func TestArbitrumEstimator(t *testing.T) {
 			assert.Equal(t, gas.ArbGasInfo_getPricesInArbGas, fmt.Sprintf("%x", callMsg.Data))
 			assert.Equal(t, big.NewInt(-1), blockNumber)
 		}).Return(zeros.Bytes(), nil)
+		mockGasEstimateComponents(t, rpcClient, gasEstimateComponents(0), nil)
 
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
 		require.NoError(t, o.Start(testutils.Context(t)))
 		t.Cleanup(func() { assert.NoError(t, o.Close()) })
 		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
@@ -74,11 +98,12 @@ func TestArbitrumEstimator(t *testing.T) {
 		client := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
 		config := mocks.NewConfig(t)
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, client, ethClient)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, client, ethClient, *testutils.FixtureChainID)
 
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(42)
+			gas.SetRPCPrice(args.Get(1), 42)
 		})
 		ethClient.On("CallContract", mock.Anything, mock.IsType(ethereum.CallMsg{}), mock.IsType(&big.Int{})).Run(func(args mock.Arguments) {
 			callMsg := args.Get(1).(ethereum.CallMsg)
@@ -100,12 +125,13 @@ func TestArbitrumEstimator(t *testing.T) {
 	t.Run("gas price is lower than global max gas price", func(t *testing.T) {
 		ethClient := mocks.NewETHClient(t)
 		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
 		client := mocks.NewRPCClient(t)
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, client, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, client, ethClient, *testutils.FixtureChainID)
 
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(120)
+			gas.SetRPCPrice(args.Get(1), 120)
 		})
 		ethClient.On("CallContract", mock.Anything, mock.IsType(ethereum.CallMsg{}), mock.IsType(&big.Int{})).Run(func(args mock.Arguments) {
 			callMsg := args.Get(1).(ethereum.CallMsg)
@@ -127,16 +153,18 @@ func TestArbitrumEstimator(t *testing.T) {
 		config := mocks.NewConfig(t)
 		rpcClient := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
 		_, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), gasLimit, assets.NewWeiI(10), nil)
 		assert.EqualError(t, err, "bump gas is not supported for this l2")
 	})
 
 	t.Run("calling GetLegacyGas on started estimator if initial call failed returns error", func(t *testing.T) {
 		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
 		client := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, client, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, client, ethClient, *testutils.FixtureChainID)
 
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom"))
 		ethClient.On("CallContract", mock.Anything, mock.IsType(ethereum.CallMsg{}), mock.IsType(&big.Int{})).Run(func(args mock.Arguments) {
@@ -156,12 +184,60 @@ func TestArbitrumEstimator(t *testing.T) {
 
 	t.Run("limit computes", func(t *testing.T) {
 		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
 		config.On("EvmGasLimitMax").Return(maxGasLimit)
 		rpcClient := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
 		rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(42)
+			gas.SetRPCPrice(args.Get(1), 42)
+		})
+		const gasEstimateForL1 = 120_000
+		var expLimit = gasLimit + gasEstimateForL1
+
+		ethClient.On("CallContract", mock.Anything, mock.IsType(ethereum.CallMsg{}), mock.IsType(&big.Int{})).Return(zeros.Bytes(), nil)
+		mockGasEstimateComponents(t, rpcClient, gasEstimateComponents(gasEstimateForL1), nil)
+
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		require.NotNil(t, gasPrice)
+		assert.Equal(t, "63 wei", gasPrice.String())
+		assert.Equal(t, expLimit, chainSpecificGasLimit, "expected %d but got %d", expLimit, chainSpecificGasLimit)
+	})
+
+	t.Run("limit exceeds max", func(t *testing.T) {
+		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
+		config.On("EvmGasLimitMax").Return(maxGasLimit)
+		rpcClient := mocks.NewRPCClient(t)
+		ethClient := mocks.NewETHClient(t)
+		rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 42)
+		})
+		ethClient.On("CallContract", mock.Anything, mock.IsType(ethereum.CallMsg{}), mock.IsType(&big.Int{})).Return(zeros.Bytes(), nil)
+		mockGasEstimateComponents(t, rpcClient, gasEstimateComponents(1_000_000), nil)
+
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.Error(t, err, "expected error but got (%s, %d)", gasPrice, chainSpecificGasLimit)
+		assert.EqualError(t, err, "estimated gas limit: 1080000 is greater than the maximum gas limit configured: 500000")
+	})
+
+	t.Run("limit falls back to getPricesInArbGas if gasEstimateComponents result cannot be decoded", func(t *testing.T) {
+		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
+		config.On("EvmGasLimitMax").Return(maxGasLimit)
+		rpcClient := mocks.NewRPCClient(t)
+		ethClient := mocks.NewETHClient(t)
+		rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 42)
 		})
 		const (
 			perL2Tx       = 50_000
@@ -180,8 +256,10 @@ func TestArbitrumEstimator(t *testing.T) {
 			assert.Equal(t, gas.ArbGasInfo_getPricesInArbGas, fmt.Sprintf("%x", callMsg.Data))
 			assert.Equal(t, big.NewInt(-1), blockNumber)
 		}).Return(b.Bytes(), nil)
+		// too short to hold (uint64, uint64, uint256, uint256)
+		mockGasEstimateComponents(t, rpcClient, gasEstimateComponents(120_000)[:64], nil)
 
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
 		require.NoError(t, o.Start(testutils.Context(t)))
 		t.Cleanup(func() { assert.NoError(t, o.Close()) })
 		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
@@ -192,14 +270,15 @@ func TestArbitrumEstimator(t *testing.T) {
 		assert.Equal(t, expLimit, chainSpecificGasLimit, "expected %d but got %d", expLimit, chainSpecificGasLimit)
 	})
 
-	t.Run("limit exceeds max", func(t *testing.T) {
+	t.Run("limit from getPricesInArbGas exceeds max if gasEstimateComponents fails", func(t *testing.T) {
 		config := mocks.NewConfig(t)
+		config.On("EvmEIP1559DynamicFees").Return(false)
+		config.On("EvmGasPriceStaleThreshold").Return(time.Duration(0))
 		config.On("EvmGasLimitMax").Return(maxGasLimit)
 		rpcClient := mocks.NewRPCClient(t)
 		ethClient := mocks.NewETHClient(t)
 		rpcClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(42)
+			gas.SetRPCPrice(args.Get(1), 42)
 		})
 		const (
 			perL2Tx       = 500_000
@@ -217,8 +296,9 @@ func TestArbitrumEstimator(t *testing.T) {
 			assert.Equal(t, gas.ArbGasInfo_getPricesInArbGas, fmt.Sprintf("%x", callMsg.Data))
 			assert.Equal(t, big.NewInt(-1), blockNumber)
 		}).Return(b.Bytes(), nil)
+		mockGasEstimateComponents(t, rpcClient, nil, errors.New("method not found"))
 
-		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient)
+		o := gas.NewArbitrumEstimator(logger.TestLogger(t), config, rpcClient, ethClient, *testutils.FixtureChainID)
 		require.NoError(t, o.Start(testutils.Context(t)))
 		t.Cleanup(func() { assert.NoError(t, o.Close()) })
 		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
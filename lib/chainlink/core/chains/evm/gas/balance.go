@@ -0,0 +1,112 @@
+package gas
+
+import (
+	"context"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+type availableBalanceKey struct{}
+
+// availableBalance is the balance of the sending key given with
+// WithAvailableBalance, and the value of the transaction
+type availableBalance struct {
+	balance *assets.Wei
+	value   *assets.Wei
+}
+
+// WithAvailableBalance returns a context that makes GetFee and BumpFee fit the
+// transaction within the available balance of the sending key, so that a key
+// that is nearly out of funds sends at a lower but still viable fee rather
+// than failing with insufficient funds. The value is the value the
+// transaction sends, which must be paid from the balance too, and may be nil.
+//
+// If the transaction would cost more than the balance at the estimated fee,
+// GetFee lowers the fee to fit, down to the minimum viable fee, and sets
+// LowBalanceAdjusted on it; if even the minimum doesn't fit, it returns an
+// ErrInsufficientBalance error. BumpFee never lowers a bump, since it would
+// no longer replace the original transaction, so it returns an
+// ErrInsufficientBalance error instead of bumping past the balance.
+func WithAvailableBalance(ctx context.Context, balance, value *assets.Wei) context.Context {
+	return context.WithValue(ctx, availableBalanceKey{}, availableBalance{balance: balance, value: value})
+}
+
+func availableBalanceFromContext(ctx context.Context) (availableBalance, bool) {
+	b, ok := ctx.Value(availableBalanceKey{}).(availableBalance)
+	return b, ok && b.balance != nil
+}
+
+// forFees returns the balance left for the fees after the value
+func (b availableBalance) forFees() *assets.Wei {
+	if b.value == nil {
+		return b.balance
+	}
+	return b.balance.Sub(b.value)
+}
+
+// minViableFee returns the lowest fee that can still be included: the base
+// fee of the latest head plus EVM.GasEstimator.TipCapMin for dynamic fees,
+// and EVM.GasEstimator.PriceMin for legacy fees, but no less than the base
+// fee. If the base fee is unknown only the configured minimums apply.
+func (e WrappedEvmEstimator) minViableFee(dynamic bool) (price, tipCap *assets.Wei) {
+	var baseFee *assets.Wei
+	if head := e.LatestHead(); head != nil {
+		baseFee = head.BaseFeePerGas
+	}
+	if dynamic {
+		tipCap = e.cfg.EvmGasTipCapMinimum()
+		if tipCap == nil {
+			tipCap = assets.NewWeiI(0)
+		}
+		if baseFee == nil {
+			return tipCap, tipCap
+		}
+		return baseFee.Add(tipCap), tipCap
+	}
+	price = e.cfg.EvmMinGasPriceWei()
+	if price == nil {
+		price = assets.NewWeiI(0)
+	}
+	if baseFee != nil {
+		price = assets.WeiMax(price, baseFee)
+	}
+	return price, nil
+}
+
+// availableBalanceLimit returns the limit of the balance given with
+// WithAvailableBalance, less the value of the transaction, if given
+func availableBalanceLimit(ctx context.Context) (costLimit, bool) {
+	b, ok := availableBalanceFromContext(ctx)
+	if !ok {
+		return costLimit{}, false
+	}
+	return costLimit{maxCost: b.forFees(), reason: ErrInsufficientBalance, name: "the available balance for fees"}, true
+}
+
+// applyAvailableBalance lowers fee so that the transaction, with the given
+// gas limit and the value given with WithAvailableBalance, costs no more than
+// the available balance, see lowerFeeToCost. If even the minimum viable fee
+// doesn't fit, an ErrInsufficientBalance error is returned.
+func (e WrappedEvmEstimator) applyAvailableBalance(ctx context.Context, fee EvmFee, gasLimit uint32) (EvmFee, error) {
+	limit, ok := availableBalanceLimit(ctx)
+	if !ok {
+		return fee, nil
+	}
+	fee, lowered, err := e.lowerFeeToCost(fee, gasLimit, limit)
+	if lowered {
+		fee.LowBalanceAdjusted = true
+	}
+	return fee, err
+}
+
+// checkAvailableBalance returns an ErrInsufficientBalance error if the bumped
+// fee would make the transaction, with the value given with
+// WithAvailableBalance, cost more than the available balance with the given
+// gas limit
+func (e WrappedEvmEstimator) checkAvailableBalance(ctx context.Context, bumpedFee EvmFee, gasLimit uint32) error {
+	limit, ok := availableBalanceLimit(ctx)
+	if !ok {
+		return nil
+	}
+	return checkFeeCost(bumpedFee, gasLimit, limit)
+}
@@ -0,0 +1,164 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_AvailableBalance(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit = 21000
+	newEstimator := func(t *testing.T, eip1559 bool, baseFee *assets.Wei) gas.EvmFeeEstimator {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = eip1559
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmMinGasPriceWeiF = assets.GWei(1)
+		cfg.EvmGasPriceDefaultF = assets.GWei(20)
+		cfg.EvmGasFeeCapDefaultF = assets.GWei(80)
+		cfg.EvmGasTipCapDefaultF = assets.GWei(2)
+		cfg.EvmGasTipCapMinimumF = assets.GWei(1)
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmGasBumpPercentF = 20
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(0)
+		cfg.EvmGasLimitMultiplierF = 1
+
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil)
+		estimator.OnNewLongestChain(testutils.Context(t), &evmtypes.Head{Number: 1, BaseFeePerGas: baseFee})
+		return estimator
+	}
+	// costing returns a balance that pays for gasLimit at price, plus value
+	costing := func(price, value *assets.Wei) *assets.Wei {
+		return price.Mul(big.NewInt(gasLimit)).Add(value)
+	}
+	value := assets.GWei(1000)
+
+	t.Run("legacy", func(t *testing.T) {
+		t.Run("exact fit", func(t *testing.T) {
+			estimator := newEstimator(t, false, nil)
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(20), value), value)
+
+			fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(20), fee.Legacy)
+			assert.False(t, fee.LowBalanceAdjusted)
+		})
+
+		t.Run("steps down to fit the balance", func(t *testing.T) {
+			estimator := newEstimator(t, false, nil)
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(10), value), value)
+
+			fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(10), fee.Legacy)
+			assert.True(t, fee.LowBalanceAdjusted)
+
+			// without the balance the fee is unchanged
+			fee, _, err = estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(20), fee.Legacy)
+			assert.False(t, fee.LowBalanceAdjusted)
+		})
+
+		t.Run("fails below the minimum viable price", func(t *testing.T) {
+			// the base fee raises the minimum over EVM.GasEstimator.PriceMin
+			estimator := newEstimator(t, false, assets.GWei(5))
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(4), value), value)
+
+			_, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.ErrorIs(t, err, gas.ErrInsufficientBalance)
+			var eErr *gas.EstimationError
+			require.ErrorAs(t, err, &eErr)
+			assert.Equal(t, costing(assets.GWei(5), assets.NewWeiI(0)), eErr.Price)
+			assert.Equal(t, costing(assets.GWei(4), assets.NewWeiI(0)), eErr.Limit)
+
+			// the balance doesn't even cover the value
+			ctx = gas.WithAvailableBalance(testutils.Context(t), value, value.Add(assets.NewWeiI(1)))
+			_, _, err = estimator.GetFee(ctx, nil, gasLimit, nil)
+			assert.ErrorIs(t, err, gas.ErrInsufficientBalance)
+		})
+
+		t.Run("refuses to bump past the balance", func(t *testing.T) {
+			estimator := newEstimator(t, false, nil)
+			original := gas.EvmFee{Legacy: assets.GWei(20)}
+
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(24), value), value)
+			bumped, _, err := estimator.BumpFee(ctx, original, gasLimit, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(24), bumped.Legacy)
+
+			ctx = gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(22), value), value)
+			_, _, err = estimator.BumpFee(ctx, original, gasLimit, nil, nil)
+			require.ErrorIs(t, err, gas.ErrInsufficientBalance)
+			assert.True(t, gas.IsBumpErr(err))
+		})
+	})
+
+	t.Run("dynamic", func(t *testing.T) {
+		baseFee := assets.GWei(10)
+
+		t.Run("exact fit", func(t *testing.T) {
+			estimator := newEstimator(t, true, baseFee)
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(80), value), value)
+
+			fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(80), fee.DynamicFeeCap)
+			assert.Equal(t, assets.GWei(2), fee.DynamicTipCap)
+			assert.False(t, fee.LowBalanceAdjusted)
+		})
+
+		t.Run("steps down to fit the balance", func(t *testing.T) {
+			estimator := newEstimator(t, true, baseFee)
+
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(40), value), value)
+			fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(40), fee.DynamicFeeCap)
+			assert.Equal(t, assets.GWei(2), fee.DynamicTipCap)
+			assert.True(t, fee.LowBalanceAdjusted)
+
+			// the tip cap is lowered to what the fee cap leaves over the base fee
+			ctx = gas.WithAvailableBalance(testutils.Context(t), costing(assets.NewWeiI(11_500_000_000), value), value)
+			fee, _, err = estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.NewWeiI(11_500_000_000), fee.DynamicFeeCap)
+			assert.Equal(t, assets.NewWeiI(1_500_000_000), fee.DynamicTipCap)
+			assert.True(t, fee.LowBalanceAdjusted)
+		})
+
+		t.Run("fails below the base fee plus the minimum tip cap", func(t *testing.T) {
+			estimator := newEstimator(t, true, baseFee)
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.NewWeiI(10_500_000_000), value), value)
+
+			_, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			require.ErrorIs(t, err, gas.ErrInsufficientBalance)
+			var eErr *gas.EstimationError
+			require.ErrorAs(t, err, &eErr)
+			assert.Equal(t, costing(assets.GWei(11), assets.NewWeiI(0)), eErr.Price)
+		})
+
+		t.Run("refuses to bump past the balance", func(t *testing.T) {
+			estimator := newEstimator(t, true, baseFee)
+			original := gas.EvmFee{DynamicFeeCap: assets.GWei(50), DynamicTipCap: assets.GWei(2)}
+
+			ctx := gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(60), value), value)
+			bumped, _, err := estimator.BumpFee(ctx, original, gasLimit, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(60), bumped.DynamicFeeCap)
+
+			ctx = gas.WithAvailableBalance(testutils.Context(t), costing(assets.GWei(59), value), value)
+			_, _, err = estimator.BumpFee(ctx, original, gasLimit, nil, nil)
+			require.ErrorIs(t, err, gas.ErrInsufficientBalance)
+		})
+	})
+}
@@ -0,0 +1,116 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+)
+
+const (
+	// baseFeeChangeDenominator bounds the change of the base fee from one
+	// block to the next to 1/8, as per EIP-1559
+	baseFeeChangeDenominator = 8
+	// elasticityMultiplier is the ratio of the gas limit to the gas target of
+	// a block, as per EIP-1559
+	elasticityMultiplier = 2
+)
+
+// blockHeader is the part of a block header needed to predict the base fee
+type blockHeader struct {
+	BaseFeePerGas *rpcPrice      `json:"baseFeePerGas"`
+	GasUsed       hexutil.Uint64 `json:"gasUsed"`
+	GasLimit      hexutil.Uint64 `json:"gasLimit"`
+}
+
+// nextBaseFee returns the base fee of the block following a block with the
+// given base fee, gas used and gas limit, as per EIP-1559
+func nextBaseFee(baseFee *big.Int, gasUsed, gasLimit uint64) *big.Int {
+	target := gasLimit / elasticityMultiplier
+	if target == 0 || gasUsed == target {
+		return new(big.Int).Set(baseFee)
+	}
+	if gasUsed > target {
+		delta := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(gasUsed-target))
+		delta.Div(delta, new(big.Int).SetUint64(target))
+		delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+		if delta.Sign() == 0 {
+			delta.SetInt64(1)
+		}
+		return delta.Add(baseFee, delta)
+	}
+	delta := new(big.Int).Mul(baseFee, new(big.Int).SetUint64(target-gasUsed))
+	delta.Div(delta, new(big.Int).SetUint64(target))
+	delta.Div(delta, big.NewInt(baseFeeChangeDenominator))
+	next := delta.Sub(baseFee, delta)
+	if next.Sign() < 0 {
+		next.SetInt64(0)
+	}
+	return next
+}
+
+// predictBaseFee predicts the base fee
+// EVM.GasEstimator.BlockHistory.BaseFeeLookaheadBlocks after head, for the
+// fee caps to cover. It starts from the base fee of the pending block if the
+// node returns one, and otherwise computes the next base fee from the latest
+// block. Each further block is assumed to be as full as the block the
+// prediction started from.
+func (b *BlockHistoryEstimator) predictBaseFee(ctx context.Context, head *evmtypes.Head) {
+	lookahead := b.config.BlockHistoryEstimatorBaseFeeLookaheadBlocks()
+	if lookahead == 0 {
+		return
+	}
+
+	var predicted *big.Int
+	var header *blockHeader
+	if err := b.ethClient.CallContext(ctx, &header, "eth_getBlockByNumber", "pending", false); err != nil || header == nil || header.BaseFeePerGas == nil {
+		// Many nodes don't support pending blocks, so this isn't worth a warning
+		b.logger.Debugw("No pending block base fee, predicting it from the latest block", "err", err)
+		header = nil
+		if err = b.ethClient.CallContext(ctx, &header, "eth_getBlockByNumber", Int64ToHex(head.Number), false); err != nil || header == nil || header.BaseFeePerGas == nil {
+			if err == nil {
+				err = errors.New("block has no base fee")
+			}
+			b.logger.Debugw("Failed to fetch latest block header, not predicting the base fee", "blockNum", head.Number, "err", err)
+			b.setPredictedBaseFee(nil)
+			return
+		}
+		predicted = nextBaseFee(header.BaseFeePerGas.ToInt(), uint64(header.GasUsed), uint64(header.GasLimit))
+	} else {
+		predicted = new(big.Int).Set(header.BaseFeePerGas.ToInt())
+	}
+	for i := uint16(1); i < lookahead; i++ {
+		predicted = nextBaseFee(predicted, uint64(header.GasUsed), uint64(header.GasLimit))
+	}
+
+	b.logger.Debugw("Predicted base fee", "blockNum", head.Number, "lookaheadBlocks", lookahead, "predictedBaseFeeWei", predicted)
+	b.setPredictedBaseFee(assets.NewWei(predicted))
+}
+
+func (b *BlockHistoryEstimator) setPredictedBaseFee(baseFee *assets.Wei) {
+	b.latestMu.Lock()
+	defer b.latestMu.Unlock()
+	b.predictedBaseFee = baseFee
+}
+
+// getFeeCapBaseFee returns the base fee that fee caps are computed from, and
+// the number of blocks of base fee increases the fee cap must cover on top of
+// it. With a predicted base fee, it is the greater of the latest and the
+// predicted base fee, which already covers the increases until the
+// transaction is included, so it isn't buffered further. Otherwise the latest
+// base fee is buffered by EVM.GasEstimator.BlockHistory.EIP1559FeeCapBufferBlocks.
+func (b *BlockHistoryEstimator) getFeeCapBaseFee() (baseFee *assets.Wei, bufferBlocks int) {
+	b.latestMu.RLock()
+	defer b.latestMu.RUnlock()
+	if b.latest == nil || b.latest.BaseFeePerGas == nil {
+		return nil, 0
+	}
+	if b.predictedBaseFee == nil {
+		return b.latest.BaseFeePerGas, int(b.config.BlockHistoryEstimatorEIP1559FeeCapBufferBlocks())
+	}
+	return assets.WeiMax(b.latest.BaseFeePerGas, b.predictedBaseFee), 0
+}
@@ -0,0 +1,142 @@
+package gas_test
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclimocks "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+)
+
+func TestNextBaseFee(t *testing.T) {
+	t.Parallel()
+
+	base := big.NewInt(1_000_000)
+	// full blocks raise the base fee by 12.5%
+	assert.Equal(t, big.NewInt(1_125_000), gas.NextBaseFee(base, 30_000_000, 30_000_000))
+	// blocks at the target keep it
+	assert.Equal(t, big.NewInt(1_000_000), gas.NextBaseFee(base, 15_000_000, 30_000_000))
+	// empty blocks lower it by 12.5%
+	assert.Equal(t, big.NewInt(875_000), gas.NextBaseFee(base, 0, 30_000_000))
+	// the increase is at least 1 wei
+	assert.Equal(t, big.NewInt(8), gas.NextBaseFee(big.NewInt(7), 15_000_001, 30_000_000))
+}
+
+func TestBlockHistoryEstimator_BaseFeeLookahead(t *testing.T) {
+	t.Parallel()
+
+	const latestBaseFee = 100000
+
+	newEstimator := func(t *testing.T, lookahead, bufferBlocks uint16) (*gas.BlockHistoryEstimator, *evmclimocks.Client) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorBaseFeeLookaheadBlocksF = lookahead
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = bufferBlocks
+		cfg.EvmGasBumpThresholdF = uint64(1)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(1000)
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+		gas.SetTipCap(bhe, assets.NewWeiI(1000))
+		gas.SimulateStart(t, bhe)
+		h := cltest.Head(10)
+		h.BaseFeePerGas = assets.NewWeiI(latestBaseFee)
+		bhe.OnNewLongestChain(testutils.Context(t), h)
+		return bhe, ethClient
+	}
+
+	mockHeader := func(t *testing.T, ethClient *evmclimocks.Client, block interface{}, header string) *mock.Call {
+		return ethClient.On("CallContext", mock.Anything, mock.Anything, "eth_getBlockByNumber", block, false).Return(nil).Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal([]byte(header), args.Get(1)))
+		}).Once()
+	}
+
+	getFeeCap := func(t *testing.T, bhe *gas.BlockHistoryEstimator) *assets.Wei {
+		fee, _, err := bhe.GetDynamicFee(testutils.Context(t), 100000, assets.GWei(1000))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(1000), fee.TipCap)
+		return fee.FeeCap
+	}
+
+	t.Run("a full latest block compounds 12.5% over the lookahead blocks if the node has no pending block", func(t *testing.T) {
+		bhe, ethClient := newEstimator(t, 3, 0)
+		mockHeader(t, ethClient, "pending", `null`)
+		mockHeader(t, ethClient, "0xa", `{"baseFeePerGas":"0x186a0","gasUsed":"0x1c9c380","gasLimit":"0x1c9c380"}`)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		// 100000 * 1.125^3, rounded down at each block, plus the tip cap
+		assert.Equal(t, assets.NewWeiI(142382+1000), getFeeCap(t, bhe))
+	})
+
+	t.Run("an unsupported pending block falls back to the latest block", func(t *testing.T) {
+		bhe, ethClient := newEstimator(t, 1, 0)
+		ethClient.On("CallContext", mock.Anything, mock.Anything, "eth_getBlockByNumber", "pending", false).Return(errors.New("pending block is not available")).Once()
+		mockHeader(t, ethClient, "0xa", `{"baseFeePerGas":"0x186a0","gasUsed":"0x1c9c380","gasLimit":"0x1c9c380"}`)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		assert.Equal(t, assets.NewWeiI(112500+1000), getFeeCap(t, bhe))
+	})
+
+	t.Run("the pending block's base fee is the first lookahead block", func(t *testing.T) {
+		bhe, ethClient := newEstimator(t, 2, 0)
+		// the pending block is full too
+		mockHeader(t, ethClient, "pending", `{"baseFeePerGas":"0x1b7740","gasUsed":"0x1c9c380","gasLimit":"0x1c9c380"}`)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		// 1800000 * 1.125
+		assert.Equal(t, assets.NewWeiI(2025000+1000), getFeeCap(t, bhe))
+	})
+
+	t.Run("a predicted base fee is not buffered again by EIP1559FeeCapBufferBlocks", func(t *testing.T) {
+		bhe, ethClient := newEstimator(t, 1, 2)
+		mockHeader(t, ethClient, "pending", `null`)
+		mockHeader(t, ethClient, "0xa", `{"baseFeePerGas":"0x186a0","gasUsed":"0x1c9c380","gasLimit":"0x1c9c380"}`)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		assert.Equal(t, assets.NewWeiI(112500+1000), getFeeCap(t, bhe))
+
+		// without a prediction the latest base fee is buffered instead
+		mockHeader(t, ethClient, "pending", `null`)
+		mockHeader(t, ethClient, "0xa", `null`)
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		// 100000 * 1.125^2
+		assert.Equal(t, assets.NewWeiI(126562+1000), getFeeCap(t, bhe))
+	})
+
+	t.Run("a predicted base fee below the latest one is ignored", func(t *testing.T) {
+		bhe, ethClient := newEstimator(t, 2, 0)
+		mockHeader(t, ethClient, "pending", `null`)
+		mockHeader(t, ethClient, "0xa", `{"baseFeePerGas":"0x186a0","gasUsed":"0x0","gasLimit":"0x1c9c380"}`)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		assert.Equal(t, assets.NewWeiI(latestBaseFee+1000), getFeeCap(t, bhe))
+	})
+
+	t.Run("the latest base fee is used if no header can be fetched", func(t *testing.T) {
+		bhe, ethClient := newEstimator(t, 2, 0)
+		mockHeader(t, ethClient, "pending", `null`)
+		mockHeader(t, ethClient, "0xa", `null`)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		assert.Equal(t, assets.NewWeiI(latestBaseFee+1000), getFeeCap(t, bhe))
+	})
+
+	t.Run("disabled lookahead makes no calls", func(t *testing.T) {
+		bhe, _ := newEstimator(t, 0, 0)
+
+		gas.PredictBaseFee(testutils.Context(t), bhe, cltest.Head(10))
+		assert.Equal(t, assets.NewWeiI(latestBaseFee+1000), getFeeCap(t, bhe))
+	})
+}
@@ -0,0 +1,95 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+var _ BatchFeeEstimator = (*WrappedEvmEstimator)(nil)
+
+// BatchFeeEstimator is implemented by fee estimators that can estimate the
+// fees of many transactions sent together at once
+type BatchFeeEstimator interface {
+	GetDynamicFees(ctx context.Context, n int, feeLimit uint32, maxFeePrice *assets.Wei) (fees []EvmFee, chainSpecificFeeLimit uint32, err error)
+}
+
+// GetDynamicFees returns the dynamic fees of n transactions sent in the same
+// block window, all derived from a single estimate, where GetFee would
+// estimate each of them, and their chain-specific fee limit. It fails if
+// EVM.GasEstimator.EIP1559DynamicFees is disabled.
+//
+// With EVM.GasEstimator.BatchTipIncrement set, the tip and fee caps of the
+// i-th fee are raised by i increments, so that under mempool policies that
+// order transactions by tip the nonces are still mined in order. The raised
+// fees never exceed the lower of maxFeePrice and EVM.GasEstimator.PriceMax.
+//
+// Each fee then goes through the same steps as the fee of GetFee, i.e. the
+// fee profile selected with WithProfile and then those of finishFee.
+func (e WrappedEvmEstimator) GetDynamicFees(ctx context.Context, n int, feeLimit uint32, maxFeePrice *assets.Wei) ([]EvmFee, uint32, error) {
+	if !e.calls.enter() {
+		return nil, 0, errStopped("WrappedEvmEstimator")
+	}
+	defer e.calls.exit()
+	if !e.EIP1559Enabled {
+		return nil, 0, errors.New("batch fee estimation requires EIP1559 dynamic fees to be enabled")
+	}
+	d := decision{op: "GetDynamicFees", feeLimit: feeLimit, maxFeePrice: maxFeePrice}
+	ctx, profileName, profile, maxFeePrice := e.resolveFeeConfig(ctx, maxFeePrice)
+	dynamicFees, chainSpecificFeeLimit, err := e.getDynamicFees(ctx, profileName, n, feeLimit, maxFeePrice)
+	if err != nil {
+		return nil, 0, err
+	}
+	chainSpecificFeeLimit = e.profileFeeLimit(profile, chainSpecificFeeLimit)
+	fees := make([]EvmFee, n)
+	for i, fee := range dynamicFees {
+		if fees[i], err = e.finishFee(ctx, d, dynamicEvmFee(fee), chainSpecificFeeLimit, maxFeePrice); err != nil {
+			return nil, 0, err
+		}
+	}
+	return fees, chainSpecificFeeLimit, nil
+}
+
+// dynamicEvmFee returns fee as an EvmFee
+func dynamicEvmFee(fee DynamicFee) EvmFee {
+	return EvmFee{DynamicFeeCap: fee.FeeCap, DynamicTipCap: fee.TipCap, GasPerPubdataLimit: fee.GasPerPubdataLimit, InclusionBlocks: fee.InclusionBlocks, FeeCurrency: fee.FeeCurrency}
+}
+
+// getDynamicFees returns n dynamic fees derived from one estimate of the
+// estimator for the given effective max fee price
+func (e WrappedEvmEstimator) getDynamicFees(ctx context.Context, profileName string, n int, feeLimit uint32, maxFeePrice *assets.Wei) ([]DynamicFee, uint32, error) {
+	if n < 1 {
+		return nil, 0, errors.Errorf("at least one fee must be requested, got %d", n)
+	}
+	if err := e.dynamicFeeSupport.err(); err != nil {
+		return nil, 0, err
+	}
+	fee, chainSpecificFeeLimit, err := e.cache.get(ctx, dynamicFeeKey(profileName, inclusionBlocksFromContext(ctx), feeLimit, maxFeePrice), feeLimit, func(ctx context.Context) (EvmFee, uint32, error) {
+		dynamicFee, limit, err := e.EvmEstimator.GetDynamicFee(ctx, feeLimit, maxFeePrice)
+		return EvmFee{DynamicFeeCap: dynamicFee.FeeCap, DynamicTipCap: dynamicFee.TipCap, GasPerPubdataLimit: dynamicFee.GasPerPubdataLimit, InclusionBlocks: dynamicFee.InclusionBlocks, FeeCurrency: dynamicFee.FeeCurrency}, limit, err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	estimated := DynamicFee{FeeCap: fee.DynamicFeeCap, TipCap: fee.DynamicTipCap, GasPerPubdataLimit: fee.GasPerPubdataLimit, InclusionBlocks: fee.InclusionBlocks, FeeCurrency: fee.FeeCurrency}
+	fees := make([]DynamicFee, n)
+	fees[0] = estimated
+	if n == 1 {
+		return fees, chainSpecificFeeLimit, nil
+	}
+	increment := e.cfg.EvmGasBatchTipIncrement()
+	for i := 1; i < n; i++ {
+		fees[i] = estimated
+		if increment == nil || increment.IsZero() {
+			continue
+		}
+		raise := increment.Mul(big.NewInt(int64(i)))
+		fees[i].FeeCap = assets.WeiMin(estimated.FeeCap.Add(raise), maxFeePrice)
+		fees[i].TipCap = assets.WeiMin(estimated.TipCap.Add(raise), fees[i].FeeCap)
+	}
+	return fees, chainSpecificFeeLimit, nil
+}
@@ -0,0 +1,125 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_GetDynamicFees(t *testing.T) {
+	t.Parallel()
+
+	const feeLimit uint32 = 100_000
+	maxGasPrice := assets.GWei(100)
+	estimated := gas.DynamicFee{FeeCap: assets.GWei(40), TipCap: assets.GWei(2)}
+
+	newEstimator := func(t *testing.T, increment *assets.Wei) gas.BatchFeeEstimator {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasBatchTipIncrementF = increment
+		e := mocks.NewEvmEstimator(t)
+		// the fee cache is disabled, so every fee would otherwise be estimated
+		e.On("GetDynamicFee", mock.Anything, feeLimit, mock.Anything).Return(estimated, feeLimit, nil).Once()
+		return gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil).(gas.BatchFeeEstimator)
+	}
+	dynamic := func(fee gas.EvmFee) gas.DynamicFee {
+		return gas.DynamicFee{FeeCap: fee.DynamicFeeCap, TipCap: fee.DynamicTipCap}
+	}
+
+	t.Run("derives all fees from a single estimate", func(t *testing.T) {
+		estimator := newEstimator(t, assets.NewWeiI(0))
+
+		fees, limit, err := estimator.GetDynamicFees(testutils.Context(t), 50, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, feeLimit, limit)
+		require.Len(t, fees, 50)
+		for _, fee := range fees {
+			assert.Equal(t, estimated, dynamic(fee))
+		}
+	})
+
+	t.Run("raises the tip of each fee by the increment", func(t *testing.T) {
+		estimator := newEstimator(t, assets.GWei(1))
+
+		fees, _, err := estimator.GetDynamicFees(testutils.Context(t), 50, feeLimit, assets.GWei(60))
+		require.NoError(t, err)
+		require.Len(t, fees, 50)
+		assert.Equal(t, estimated, dynamic(fees[0]))
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(41), TipCap: assets.GWei(3)}, dynamic(fees[1]))
+		for i := 1; i < len(fees); i++ {
+			assert.True(t, fees[i].DynamicTipCap.Cmp(fees[i-1].DynamicTipCap) >= 0, "tip of fee %d is below the previous one", i)
+			assert.True(t, fees[i].DynamicFeeCap.Cmp(fees[i-1].DynamicFeeCap) >= 0, "fee cap of fee %d is below the previous one", i)
+			assert.True(t, fees[i].DynamicTipCap.Cmp(fees[i].DynamicFeeCap) <= 0, "tip of fee %d is above its fee cap", i)
+			assert.True(t, fees[i].DynamicFeeCap.Cmp(assets.GWei(60)) <= 0, "fee cap of fee %d is above the max fee price", i)
+		}
+		// the fee caps are capped at the max fee price from the 21st fee on
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(60), TipCap: assets.GWei(22)}, dynamic(fees[20]))
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(60), TipCap: assets.GWei(51)}, dynamic(fees[49]))
+	})
+
+	t.Run("GetFee estimates like a batch of one", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetDynamicFee", mock.Anything, feeLimit, maxGasPrice).Return(estimated, feeLimit, nil).Twice()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
+
+		fee, limit, err := estimator.GetFee(testutils.Context(t), nil, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, feeLimit, limit)
+		fees, batchLimit, err := estimator.(gas.BatchFeeEstimator).GetDynamicFees(testutils.Context(t), 1, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, limit, batchLimit)
+		assert.Equal(t, []gas.EvmFee{fee}, fees)
+	})
+
+	t.Run("applies the steps of GetFee to each fee", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasBatchTipIncrementF = assets.NewWeiI(100_000_000)
+		cfg.EvmGasFeeRoundingF = assets.GWei(1)
+		// 0.0042 ether, i.e. 42 gwei for the fee limit
+		cfg.EvmGasMaxTxCostF = assets.NewWeiI(4_200_000_000_000_000)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetDynamicFee", mock.Anything, feeLimit, mock.Anything).Return(estimated, feeLimit, nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil).(gas.BatchFeeEstimator)
+
+		fees, _, err := estimator.GetDynamicFees(testutils.Context(t), 30, feeLimit, nil)
+		require.NoError(t, err)
+		// 40.1 gwei is rounded up
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(41), TipCap: assets.GWei(3)}, dynamic(fees[1]))
+		// 42.1 gwei is lowered to the budget, which is a multiple of the rounding
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(42), TipCap: assets.GWei(5)}, dynamic(fees[21]))
+		assert.Equal(t, assets.GWei(42), fees[29].DynamicFeeCap)
+	})
+
+	t.Run("fails with legacy fees", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), mocks.NewEvmEstimator(t), cfg, nil).(gas.BatchFeeEstimator)
+
+		_, _, err := estimator.GetDynamicFees(testutils.Context(t), 2, feeLimit, nil)
+		assert.EqualError(t, err, "batch fee estimation requires EIP1559 dynamic fees to be enabled")
+	})
+
+	t.Run("fails without fees to estimate", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), mocks.NewEvmEstimator(t), cfg, nil).(gas.BatchFeeEstimator)
+
+		_, _, err := estimator.GetDynamicFees(testutils.Context(t), 0, feeLimit, nil)
+		assert.EqualError(t, err, "at least one fee must be requested, got 0")
+	})
+}
@@ -0,0 +1,65 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// EIP-4844 blob gas pricing parameters
+// See: https://eips.ethereum.org/EIPS/eip-4844#parameters
+const (
+	minBlobBaseFee            = 1
+	blobBaseFeeUpdateFraction = 3338477
+	targetBlobGasPerBlock     = 393216
+)
+
+// BlobFeeEstimator is implemented by estimators that are able to estimate the
+// blob fee cap (max_fee_per_blob_gas) for EIP-4844 blob transactions
+type BlobFeeEstimator interface {
+	// GetBlobFee Calculates the blob fee cap for a blob transaction
+	// The returned value never exceeds EVM.GasEstimator.PriceMaxBlob
+	GetBlobFee(ctx context.Context) (blobFeeCap *assets.Wei, err error)
+}
+
+// CalcExcessBlobGas returns the excess blob gas of the block following a
+// block with the given excess blob gas and blob gas used
+func CalcExcessBlobGas(parentExcessBlobGas, parentBlobGasUsed uint64) uint64 {
+	if parentExcessBlobGas+parentBlobGasUsed < targetBlobGasPerBlock {
+		return 0
+	}
+	return parentExcessBlobGas + parentBlobGasUsed - targetBlobGasPerBlock
+}
+
+// CalcBlobBaseFee returns the blob base fee for a block with the given excess
+// blob gas
+func CalcBlobBaseFee(excessBlobGas uint64) *assets.Wei {
+	return assets.NewWei(fakeExponential(big.NewInt(minBlobBaseFee), new(big.Int).SetUint64(excessBlobGas), big.NewInt(blobBaseFeeUpdateFraction)))
+}
+
+// nextBlobBaseFee returns the blob base fee of the block following the given
+// block, or nil if the block does not carry blob gas fields (pre-Cancun)
+func nextBlobBaseFee(excessBlobGas, blobGasUsed *uint64) *assets.Wei {
+	if excessBlobGas == nil || blobGasUsed == nil {
+		return nil
+	}
+	return CalcBlobBaseFee(CalcExcessBlobGas(*excessBlobGas, *blobGasUsed))
+}
+
+// fakeExponential approximates factor * e ** (numerator / denominator) using
+// Taylor expansion, as specified in EIP-4844
+func fakeExponential(factor, numerator, denominator *big.Int) *big.Int {
+	var (
+		output = new(big.Int)
+		accum  = new(big.Int).Mul(factor, denominator)
+	)
+	for i := 1; accum.Sign() > 0; i++ {
+		output.Add(output, accum)
+
+		accum.Mul(accum, numerator)
+		accum.Div(accum, denominator)
+		accum.Div(accum, big.NewInt(int64(i)))
+	}
+	return output.Div(output, denominator)
+}
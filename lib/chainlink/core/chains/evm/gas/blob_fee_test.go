@@ -0,0 +1,50 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+)
+
+func TestCalcExcessBlobGas(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		name                string
+		parentExcessBlobGas uint64
+		parentBlobGasUsed   uint64
+		expected            uint64
+	}{
+		{"no blobs", 0, 0, 0},
+		{"below target", 0, 131072, 0},
+		{"at target", 0, 393216, 0},
+		{"above target", 0, 786432, 393216},
+		{"drains excess below target", 524288, 0, 131072},
+		{"does not underflow", 131072, 0, 0},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, gas.CalcExcessBlobGas(test.parentExcessBlobGas, test.parentBlobGasUsed))
+		})
+	}
+}
+
+func TestCalcBlobBaseFee(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		excessBlobGas uint64
+		expected      *assets.Wei
+	}{
+		{0, assets.NewWeiI(1)},
+		{2314057, assets.NewWeiI(1)},
+		{3338477, assets.NewWeiI(2)},
+		{33384770, assets.NewWeiI(22026)},
+		{66769540, assets.NewWeiI(485165195)},
+		{100154310, assets.NewWeiI(10686474581524)},
+	} {
+		assert.Equal(t, test.expected, gas.CalcBlobBaseFee(test.excessBlobGas), "excessBlobGas=%d", test.excessBlobGas)
+	}
+}
@@ -30,6 +30,10 @@ import (
 // block the application from starting.
 var MaxStartTime = 10 * time.Second
 
+// BlockHistorySaveInterval is how often the BlockHistoryEstimator persists its
+// block history, if it has a BlockHistoryStore
+var BlockHistorySaveInterval = time.Minute
+
 var (
 	promBlockHistoryEstimatorAllGasPricePercentiles = promauto.NewGaugeVec(prometheus.GaugeOpts{
 		Name: "gas_updater_all_gas_price_percentiles",
@@ -85,43 +89,72 @@ type (
 		config    Config
 		// NOTE: it is assumed that blocks will be kept sorted by
 		// block number ascending
-		blocks    []evmtypes.Block
-		blocksMu  sync.RWMutex
-		size      int64
+		blocks   []evmtypes.Block
+		blocksMu sync.RWMutex
+		// window is the effective history size, see EffectiveHistorySize
+		window    atomic.Int64
 		mb        *utils.Mailbox[*evmtypes.Head]
 		wg        *sync.WaitGroup
 		ctx       context.Context
 		ctxCancel context.CancelFunc
-
-		gasPrice     *assets.Wei
-		tipCap       *assets.Wei
-		priceMu      sync.RWMutex
-		latest       *evmtypes.Head
-		latestMu     sync.RWMutex
-		initialFetch atomic.Bool
-
-		logger logger.SugaredLogger
+		store     BlockHistoryStore
+		// fetchMu serializes fetching the block history and recalculating the
+		// prices from it, between runLoop and bumps with the Rebase strategy
+		fetchMu sync.Mutex
+		// fetched is the last head the history was fetched up to, guarded by
+		// fetchMu
+		fetched *evmtypes.Head
+
+		gasPrice    *assets.Wei
+		tipCap      *assets.Wei
+		blobBaseFee *assets.Wei
+		// rawGasPrice and rawTipCap are the percentile prices of the block
+		// history, before they are bounded by the configured min and max
+		rawGasPrice *assets.Wei
+		rawTipCap   *assets.Wei
+		priceMu     sync.RWMutex
+		latest      *evmtypes.Head
+		// predictedBaseFee is the base fee predicted with
+		// EVM.GasEstimator.BlockHistory.BaseFeeLookaheadBlocks, if enabled
+		predictedBaseFee *assets.Wei
+		latestMu         sync.RWMutex
+		initialFetch     atomic.Bool
+		health           refreshHealth
+		fees             feeFeed
+		// noData makes the estimator fail with ErrNoData instead of using the
+		// default prices when it has none, see NewFallbackEstimator
+		noData bool
+		// minPrice is only set with EVM.GasEstimator.NodeMinPriceSync
+		minPrice *nodeMinPrice
+		// calls are the in-flight estimations, drained by Close
+		calls inFlight
+
+		logger  logger.SugaredLogger
+		metrics *estimatorMetrics
 	}
 )
 
 // NewBlockHistoryEstimator returns a new BlockHistoryEstimator that listens
 // for new heads and updates the base gas price dynamically based on the
-// configured percentile of gas prices in that block
-func NewBlockHistoryEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config, chainID big.Int) EvmEstimator {
+// configured percentile of gas prices in that block.
+// If store is not nil, the block history is persisted to it periodically and
+// on Close, and loaded from it on Start.
+func NewBlockHistoryEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config, chainID big.Int, store BlockHistoryStore) EvmEstimator {
 	ctx, cancel := context.WithCancel(context.Background())
 	b := &BlockHistoryEstimator{
 		ethClient: ethClient,
 		chainID:   chainID,
 		config:    cfg,
 		blocks:    make([]evmtypes.Block, 0),
-		// Must have enough blocks for both estimator and connectivity checker
-		size:      int64(mathutil.Max(cfg.BlockHistoryEstimatorBlockHistorySize(), cfg.BlockHistoryEstimatorCheckInclusionBlocks())),
 		mb:        utils.NewSingleMailbox[*evmtypes.Head](),
 		wg:        new(sync.WaitGroup),
 		ctx:       ctx,
 		ctxCancel: cancel,
+		store:     store,
 		logger:    logger.Sugared(lggr.Named("BlockHistoryEstimator")),
+		metrics:   newEstimatorMetrics(chainID, "BlockHistory"),
 	}
+	b.setWindowSize(initialWindowSize(cfg))
 
 	return b
 }
@@ -140,6 +173,7 @@ func (b *BlockHistoryEstimator) setLatest(head *evmtypes.Head) {
 	// Non-eip1559 blocks don't include base fee
 	if baseFee := head.BaseFeePerGas; baseFee != nil {
 		promBlockHistoryEstimatorCurrentBaseFee.WithLabelValues(b.chainID.String()).Set(float64(baseFee.Int64()))
+		b.metrics.setBaseFee(baseFee)
 	}
 	b.logger.Debugw("Set latest block", "blockNum", head.Number, "blockHash", head.Hash, "baseFee", head.BaseFeePerGas)
 	b.latestMu.Lock()
@@ -176,11 +210,12 @@ func (b *BlockHistoryEstimator) getBlocks() []evmtypes.Block {
 func (b *BlockHistoryEstimator) Start(ctx context.Context) error {
 	return b.StartOnce("BlockHistoryEstimator", func() error {
 		b.logger.Trace("Starting")
+		b.health.start()
 
 		if b.config.BlockHistoryEstimatorCheckInclusionBlocks() > 0 {
 			b.logger.Infof("Inclusion checking enabled, bumping will be prevented on transactions that have been priced above the %d percentile for %d blocks", b.config.BlockHistoryEstimatorCheckInclusionPercentile(), b.config.BlockHistoryEstimatorCheckInclusionBlocks())
 		}
-		if b.config.BlockHistoryEstimatorBlockHistorySize() == 0 {
+		if b.EffectiveHistorySize() == 0 {
 			return errors.New("BlockHistoryEstimatorBlockHistorySize must be set to a value greater than 0")
 		}
 
@@ -194,6 +229,7 @@ func (b *BlockHistoryEstimator) Start(ctx context.Context) error {
 		} else {
 			b.logger.Debugw("Got latest head", "number", latestHead.Number, "blockHash", latestHead.Hash.Hex())
 			b.setLatest(latestHead)
+			b.loadBlocks(fetchCtx, latestHead)
 			b.FetchBlocksAndRecalculate(fetchCtx, latestHead)
 		}
 
@@ -202,6 +238,8 @@ func (b *BlockHistoryEstimator) Start(ctx context.Context) error {
 			return errors.Wrap(ctx.Err(), "failed to start BlockHistoryEstimator due to main context error")
 		}
 
+		b.minPrice.start(fetchCtx)
+
 		b.wg.Add(1)
 		go b.runLoop()
 
@@ -210,22 +248,101 @@ func (b *BlockHistoryEstimator) Start(ctx context.Context) error {
 	})
 }
 
+// Close stops the background loops, then waits up to
+// EVM.GasEstimator.DrainTimeout for in-flight estimations to return before
+// persisting the block history. Estimations made once Close has begun fail
+// with ErrEstimatorStopped.
 func (b *BlockHistoryEstimator) Close() error {
-	return b.StopOnce("BlockHistoryEstimator", func() error {
+	b.calls.stop()
+	if err := b.StopOnce("BlockHistoryEstimator", func() error {
 		b.ctxCancel()
 		b.wg.Wait()
 		return nil
+	}); err != nil {
+		return err
+	}
+	// in-flight estimations are drained after StopOnce, as they would block
+	// on it in IfStarted
+	err := b.calls.drain(b.config.EvmGasDrainTimeout())
+	b.minPrice.close()
+	b.fees.close()
+	ctx, cancel := context.WithTimeout(context.Background(), MaxStartTime)
+	defer cancel()
+	b.saveBlocks(ctx)
+	return err
+}
+
+// loadBlocks loads the persisted block history, discarding blocks that are
+// outside of the history of head, and recalculates the gas price from it so
+// that the estimator can estimate before it has fetched any blocks
+func (b *BlockHistoryEstimator) loadBlocks(ctx context.Context, head *evmtypes.Head) {
+	if b.store == nil {
+		return
+	}
+	persisted, err := b.store.LoadBlocks(ctx, &b.chainID)
+	if err != nil {
+		b.logger.Warnw("Failed to load persisted block history", "err", err)
+		return
+	}
+	blocks := make([]evmtypes.Block, 0, len(persisted))
+	for _, block := range persisted {
+		if block.Number > head.Number-b.historySize() && block.Number <= head.Number {
+			blocks = append(blocks, block)
+		}
+	}
+	b.logger.Debugw(fmt.Sprintf("Loaded %d persisted blocks (%d stale)", len(blocks), len(persisted)-len(blocks)), "headNum", head.Number)
+	if len(blocks) == 0 {
+		return
+	}
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Number < blocks[j].Number
 	})
+	b.blocksMu.Lock()
+	b.blocks = blocks
+	b.blocksMu.Unlock()
+	b.initialFetch.Store(true)
+	b.Recalculate(head)
+}
+
+// saveBlocks persists the block history, if there is a store
+func (b *BlockHistoryEstimator) saveBlocks(ctx context.Context) {
+	if b.store == nil {
+		return
+	}
+	blocks := b.getBlocks()
+	if len(blocks) == 0 {
+		return
+	}
+	if err := b.store.SaveBlocks(ctx, &b.chainID, blocks); err != nil {
+		b.logger.Warnw("Failed to persist block history", "err", err)
+	}
 }
 
 func (b *BlockHistoryEstimator) Name() string {
 	return b.logger.Name()
 }
+
+// HealthReport reports the estimator unhealthy if the last
+// healthFailureThreshold block fetches failed, the prices were not
+// recalculated within EVM.GasEstimator.PriceStaleThreshold, or the block
+// history is still empty after the startup grace period
 func (b *BlockHistoryEstimator) HealthReport() map[string]error {
-	return map[string]error{b.Name(): b.StartStopOnce.Healthy()}
+	err := b.StartStopOnce.Healthy()
+	if err == nil {
+		err = b.health.check(b.config.EvmGasPriceStaleThreshold())
+	}
+	if err == nil && b.health.pastGracePeriod() && len(b.getBlocks()) == 0 {
+		err = errors.Errorf("block history is still empty %s after starting", healthStartupGracePeriod)
+	}
+	return map[string]error{b.Name(): err}
 }
 
 func (b *BlockHistoryEstimator) GetLegacyGas(_ context.Context, _ []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, _ ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &b.chainID, "BlockHistory") }()
+	if !b.calls.enter() {
+		return nil, 0, errStopped("BlockHistoryEstimator")
+	}
+	defer b.calls.exit()
 	ok := b.IfStarted(func() {
 		gasPrice = b.getGasPrice()
 	})
@@ -233,17 +350,36 @@ func (b *BlockHistoryEstimator) GetLegacyGas(_ context.Context, _ []byte, gasLim
 		return nil, 0, errors.New("BlockHistoryEstimator is not started; cannot estimate gas")
 	}
 	if gasPrice == nil {
+		if b.noData {
+			return nil, 0, &EstimationError{Reason: ErrNoData, Err: errors.New("BlockHistoryEstimator has no gas price to estimate from, the block history is empty or has no suitable transactions")}
+		}
 		if !b.initialFetch.Load() {
-			return nil, 0, errors.New("BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+			return nil, 0, &EstimationError{Reason: ErrStalePrice, Err: errors.New("BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")}
 		}
 		b.logger.Warnw("Failed to estimate gas price. This is likely because there aren't any valid transactions to estimate from."+
 			"Using EvmGasPriceDefault as fallback.", "blocks", b.getBlockHistoryNumbers())
 		gasPrice = b.config.EvmGasPriceDefault()
 	}
+	gasPrice = b.minPrice.apply(gasPrice, getMaxGasPrice(maxGasPriceWei, b.config.EvmMaxGasPriceWei()))
+	estimatedGasPrice := gasPrice
 	gasPrice, chainSpecificGasLimit = capGasPrice(gasPrice, maxGasPriceWei, b.config.EvmMaxGasPriceWei(), gasLimit, b.config.EvmGasLimitMultiplier())
+	b.metrics.recordCap(estimatedGasPrice, gasPrice)
 	return
 }
 
+// useNodeMinPrice floors the legacy gas price at the minimum gas price of the
+// node, see EVM.GasEstimator.NodeMinPriceSync. It must be called before Start.
+func (b *BlockHistoryEstimator) useNodeMinPrice(floor *nodeMinPrice) {
+	b.minPrice = floor
+}
+
+// reportNoData makes the estimator fail with ErrNoData when it has no prices,
+// instead of using EVM.GasEstimator.PriceDefault and TipCapDefault. It must be
+// called before Start.
+func (b *BlockHistoryEstimator) reportNoData() {
+	b.noData = true
+}
+
 func (b *BlockHistoryEstimator) getGasPrice() *assets.Wei {
 	b.priceMu.RLock()
 	defer b.priceMu.RUnlock()
@@ -257,13 +393,30 @@ func (b *BlockHistoryEstimator) getBlockHistoryNumbers() (numsInHistory []int64)
 	return
 }
 
+// decisionInputs returns the block range of the history and its raw
+// percentile prices for the decision log
+func (b *BlockHistoryEstimator) decisionInputs() []interface{} {
+	var blockRange string
+	if blocks := b.getBlocks(); len(blocks) > 0 {
+		blockRange = fmt.Sprintf("%d-%d", blocks[0].Number, blocks[len(blocks)-1].Number)
+	}
+	b.priceMu.RLock()
+	defer b.priceMu.RUnlock()
+	return []interface{}{"blockRange", blockRange, "rawGasPrice", b.rawGasPrice, "rawTipCap", b.rawTipCap, "latestBaseFee", b.getCurrentBaseFee()}
+}
+
 func (b *BlockHistoryEstimator) getTipCap() *assets.Wei {
 	b.priceMu.RLock()
 	defer b.priceMu.RUnlock()
 	return b.tipCap
 }
 
-func (b *BlockHistoryEstimator) BumpLegacyGas(_ context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, attempts []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+func (b *BlockHistoryEstimator) BumpLegacyGas(ctx context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, attempts []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &b.chainID, "BlockHistory") }()
+	if !b.calls.enter() {
+		return nil, 0, errStopped("BlockHistoryEstimator")
+	}
+	defer b.calls.exit()
 	if b.config.BlockHistoryEstimatorCheckInclusionBlocks() > 0 {
 		if err = b.checkConnectivity(attempts); err != nil {
 			if errors.Is(err, ErrConnectivity) {
@@ -274,7 +427,39 @@ func (b *BlockHistoryEstimator) BumpLegacyGas(_ context.Context, originalGasPric
 			return nil, 0, err
 		}
 	}
-	return BumpLegacyGasPriceOnly(b.config, b.logger, b.getGasPrice(), originalGasPrice, gasLimit, maxGasPriceWei)
+	b.rebase(ctx)
+	bumpedGasPrice, chainSpecificGasLimit, err = BumpLegacyGasPriceOnly(b.config, b.logger, b.getGasPrice(), originalGasPrice, gasLimit, maxGasPriceWei)
+	b.metrics.recordLegacyBump(err)
+	return
+}
+
+// rebase refreshes the prices before a bump with the Rebase strategy, which
+// re-prices the transaction at the current market price. If runLoop has not
+// fetched the history up to the latest head yet, it is fetched and
+// recalculated here, rather than using the prices of the last head runLoop
+// processed. If the blocks can't be fetched, the prices are recalculated from
+// the history there is.
+func (b *BlockHistoryEstimator) rebase(ctx context.Context) {
+	if b.config.EvmGasBumpStrategy() != BumpStrategyRebase {
+		return
+	}
+	b.latestMu.RLock()
+	head := b.latest
+	b.latestMu.RUnlock()
+	if head == nil {
+		return
+	}
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+	if b.fetchedUpTo(head) {
+		return
+	}
+	if err := b.FetchBlocks(ctx, head); err != nil {
+		b.logger.Warnw("Error fetching blocks to rebase the bump", "head", head, "err", err)
+	} else {
+		b.fetched = head
+	}
+	b.Recalculate(head)
 }
 
 // checkConnectivity detects if the transaction is not being included due to
@@ -366,21 +551,40 @@ func (b *BlockHistoryEstimator) checkConnectivity(attempts []EvmPriorAttempt) er
 	return nil
 }
 
-func (b *BlockHistoryEstimator) GetDynamicFee(_ context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+func (b *BlockHistoryEstimator) GetDynamicFee(ctx context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &b.chainID, "BlockHistory") }()
+	if !b.calls.enter() {
+		return fee, 0, errStopped("BlockHistoryEstimator")
+	}
+	defer b.calls.exit()
 	if !b.config.EvmEIP1559DynamicFees() {
 		return fee, 0, errors.New("Can't get dynamic fee, EIP1559 is disabled")
 	}
 
 	var feeCap *assets.Wei
 	var tipCap *assets.Wei
+	var inclusionBlocks uint32
 	ok := b.IfStarted(func() {
 		chainSpecificGasLimit = commonfee.ApplyMultiplier(gasLimit, b.config.EvmGasLimitMultiplier())
+		profileTipCap := b.profileTipCap(ctx)
+		// an inclusion target is more specific than the profile's percentile
+		var inclusionTipCap *assets.Wei
+		if inclusionTipCap, inclusionBlocks = b.inclusionTipCap(ctx); inclusionTipCap != nil {
+			profileTipCap = inclusionTipCap
+		}
 		b.priceMu.RLock()
 		defer b.priceMu.RUnlock()
 		tipCap = b.tipCap
+		if profileTipCap != nil {
+			tipCap = profileTipCap
+		}
 		if tipCap == nil {
+			if b.noData {
+				err = &EstimationError{Reason: ErrNoData, Err: errors.New("BlockHistoryEstimator has no tip cap to estimate from, the block history is empty or has no suitable transactions")}
+				return
+			}
 			if !b.initialFetch.Load() {
-				err = errors.New("BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+				err = &EstimationError{Reason: ErrStalePrice, Err: errors.New("BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")}
 				return
 			}
 			b.logger.Warnw("Failed to estimate gas price. This is likely because there aren't any valid transactions to estimate from."+
@@ -391,12 +595,12 @@ func (b *BlockHistoryEstimator) GetDynamicFee(_ context.Context, gasLimit uint32
 		if b.config.EvmGasBumpThreshold() == 0 {
 			// just use the max gas price if gas bumping is disabled
 			feeCap = maxGasPrice
-		} else if b.getCurrentBaseFee() != nil {
+		} else if baseFee, bufferBlocks := b.getFeeCapBaseFee(); baseFee != nil {
 			// HACK: due to a flaw of how EIP-1559 is implemented we have to
 			// set a much lower FeeCap than the actual maximum we are willing
 			// to pay in order to give ourselves headroom for bumping
 			// See: https://github.com/ethereum/go-ethereum/issues/24284
-			feeCap = calcFeeCap(b.getCurrentBaseFee(), b.config, tipCap, maxGasPrice)
+			feeCap, err = feeCapAfterBlocks(baseFee, bufferBlocks, tipCap, maxGasPrice)
 		} else {
 			// This shouldn't happen on EIP-1559 blocks, since if the tip cap
 			// is set, Start must have succeeded and we would expect an initial
@@ -411,34 +615,275 @@ func (b *BlockHistoryEstimator) GetDynamicFee(_ context.Context, gasLimit uint32
 	if err != nil {
 		return fee, 0, err
 	}
-	fee.FeeCap = feeCap
-	fee.TipCap = tipCap
+	fee, err = applyTipCapMin(b.config, DynamicFee{FeeCap: feeCap, TipCap: tipCap}, getMaxGasPrice(maxGasPriceWei, b.config.EvmMaxGasPriceWei()))
+	if err != nil {
+		return fee, 0, err
+	}
+	fee.InclusionBlocks = inclusionBlocks
 	return
 }
 
-func calcFeeCap(latestAvailableBaseFeePerGas *assets.Wei, cfg Config, tipCap *assets.Wei, maxGasPriceWei *assets.Wei) (feeCap *assets.Wei) {
-	const maxBaseFeeIncreasePerBlock float64 = 1.125
+// profileTipCap returns the tip cap at the TipCapPercentile of the fee profile
+// in ctx, calculated from the same block history as the estimator's tip cap.
+// It returns nil if there is no such profile or no suitable transactions, in
+// which case the estimator's tip cap is used.
+func (b *BlockHistoryEstimator) profileTipCap(ctx context.Context) *assets.Wei {
+	percentile := feeProfileFromContext(ctx).TipCapPercentile
+	if percentile == nil {
+		return nil
+	}
+	tipCap, err := b.tipCapAtPercentile(*percentile)
+	if err != nil {
+		b.logger.Debugw("Cannot calculate tip cap of fee profile, using the estimated tip cap", "percentile", *percentile, "err", err)
+		return nil
+	}
+	return tipCap
+}
+
+// inclusionTipCap returns the tip cap for the inclusion target of
+// WithInclusionBlocks in ctx, at the percentile that
+// EVM.GasEstimator.BlockHistory.InclusionPercentiles maps it to, along with
+// the target. It returns nil and 0 if there is no target or no suitable
+// transactions, in which case the estimator's tip cap is used.
+func (b *BlockHistoryEstimator) inclusionTipCap(ctx context.Context) (*assets.Wei, uint32) {
+	n := inclusionBlocksFromContext(ctx)
+	if n == 0 {
+		return nil, 0
+	}
+	curve, err := parseInclusionPercentiles(b.config.BlockHistoryEstimatorInclusionPercentiles())
+	if err != nil || len(curve) == 0 {
+		b.logger.Warnw("Invalid InclusionPercentiles, ignoring the inclusion target", "inclusionBlocks", n, "err", err)
+		return nil, 0
+	}
+	percentile := percentileForInclusion(curve, n)
+	tipCap, err := b.tipCapAtPercentile(percentile)
+	if err != nil {
+		b.logger.Debugw("Cannot calculate tip cap for the inclusion target, using the estimated tip cap", "inclusionBlocks", n, "percentile", percentile, "err", err)
+		return nil, 0
+	}
+	return tipCap, n
+}
 
-	bufferBlocks := int(cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocks())
+// tipCapAtPercentile returns the tip cap at percentile of the same block
+// history as the estimator's tip cap, within the configured bounds
+func (b *BlockHistoryEstimator) tipCapAtPercentile(percentile uint16) (*assets.Wei, error) {
+	blockHistory := b.getBlocks()
+	l := mathutil.Min(len(blockHistory), int(b.EffectiveHistorySize()))
+	_, tipCap, err := b.calculatePercentilePrices(blockHistory[:l], int(percentile), true, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if max := b.config.EvmMaxGasPriceWei(); tipCap.Cmp(max) > 0 {
+		return max, nil
+	}
+	return assets.WeiMax(tipCap, b.config.EvmGasTipCapMinimum()), nil
+}
 
-	baseFee := new(big.Float)
-	baseFee.SetInt(latestAvailableBaseFeePerGas.ToInt())
-	// Find out the worst case base fee before we should bump
-	multiplier := big.NewFloat(maxBaseFeeIncreasePerBlock)
-	for i := 0; i < bufferBlocks; i++ {
-		baseFee.Mul(baseFee, multiplier)
+// feeCapConfig is the subset of Config needed to compute a fee cap from the base fee
+type feeCapConfig interface {
+	BlockHistoryEstimatorEIP1559FeeCapBufferBlocks() uint16
+}
+
+// calcFeeCap returns the worst case base fee after
+// EVM.GasEstimator.BlockHistory.EIP1559FeeCapBufferBlocks plus the tip cap,
+// capped at the max gas price
+func calcFeeCap(latestAvailableBaseFeePerGas *assets.Wei, cfg feeCapConfig, tipCap *assets.Wei, maxGasPriceWei *assets.Wei) (feeCap *assets.Wei, err error) {
+	return feeCapAfterBlocks(latestAvailableBaseFeePerGas, int(cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocks()), tipCap, maxGasPriceWei)
+}
+
+// feeCapAfterBlocks returns the worst case base fee after bufferBlocks plus
+// the tip cap, capped at the max gas price. A nil max leaves the fee cap
+// uncapped.
+func feeCapAfterBlocks(baseFee *assets.Wei, bufferBlocks int, tipCap *assets.Wei, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
+	buffered, err := worstCaseBaseFee(baseFee, bufferBlocks)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute fee cap")
 	}
+	return addCapped(buffered, tipCap, maxGasPriceWei)
+}
 
-	baseFeeInt, _ := baseFee.Int(nil)
-	feeCap = assets.NewWei(baseFeeInt.Add(baseFeeInt, tipCap.ToInt()))
+// multipliedFeeCap returns the base fee multiplied by
+// EVM.GasEstimator.BaseFeeBufferMultiplier plus the tip cap, capped at the max
+// gas price. A nil max leaves the fee cap uncapped.
+func multipliedFeeCap(baseFee *assets.Wei, multiplier float32, tipCap, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
+	buffered, err := mulMultiplier(baseFee, multiplier)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute fee cap")
+	}
+	return addCapped(buffered, tipCap, maxGasPriceWei)
+}
 
-	if feeCap.Cmp(maxGasPriceWei) > 0 {
-		return maxGasPriceWei
+// feeCapFunc computes the fee cap for a base fee and tip cap, capped at the
+// max gas price
+type feeCapFunc func(baseFee, tipCap, maxGasPriceWei *assets.Wei) (*assets.Wei, error)
+
+// bufferedFeeCap returns the feeCapFunc of calcFeeCap for cfg
+func bufferedFeeCap(cfg feeCapConfig) feeCapFunc {
+	return func(baseFee, tipCap, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
+		return calcFeeCap(baseFee, cfg, tipCap, maxGasPriceWei)
 	}
-	return feeCap
 }
 
-func (b *BlockHistoryEstimator) BumpDynamicFee(_ context.Context, originalFee DynamicFee, originalGasLimit uint32, maxGasPriceWei *assets.Wei, attempts []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+// worstCaseBaseFee returns the base fee after it has increased by the maximum
+// allowed amount for bufferBlocks consecutive blocks. Both the EIP-1559 base
+// fee and the EIP-4844 blob base fee can increase by at most 12.5%, or 9/8,
+// per block.
+func worstCaseBaseFee(baseFeePerGas *assets.Wei, bufferBlocks int) (*assets.Wei, error) {
+	n := big.NewInt(int64(bufferBlocks))
+	return mulDiv(baseFeePerGas, new(big.Int).Exp(big.NewInt(9), n, nil), new(big.Int).Exp(big.NewInt(8), n, nil), roundDown)
+}
+
+var _ FeeExplainer = (*BlockHistoryEstimator)(nil)
+
+// ExplainFee explains the fee that GetLegacyGas or GetDynamicFee would
+// currently return. The raw percentile price is recomputed from the current
+// block history, which the current price was calculated from. The tip cap of
+// the fee profile or inclusion target in ctx is recorded as a clamp.
+func (b *BlockHistoryEstimator) ExplainFee(ctx context.Context, _ []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, dynamic bool) (ex FeeExplanation, err error) {
+	defer func() { err = annotateError(err, &b.chainID, "BlockHistory") }()
+	if !b.calls.enter() {
+		return ex, errStopped("BlockHistoryEstimator")
+	}
+	defer b.calls.exit()
+	if dynamic && !b.config.EvmEIP1559DynamicFees() {
+		return ex, errors.New("Can't get dynamic fee, EIP1559 is disabled")
+	}
+	var price *assets.Wei
+	ok := b.IfStarted(func() {
+		if dynamic {
+			price = b.getTipCap()
+		} else {
+			price = b.getGasPrice()
+		}
+	})
+	if !ok {
+		return ex, errors.New("BlockHistoryEstimator is not started; cannot estimate gas")
+	}
+
+	ex.Mode = "BlockHistory"
+	ex.Percentile = b.config.BlockHistoryEstimatorTransactionPercentile()
+	blocks := b.getBlocks()
+	blocks = blocks[:mathutil.Min(len(blocks), int(b.EffectiveHistorySize()))]
+	ex.BlocksSampled = len(blocks)
+	gasPrices, tipCaps := b.getPricesFromBlocks(blocks, dynamic)
+	ex.TransactionsSampled = len(gasPrices)
+	if len(gasPrices) > 0 {
+		sort.Slice(gasPrices, func(i, j int) bool { return gasPrices[i].Cmp(gasPrices[j]) < 0 })
+		ex.GasPrice = percentilePrice(gasPrices, int(ex.Percentile))
+	}
+	if dynamic && len(tipCaps) > 0 {
+		sort.Slice(tipCaps, func(i, j int) bool { return tipCaps[i].Cmp(tipCaps[j]) < 0 })
+		ex.TipCap = percentilePrice(tipCaps, int(ex.Percentile))
+	}
+
+	raw, minClamp := ex.GasPrice, ClampPriceMin
+	// as with GetDynamicFee, the tip cap of the fee profile or inclusion
+	// target replaces the estimator's
+	var override *assets.Wei
+	var overrideClamp string
+	if dynamic {
+		raw, minClamp = ex.TipCap, ClampTipCapMin
+		override, overrideClamp = b.profileTipCap(ctx), ClampFeeProfile
+		if inclusionTipCap, n := b.inclusionTipCap(ctx); inclusionTipCap != nil {
+			override, overrideClamp = inclusionTipCap, ClampInclusionTarget
+			ex.Fee.InclusionBlocks = n
+		}
+	}
+	if price == nil && override != nil {
+		price = override
+	} else if price == nil {
+		if !b.initialFetch.Load() {
+			return ex, &EstimationError{Reason: ErrStalePrice, Err: errors.New("BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")}
+		}
+		ex.UsedDefault = true
+		if dynamic {
+			price = b.config.EvmGasTipCapDefault()
+		} else {
+			price = b.config.EvmGasPriceDefault()
+		}
+	} else if raw != nil {
+		// Recalculate clamps the percentile price to the configured limits
+		if raw.Cmp(price) > 0 {
+			ex.Clamps = append(ex.Clamps, ClampPriceMax)
+		} else if raw.Cmp(price) < 0 {
+			ex.Clamps = append(ex.Clamps, minClamp)
+		}
+	}
+	if override != nil {
+		ex.clampIfChanged(overrideClamp, price, override)
+		price = override
+	}
+
+	if !dynamic {
+		floored := b.minPrice.apply(price, getMaxGasPrice(maxGasPriceWei, b.config.EvmMaxGasPriceWei()))
+		ex.clampIfChanged(ClampNodeMinPrice, price, floored)
+		price = floored
+		ex.Fee.Legacy, ex.ChainSpecificFeeLimit = capGasPrice(price, maxGasPriceWei, b.config.EvmMaxGasPriceWei(), gasLimit, b.config.EvmGasLimitMultiplier())
+		ex.clampIfChanged(ClampMaxGasPrice, price, ex.Fee.Legacy)
+	} else {
+		ex.ChainSpecificFeeLimit = commonfee.ApplyMultiplier(gasLimit, b.config.EvmGasLimitMultiplier())
+		maxGasPrice := getMaxGasPrice(maxGasPriceWei, b.config.EvmMaxGasPriceWei())
+		if b.config.EvmGasBumpThreshold() == 0 {
+			ex.Fee.DynamicFeeCap = maxGasPrice
+		} else if baseFee, bufferBlocks := b.getFeeCapBaseFee(); baseFee != nil {
+			ex.BaseFee = baseFee
+			uncapped, err := feeCapAfterBlocks(baseFee, bufferBlocks, price, nil)
+			if err != nil {
+				return ex, err
+			}
+			ex.Fee.DynamicFeeCap = assets.WeiMin(uncapped, maxGasPrice)
+			ex.clampIfChanged(ClampMaxGasPrice, uncapped, ex.Fee.DynamicFeeCap)
+		} else {
+			return ex, errors.New("BlockHistoryEstimator: no value for latest block base fee; cannot estimate EIP-1559 base fee. Are you trying to run with EIP1559 enabled on a non-EIP1559 chain?")
+		}
+		ex.Fee.DynamicTipCap = price
+	}
+	if ex.ChainSpecificFeeLimit != gasLimit {
+		ex.Clamps = append(ex.Clamps, ClampLimitMultiplier)
+	}
+	return ex, nil
+}
+
+// GetBlobFee returns the blob fee cap for EIP-4844 blob transactions. It is
+// derived from the blob base fee of the next block, computed from the excess
+// blob gas and blob gas used of the latest block in history.
+func (b *BlockHistoryEstimator) GetBlobFee(_ context.Context) (blobFeeCap *assets.Wei, err error) {
+	defer func() { err = annotateError(err, &b.chainID, "BlockHistory") }()
+	if !b.calls.enter() {
+		return nil, errStopped("BlockHistoryEstimator")
+	}
+	defer b.calls.exit()
+	var blobBaseFee *assets.Wei
+	ok := b.IfStarted(func() {
+		b.priceMu.RLock()
+		defer b.priceMu.RUnlock()
+		blobBaseFee = b.blobBaseFee
+	})
+	if !ok {
+		return nil, errors.New("BlockHistoryEstimator is not started; cannot estimate blob fee")
+	}
+	if blobBaseFee == nil {
+		return nil, errors.New("BlockHistoryEstimator: no blob gas data in block history; cannot estimate EIP-4844 blob fee. Are you trying to send blob transactions on a pre-Cancun chain?")
+	}
+	// Give the same headroom as for the fee cap, to avoid having to bump as
+	// soon as the blob base fee goes up
+	blobFeeCap, err = worstCaseBaseFee(blobBaseFee, int(b.config.BlockHistoryEstimatorEIP1559FeeCapBufferBlocks()))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute blob fee cap")
+	}
+	if max := b.config.EvmMaxBlobGasPriceWei(); blobFeeCap.Cmp(max) > 0 {
+		b.logger.Warnw(fmt.Sprintf("Calculated blob fee cap of %s exceeds EVM.GasEstimator.PriceMaxBlob=%[2]s, setting blob fee cap to the maximum allowed value of %[2]s instead", blobFeeCap.String(), max.String()), "blobFeeCapWei", blobFeeCap, "maxBlobFeeCapWei", max)
+		blobFeeCap = max
+	}
+	return blobFeeCap, nil
+}
+
+func (b *BlockHistoryEstimator) BumpDynamicFee(ctx context.Context, originalFee DynamicFee, originalGasLimit uint32, maxGasPriceWei *assets.Wei, attempts []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &b.chainID, "BlockHistory") }()
+	if !b.calls.enter() {
+		return bumped, 0, errStopped("BlockHistoryEstimator")
+	}
+	defer b.calls.exit()
 	if b.config.BlockHistoryEstimatorCheckInclusionBlocks() > 0 {
 		if err = b.checkConnectivity(attempts); err != nil {
 			if errors.Is(err, ErrConnectivity) {
@@ -449,32 +894,70 @@ func (b *BlockHistoryEstimator) BumpDynamicFee(_ context.Context, originalFee Dy
 			return bumped, 0, err
 		}
 	}
-	return BumpDynamicFeeOnly(b.config, b.logger, b.getTipCap(), b.getCurrentBaseFee(), originalFee, originalGasLimit, maxGasPriceWei)
+	b.rebase(ctx)
+	bumped, chainSpecificGasLimit, err = BumpDynamicFeeOnly(b.config, b.logger, b.getTipCap(), b.getCurrentBaseFee(), originalFee, originalGasLimit, maxGasPriceWei)
+	b.metrics.recordDynamicBump(err)
+	return
 }
 
 func (b *BlockHistoryEstimator) runLoop() {
 	defer b.wg.Done()
+	saveTicker := time.NewTicker(utils.WithJitter(BlockHistorySaveInterval))
+	defer saveTicker.Stop()
 	for {
 		select {
 		case <-b.ctx.Done():
 			return
+		case <-saveTicker.C:
+			b.saveBlocks(b.ctx)
 		case <-b.mb.Notify():
 			head, exists := b.mb.Retrieve()
 			if !exists {
 				b.logger.Debug("No head to retrieve")
 				continue
 			}
-			b.FetchBlocksAndRecalculate(b.ctx, head)
+			b.processHead(b.ctx, head)
 		}
 	}
 }
 
 // FetchBlocksAndRecalculate fetches block history leading up to head and recalculates gas price.
 func (b *BlockHistoryEstimator) FetchBlocksAndRecalculate(ctx context.Context, head *evmtypes.Head) {
-	if err := b.FetchBlocks(ctx, head); err != nil {
+	b.predictBaseFee(ctx, head)
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+	b.fetchBlocksAndRecalculate(ctx, head)
+}
+
+// processHead is FetchBlocksAndRecalculate for the heads of runLoop, which
+// skips fetching the history if a bump with the Rebase strategy has already
+// fetched it up to head
+func (b *BlockHistoryEstimator) processHead(ctx context.Context, head *evmtypes.Head) {
+	b.predictBaseFee(ctx, head)
+	b.fetchMu.Lock()
+	defer b.fetchMu.Unlock()
+	if b.fetchedUpTo(head) {
+		return
+	}
+	b.fetchBlocksAndRecalculate(ctx, head)
+}
+
+// fetchedUpTo returns whether the history has been fetched up to head, or
+// past it. A different head of the same height, i.e. a reorg, is not
+// fetched. It must be called with fetchMu held.
+func (b *BlockHistoryEstimator) fetchedUpTo(head *evmtypes.Head) bool {
+	return b.fetched != nil && (b.fetched.Number > head.Number || b.fetched.Number == head.Number && b.fetched.Hash == head.Hash)
+}
+
+// fetchBlocksAndRecalculate must be called with fetchMu held
+func (b *BlockHistoryEstimator) fetchBlocksAndRecalculate(ctx context.Context, head *evmtypes.Head) {
+	err := b.FetchBlocks(ctx, head)
+	b.health.record(err)
+	if err != nil {
 		b.logger.Warnw("Error fetching blocks", "head", head, "err", err)
 		return
 	}
+	b.fetched = head
 	b.initialFetch.Store(true)
 	b.Recalculate(head)
 }
@@ -491,9 +974,11 @@ func (b *BlockHistoryEstimator) Recalculate(head *evmtypes.Head) {
 		return
 	}
 
-	l := mathutil.Min(len(blockHistory), int(b.config.BlockHistoryEstimatorBlockHistorySize()))
+	l := mathutil.Min(len(blockHistory), int(b.EffectiveHistorySize()))
 	blocks := blockHistory[:l]
 
+	b.setBlobBaseFee(blockHistory[len(blockHistory)-1])
+
 	eip1559 := b.config.EvmEIP1559DynamicFees()
 	percentileGasPrice, percentileTipCap, err := b.calculatePercentilePrices(blocks, percentile, eip1559,
 		func(gasPrices []*assets.Wei) {
@@ -534,6 +1019,7 @@ func (b *BlockHistoryEstimator) Recalculate(head *evmtypes.Head) {
 	}
 	b.setPercentileGasPrice(percentileGasPrice)
 	promBlockHistoryEstimatorSetGasPrice.WithLabelValues(fmt.Sprintf("%v%%", percentile), b.chainID.String()).Set(float64(percentileGasPrice.Int64()))
+	b.metrics.setGasPrice(b.getGasPrice())
 
 	if eip1559 {
 		float = new(big.Float).SetInt(percentileTipCap.ToInt())
@@ -546,9 +1032,11 @@ func (b *BlockHistoryEstimator) Recalculate(head *evmtypes.Head) {
 		lggr.Debugw(fmt.Sprintf("Setting new default prices, GasPrice: %v Gwei, TipCap: %v Gwei", gasPriceGwei, tipCapGwei), lggrFields...)
 		b.setPercentileTipCap(percentileTipCap)
 		promBlockHistoryEstimatorSetTipCap.WithLabelValues(fmt.Sprintf("%v%%", percentile), b.chainID.String()).Set(float64(percentileTipCap.Int64()))
+		b.metrics.setTipCap(b.getTipCap())
 	} else {
 		lggr.Debugw(fmt.Sprintf("Setting new default gas price: %v Gwei", gasPriceGwei), lggrFields...)
 	}
+	b.publishFees(head, eip1559)
 }
 
 // FetchBlocks fetches block history leading up to the given head.
@@ -560,7 +1048,7 @@ func (b *BlockHistoryEstimator) FetchBlocks(ctx context.Context, head *evmtypes.
 	// them and often the actual block is not available until later. Fetching
 	// it too early results in an empty block.
 	blockDelay := int64(b.config.BlockHistoryEstimatorBlockDelay())
-	historySize := b.size
+	historySize := b.historySize()
 
 	if historySize <= 0 {
 		return errors.Errorf("BlockHistoryEstimator: history size must be > 0, got: %d", historySize)
@@ -626,6 +1114,7 @@ func (b *BlockHistoryEstimator) FetchBlocks(ctx context.Context, head *evmtypes.
 					"err", err, "blockNum", num, "headNum", head.Number)
 			} else {
 				lggr.Warnw("Failed to fetch block", "err", err, "blockNum", HexToInt64(req.Args[0]), "headNum", head.Number)
+				b.metrics.recordRPCError(err)
 			}
 			continue
 		}
@@ -653,6 +1142,8 @@ func (b *BlockHistoryEstimator) FetchBlocks(ctx context.Context, head *evmtypes.
 			"blockNums", missingBlocks, "headNum", head.Number)
 	}
 
+	b.replaceOrphanedBlocks(ctx, blocks, lggr)
+
 	newBlockHistory := make([]evmtypes.Block, 0)
 
 	for _, block := range blocks {
@@ -662,6 +1153,8 @@ func (b *BlockHistoryEstimator) FetchBlocks(ctx context.Context, head *evmtypes.
 		return newBlockHistory[i].Number < newBlockHistory[j].Number
 	})
 
+	b.adaptWindowSize(newBlockHistory, lggr)
+	historySize = b.historySize()
 	start := len(newBlockHistory) - int(historySize)
 	if start < 0 {
 		lggr.Debugw(fmt.Sprintf("Using fewer blocks than the specified history size: %v/%v", len(newBlockHistory), historySize), "blocksSize", historySize, "headNum", head.Number, "blocksAvailable", len(newBlockHistory))
@@ -691,24 +1184,46 @@ func (b *BlockHistoryEstimator) batchFetch(ctx context.Context, reqs []rpc.Batch
 		b.logger.Tracew(fmt.Sprintf("Batch fetching blocks %v thru %v", HexToInt64(reqs[i].Args[0]), HexToInt64(reqs[j-1].Args[0])))
 
 		err := b.ethClient.BatchCallContext(ctx, reqs[i:j])
-		if errors.Is(err, context.DeadlineExceeded) {
-			// We ran out of time, return what we have
-			b.logger.Warnf("Batch fetching timed out; loaded %d/%d results", i, len(reqs))
+		if isRPCTimeout(err) {
+			// Only this batch timed out, keep the blocks of it that were
+			// decoded before the timeout and carry on with the next one
+			b.metrics.recordRPCError(err)
+			for k := i; k < j; k++ {
+				if !isFetchedBlock(reqs[k]) {
+					reqs[k].Error = errors.Wrap(err, "request failed")
+				}
+			}
+			b.logger.Warnw("Batch fetching timed out, continuing with the next batch", "err", err)
+			continue
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			// We ran out of time, return what we have, including the blocks
+			// of this batch that were decoded before the deadline
+			loaded := i
 			for k := i; k < len(reqs); k++ {
-				if k < j {
+				if k < j && isFetchedBlock(reqs[k]) {
+					loaded++
+				} else if k < j {
 					reqs[k].Error = errors.Wrap(err, "request failed")
 				} else {
 					reqs[k].Error = errors.Wrap(err, "request skipped; previous request exceeded deadline")
 				}
 			}
+			b.logger.Warnw(fmt.Sprintf("Batch fetching timed out; loaded %d/%d results", loaded, len(reqs)), "err", err)
 			return nil
 		} else if err != nil {
-			return errors.Wrap(err, "BlockHistoryEstimator#fetchBlocks error fetching blocks with BatchCallContext")
+			b.metrics.recordRPCError(err)
+			return &EstimationError{Reason: ErrRPCFailure, Err: errors.Wrap(err, "BlockHistoryEstimator#fetchBlocks error fetching blocks with BatchCallContext")}
 		}
 	}
 	return nil
 }
 
+// isFetchedBlock returns true if the client decoded the block of req
+func isFetchedBlock(req rpc.BatchElem) bool {
+	block, is := req.Result.(*evmtypes.Block)
+	return req.Error == nil && is && block != nil && block.Hash != (common.Hash{})
+}
+
 var (
 	ErrNoSuitableTransactions = errors.New("no suitable transactions")
 )
@@ -722,7 +1237,7 @@ func (b *BlockHistoryEstimator) calculatePercentilePrices(blocks []evmtypes.Bloc
 	if f != nil {
 		f(gasPrices)
 	}
-	gasPrice = gasPrices[((len(gasPrices)-1)*percentile)/100]
+	gasPrice = percentilePrice(gasPrices, percentile)
 
 	if !eip1559 {
 		return
@@ -734,23 +1249,30 @@ func (b *BlockHistoryEstimator) calculatePercentilePrices(blocks []evmtypes.Bloc
 	if f2 != nil {
 		f2(tipCaps)
 	}
-	tipCap = tipCaps[((len(tipCaps)-1)*percentile)/100]
+	tipCap = percentilePrice(tipCaps, percentile)
 
 	return
 }
 
+// percentilePrice returns the price at percentile of the sorted, non-empty prices
+func percentilePrice(prices []*assets.Wei, percentile int) *assets.Wei {
+	return prices[((len(prices)-1)*percentile)/100]
+}
+
 func (b *BlockHistoryEstimator) getPricesFromBlocks(blocks []evmtypes.Block, eip1559 bool) (gasPrices, tipCaps []*assets.Wei) {
 	gasPrices = make([]*assets.Wei, 0)
 	tipCaps = make([]*assets.Wei, 0)
+	trimPercentile := b.config.BlockHistoryEstimatorTipCapTrimPercentile()
 	for _, block := range blocks {
 		if err := verifyBlock(block, eip1559); err != nil {
 			b.logger.Warnw(fmt.Sprintf("Block %v is not usable, %s", block.Number, err.Error()), "block", block, "err", err)
 		}
+		var blockGasPrices, blockTipCaps []*assets.Wei
 		for _, tx := range block.Transactions {
 			if b.isUsable(tx, b.config, b.logger) {
 				gp := b.EffectiveGasPrice(block, tx)
 				if gp != nil {
-					gasPrices = append(gasPrices, gp)
+					blockGasPrices = append(blockGasPrices, gp)
 				} else {
 					b.logger.Warnw("Unable to get gas price for tx", "tx", tx, "block", block)
 					continue
@@ -758,7 +1280,7 @@ func (b *BlockHistoryEstimator) getPricesFromBlocks(blocks []evmtypes.Block, eip
 				if eip1559 {
 					tc := b.EffectiveTipCap(block, tx)
 					if tc != nil {
-						tipCaps = append(tipCaps, tc)
+						blockTipCaps = append(blockTipCaps, tc)
 					} else {
 						b.logger.Warnw("Unable to get tip cap for tx", "tx", tx, "block", block)
 						continue
@@ -766,10 +1288,25 @@ func (b *BlockHistoryEstimator) getPricesFromBlocks(blocks []evmtypes.Block, eip
 				}
 			}
 		}
+		// Outliers are trimmed per block, so that a single block full of
+		// extreme prices cannot dominate the rolling window
+		gasPrices = append(gasPrices, trimOutliers(blockGasPrices, trimPercentile)...)
+		tipCaps = append(tipCaps, trimOutliers(blockTipCaps, trimPercentile)...)
 	}
 	return
 }
 
+// trimOutliers sorts prices and drops the lowest and highest trimPercentile
+// percent of them
+func trimOutliers(prices []*assets.Wei, trimPercentile uint16) []*assets.Wei {
+	if trimPercentile == 0 || len(prices) == 0 {
+		return prices
+	}
+	sort.Slice(prices, func(i, j int) bool { return prices[i].Cmp(prices[j]) < 0 })
+	n := len(prices) * int(trimPercentile) / 100
+	return prices[n : len(prices)-n]
+}
+
 func verifyBlock(block evmtypes.Block, eip1559 bool) error {
 	if eip1559 && block.BaseFeePerGas == nil {
 		return errors.New("EIP-1559 mode was enabled, but block was missing baseFeePerGas")
@@ -783,9 +1320,11 @@ func (b *BlockHistoryEstimator) setPercentileTipCap(tipCap *assets.Wei) {
 
 	b.priceMu.Lock()
 	defer b.priceMu.Unlock()
+	b.rawTipCap = tipCap
 	if tipCap.Cmp(max) > 0 {
 		b.logger.Warnw(fmt.Sprintf("Calculated gas tip cap of %s exceeds EVM.GasEstimator.PriceMax=%[2]s, setting gas tip cap to the maximum allowed value of %[2]s instead", tipCap.String(), max.String()), "tipCapWei", tipCap, "minTipCapWei", min, "maxTipCapWei", max)
 		b.tipCap = max
+		b.metrics.maxPriceCapped.Inc()
 	} else if tipCap.Cmp(min) < 0 {
 		b.logger.Warnw(fmt.Sprintf("Calculated gas tip cap of %s falls below EVM.GasEstimator.TipCapMin=%[2]s, setting gas tip cap to the minimum allowed value of %[2]s instead", tipCap.String(), min.String()), "tipCapWei", tipCap, "minTipCapWei", min, "maxTipCapWei", max)
 		b.tipCap = min
@@ -794,15 +1333,32 @@ func (b *BlockHistoryEstimator) setPercentileTipCap(tipCap *assets.Wei) {
 	}
 }
 
+// setBlobBaseFee sets the blob base fee of the block following the given
+// block. It is always recomputed from the latest block rather than bumped
+// relative to the previous value, since the excess blob gas can drop sharply
+// e.g. after a re-org.
+func (b *BlockHistoryEstimator) setBlobBaseFee(latest evmtypes.Block) {
+	blobBaseFee := nextBlobBaseFee(latest.ExcessBlobGas, latest.BlobGasUsed)
+	if blobBaseFee != nil {
+		b.logger.Debugw("Setting new blob base fee", "blobBaseFeeWei", blobBaseFee, "blockNum", latest.Number, "blockHash", latest.Hash, "excessBlobGas", *latest.ExcessBlobGas, "blobGasUsed", *latest.BlobGasUsed)
+	}
+
+	b.priceMu.Lock()
+	defer b.priceMu.Unlock()
+	b.blobBaseFee = blobBaseFee
+}
+
 func (b *BlockHistoryEstimator) setPercentileGasPrice(gasPrice *assets.Wei) {
 	max := b.config.EvmMaxGasPriceWei()
 	min := b.config.EvmMinGasPriceWei()
 
 	b.priceMu.Lock()
 	defer b.priceMu.Unlock()
+	b.rawGasPrice = gasPrice
 	if gasPrice.Cmp(max) > 0 {
 		b.logger.Warnw(fmt.Sprintf("Calculated gas price of %s exceeds EVM.GasEstimator.PriceMax=%[2]s, setting gas price to the maximum allowed value of %[2]s instead", gasPrice.String(), max.String()), "gasPriceWei", gasPrice, "maxGasPriceWei", max)
 		b.gasPrice = max
+		b.metrics.maxPriceCapped.Inc()
 	} else if gasPrice.Cmp(min) < 0 {
 		b.logger.Warnw(fmt.Sprintf("Calculated gas price of %s falls below EVM.Transactions.PriceMin=%[2]s, setting gas price to the minimum allowed value of %[2]s instead", gasPrice.String(), min.String()), "gasPriceWei", gasPrice, "minGasPriceWei", min)
 		b.gasPrice = min
@@ -829,13 +1385,38 @@ func (b *BlockHistoryEstimator) isUsable(tx evmtypes.Transaction, cfg Config, lg
 	return chainSpecificIsUsable(tx, cfg)
 }
 
-func (b *BlockHistoryEstimator) EffectiveGasPrice(block evmtypes.Block, tx evmtypes.Transaction) *assets.Wei {
+// txClass is how the BlockHistoryEstimator prices a type of transaction
+type txClass int
+
+const (
+	// txClassUnknown transactions are ignored
+	txClassUnknown txClass = iota
+	// txClassGasPrice transactions (types 0x0 and 0x1) pay their gas price
+	txClassGasPrice
+	// txClassDynamicFee transactions (types 0x2 and 0x3) pay the base fee
+	// plus their effective tip. Blob transactions (type 0x3) also pay blob
+	// fees, which are priced separately and left out of their gas price.
+	txClassDynamicFee
+)
+
+func classifyTx(tx evmtypes.Transaction) txClass {
 	switch tx.Type {
 	case 0x0, 0x1:
+		return txClassGasPrice
+	case 0x2, 0x3:
+		return txClassDynamicFee
+	default:
+		return txClassUnknown
+	}
+}
+
+func (b *BlockHistoryEstimator) EffectiveGasPrice(block evmtypes.Block, tx evmtypes.Transaction) *assets.Wei {
+	switch classifyTx(tx) {
+	case txClassGasPrice:
 		return tx.GasPrice
-	case 0x2:
+	case txClassDynamicFee:
 		if block.BaseFeePerGas == nil || tx.MaxPriorityFeePerGas == nil || tx.MaxFeePerGas == nil {
-			b.logger.Warnw("Got transaction type 0x2 but one of the required EIP1559 fields was missing, falling back to gasPrice", "block", block, "tx", tx)
+			b.logger.Warnw(fmt.Sprintf("Got transaction type %v but one of the required EIP1559 fields was missing, falling back to gasPrice", tx.Type), "block", block, "tx", tx)
 			return tx.GasPrice
 		}
 		if tx.MaxFeePerGas.Cmp(block.BaseFeePerGas) < 0 {
@@ -854,25 +1435,27 @@ func (b *BlockHistoryEstimator) EffectiveGasPrice(block evmtypes.Block, tx evmty
 		}
 
 		// From: https://github.com/ethereum/EIPs/blob/master/EIPS/eip-1559.md
-		priorityFeePerGas := tx.MaxPriorityFeePerGas
-		maxFeeMinusBaseFee := tx.MaxFeePerGas.Sub(block.BaseFeePerGas)
-		if maxFeeMinusBaseFee.Cmp(priorityFeePerGas) < 0 {
-			priorityFeePerGas = maxFeeMinusBaseFee
-		}
-
-		effectiveGasPrice := priorityFeePerGas.Add(block.BaseFeePerGas)
-		return effectiveGasPrice
+		return b.EffectiveTipCap(block, tx).Add(block.BaseFeePerGas)
 	default:
 		b.logger.Warnw(fmt.Sprintf("Ignoring unknown transaction type %v", tx.Type), "block", block, "tx", tx)
 		return nil
 	}
 }
 
+// EffectiveTipCap returns the tip that the transaction paid on top of the base
+// fee: min(maxPriorityFeePerGas, maxFeePerGas - baseFee) for dynamic fee and
+// blob transactions, and gasPrice - baseFee for the others
 func (b *BlockHistoryEstimator) EffectiveTipCap(block evmtypes.Block, tx evmtypes.Transaction) *assets.Wei {
-	switch tx.Type {
-	case 0x2:
+	switch classifyTx(tx) {
+	case txClassDynamicFee:
+		if tx.MaxPriorityFeePerGas == nil || tx.MaxFeePerGas == nil || block.BaseFeePerGas == nil {
+			return tx.MaxPriorityFeePerGas
+		}
+		if maxFeeMinusBaseFee := tx.MaxFeePerGas.Sub(block.BaseFeePerGas); maxFeeMinusBaseFee.Cmp(tx.MaxPriorityFeePerGas) < 0 {
+			return maxFeeMinusBaseFee
+		}
 		return tx.MaxPriorityFeePerGas
-	case 0x0, 0x1:
+	case txClassGasPrice:
 		if tx.GasPrice == nil {
 			return nil
 		}
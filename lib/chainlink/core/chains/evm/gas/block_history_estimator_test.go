@@ -22,6 +22,8 @@ import (
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
 	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/gastestutils"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/label"
 	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
 	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
 	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
@@ -49,7 +51,7 @@ func newConfigWithEIP1559DynamicFeesDisabled(t *testing.T) *gas.MockConfig {
 }
 
 func newBlockHistoryEstimatorWithChainID(t *testing.T, c evmclient.Client, cfg gas.Config, cid big.Int) gas.EvmEstimator {
-	return gas.NewBlockHistoryEstimator(logger.TestLogger(t), c, cfg, cid)
+	return gas.NewBlockHistoryEstimator(logger.TestLogger(t), c, cfg, cid, nil)
 }
 
 func newBlockHistoryEstimator(t *testing.T, c evmclient.Client, cfg gas.Config) *gas.BlockHistoryEstimator {
@@ -256,6 +258,88 @@ func TestBlockHistoryEstimator_Start(t *testing.T) {
 	})
 }
 
+func TestBlockHistoryEstimator_Store(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+	cfg.BlockHistoryEstimatorBlockHistorySizeF = 3
+	cfg.BlockHistoryEstimatorTransactionPercentileF = 60
+	cfg.EvmGasLimitMultiplierF = float32(1)
+	cfg.EvmMinGasPriceWeiF = assets.NewWeiI(1)
+	cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000)
+
+	h := &evmtypes.Head{Hash: utils.NewHash(), Number: 42}
+	blocks := []evmtypes.Block{
+		// stale, since it is older than the head minus BlockHistorySize
+		{Number: 39, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(1)},
+		{Number: 40, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(10, 20)},
+		{Number: 41, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(30, 40)},
+		{Number: 42, Hash: h.Hash, Transactions: cltest.LegacyTransactionsFromGasPrices(50, 60)},
+	}
+
+	t.Run("estimates from persisted blocks on start without fetching blocks", func(t *testing.T) {
+		store := gas.NewInMemoryBlockHistoryStore()
+		require.NoError(t, store.SaveBlocks(testutils.Context(t), &cltest.FixtureChainID, blocks))
+
+		// only HeadByNumber is expected, so any block fetch fails the test
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		ethClient.On("HeadByNumber", mock.Anything, (*big.Int)(nil)).Return(h, nil).Once()
+		bhe := gas.NewBlockHistoryEstimator(logger.TestLogger(t), ethClient, cfg, cltest.FixtureChainID, store)
+		require.NoError(t, bhe.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, bhe.Close()) })
+
+		gasPrice, _, err := bhe.GetLegacyGas(testutils.Context(t), make([]byte, 0), 100, cfg.EvmMaxGasPriceWeiF)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(40), gasPrice)
+		history := gas.GetRollingBlockHistory(bhe)
+		require.Len(t, history, 3)
+		assert.Equal(t, int64(40), history[0].Number)
+	})
+
+	t.Run("persists the block history on close", func(t *testing.T) {
+		store := gas.NewInMemoryBlockHistoryStore()
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		ethClient.On("HeadByNumber", mock.Anything, (*big.Int)(nil)).Return(h, nil).Once()
+		ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+			return len(b) == 3
+		})).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			for i := range elems {
+				elems[i].Result = &blocks[len(blocks)-1-i]
+			}
+		}).Once()
+		bhe := gas.NewBlockHistoryEstimator(logger.TestLogger(t), ethClient, cfg, cltest.FixtureChainID, store)
+		require.NoError(t, bhe.Start(testutils.Context(t)))
+		require.NoError(t, bhe.Close())
+
+		persisted, err := store.LoadBlocks(testutils.Context(t), &cltest.FixtureChainID)
+		require.NoError(t, err)
+		assert.Equal(t, blocks[1:], persisted)
+	})
+
+	t.Run("starts cold if loading persisted blocks fails", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		ethClient.On("HeadByNumber", mock.Anything, (*big.Int)(nil)).Return(h, nil).Once()
+		ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("kaboom")).Once()
+		bhe := gas.NewBlockHistoryEstimator(logger.TestLogger(t), ethClient, cfg, cltest.FixtureChainID, erroringBlockHistoryStore{})
+		require.NoError(t, bhe.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, bhe.Close()) })
+
+		_, _, err := bhe.GetLegacyGas(testutils.Context(t), make([]byte, 0), 100, cfg.EvmMaxGasPriceWeiF)
+		requireEstimationError(t, err, "BlockHistory", gas.ErrStalePrice, "BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+	})
+}
+
+type erroringBlockHistoryStore struct{}
+
+func (erroringBlockHistoryStore) SaveBlocks(context.Context, *big.Int, []evmtypes.Block) error {
+	return errors.New("kaboom")
+}
+
+func (erroringBlockHistoryStore) LoadBlocks(context.Context, *big.Int) ([]evmtypes.Block, error) {
+	return nil, errors.New("kaboom")
+}
+
 func TestBlockHistoryEstimator_OnNewLongestChain(t *testing.T) {
 	cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
 	bhe := newBlockHistoryEstimator(t, nil, cfg)
@@ -967,6 +1051,46 @@ func TestBlockHistoryEstimator_Recalculate_NoEIP1559(t *testing.T) {
 		price := gas.GetGasPrice(bhe)
 		require.Equal(t, assets.NewWeiI(100), price)
 	})
+
+	t.Run("trims outliers per block before computing the gas price percentile", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(1000)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(98)
+
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+
+		var blocks []evmtypes.Block
+		for i := 0; i < 3; i++ {
+			blocks = append(blocks, evmtypes.Block{
+				Number:       int64(i),
+				Hash:         utils.NewHash(),
+				Transactions: cltest.LegacyTransactionsFromGasPrices(pricesWithOutliers(assets.GWei(1).Int64(), assets.GWei(500).Int64())...),
+			})
+		}
+		gas.SetRollingBlockHistory(bhe, blocks)
+
+		bhe.Recalculate(cltest.Head(2))
+		assert.Equal(t, assets.GWei(500), gas.GetGasPrice(bhe))
+
+		cfg.BlockHistoryEstimatorTipCapTrimPercentileF = 5
+		bhe.Recalculate(cltest.Head(2))
+		price := gas.GetGasPrice(bhe)
+		assert.True(t, price.Cmp(assets.GWei(2)) < 0, "expected gas price within a small factor of the normal fee, got %s", price)
+	})
+}
+
+// pricesWithOutliers returns 95 prices close to normal followed by 5 outliers
+func pricesWithOutliers(normal, outlier int64) (prices []int64) {
+	for i := 0; i < 95; i++ {
+		prices = append(prices, normal+int64(i))
+	}
+	for i := 0; i < 5; i++ {
+		prices = append(prices, outlier)
+	}
+	return
 }
 
 func newBlockWithBaseFee() evmtypes.Block {
@@ -1186,6 +1310,38 @@ func TestBlockHistoryEstimator_Recalculate_EIP1559(t *testing.T) {
 		price := gas.GetTipCap(bhe)
 		require.Equal(t, assets.NewWeiI(0), price)
 	})
+
+	t.Run("trims outliers per block before computing the tip cap percentile", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(1000)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(98)
+
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+
+		var blocks []evmtypes.Block
+		for i := 0; i < 3; i++ {
+			blocks = append(blocks, evmtypes.Block{
+				BaseFeePerGas: assets.GWei(1),
+				Number:        int64(i),
+				Hash:          utils.NewHash(),
+				Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(pricesWithOutliers(assets.GWei(1).Int64(), assets.GWei(500).Int64())...),
+			})
+		}
+		gas.SetRollingBlockHistory(bhe, blocks)
+
+		// without trimming the outliers dominate the percentile
+		bhe.Recalculate(cltest.Head(2))
+		assert.Equal(t, assets.GWei(500), gas.GetTipCap(bhe))
+
+		cfg.BlockHistoryEstimatorTipCapTrimPercentileF = 5
+		bhe.Recalculate(cltest.Head(2))
+		tipCap := gas.GetTipCap(bhe)
+		assert.True(t, tipCap.Cmp(assets.GWei(2)) < 0, "expected tip cap within a small factor of the normal fee, got %s", tipCap)
+	})
 }
 
 func TestBlockHistoryEstimator_EffectiveTipCap(t *testing.T) {
@@ -1214,10 +1370,10 @@ func TestBlockHistoryEstimator_EffectiveTipCap(t *testing.T) {
 		assert.Equal(t, "42 wei", res.String())
 	})
 	t.Run("tx type 2 should calculate gas price", func(t *testing.T) {
-		// 0x2 transaction (should use MaxPriorityFeePerGas)
+		// 0x2 transaction (should use MaxFeePerGas - BaseFeePerGas, which is below MaxPriorityFeePerGas)
 		tx := evmtypes.Transaction{Type: 0x2, MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(250), GasLimit: 42, Hash: utils.NewHash()}
 		res := bhe.EffectiveTipCap(eipblock, tx)
-		assert.Equal(t, "200 wei", res.String())
+		assert.Equal(t, "150 wei", res.String())
 		// 0x2 transaction (should use MaxPriorityFeePerGas, ignoring gas price)
 		tx = evmtypes.Transaction{Type: 0x2, GasPrice: assets.NewWeiI(400), MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(350), GasLimit: 42, Hash: utils.NewHash()}
 		res = bhe.EffectiveTipCap(eipblock, tx)
@@ -1228,8 +1384,16 @@ func TestBlockHistoryEstimator_EffectiveTipCap(t *testing.T) {
 		res := bhe.EffectiveTipCap(eipblock, tx)
 		assert.Nil(t, res)
 	})
+	t.Run("tx type 3 should use the effective tip like type 2", func(t *testing.T) {
+		tx := evmtypes.Transaction{Type: 0x3, GasPrice: assets.NewWeiI(250), MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(250), GasLimit: 42, Hash: utils.NewHash()}
+		res := bhe.EffectiveTipCap(eipblock, tx)
+		assert.Equal(t, "150 wei", res.String())
+		tx = evmtypes.Transaction{Type: 0x3, MaxPriorityFeePerGas: assets.NewWeiI(20), MaxFeePerGas: assets.NewWeiI(10_000), GasLimit: 42, Hash: utils.NewHash()}
+		res = bhe.EffectiveTipCap(eipblock, tx)
+		assert.Equal(t, "20 wei", res.String())
+	})
 	t.Run("unknown type returns nil", func(t *testing.T) {
-		tx := evmtypes.Transaction{Type: 0x3, GasPrice: assets.NewWeiI(55555), MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(250), GasLimit: 42, Hash: utils.NewHash()}
+		tx := evmtypes.Transaction{Type: 0x7e, GasPrice: assets.NewWeiI(55555), MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(250), GasLimit: 42, Hash: utils.NewHash()}
 		res := bhe.EffectiveTipCap(eipblock, tx)
 		assert.Nil(t, res)
 	})
@@ -1285,13 +1449,76 @@ func TestBlockHistoryEstimator_EffectiveGasPrice(t *testing.T) {
 		res := bhe.EffectiveGasPrice(block, tx)
 		assert.Equal(t, "55.555 kwei", res.String())
 	})
+	t.Run("tx type 3 should calculate the execution gas price, leaving out blob fees", func(t *testing.T) {
+		// 0x3 transaction (should calculate to 120, not its MaxFeePerGas)
+		tx := evmtypes.Transaction{Type: 0x3, MaxPriorityFeePerGas: assets.NewWeiI(20), MaxFeePerGas: assets.NewWeiI(10_000), GasLimit: 42, Hash: utils.NewHash()}
+		res := bhe.EffectiveGasPrice(eipblock, tx)
+		assert.Equal(t, "120 wei", res.String())
+	})
 	t.Run("unknown type returns nil", func(t *testing.T) {
-		tx := evmtypes.Transaction{Type: 0x3, GasPrice: assets.NewWeiI(55555), MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(250), GasLimit: 42, Hash: utils.NewHash()}
+		tx := evmtypes.Transaction{Type: 0x7e, GasPrice: assets.NewWeiI(55555), MaxPriorityFeePerGas: assets.NewWeiI(200), MaxFeePerGas: assets.NewWeiI(250), GasLimit: 42, Hash: utils.NewHash()}
 		res := bhe.EffectiveGasPrice(block, tx)
 		assert.Nil(t, res)
 	})
 }
 
+func TestBlockHistoryEstimator_TransactionTypes(t *testing.T) {
+	t.Parallel()
+
+	// A block with a base fee of 100 wei and one transaction of each type.
+	// The effective tips are 30 (legacy), 40 (blob), 50 and
+	// min(80, 160-100) = 60 (dynamic fee), and the effective gas prices are
+	// the base fee plus the tips, regardless of the max fee of the blob
+	// transaction.
+	block := evmtypes.Block{
+		Number:        1,
+		Hash:          utils.NewHash(),
+		BaseFeePerGas: assets.NewWeiI(100),
+		Transactions: []evmtypes.Transaction{
+			// Optimism deposit transaction, which pays no gas price
+			{Type: 0x7e, GasPrice: assets.NewWeiI(0), GasLimit: 1_000_000, Hash: utils.NewHash()},
+			{Type: 0x0, GasPrice: assets.NewWeiI(130), GasLimit: 21_000, Hash: utils.NewHash()},
+			{Type: 0x2, MaxPriorityFeePerGas: assets.NewWeiI(50), MaxFeePerGas: assets.NewWeiI(300), GasLimit: 21_000, Hash: utils.NewHash()},
+			{Type: 0x2, MaxPriorityFeePerGas: assets.NewWeiI(80), MaxFeePerGas: assets.NewWeiI(160), GasLimit: 21_000, Hash: utils.NewHash()},
+			{Type: 0x3, MaxPriorityFeePerGas: assets.NewWeiI(40), MaxFeePerGas: assets.NewWeiI(1000), GasLimit: 21_000, Hash: utils.NewHash()},
+		},
+	}
+	estimate := func(t *testing.T, percentile uint16, excluded ...uint8) (gasPrice, tipCap *assets.Wei) {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = percentile
+		cfg.BlockHistoryEstimatorExcludedTxTypesF = excluded
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(1)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{block})
+		bhe.Recalculate(cltest.Head(1))
+		return gas.GetGasPrice(bhe), gas.GetTipCap(bhe)
+	}
+
+	t.Run("uses the effective tip of dynamic fee and blob transactions", func(t *testing.T) {
+		gasPrice, tipCap := estimate(t, 100, 0x7e)
+		assert.Equal(t, assets.NewWeiI(160), gasPrice)
+		assert.Equal(t, assets.NewWeiI(60), tipCap)
+
+		gasPrice, tipCap = estimate(t, 60, 0x7e)
+		// the second of [130, 140, 150, 160] and [30, 40, 50, 60]
+		assert.Equal(t, assets.NewWeiI(140), gasPrice)
+		assert.Equal(t, assets.NewWeiI(40), tipCap)
+	})
+
+	t.Run("excluded system transactions don't count towards the percentiles", func(t *testing.T) {
+		gasPrice, tipCap := estimate(t, 0, 0x7e)
+		assert.Equal(t, assets.NewWeiI(130), gasPrice)
+		assert.Equal(t, assets.NewWeiI(30), tipCap)
+
+		// e.g. a chain whose system transactions are legacy transactions
+		gasPrice, tipCap = estimate(t, 0, 0x7e, 0x0)
+		assert.Equal(t, assets.NewWeiI(140), gasPrice)
+		assert.Equal(t, assets.NewWeiI(40), tipCap)
+	})
+}
+
 func TestBlockHistoryEstimator_Block_Unmarshal(t *testing.T) {
 	blockJSON := `
 {
@@ -1811,6 +2038,68 @@ func TestBlockHistoryEstimator_GetDynamicFee(t *testing.T) {
 	})
 }
 
+func TestBlockHistoryEstimator_TipCapMin(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+	maxGasPrice := assets.NewWeiI(1000000)
+	cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = uint16(4)
+	cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(35)
+	cfg.EvmGasBumpThresholdF = uint64(1)
+	cfg.EvmGasBumpPercentF = 10
+	cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+	cfg.EvmGasLimitMultiplierF = float32(1)
+	cfg.EvmMaxGasPriceWeiF = maxGasPrice
+	cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+	cfg.EvmGasTipCapMinimumF = assets.NewWeiI(30000)
+	cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+
+	bhe := newBlockHistoryEstimator(t, nil, cfg)
+	gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+		{
+			BaseFeePerGas: assets.NewWeiI(100000),
+			Number:        1,
+			Hash:          utils.NewHash(),
+			Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(5000, 6000, 10000),
+		},
+	})
+	bhe.Recalculate(cltest.Head(1))
+	gas.SimulateStart(t, bhe)
+
+	h := cltest.Head(1)
+	h.BaseFeePerGas = assets.NewWeiI(112500)
+	bhe.OnNewLongestChain(testutils.Context(t), h)
+
+	t.Run("the percentile tip cap of quiet blocks is raised to the floor", func(t *testing.T) {
+		assert.Equal(t, assets.NewWeiI(30000), gas.GetTipCap(bhe))
+
+		fee, _, err := bhe.GetDynamicFee(testutils.Context(t), 100000, maxGasPrice)
+		require.NoError(t, err)
+		// 112500 * 1.125^4 + 30000
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.NewWeiI(210203), TipCap: assets.NewWeiI(30000)}, fee)
+	})
+
+	t.Run("GetDynamicFee raises a tip cap below the floor with the fee cap", func(t *testing.T) {
+		gas.SetTipCap(bhe, assets.NewWeiI(6000))
+		t.Cleanup(func() { gas.SetTipCap(bhe, assets.NewWeiI(30000)) })
+
+		fee, _, err := bhe.GetDynamicFee(testutils.Context(t), 100000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.NewWeiI(210203), TipCap: assets.NewWeiI(30000)}, fee)
+
+		// a floor above the max gas price is an error rather than a lower tip cap
+		_, _, err = bhe.GetDynamicFee(testutils.Context(t), 100000, assets.NewWeiI(20000))
+		requireEstimationError(t, err, "BlockHistory", nil, "EVM.GasEstimator.TipCapMin of 30 kwei is greater than the maximum gas price configured: 20 kwei")
+	})
+
+	t.Run("BumpDynamicFee never goes below the floor", func(t *testing.T) {
+		original := gas.DynamicFee{FeeCap: assets.NewWeiI(186203), TipCap: assets.NewWeiI(6000)}
+		bumped, _, err := bhe.BumpDynamicFee(testutils.Context(t), original, 100000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.NewWeiI(210203), TipCap: assets.NewWeiI(30000)}, bumped)
+	})
+}
+
 var _ txmgrtypes.PriorAttempt[gas.EvmFee, common.Hash] = &MockAttempt{}
 
 type MockAttempt struct {
@@ -1861,12 +2150,126 @@ func (m *MockAttempt) GetTxType() int {
 	return m.TxType
 }
 
+func TestBlockHistoryEstimator_GetBlobFee(t *testing.T) {
+	t.Parallel()
+
+	ptr := func(v uint64) *uint64 { return &v }
+
+	newCfg := func() *gas.MockConfig {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(100000)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmMaxBlobGasPriceWeiF = assets.NewWeiI(100000)
+		return cfg
+	}
+
+	t.Run("returns error if estimator is not started", func(t *testing.T) {
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), newCfg())
+		_, err := bhe.GetBlobFee(testutils.Context(t))
+		assert.EqualError(t, err, "BlockHistoryEstimator is not started; cannot estimate blob fee")
+	})
+
+	t.Run("returns error if latest block has no blob gas fields", func(t *testing.T) {
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), newCfg())
+		gas.SimulateStart(t, bhe)
+
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{{Number: 1, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(10)}})
+		bhe.Recalculate(cltest.Head(1))
+
+		_, err := bhe.GetBlobFee(testutils.Context(t))
+		assert.EqualError(t, err, "BlockHistoryEstimator: no blob gas data in block history; cannot estimate EIP-4844 blob fee. Are you trying to send blob transactions on a pre-Cancun chain?")
+	})
+
+	t.Run("computes blob base fee of next block from latest block, with fee cap buffer", func(t *testing.T) {
+		cfg := newCfg()
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), cfg)
+		gas.SimulateStart(t, bhe)
+
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 1, Hash: utils.NewHash(), ExcessBlobGas: ptr(0), BlobGasUsed: ptr(0), Transactions: cltest.LegacyTransactionsFromGasPrices(10)},
+			// next block excess blob gas = 33384770 + 786432 - 393216 = 33777986
+			{Number: 2, Hash: utils.NewHash(), ExcessBlobGas: ptr(33384770), BlobGasUsed: ptr(786432), Transactions: cltest.LegacyTransactionsFromGasPrices(10)},
+		})
+		bhe.Recalculate(cltest.Head(2))
+
+		blobFee, err := bhe.GetBlobFee(testutils.Context(t))
+		require.NoError(t, err)
+		assert.Equal(t, gas.CalcBlobBaseFee(33777986), blobFee)
+
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = 2
+		blobFee, err = bhe.GetBlobFee(testutils.Context(t))
+		require.NoError(t, err)
+		// 24779 * 1.125 ^ 2
+		assert.Equal(t, assets.NewWeiI(31360), blobFee)
+	})
+
+	t.Run("caps blob fee at PriceMaxBlob", func(t *testing.T) {
+		cfg := newCfg()
+		cfg.EvmMaxBlobGasPriceWeiF = assets.NewWeiI(1000)
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), cfg)
+		gas.SimulateStart(t, bhe)
+
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 1, Hash: utils.NewHash(), ExcessBlobGas: ptr(33384770), BlobGasUsed: ptr(393216), Transactions: cltest.LegacyTransactionsFromGasPrices(10)},
+		})
+		bhe.Recalculate(cltest.Head(1))
+
+		blobFee, err := bhe.GetBlobFee(testutils.Context(t))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(1000), blobFee)
+	})
+
+	t.Run("recomputes blob base fee when excess blob gas drops sharply after a re-org", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newCfg()
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = 3
+		cfg.BlockHistoryEstimatorBatchSizeF = 2
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+		gas.SimulateStart(t, bhe)
+
+		b1 := evmtypes.Block{Number: 1, Hash: utils.NewHash(), ExcessBlobGas: ptr(33384770), BlobGasUsed: ptr(393216), Transactions: cltest.LegacyTransactionsFromGasPrices(10)}
+		b2 := evmtypes.Block{Number: 2, Hash: utils.NewHash(), ParentHash: b1.Hash, ExcessBlobGas: ptr(33384770), BlobGasUsed: ptr(393216), Transactions: cltest.LegacyTransactionsFromGasPrices(10)}
+		b3 := evmtypes.Block{Number: 3, Hash: utils.NewHash(), ParentHash: b2.Hash, ExcessBlobGas: ptr(33384770), BlobGasUsed: ptr(393216), Transactions: cltest.LegacyTransactionsFromGasPrices(10)}
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{b1, b2, b3})
+		bhe.Recalculate(cltest.Head(3))
+
+		blobFee, err := bhe.GetBlobFee(testutils.Context(t))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(22026), blobFee)
+
+		// RE-ORG, the new blocks 2 and 3 have far lower excess blob gas
+		head2 := evmtypes.NewHead(big.NewInt(2), utils.NewHash(), b1.Hash, uint64(time.Now().Unix()), utils.NewBig(&cltest.FixtureChainID))
+		head3 := evmtypes.NewHead(big.NewInt(3), utils.NewHash(), head2.Hash, uint64(time.Now().Unix()), utils.NewBig(&cltest.FixtureChainID))
+		head3.Parent = &head2
+
+		ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+			return len(b) == 2 &&
+				b[0].Method == "eth_getBlockByNumber" && b[0].Args[0] == gas.Int64ToHex(3) &&
+				b[1].Method == "eth_getBlockByNumber" && b[1].Args[0] == gas.Int64ToHex(2)
+		})).Once().Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			elems[1].Result = &evmtypes.Block{Number: 2, Hash: head2.Hash, ParentHash: b1.Hash, ExcessBlobGas: ptr(3338477), BlobGasUsed: ptr(393216), Transactions: cltest.LegacyTransactionsFromGasPrices(10)}
+			elems[0].Result = &evmtypes.Block{Number: 3, Hash: head3.Hash, ParentHash: head2.Hash, ExcessBlobGas: ptr(3338477), BlobGasUsed: ptr(393216), Transactions: cltest.LegacyTransactionsFromGasPrices(10)}
+		})
+
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), &head3)
+
+		require.Len(t, gas.GetRollingBlockHistory(bhe), 3)
+		assert.Equal(t, head3.Hash, gas.GetRollingBlockHistory(bhe)[2].Hash)
+
+		blobFee, err = bhe.GetBlobFee(testutils.Context(t))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(2), blobFee)
+	})
+}
+
 func TestBlockHistoryEstimator_CheckConnectivity(t *testing.T) {
 	cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
 	cfg.BlockHistoryEstimatorCheckInclusionBlocksF = uint16(4)
 	lggr, obs := logger.TestLoggerObserved(t, zapcore.DebugLevel)
 	bhe := gas.BlockHistoryEstimatorFromInterface(
-		gas.NewBlockHistoryEstimator(lggr, nil, cfg, *testutils.NewRandomEVMChainID()),
+		gas.NewBlockHistoryEstimator(lggr, nil, cfg, *testutils.NewRandomEVMChainID(), nil),
 	)
 
 	attempts := []txmgrtypes.PriorAttempt[gas.EvmFee, common.Hash]{
@@ -2183,6 +2586,92 @@ func TestBlockHistoryEstimator_Bumps(t *testing.T) {
 		assert.Contains(t, err.Error(), fmt.Sprintf("transaction %s has gas price of 1 kwei, which is above percentile=10%% (percentile price: 1 wei) for blocks 1 thru 1 (checking 1 blocks)", attempts[0].GetHash()))
 	})
 
+	t.Run("BumpLegacyGas with the Rebase strategy re-prices on the latest head", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorBatchSizeF = uint32(0)
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = uint16(2)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(100)
+		cfg.EvmGasBumpStrategyF = gas.BumpStrategyRebase
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{{
+			Number:       1,
+			Hash:         utils.NewHash(),
+			Transactions: cltest.LegacyTransactionsFromGasPrices(50),
+		}})
+		bhe.Recalculate(cltest.Head(1))
+
+		// the latest head has not been processed yet, so the cached price
+		// is still the one of block 1
+		bhe.OnNewLongestChain(testutils.Context(t), cltest.Head(2))
+		ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+			return len(b) == 1 && b[0].Method == "eth_getBlockByNumber" && b[0].Args[0] == gas.Int64ToHex(2)
+		})).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			elems[0].Result = &evmtypes.Block{
+				Number:       2,
+				Hash:         utils.NewHash(),
+				Transactions: cltest.LegacyTransactionsFromGasPrices(200),
+			}
+		}).Once()
+
+		gasPrice, _, err := bhe.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(40), 100000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(200), gasPrice)
+	})
+
+	t.Run("BumpLegacyGas with the Rebase strategy fetches each head once while the run loop fetches", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorBatchSizeF = uint32(0)
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = uint16(2)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(100)
+		cfg.EvmGasBumpStrategyF = gas.BumpStrategyRebase
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{{
+			Number:       1,
+			Hash:         utils.NewHash(),
+			Transactions: cltest.LegacyTransactionsFromGasPrices(50),
+		}})
+		bhe.Recalculate(cltest.Head(1))
+
+		for n := int64(2); n < 20; n++ {
+			n := n
+			// each head must be fetched once, by whichever of the run loop
+			// and the bump gets to it first
+			ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+				return len(b) == 1 && b[0].Method == "eth_getBlockByNumber" && b[0].Args[0] == gas.Int64ToHex(n)
+			})).Return(nil).Run(func(args mock.Arguments) {
+				elems := args.Get(1).([]rpc.BatchElem)
+				elems[0].Result = &evmtypes.Block{
+					Number:       n,
+					Hash:         utils.NewHash(),
+					Transactions: cltest.LegacyTransactionsFromGasPrices(100 + n),
+				}
+			}).Once()
+
+			head := cltest.Head(n)
+			bhe.OnNewLongestChain(testutils.Context(t), head)
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				gas.ProcessHead(bhe, head)
+			}()
+			gasPrice, _, err := bhe.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(40), 100000, maxGasPrice, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.NewWeiI(100+n), gasPrice)
+			<-done
+		}
+	})
+
 	t.Run("BumpLegacyGas calls BumpLegacyGasPriceOnly with proper current gas price", func(t *testing.T) {
 		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
 		cfg.EvmGasBumpPercentF = 10
@@ -2364,3 +2853,166 @@ func TestBlockHistoryEstimator_Bumps(t *testing.T) {
 		})
 	})
 }
+
+func TestBlockHistoryEstimator_Errors(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.NewWeiI(1000)
+
+	t.Run("not started", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+
+		_, _, err := bhe.GetLegacyGas(testutils.Context(t), make([]byte, 0), 100000, maxGasPrice)
+		e := requireEstimationError(t, err, "BlockHistory", nil, "BlockHistoryEstimator is not started; cannot estimate gas")
+		assert.Equal(t, &cltest.FixtureChainID, e.ChainID)
+	})
+
+	t.Run("first estimation not finished", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SimulateStart(t, bhe)
+
+		_, _, err := bhe.GetLegacyGas(testutils.Context(t), make([]byte, 0), 100000, maxGasPrice)
+		requireEstimationError(t, err, "BlockHistory", gas.ErrStalePrice, "BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+
+		_, _, err = bhe.GetDynamicFee(testutils.Context(t), 100000, maxGasPrice)
+		requireEstimationError(t, err, "BlockHistory", gas.ErrStalePrice, "BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+	})
+
+	t.Run("error fetching blocks", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = 3
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+
+		ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("something exploded"))
+
+		err := bhe.FetchBlocks(testutils.Context(t), cltest.Head(42))
+		assert.ErrorIs(t, err, gas.ErrRPCFailure)
+		assert.EqualError(t, err, "BlockHistoryEstimator#fetchBlocks error fetching blocks with BatchCallContext: something exploded")
+	})
+
+	t.Run("bump exceeds limit", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(150)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+
+		_, _, err := bhe.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(900), 100000, maxGasPrice, nil)
+		e := requireEstimationError(t, err, "BlockHistory", gas.ErrBumpLimitExceeded,
+			fmt.Sprintf("bumped gas price of 1.05 kwei would exceed configured max gas price of 1 kwei (original price was 900 wei). %s: gas bump exceeds limit", label.NodeConnectivityProblemWarning))
+		assert.Equal(t, assets.NewWeiI(1050), e.Price)
+		assert.Equal(t, maxGasPrice, e.Limit)
+		assert.Equal(t, &cltest.FixtureChainID, e.ChainID)
+	})
+
+	t.Run("connectivity", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorCheckInclusionBlocksF = 1
+		cfg.BlockHistoryEstimatorCheckInclusionPercentileF = 10
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+
+		b1 := evmtypes.Block{
+			Number:       1,
+			Hash:         utils.NewHash(),
+			Transactions: cltest.LegacyTransactionsFromGasPrices(1),
+		}
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{b1})
+		bhe.OnNewLongestChain(testutils.Context(t), cltest.Head(1))
+
+		attempts := []txmgrtypes.PriorAttempt[gas.EvmFee, common.Hash]{
+			&MockAttempt{TxType: 0x0, Hash: NewEvmHash(), GasPrice: assets.NewWeiI(1000), BroadcastBeforeBlockNum: testutils.Ptr(int64(0))},
+		}
+
+		_, _, err := bhe.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), 100000, maxGasPrice, gas.MakeEvmPriorAttempts(attempts))
+		requireEstimationError(t, err, "BlockHistory", gas.ErrConnectivity,
+			fmt.Sprintf("transaction %s has gas price of 1 kwei, which is above percentile=10%% (percentile price: 1 wei) for blocks 1 thru 1 (checking 1 blocks): transaction propagation issue: transactions are not being mined", attempts[0].GetHash()))
+	})
+}
+
+func TestBlockHistoryEstimator_PercentileProperties(t *testing.T) {
+	t.Parallel()
+
+	specs := map[string]gastestutils.BlockSpec{
+		"constant tips, flat base fee": {
+			Blocks: 20, TxsPerBlock: 50,
+			Tips:    gastestutils.TipDistribution{Kind: gastestutils.TipsConstant, Value: assets.GWei(2)},
+			BaseFee: gastestutils.BaseFeeTrajectory{Kind: gastestutils.BaseFeeFlat, Start: assets.GWei(30)},
+		},
+		"normal tips, rising base fee, some zero tips": {
+			Blocks: 20, TxsPerBlock: 50,
+			Tips:            gastestutils.TipDistribution{Kind: gastestutils.TipsNormal, Value: assets.GWei(2), StdDev: assets.GWei(1)},
+			BaseFee:         gastestutils.BaseFeeTrajectory{Kind: gastestutils.BaseFeeRising, Start: assets.GWei(10)},
+			ZeroTipFraction: 0.2,
+		},
+		"pareto tips, spiky base fee, mostly legacy": {
+			Blocks: 20, TxsPerBlock: 50,
+			Tips:           gastestutils.TipDistribution{Kind: gastestutils.TipsPareto, Value: assets.GWei(1)},
+			BaseFee:        gastestutils.BaseFeeTrajectory{Kind: gastestutils.BaseFeeSpiky, Start: assets.GWei(20), SpikeEvery: 5},
+			LegacyFraction: 0.7,
+		},
+		"pareto tips, no base fee": {
+			Blocks: 20, TxsPerBlock: 50,
+			Tips: gastestutils.TipDistribution{Kind: gastestutils.TipsPareto, Value: assets.GWei(5), Alpha: 3},
+		},
+	}
+	newConfig := func(eip1559 bool, percentile uint16) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = eip1559
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = 20
+		cfg.BlockHistoryEstimatorTransactionPercentileF = percentile
+		cfg.EvmMaxGasPriceWeiF = assets.Ether(1)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		return cfg
+	}
+	// prices returns the gas price and, with EIP-1559, the tip cap estimated
+	// from blocks at percentile
+	prices := func(t *testing.T, blocks []evmtypes.Block, percentile uint16) (gasPrice, tipCap *assets.Wei) {
+		eip1559 := blocks[0].BaseFeePerGas != nil
+		bhe := gastestutils.FeedBlockHistory(t, newConfig(eip1559, percentile), blocks)
+		return gas.GetGasPrice(bhe), gas.GetTipCap(bhe)
+	}
+
+	for name, spec := range specs {
+		spec := spec
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for seed := int64(1); seed <= 3; seed++ {
+				seed := seed
+				t.Run(fmt.Sprintf("seed %d", seed), func(t *testing.T) {
+					g := gastestutils.NewBlockGenerator(seed)
+					blocks := g.Generate(spec)
+
+					t.Run("a higher percentile never estimates a lower price", func(t *testing.T) {
+						var lastGasPrice, lastTipCap *assets.Wei
+						for percentile := uint16(0); percentile <= 100; percentile += 10 {
+							gasPrice, tipCap := prices(t, blocks, percentile)
+							require.NotNil(t, gasPrice)
+							if lastGasPrice != nil {
+								assert.GreaterOrEqual(t, gasPrice.Cmp(lastGasPrice), 0, "gas price at percentile %d is below the previous percentile", percentile)
+							}
+							if tipCap != nil && lastTipCap != nil {
+								assert.GreaterOrEqual(t, tipCap.Cmp(lastTipCap), 0, "tip cap at percentile %d is below the previous percentile", percentile)
+							}
+							lastGasPrice, lastTipCap = gasPrice, tipCap
+						}
+					})
+
+					t.Run("the order of transactions within a block doesn't matter", func(t *testing.T) {
+						gasPrice, tipCap := prices(t, blocks, 60)
+						for i := 0; i < 3; i++ {
+							shuffledGasPrice, shuffledTipCap := prices(t, g.ShuffleTransactions(blocks), 60)
+							assert.Equal(t, gasPrice, shuffledGasPrice)
+							assert.Equal(t, tipCap, shuffledTipCap)
+						}
+					})
+				})
+			}
+		})
+	}
+}
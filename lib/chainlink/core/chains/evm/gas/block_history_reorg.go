@@ -0,0 +1,120 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+var promBlockHistoryEstimatorReorgDepth = promauto.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "block_history_estimator_reorg_depth",
+	Help:    "Number of orphaned blocks replaced in the block history by each detected re-org",
+	Buckets: []float64{1, 2, 4, 8, 16, 32, 64, 128},
+},
+	[]string{"evmChainID"},
+)
+
+// replaceOrphanedBlocks detects re-orgs in blocks, the block history by
+// number, from blocks whose parent hash doesn't match the hash of the block
+// before them. The newer block is taken to be canonical, so the divergent
+// range below it is fetched again, up to
+// EVM.GasEstimator.BlockHistory.MaxReorgDepth blocks deep, and replaces the
+// orphaned blocks. Blocks that can't be replaced are dropped along with all
+// older blocks, so that orphaned transactions never influence the prices; the
+// history is filled again on the next head.
+func (b *BlockHistoryEstimator) replaceOrphanedBlocks(ctx context.Context, blocks map[int64]evmtypes.Block, lggr logger.Logger) {
+	maxDepth := int64(b.config.BlockHistoryEstimatorMaxReorgDepth())
+	if maxDepth == 0 {
+		return
+	}
+
+	nums := make([]int64, 0, len(blocks))
+	for num := range blocks {
+		nums = append(nums, num)
+	}
+	sort.Slice(nums, func(i, j int) bool { return nums[i] > nums[j] })
+
+	for _, num := range nums {
+		child, exists := blocks[num]
+		if !exists {
+			// Dropped by a re-org detected further up
+			continue
+		}
+		parent, exists := blocks[num-1]
+		if !exists || child.ParentHash == (common.Hash{}) || child.ParentHash == parent.Hash {
+			continue
+		}
+		b.replaceOrphanedRange(ctx, blocks, child, maxDepth, lggr)
+	}
+}
+
+// replaceOrphanedRange replaces the orphaned blocks below child, the first
+// block of the canonical chain
+func (b *BlockHistoryEstimator) replaceOrphanedRange(ctx context.Context, blocks map[int64]evmtypes.Block, child evmtypes.Block, maxDepth int64, lggr logger.Logger) {
+	var reqs []rpc.BatchElem
+	for num := child.Number - 1; num >= 0 && num >= child.Number-maxDepth; num-- {
+		if _, exists := blocks[num]; !exists {
+			// Blocks below a gap can't be checked against the canonical chain
+			// until the gap is filled
+			break
+		}
+		reqs = append(reqs, rpc.BatchElem{
+			Method: "eth_getBlockByNumber",
+			Args:   []interface{}{Int64ToHex(num), true},
+			Result: &evmtypes.Block{},
+		})
+	}
+
+	lggr.Debugw(fmt.Sprintf("Block %d does not descend from block %d in the history, re-fetching up to %d blocks", child.Number, child.Number-1, len(reqs)), "blockNum", child.Number, "parentHash", child.ParentHash, "orphanedHash", blocks[child.Number-1].Hash)
+	if err := b.batchFetch(ctx, reqs); err != nil {
+		lggr.Warnw("Failed to re-fetch re-orged blocks, dropping them from the history", "err", err, "blockNum", child.Number)
+		dropBlocksBelow(blocks, child.Number)
+		return
+	}
+
+	expected := child.ParentHash
+	var depth int64
+	defer func() {
+		lggr.Warnw(fmt.Sprintf("Detected re-org of depth %d in block history", depth), "depth", depth, "blockNum", child.Number)
+		promBlockHistoryEstimatorReorgDepth.WithLabelValues(b.chainID.String()).Observe(float64(depth))
+	}()
+	for _, req := range reqs {
+		num := HexToInt64(req.Args[0])
+		if blocks[num].Hash == expected {
+			// Reached the common ancestor
+			return
+		}
+		depth++
+		block, is := req.Result.(*evmtypes.Block)
+		if req.Error != nil || !is || block == nil || block.Hash != expected {
+			lggr.Warnw("Failed to re-fetch re-orged block, dropping it and all older blocks from the history", "err", req.Error, "blockNum", num)
+			dropBlocksBelow(blocks, num+1)
+			return
+		}
+		blocks[num] = *block
+		expected = block.ParentHash
+	}
+
+	lowest := child.Number - int64(len(reqs)) - 1
+	if ancestor, exists := blocks[lowest]; exists && ancestor.Hash != expected {
+		lggr.Warnw(fmt.Sprintf("Re-org is deeper than EVM.GasEstimator.BlockHistory.MaxReorgDepth=%d, dropping all older blocks from the history", maxDepth), "blockNum", child.Number, "maxReorgDepth", maxDepth)
+		dropBlocksBelow(blocks, lowest+1)
+	}
+}
+
+// dropBlocksBelow removes the blocks numbered below num
+func dropBlocksBelow(blocks map[int64]evmtypes.Block, num int64) {
+	for n := range blocks {
+		if n < num {
+			delete(blocks, n)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestBlockHistoryEstimator_ReorgedBlocks(t *testing.T) {
+	t.Parallel()
+
+	// chain returns the blocks from..to descending from parent, with the
+	// given gas price
+	chain := func(parent common.Hash, from, to int64, gasPrice int64) (blocks []evmtypes.Block) {
+		for num := from; num <= to; num++ {
+			block := evmtypes.Block{
+				Number:       num,
+				Hash:         utils.NewHash(),
+				ParentHash:   parent,
+				Transactions: cltest.LegacyTransactionsFromGasPrices(gasPrice),
+			}
+			blocks = append(blocks, block)
+			parent = block.Hash
+		}
+		return
+	}
+
+	// Blocks 100-103 of the history were re-orged out, their orphaned
+	// transactions pay far more than those of the canonical chain
+	ancestors := chain(utils.NewHash(), 96, 99, 10)
+	orphaned := chain(ancestors[3].Hash, 100, 103, 1000)
+	canonical := chain(ancestors[3].Hash, 100, 104, 20)
+
+	newEstimator := func(t *testing.T, maxReorgDepth uint16) *gas.BlockHistoryEstimator {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorBlockDelayF = uint16(0)
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = uint16(9)
+		cfg.BlockHistoryEstimatorBatchSizeF = uint32(0)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(90)
+		cfg.BlockHistoryEstimatorMaxReorgDepthF = maxReorgDepth
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(10000)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+		gas.SetRollingBlockHistory(bhe, append(append([]evmtypes.Block{}, ancestors...), orphaned...))
+
+		// The new head is fetched first, then the divergent range below it
+		ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+			return len(b) == 1 && b[0].Args[0] == gas.Int64ToHex(104)
+		})).Return(nil).Run(func(args mock.Arguments) {
+			block := canonical[4]
+			args.Get(1).([]rpc.BatchElem)[0].Result = &block
+		}).Once()
+		if maxReorgDepth > 0 {
+			ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+				return len(b) == int(maxReorgDepth) && b[0].Args[0] == gas.Int64ToHex(103)
+			})).Return(nil).Run(func(args mock.Arguments) {
+				for i, elem := range args.Get(1).([]rpc.BatchElem) {
+					num := gas.HexToInt64(elem.Args[0])
+					var block evmtypes.Block
+					if num >= 100 {
+						block = canonical[num-100]
+					} else {
+						block = ancestors[num-96]
+					}
+					args.Get(1).([]rpc.BatchElem)[i].Result = &block
+				}
+			}).Once()
+		}
+		gas.SimulateStart(t, bhe)
+		return bhe
+	}
+
+	getLegacyGas := func(t *testing.T, bhe *gas.BlockHistoryEstimator) *assets.Wei {
+		price, _, err := bhe.GetLegacyGas(testutils.Context(t), nil, 100000, assets.NewWeiI(10000))
+		require.NoError(t, err)
+		return price
+	}
+
+	t.Run("replaces the orphaned blocks with the canonical ones", func(t *testing.T) {
+		bhe := newEstimator(t, 8)
+
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), cltest.Head(104))
+
+		history := gas.GetRollingBlockHistory(bhe)
+		require.Len(t, history, 9)
+		for i, block := range history {
+			if i < 4 {
+				assert.Equal(t, ancestors[i].Hash, block.Hash)
+			} else {
+				assert.Equal(t, canonical[i-4].Hash, block.Hash)
+			}
+		}
+		// the orphaned transactions would have set the price to 1000
+		assert.Equal(t, assets.NewWeiI(20), getLegacyGas(t, bhe))
+	})
+
+	t.Run("drops the blocks below a re-org deeper than the max depth", func(t *testing.T) {
+		bhe := newEstimator(t, 2)
+
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), cltest.Head(104))
+
+		history := gas.GetRollingBlockHistory(bhe)
+		require.Len(t, history, 3)
+		assert.Equal(t, canonical[2].Hash, history[0].Hash)
+		assert.Equal(t, canonical[3].Hash, history[1].Hash)
+		assert.Equal(t, canonical[4].Hash, history[2].Hash)
+		assert.Equal(t, assets.NewWeiI(20), getLegacyGas(t, bhe))
+	})
+
+	t.Run("keeps the orphaned blocks if re-org detection is disabled", func(t *testing.T) {
+		bhe := newEstimator(t, 0)
+
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), cltest.Head(104))
+
+		history := gas.GetRollingBlockHistory(bhe)
+		require.Len(t, history, 9)
+		assert.Equal(t, orphaned[3].Hash, history[7].Hash)
+		assert.Equal(t, assets.NewWeiI(1000), getLegacyGas(t, bhe))
+	})
+}
@@ -0,0 +1,48 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+)
+
+// BlockHistoryStore persists the block history of the BlockHistoryEstimator,
+// so that after a restart it can estimate from the persisted blocks instead of
+// falling back to EVM.GasEstimator.PriceDefault until it has fetched
+// BlockHistorySize blocks again.
+type BlockHistoryStore interface {
+	// SaveBlocks replaces the persisted blocks of the chain with blocks
+	SaveBlocks(ctx context.Context, chainID *big.Int, blocks []evmtypes.Block) error
+	// LoadBlocks returns the persisted blocks of the chain, sorted by block
+	// number ascending
+	LoadBlocks(ctx context.Context, chainID *big.Int) ([]evmtypes.Block, error)
+}
+
+var _ BlockHistoryStore = (*inMemoryBlockHistoryStore)(nil)
+
+type inMemoryBlockHistoryStore struct {
+	mu     sync.RWMutex
+	blocks map[string][]evmtypes.Block
+}
+
+// NewInMemoryBlockHistoryStore returns a BlockHistoryStore that keeps the
+// blocks in memory, so they survive restarts of the estimator but not of the
+// node
+func NewInMemoryBlockHistoryStore() BlockHistoryStore {
+	return &inMemoryBlockHistoryStore{blocks: make(map[string][]evmtypes.Block)}
+}
+
+func (s *inMemoryBlockHistoryStore) SaveBlocks(_ context.Context, chainID *big.Int, blocks []evmtypes.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blocks[chainID.String()] = append([]evmtypes.Block(nil), blocks...)
+	return nil
+}
+
+func (s *inMemoryBlockHistoryStore) LoadBlocks(_ context.Context, chainID *big.Int) ([]evmtypes.Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]evmtypes.Block(nil), s.blocks[chainID.String()]...), nil
+}
@@ -0,0 +1,117 @@
+package gas
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+var promBlockHistoryEstimatorWindowSize = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "block_history_estimator_window_size",
+	Help: "Number of blocks the block history estimator computes its percentile prices from",
+},
+	[]string{"evmChainID"},
+)
+
+// windowMaxChangePercent is the most that the adaptive window of
+// EVM.GasEstimator.BlockHistory.HistoryDuration changes by on each head, so
+// that jitter in the block times doesn't make it thrash
+const windowMaxChangePercent = 20
+
+// initialWindowSize returns EVM.GasEstimator.BlockHistory.BlockHistorySize,
+// within [HistorySizeMin, HistorySizeMax] if the window is adaptive
+func initialWindowSize(cfg Config) int64 {
+	size := int64(cfg.BlockHistoryEstimatorBlockHistorySize())
+	if cfg.BlockHistoryEstimatorHistoryDuration() > 0 {
+		size = clampWindowSize(cfg, size)
+	}
+	return size
+}
+
+func clampWindowSize(cfg Config, size int64) int64 {
+	if min := int64(cfg.BlockHistoryEstimatorHistorySizeMin()); size < min {
+		return min
+	}
+	if max := int64(cfg.BlockHistoryEstimatorHistorySizeMax()); size > max {
+		return max
+	}
+	return size
+}
+
+// EffectiveHistorySize returns the number of blocks that the estimator
+// computes its prices from. It is EVM.GasEstimator.BlockHistory.BlockHistorySize,
+// unless HistoryDuration is set, in which case it adapts to the block time of
+// the chain so that the window spans about HistoryDuration.
+func (b *BlockHistoryEstimator) EffectiveHistorySize() int64 {
+	return b.window.Load()
+}
+
+// historySize returns the number of blocks to keep in the history, which must
+// be enough for both the estimator and the connectivity checker
+func (b *BlockHistoryEstimator) historySize() int64 {
+	size := b.EffectiveHistorySize()
+	if inclusion := int64(b.config.BlockHistoryEstimatorCheckInclusionBlocks()); inclusion > size {
+		return inclusion
+	}
+	return size
+}
+
+// setWindowSize sets the effective history size and reports it
+func (b *BlockHistoryEstimator) setWindowSize(size int64) {
+	b.window.Store(size)
+	promBlockHistoryEstimatorWindowSize.WithLabelValues(b.chainID.String()).Set(float64(size))
+}
+
+// adaptWindowSize moves the effective history size towards the number of
+// blocks produced in EVM.GasEstimator.BlockHistory.HistoryDuration, at the
+// average block time of blocks, by at most windowMaxChangePercent of the
+// current size. The blocks must be sorted by number ascending. It does
+// nothing unless HistoryDuration is set.
+//
+// Growing the window keeps the blocks already in the history, and the missing
+// older blocks are fetched with the next head.
+func (b *BlockHistoryEstimator) adaptWindowSize(blocks []evmtypes.Block, lggr logger.Logger) {
+	duration := b.config.BlockHistoryEstimatorHistoryDuration()
+	if duration <= 0 {
+		return
+	}
+	var first, last *evmtypes.Block
+	for i := range blocks {
+		if blocks[i].Timestamp.IsZero() {
+			continue
+		}
+		if first == nil {
+			first = &blocks[i]
+		}
+		last = &blocks[i]
+	}
+	if first == nil || last.Number <= first.Number || !last.Timestamp.After(first.Timestamp) {
+		// Not enough blocks to measure the block time yet
+		return
+	}
+	blockTime := last.Timestamp.Sub(first.Timestamp) / time.Duration(last.Number-first.Number)
+	target := clampWindowSize(b.config, int64(math.Round(float64(duration)/float64(blockTime))))
+
+	current := b.EffectiveHistorySize()
+	step := current * windowMaxChangePercent / 100
+	if step < 1 {
+		step = 1
+	}
+	size := target
+	if size > current+step {
+		size = current + step
+	} else if size < current-step {
+		size = current - step
+	}
+	if size == current {
+		return
+	}
+	lggr.Debugw(fmt.Sprintf("Resizing block history window from %d to %d blocks", current, size), "blockTime", blockTime, "historyDuration", duration, "targetSize", target)
+	b.setWindowSize(size)
+}
@@ -0,0 +1,98 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+)
+
+func TestBlockHistoryEstimator_AdaptiveWindow(t *testing.T) {
+	t.Parallel()
+
+	genesis := time.Unix(1_700_000_000, 0)
+	hash := func(num int64) common.Hash { return common.BigToHash(big.NewInt(num + 1)) }
+
+	newEstimator := func(t *testing.T, blockTime time.Duration, historyDuration time.Duration) *gas.BlockHistoryEstimator {
+		cfg := gas.NewMockConfig()
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = 8
+		cfg.BlockHistoryEstimatorHistoryDurationF = historyDuration
+		cfg.BlockHistoryEstimatorHistorySizeMinF = 4
+		cfg.BlockHistoryEstimatorHistorySizeMaxF = 100
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+
+		// the chain produces a block every blockTime
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			for i, elem := range args.Get(1).([]rpc.BatchElem) {
+				num := gas.HexToInt64(elem.Args[0])
+				*args.Get(1).([]rpc.BatchElem)[i].Result.(*evmtypes.Block) = evmtypes.Block{
+					Number:    num,
+					Hash:      hash(num),
+					Timestamp: genesis.Add(time.Duration(num) * blockTime),
+				}
+			}
+		}).Return(nil).Maybe()
+		return newBlockHistoryEstimator(t, ethClient, cfg)
+	}
+	// onHeads fetches the blocks of heads from..to, returning the window
+	// size after each head
+	onHeads := func(t *testing.T, bhe *gas.BlockHistoryEstimator, from, to int64) (sizes []int64) {
+		for num := from; num <= to; num++ {
+			require.NoError(t, bhe.FetchBlocks(testutils.Context(t), &evmtypes.Head{Number: num, Hash: hash(num)}))
+			sizes = append(sizes, bhe.EffectiveHistorySize())
+		}
+		return
+	}
+
+	t.Run("grows the window to span the history duration on a chain with 2s blocks", func(t *testing.T) {
+		bhe := newEstimator(t, 2*time.Second, 2*time.Minute)
+		assert.Equal(t, int64(8), bhe.EffectiveHistorySize())
+
+		sizes := onHeads(t, bhe, 1000, 1030)
+		for i := 1; i < len(sizes); i++ {
+			// damped to at most 20% per head
+			assert.LessOrEqual(t, sizes[i]-sizes[i-1], sizes[i-1]/5+1)
+		}
+		assert.Equal(t, int64(60), bhe.EffectiveHistorySize())
+		// the history kept the blocks it had while growing
+		assert.Len(t, gas.GetRollingBlockHistory(bhe), 60)
+		assert.Equal(t, float64(60), promtestutil.ToFloat64(gas.PromBlockHistoryEstimatorWindowSize.WithLabelValues(testutils.FixtureChainID.String())))
+	})
+
+	t.Run("converges on a chain with 12s blocks", func(t *testing.T) {
+		bhe := newEstimator(t, 12*time.Second, 2*time.Minute)
+
+		onHeads(t, bhe, 1000, 1010)
+		assert.Equal(t, int64(10), bhe.EffectiveHistorySize())
+		assert.Len(t, gas.GetRollingBlockHistory(bhe), 10)
+	})
+
+	t.Run("shrinks the window and stays within the bounds", func(t *testing.T) {
+		bhe := newEstimator(t, 12*time.Second, 12*time.Second)
+
+		sizes := onHeads(t, bhe, 1000, 1010)
+		assert.Equal(t, []int64{7, 6, 5, 4}, sizes[:4])
+		assert.Equal(t, int64(4), bhe.EffectiveHistorySize())
+		assert.Len(t, gas.GetRollingBlockHistory(bhe), 4)
+	})
+
+	t.Run("keeps BlockHistorySize without a history duration", func(t *testing.T) {
+		bhe := newEstimator(t, 2*time.Second, 0)
+
+		onHeads(t, bhe, 1000, 1010)
+		assert.Equal(t, int64(8), bhe.EffectiveHistorySize())
+		assert.Len(t, gas.GetRollingBlockHistory(bhe), 8)
+	})
+}
@@ -0,0 +1,228 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// CeloFeeCurrencyGasOverhead is the gas that Celo charges on top of the
+// execution of a transaction to debit and credit its fee in a fee currency
+// other than CELO, see https://docs.celo.org/protocol/transaction/erc20-transaction-fees
+const CeloFeeCurrencyGasOverhead = 50_000
+
+// CeloConfig is the config needed by the Celo estimator
+type CeloConfig interface {
+	dynamicFeeBumpConfig
+	feeCapConfig
+	EvmGasFeeCurrency() *common.Address
+	EvmGasLimitMax() uint32
+}
+
+// FeeCurrencyEstimator is implemented by estimators that price fees in a
+// currency other than the native currency of the chain
+type FeeCurrencyEstimator interface {
+	// FeeCurrency returns the address of the fee currency, or nil if the
+	// fees are paid in the native currency
+	FeeCurrency() *common.Address
+}
+
+var (
+	_ EvmEstimator         = (*celoEstimator)(nil)
+	_ FeeCurrencyEstimator = (*celoEstimator)(nil)
+)
+
+// celoEstimator estimates fees on Celo with the prices suggested by the node
+// for EVM.GasEstimator.FeeCurrency. Celo's eth_gasPrice and
+// eth_maxPriorityFeePerGas take the fee currency as an optional parameter and
+// return the price in that currency; without a fee currency they are called
+// exactly as on any other chain, and the fees are in CELO.
+//
+// Paying in a fee currency costs CeloFeeCurrencyGasOverhead more gas, which is
+// added to the gas limits.
+type celoEstimator struct {
+	utils.StartStopOnce
+
+	cfg      CeloConfig
+	client   rpcClient
+	lggr     logger.SugaredLogger
+	chainID  big.Int
+	currency *common.Address
+	metrics  *estimatorMetrics
+	// baseFee is the base fee of the latest head, in CELO
+	baseFee atomic.Pointer[assets.Wei]
+}
+
+// NewCeloEstimator returns a new "Celo" estimator
+func NewCeloEstimator(lggr logger.Logger, cfg CeloConfig, client rpcClient, chainID big.Int) EvmEstimator {
+	return &celoEstimator{
+		cfg:      cfg,
+		client:   client,
+		lggr:     logger.Sugared(lggr.Named("CeloEstimator")),
+		chainID:  chainID,
+		currency: cfg.EvmGasFeeCurrency(),
+		metrics:  newEstimatorMetrics(chainID, "Celo"),
+	}
+}
+
+func (c *celoEstimator) Name() string {
+	return c.lggr.Name()
+}
+
+func (c *celoEstimator) Start(context.Context) error {
+	return c.StartOnce("CeloEstimator", func() error { return nil })
+}
+
+func (c *celoEstimator) Close() error {
+	return c.StopOnce("CeloEstimator", func() error { return nil })
+}
+
+func (c *celoEstimator) HealthReport() map[string]error {
+	return map[string]error{c.Name(): c.Healthy()}
+}
+
+// OnNewLongestChain records the base fee of the head, which dynamic fee bumps
+// are anchored to
+func (c *celoEstimator) OnNewLongestChain(_ context.Context, head *evmtypes.Head) {
+	if head != nil && head.BaseFeePerGas != nil {
+		c.baseFee.Store(head.BaseFeePerGas)
+	}
+}
+
+// FeeCurrency returns EVM.GasEstimator.FeeCurrency
+func (c *celoEstimator) FeeCurrency() *common.Address {
+	return c.currency
+}
+
+// suggestedPrice calls the given price method of the node for the fee currency
+func (c *celoEstimator) suggestedPrice(ctx context.Context, method string) (*assets.Wei, error) {
+	if !c.IfStarted(func() {}) {
+		return nil, errors.New("estimator is not started")
+	}
+	var args []interface{}
+	if c.currency != nil {
+		args = append(args, *c.currency)
+	}
+	var res rpcPrice
+	if err := c.client.CallContext(ctx, &res, method, args...); err != nil {
+		c.metrics.recordRPCError(err)
+		return nil, &EstimationError{Reason: ErrRPCFailure, Err: errors.Wrapf(err, "%s failed", method)}
+	}
+	return res.Wei(), nil
+}
+
+// gasLimit adds CeloFeeCurrencyGasOverhead to gasLimit if the fees are paid in
+// a fee currency
+func (c *celoEstimator) gasLimit(gasLimit uint32) (uint32, error) {
+	if c.currency == nil {
+		return gasLimit, nil
+	}
+	limit := uint64(gasLimit) + CeloFeeCurrencyGasOverhead
+	if limit > uint64(c.cfg.EvmGasLimitMax()) {
+		return 0, errors.Errorf("gas limit of %d with the fee currency overhead of %d exceeds the configured max of %d", gasLimit, CeloFeeCurrencyGasOverhead, c.cfg.EvmGasLimitMax())
+	}
+	return uint32(limit), nil
+}
+
+// GetLegacyGas returns the gas price suggested by eth_gasPrice
+func (c *celoEstimator) GetLegacyGas(ctx context.Context, _ []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, _ ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &c.chainID, "Celo") }()
+	if chainSpecificGasLimit, err = c.gasLimit(gasLimit); err != nil {
+		return nil, 0, err
+	}
+	if gasPrice, err = c.suggestedPrice(ctx, "eth_gasPrice"); err != nil {
+		return nil, 0, err
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, c.cfg.EvmMaxGasPriceWei())
+	if gasPrice.Cmp(maxGasPrice) > 0 {
+		c.metrics.recordCap(gasPrice, maxGasPrice)
+		return nil, 0, &EstimationError{Price: gasPrice, Limit: maxGasPrice,
+			Err: errors.Errorf("estimated gas price of %s is greater than the maximum gas price configured: %s", gasPrice, maxGasPrice)}
+	}
+	c.metrics.setGasPrice(gasPrice)
+	return gasPrice, chainSpecificGasLimit, nil
+}
+
+// BumpLegacyGas bumps the original gas price to at least the price currently
+// suggested by eth_gasPrice
+func (c *celoEstimator) BumpLegacyGas(ctx context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &c.chainID, "Celo") }()
+	if chainSpecificGasLimit, err = c.gasLimit(gasLimit); err != nil {
+		return nil, 0, err
+	}
+	currentGasPrice, err := c.suggestedPrice(ctx, "eth_gasPrice")
+	if err != nil {
+		c.lggr.Warnw("Failed to fetch the current gas price, bumping the original gas price only", "err", err)
+	}
+	bumpedGasPrice, err = bumpGasPrice(c.cfg, c.lggr, currentGasPrice, originalGasPrice, maxGasPriceWei)
+	c.metrics.recordLegacyBump(err)
+	return bumpedGasPrice, chainSpecificGasLimit, err
+}
+
+// GetDynamicFee returns the tip cap suggested by eth_maxPriorityFeePerGas, and
+// the price suggested by eth_gasPrice as the fee cap
+func (c *celoEstimator) GetDynamicFee(ctx context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &c.chainID, "Celo") }()
+	if chainSpecificGasLimit, err = c.gasLimit(gasLimit); err != nil {
+		return fee, 0, err
+	}
+	tipCap, err := c.suggestedPrice(ctx, "eth_maxPriorityFeePerGas")
+	if err != nil {
+		return fee, 0, err
+	}
+	feeCap, err := c.suggestedPrice(ctx, "eth_gasPrice")
+	if err != nil {
+		return fee, 0, err
+	}
+	// A fee cap below the tip cap would make the transaction invalid
+	feeCap = assets.WeiMax(feeCap, tipCap)
+
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, c.cfg.EvmMaxGasPriceWei())
+	if feeCap.Cmp(maxGasPrice) > 0 {
+		c.metrics.recordCap(feeCap, maxGasPrice)
+		return fee, 0, &EstimationError{Price: feeCap, Limit: maxGasPrice,
+			Err: errors.Errorf("estimated fee cap of %s is greater than the maximum gas price configured: %s", feeCap, maxGasPrice)}
+	}
+	if fee, err = applyTipCapMin(c.cfg, DynamicFee{FeeCap: feeCap, TipCap: tipCap}, maxGasPrice); err != nil {
+		return fee, 0, err
+	}
+	fee.FeeCurrency = c.currency
+	c.metrics.setGasPrice(fee.FeeCap)
+	c.metrics.setTipCap(fee.TipCap)
+	return fee, chainSpecificGasLimit, nil
+}
+
+// BumpDynamicFee bumps the original fee, with the tip cap at least the one
+// currently suggested by eth_maxPriorityFeePerGas, and the fee cap at least
+// what GetDynamicFee would give at the base fee of the latest head. The base
+// fee is in CELO, so it is left out for fees priced in a fee currency.
+func (c *celoEstimator) BumpDynamicFee(ctx context.Context, original DynamicFee, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &c.chainID, "Celo") }()
+	if chainSpecificGasLimit, err = c.gasLimit(gasLimit); err != nil {
+		return bumped, 0, err
+	}
+	currentTipCap, err := c.suggestedPrice(ctx, "eth_maxPriorityFeePerGas")
+	if err != nil {
+		c.lggr.Warnw("Failed to fetch the current tip cap, bumping the original fee only", "err", err)
+	}
+	var currentBaseFee *assets.Wei
+	if c.currency == nil {
+		currentBaseFee = c.baseFee.Load()
+	}
+	bumped, err = bumpDynamicFee(c.cfg, bufferedFeeCap(c.cfg), c.lggr, currentTipCap, currentBaseFee, original, maxGasPriceWei)
+	c.metrics.recordDynamicBump(err)
+	if err != nil {
+		return bumped, 0, err
+	}
+	bumped.FeeCurrency = c.currency
+	return bumped, chainSpecificGasLimit, nil
+}
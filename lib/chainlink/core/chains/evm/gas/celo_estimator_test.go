@@ -0,0 +1,234 @@
+package gas_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestCeloEstimator(t *testing.T) {
+	t.Parallel()
+
+	cUSD := common.HexToAddress("0x765DE816845861e75A25fCA122bb6898B8B1282a")
+	maxGasPrice := assets.GWei(100)
+	chainID := big.NewInt(42220)
+
+	newConfig := func(currency *common.Address) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasFeeCurrencyF = currency
+		cfg.EvmGasLimitMaxF = 500_000
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+		return cfg
+	}
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, client gas.RPCClient) gas.EvmEstimator {
+		e := gas.NewCeloEstimator(logger.TestLogger(t), cfg, client, *chainID)
+		require.NoError(t, e.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, e.Close()) })
+		return e
+	}
+	setPrice := func(price int64) func(mock.Arguments) {
+		return func(args mock.Arguments) { gas.SetRPCPrice(args.Get(1), price) }
+	}
+
+	t.Run("without a fee currency calls eth_gasPrice without params", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Run(setPrice(5_000_000_000)).Return(nil).Once()
+		e := newEstimator(t, newConfig(nil), client)
+
+		gasPrice, gasLimit, err := e.GetLegacyGas(testutils.Context(t), nil, 100_000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(5), gasPrice)
+		assert.Equal(t, uint32(100_000), gasLimit)
+		assert.Nil(t, e.(gas.FeeCurrencyEstimator).FeeCurrency())
+	})
+
+	t.Run("with a fee currency passes it to eth_gasPrice and adds the overhead to the gas limit", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice", cUSD).Run(setPrice(6_000_000_000)).Return(nil).Once()
+		e := newEstimator(t, newConfig(&cUSD), client)
+
+		gasPrice, gasLimit, err := e.GetLegacyGas(testutils.Context(t), nil, 100_000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(6), gasPrice)
+		assert.Equal(t, uint32(100_000+gas.CeloFeeCurrencyGasOverhead), gasLimit)
+		assert.Equal(t, &cUSD, e.(gas.FeeCurrencyEstimator).FeeCurrency())
+	})
+
+	t.Run("fails if the gas limit with the overhead exceeds LimitMax", func(t *testing.T) {
+		e := newEstimator(t, newConfig(&cUSD), mocks.NewRPCClient(t))
+
+		_, _, err := e.GetLegacyGas(testutils.Context(t), nil, 480_000, maxGasPrice)
+		require.EqualError(t, err, "gas limit of 480000 with the fee currency overhead of 50000 exceeds the configured max of 500000")
+	})
+
+	t.Run("fails if the gas price exceeds the max gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice", cUSD).Run(setPrice(200_000_000_000)).Return(nil).Once()
+		e := newEstimator(t, newConfig(&cUSD), client)
+
+		_, _, err := e.GetLegacyGas(testutils.Context(t), nil, 100_000, maxGasPrice)
+		require.EqualError(t, err, "estimated gas price of 200 gwei is greater than the maximum gas price configured: 100 gwei")
+	})
+
+	t.Run("GetDynamicFee passes the fee currency to eth_maxPriorityFeePerGas and eth_gasPrice", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_maxPriorityFeePerGas", cUSD).Run(setPrice(1_000_000_000)).Return(nil).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice", cUSD).Run(setPrice(10_000_000_000)).Return(nil).Once()
+		e := newEstimator(t, newConfig(&cUSD), client)
+
+		fee, gasLimit, err := e.GetDynamicFee(testutils.Context(t), 100_000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(1), FeeCurrency: &cUSD}, fee)
+		assert.Equal(t, uint32(100_000+gas.CeloFeeCurrencyGasOverhead), gasLimit)
+	})
+
+	t.Run("BumpDynamicFee bumps the original fee and keeps the fee currency", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_maxPriorityFeePerGas", cUSD).Run(setPrice(3_000_000_000)).Return(nil).Once()
+		e := newEstimator(t, newConfig(&cUSD), client)
+
+		original := gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(1), FeeCurrency: &cUSD}
+		bumped, gasLimit, err := e.BumpDynamicFee(testutils.Context(t), original, 100_000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: assets.GWei(11), TipCap: assets.GWei(3), FeeCurrency: &cUSD}, bumped)
+		assert.Equal(t, uint32(100_000+gas.CeloFeeCurrencyGasOverhead), gasLimit)
+	})
+
+	t.Run("BumpDynamicFee anchors the fee cap to the base fee of the latest head", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_maxPriorityFeePerGas").Run(setPrice(1_000_000_000)).Return(nil).Once()
+		cfg := newConfig(nil)
+		cfg.EvmGasBumpFeeCapFromBaseFeeF = true
+		e := newEstimator(t, cfg, client)
+		e.OnNewLongestChain(testutils.Context(t), &evmtypes.Head{Number: 1, BaseFeePerGas: assets.GWei(20)})
+
+		original := gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(1)}
+		bumped, _, err := e.BumpDynamicFee(testutils.Context(t), original, 100_000, maxGasPrice, nil)
+		require.NoError(t, err)
+		// the fee cap follows the base fee of 20 gwei rather than the 10%
+		// bump of the original fee cap
+		assert.Equal(t, 1, bumped.FeeCap.Cmp(assets.GWei(20)), "fee cap %s", bumped.FeeCap)
+	})
+
+	t.Run("BumpLegacyGas bumps to at least the current gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Run(setPrice(20_000_000_000)).Return(nil).Once()
+		e := newEstimator(t, newConfig(nil), client)
+
+		bumped, gasLimit, err := e.BumpLegacyGas(testutils.Context(t), assets.GWei(10), 100_000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(20), bumped)
+		assert.Equal(t, uint32(100_000), gasLimit)
+	})
+
+	t.Run("keeps the request shapes without a fee currency", func(t *testing.T) {
+		record := func(t *testing.T, currency *common.Address) string {
+			client := mocks.NewRPCClient(t)
+			client.On("CallContext", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				*args.Get(1).(*json.RawMessage) = json.RawMessage(`"0x1"`)
+			}).Return(nil).Maybe()
+			client.On("CallContext", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				*args.Get(1).(*json.RawMessage) = json.RawMessage(`"0x1"`)
+			}).Return(nil).Maybe()
+			var buf bytes.Buffer
+			e := newEstimator(t, newConfig(currency), gas.NewRecordingRPCClient(client, &buf))
+			_, _, err := e.GetDynamicFee(testutils.Context(t), 100_000, maxGasPrice)
+			require.NoError(t, err)
+			return buf.String()
+		}
+
+		assert.Equal(t, `{"method":"eth_maxPriorityFeePerGas","params":[],"result":"0x1"}
+{"method":"eth_gasPrice","params":[],"result":"0x1"}
+`, record(t, nil))
+		assert.Equal(t, `{"method":"eth_maxPriorityFeePerGas","params":["0x765de816845861e75a25fca122bb6898b8b1282a"],"result":"0x1"}
+{"method":"eth_gasPrice","params":["0x765de816845861e75a25fca122bb6898b8b1282a"],"result":"0x1"}
+`, record(t, &cUSD))
+	})
+}
+
+func TestWrappedEvmEstimator_FeeCurrency(t *testing.T) {
+	t.Parallel()
+
+	cUSD := common.HexToAddress("0x765DE816845861e75A25fCA122bb6898B8B1282a")
+	call := gas.EstimateGasCall{From: testutils.NewAddress(), To: testutils.NewAddress()}
+	newConfig := func(currency *common.Address) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasFeeCurrencyF = currency
+		cfg.EvmGasEstimateGasLimitF = true
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.EvmGasLimitMinF = 21_000
+		cfg.EvmGasLimitMaxF = 500_000
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		return cfg
+	}
+
+	t.Run("passes the fee currency of the estimator to eth_estimateGas and returns it with the fee", func(t *testing.T) {
+		cfg := newConfig(&cUSD)
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice", cUSD).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 5_000_000_000)
+		}).Return(nil)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_estimateGas", mock.MatchedBy(func(args map[string]interface{}) bool {
+			return args["to"] == call.To && args["feeCurrency"] == &cUSD
+		})).Run(func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = 120_000
+		}).Return(nil).Once()
+		celo := gas.NewCeloEstimator(logger.TestLogger(t), cfg, client, *big.NewInt(42220))
+		require.NoError(t, celo.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, celo.Close()) })
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), celo, cfg, client)
+
+		fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), nil, 100_000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(120_000), gasLimit)
+		assert.Equal(t, gas.EvmFee{Legacy: assets.GWei(5), FeeCurrency: &cUSD}, fee)
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), fee, 100_000, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gas.EvmFee{Legacy: assets.NewWeiI(5_500_000_000), FeeCurrency: &cUSD}, bumped)
+	})
+
+	t.Run("leaves the fee currency unset for other estimators", func(t *testing.T) {
+		cfg := newConfig(nil)
+		cfg.EvmEIP1559DynamicFeesF = true
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_estimateGas", mock.MatchedBy(func(args map[string]interface{}) bool {
+			_, ok := args["feeCurrency"]
+			return args["to"] == call.To && !ok
+		})).Run(func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = 120_000
+		}).Return(nil).Once()
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetDynamicFee", mock.Anything, uint32(100_000), mock.Anything).Return(gas.DynamicFee{FeeCap: assets.GWei(2), TipCap: assets.GWei(1)}, uint32(100_000), nil).Once()
+		e.On("BumpDynamicFee", mock.Anything, gas.DynamicFee{FeeCap: assets.GWei(2), TipCap: assets.GWei(1)}, uint32(100_000), mock.Anything, mock.Anything).
+			Return(gas.DynamicFee{FeeCap: assets.GWei(3), TipCap: assets.GWei(2)}, uint32(100_000), nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, client)
+
+		fee, _, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), nil, 100_000, nil)
+		require.NoError(t, err)
+		assert.Nil(t, fee.FeeCurrency)
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), fee, 100_000, nil, nil)
+		require.NoError(t, err)
+		assert.Nil(t, bumped.FeeCurrency)
+	})
+}
@@ -0,0 +1,150 @@
+package gas
+
+import (
+	"math/big"
+
+	"go.uber.org/multierr"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmcfg "github.com/smartcontractkit/chainlink/v2/core/chains/evm/config/v2"
+	v2 "github.com/smartcontractkit/chainlink/v2/core/config/v2"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// ChainDefaults are the gas estimator settings that need tuning for each
+// chain. Nil fields are unset: Merge leaves them at the value they override.
+type ChainDefaults struct {
+	Mode               *string
+	EIP1559DynamicFees *bool
+	PriceDefault       *assets.Wei
+	PriceMin           *assets.Wei
+	PriceMax           *assets.Wei
+	BumpMin            *assets.Wei
+	BumpThreshold      *uint32
+	TipCapDefault      *assets.Wei
+	FeeCapDefault      *assets.Wei
+	BlockHistorySize   *uint16
+}
+
+// DefaultsFor returns the gas estimator defaults of the chain, with every
+// field set. They are read from the config defaults of EVM chains, i.e. the
+// chain's defaults file merged on top of fallback.toml, so chains without
+// their own defaults get the fallback ones.
+func DefaultsFor(chainID *big.Int) ChainDefaults {
+	var id *utils.Big
+	if chainID != nil {
+		id = utils.NewBig(chainID)
+	}
+	g := evmcfg.Defaults(id).GasEstimator
+	return ChainDefaults{
+		Mode:               g.Mode,
+		EIP1559DynamicFees: g.EIP1559DynamicFees,
+		PriceDefault:       g.PriceDefault,
+		PriceMin:           g.PriceMin,
+		PriceMax:           g.PriceMax,
+		BumpMin:            g.BumpMin,
+		BumpThreshold:      g.BumpThreshold,
+		TipCapDefault:      g.TipCapDefault,
+		FeeCapDefault:      g.FeeCapDefault,
+		BlockHistorySize:   g.BlockHistory.BlockHistorySize,
+	}
+}
+
+// MergeDefaults applies the user's overrides on top of the defaults of the
+// chain, and validates the result
+func MergeDefaults(chainID *big.Int, overrides ChainDefaults) (ChainDefaults, error) {
+	d := DefaultsFor(chainID).Merge(overrides)
+	return d, d.Validate()
+}
+
+// Merge returns d with the fields set in overrides replaced
+func (d ChainDefaults) Merge(overrides ChainDefaults) ChainDefaults {
+	if overrides.Mode != nil {
+		d.Mode = overrides.Mode
+	}
+	if overrides.EIP1559DynamicFees != nil {
+		d.EIP1559DynamicFees = overrides.EIP1559DynamicFees
+	}
+	if overrides.PriceDefault != nil {
+		d.PriceDefault = overrides.PriceDefault
+	}
+	if overrides.PriceMin != nil {
+		d.PriceMin = overrides.PriceMin
+	}
+	if overrides.PriceMax != nil {
+		d.PriceMax = overrides.PriceMax
+	}
+	if overrides.BumpMin != nil {
+		d.BumpMin = overrides.BumpMin
+	}
+	if overrides.BumpThreshold != nil {
+		d.BumpThreshold = overrides.BumpThreshold
+	}
+	if overrides.TipCapDefault != nil {
+		d.TipCapDefault = overrides.TipCapDefault
+	}
+	if overrides.FeeCapDefault != nil {
+		d.FeeCapDefault = overrides.FeeCapDefault
+	}
+	if overrides.BlockHistorySize != nil {
+		d.BlockHistorySize = overrides.BlockHistorySize
+	}
+	return d
+}
+
+// Validate checks that the fields are set and consistent with each other,
+// and returns every violated constraint
+func (d ChainDefaults) Validate() (err error) {
+	for _, f := range []struct {
+		name  string
+		unset bool
+	}{
+		{"Mode", d.Mode == nil},
+		{"EIP1559DynamicFees", d.EIP1559DynamicFees == nil},
+		{"PriceDefault", d.PriceDefault == nil},
+		{"PriceMin", d.PriceMin == nil},
+		{"PriceMax", d.PriceMax == nil},
+		{"BumpMin", d.BumpMin == nil},
+		{"BumpThreshold", d.BumpThreshold == nil},
+		{"TipCapDefault", d.TipCapDefault == nil},
+		{"FeeCapDefault", d.FeeCapDefault == nil},
+		{"BlockHistorySize", d.BlockHistorySize == nil},
+	} {
+		if f.unset {
+			err = multierr.Append(err, v2.ErrMissing{Name: f.name, Msg: "must be set"})
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if !evmcfg.IsGasEstimatorMode(*d.Mode) {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "Mode", Value: *d.Mode,
+			Msg: "must be a built-in or registered estimator mode"})
+	}
+	if d.PriceMin.Cmp(d.PriceDefault) > 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "PriceMin", Value: d.PriceMin,
+			Msg: "must be less than or equal to PriceDefault"})
+	}
+	if d.PriceMax.Cmp(d.PriceDefault) < 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "PriceMax", Value: d.PriceMax,
+			Msg: "must be greater than or equal to PriceDefault"})
+	}
+	if d.BumpMin.Cmp(d.PriceMax) >= 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "BumpMin", Value: d.BumpMin,
+			Msg: "must be less than PriceMax"})
+	}
+	if d.FeeCapDefault.Cmp(d.TipCapDefault) < 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FeeCapDefault", Value: d.FeeCapDefault,
+			Msg: "must be greater than or equal to TipCapDefault"})
+	}
+	if *d.EIP1559DynamicFees && d.FeeCapDefault.Cmp(d.PriceMax) > 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "FeeCapDefault", Value: d.FeeCapDefault,
+			Msg: "must be less than or equal to PriceMax with EIP1559DynamicFees"})
+	}
+	if (*d.Mode == "BlockHistory" || *d.Mode == "FeeHistory") && *d.BlockHistorySize == 0 {
+		err = multierr.Append(err, v2.ErrInvalid{Name: "BlockHistorySize", Value: *d.BlockHistorySize,
+			Msg: "must be greater than or equal to 1 with " + *d.Mode + " Mode"})
+	}
+	return err
+}
@@ -0,0 +1,144 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/multierr"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmcfg "github.com/smartcontractkit/chainlink/v2/core/chains/evm/config/v2"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestDefaultsFor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("every chain's defaults are valid", func(t *testing.T) {
+		for _, chainID := range evmcfg.DefaultIDs {
+			assert.NoError(t, gas.DefaultsFor(chainID.ToInt()).Validate(), "chain %s", chainID)
+		}
+		assert.NoError(t, gas.DefaultsFor(big.NewInt(424242)).Validate())
+	})
+
+	t.Run("merges the chain's defaults on top of the fallback ones", func(t *testing.T) {
+		polygon := gas.DefaultsFor(big.NewInt(137))
+		assert.Equal(t, "BlockHistory", *polygon.Mode)
+		assert.Equal(t, assets.GWei(30), polygon.TipCapDefault)
+		assert.Equal(t, assets.GWei(30), polygon.PriceMin)
+		assert.Equal(t, uint16(24), *polygon.BlockHistorySize)
+		// from the fallback defaults
+		assert.Equal(t, assets.GWei(100), polygon.FeeCapDefault)
+		assert.False(t, *polygon.EIP1559DynamicFees)
+
+		arbitrum := gas.DefaultsFor(big.NewInt(42161))
+		assert.Equal(t, "Arbitrum", *arbitrum.Mode)
+		assert.Equal(t, uint32(0), *arbitrum.BumpThreshold)
+
+		assert.True(t, *gas.DefaultsFor(big.NewInt(1)).EIP1559DynamicFees)
+	})
+
+	t.Run("matches the config defaults of the chain", func(t *testing.T) {
+		base := gas.DefaultsFor(big.NewInt(8453))
+		cfg := evmcfg.Defaults(utils.NewBigI(8453)).GasEstimator
+		assert.Equal(t, cfg.EIP1559DynamicFees, base.EIP1559DynamicFees)
+		assert.Equal(t, cfg.BumpMin, base.BumpMin)
+		assert.Equal(t, cfg.BlockHistory.BlockHistorySize, base.BlockHistorySize)
+		assert.Equal(t, uint16(60), *base.BlockHistorySize)
+	})
+
+	t.Run("returns the fallback defaults for unknown chains", func(t *testing.T) {
+		assert.Equal(t, gas.DefaultsFor(big.NewInt(424242)), gas.DefaultsFor(nil))
+		fallback := gas.DefaultsFor(big.NewInt(424242))
+		assert.Equal(t, "BlockHistory", *fallback.Mode)
+		assert.Equal(t, assets.GWei(20), fallback.PriceDefault)
+		assert.Equal(t, uint16(8), *fallback.BlockHistorySize)
+	})
+}
+
+func TestMergeDefaults(t *testing.T) {
+	t.Parallel()
+
+	t.Run("user config takes precedence over the chain's defaults", func(t *testing.T) {
+		d, err := gas.MergeDefaults(big.NewInt(137), gas.ChainDefaults{
+			TipCapDefault:    assets.GWei(50),
+			BlockHistorySize: ptr[uint16](12),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(50), d.TipCapDefault)
+		assert.Equal(t, uint16(12), *d.BlockHistorySize)
+		// unset fields keep the chain's defaults
+		assert.Equal(t, assets.GWei(30), d.PriceMin)
+		assert.Equal(t, uint32(5), *d.BumpThreshold)
+		// and the chain's defaults are unchanged
+		assert.Equal(t, assets.GWei(30), gas.DefaultsFor(big.NewInt(137)).TipCapDefault)
+	})
+
+	t.Run("user config can unset values to their zero value", func(t *testing.T) {
+		d, err := gas.MergeDefaults(big.NewInt(1), gas.ChainDefaults{
+			EIP1559DynamicFees: ptr(false),
+			BumpThreshold:      ptr[uint32](0),
+		})
+		require.NoError(t, err)
+		assert.False(t, *d.EIP1559DynamicFees)
+		assert.Equal(t, uint32(0), *d.BumpThreshold)
+	})
+
+	t.Run("validates the merged config", func(t *testing.T) {
+		for _, tt := range []struct {
+			name      string
+			chainID   int64
+			overrides gas.ChainDefaults
+			err       string
+		}{
+			{"unknown mode", 1, gas.ChainDefaults{Mode: ptr("Magic")},
+				"Mode: invalid value (Magic): must be a built-in or registered estimator mode"},
+			{"PriceMin above PriceDefault", 137, gas.ChainDefaults{PriceDefault: assets.GWei(20)},
+				"PriceMin: invalid value (30 gwei): must be less than or equal to PriceDefault"},
+			{"PriceMax below PriceDefault", 424242, gas.ChainDefaults{PriceMax: assets.GWei(10)},
+				"PriceMax: invalid value (10 gwei): must be greater than or equal to PriceDefault"},
+			{"BumpMin not below PriceMax", 424242, gas.ChainDefaults{PriceMax: assets.GWei(20), BumpMin: assets.GWei(20)},
+				"BumpMin: invalid value (20 gwei): must be less than PriceMax"},
+			{"FeeCapDefault below TipCapDefault", 137, gas.ChainDefaults{FeeCapDefault: assets.GWei(20)},
+				"FeeCapDefault: invalid value (20 gwei): must be greater than or equal to TipCapDefault"},
+			{"FeeCapDefault above PriceMax with EIP-1559", 1, gas.ChainDefaults{PriceMax: assets.GWei(50)},
+				"FeeCapDefault: invalid value (100 gwei): must be less than or equal to PriceMax with EIP1559DynamicFees"},
+			{"no block history with BlockHistory mode", 1, gas.ChainDefaults{BlockHistorySize: ptr[uint16](0)},
+				"BlockHistorySize: invalid value (0): must be greater than or equal to 1 with BlockHistory Mode"},
+			{"no block history with FeeHistory mode", 42161, gas.ChainDefaults{Mode: ptr("FeeHistory")},
+				"BlockHistorySize: invalid value (0): must be greater than or equal to 1 with FeeHistory Mode"},
+		} {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				_, err := gas.MergeDefaults(big.NewInt(tt.chainID), tt.overrides)
+				assert.EqualError(t, err, tt.err)
+			})
+		}
+	})
+
+	t.Run("lists every violated constraint", func(t *testing.T) {
+		_, err := gas.MergeDefaults(big.NewInt(137), gas.ChainDefaults{
+			PriceDefault:     assets.GWei(10),
+			FeeCapDefault:    assets.GWei(20),
+			BlockHistorySize: ptr[uint16](0),
+		})
+		require.Error(t, err)
+		errs := multierr.Errors(err)
+		require.Len(t, errs, 3)
+		assert.EqualError(t, errs[0], "PriceMin: invalid value (30 gwei): must be less than or equal to PriceDefault")
+		assert.EqualError(t, errs[1], "FeeCapDefault: invalid value (20 gwei): must be greater than or equal to TipCapDefault")
+		assert.EqualError(t, errs[2], "BlockHistorySize: invalid value (0): must be greater than or equal to 1 with BlockHistory Mode")
+	})
+
+	t.Run("Validate reports unset fields", func(t *testing.T) {
+		err := gas.ChainDefaults{Mode: ptr("BlockHistory"), PriceMin: assets.GWei(1)}.Validate()
+		require.Error(t, err)
+		assert.Len(t, multierr.Errors(err), 8)
+		assert.ErrorContains(t, err, "PriceDefault: missing: must be set")
+	})
+}
+
+func ptr[T any](t T) *T { return &t }
@@ -1,6 +1,8 @@
 package gas
 
 import (
+	"golang.org/x/exp/slices"
+
 	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
 	"github.com/smartcontractkit/chainlink/v2/core/config"
 )
@@ -8,6 +10,11 @@ import (
 // chainSpecificIsUsable allows for additional logic specific to a particular
 // Config that determines whether a transaction should be used for gas estimation
 func chainSpecificIsUsable(tx evmtypes.Transaction, cfg Config) bool {
+	// System transactions such as Optimism deposits and Arbitrum retryables
+	// pay no gas price, and would drag the percentiles towards zero
+	if slices.Contains(cfg.BlockHistoryEstimatorExcludedTxTypes(), uint8(tx.Type)) {
+		return false
+	}
 	if cfg.ChainType() == config.ChainXDai {
 		// GasPrice 0 on most chains is great since it indicates cheap/free transactions.
 		// However, xDai reserves a special type of "bridge" transaction with 0 gas
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/rpc"
@@ -52,9 +53,13 @@ func withEstimator(ctx context.Context, lggr logger.SugaredLogger, url string, f
 		log.Fatal(err)
 	}
 	ec := ethclient.NewClient(rc)
-	e := gas.NewArbitrumEstimator(lggr, &config{max: max}, rc, ec)
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
+	chainID, err := ec.ChainID(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
+	e := gas.NewArbitrumEstimator(lggr, &config{max: max}, rc, ec, *chainID)
 	err = e.Start(ctx)
 	if err != nil {
 		log.Fatal(err)
@@ -73,3 +78,59 @@ type config struct {
 func (c *config) EvmGasLimitMax() uint32 {
 	return c.max
 }
+
+func (c *config) EvmEIP1559DynamicFees() bool {
+	return false
+}
+
+func (c *config) EvmGasPriceStaleThreshold() time.Duration {
+	return 30 * time.Second
+}
+
+func (c *config) EvmGasPriceUpdateThreshold() uint16 {
+	return 0
+}
+
+func (c *config) EvmMaxBlobGasPriceWei() *assets.Wei {
+	return assets.GWei(1)
+}
+
+func (c *config) EvmGasBumpFeeCapFromBaseFee() bool {
+	return false
+}
+
+func (c *config) EvmGasBumpPercent() uint16 {
+	return 20
+}
+
+func (c *config) EvmGasBumpStrategy() string {
+	return gas.BumpStrategyPercent
+}
+
+func (c *config) EvmGasBumpWei() *assets.Wei {
+	return assets.GWei(5)
+}
+
+func (c *config) EvmGasSuggestedPriceConnectivityCheck() bool {
+	return true
+}
+
+func (c *config) EvmMaxGasPriceWei() *assets.Wei {
+	return assets.GWei(100)
+}
+
+func (c *config) EvmGasBaseFeeBufferMultiplier() float32 {
+	return 2
+}
+
+func (c *config) EvmGasBumpThreshold() uint64 {
+	return 3
+}
+
+func (c *config) EvmGasTipCapDefault() *assets.Wei {
+	return assets.NewWeiI(1)
+}
+
+func (c *config) EvmGasTipCapMinimum() *assets.Wei {
+	return assets.NewWeiI(1)
+}
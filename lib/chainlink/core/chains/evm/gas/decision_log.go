@@ -0,0 +1,122 @@
+package gas
+
+import (
+	"sync/atomic"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// The conditions of EVM.GasEstimator.DecisionLogAlways
+const (
+	// DecisionLogCapped logs fees capped at the max fee price
+	DecisionLogCapped = "Capped"
+	// DecisionLogBumped logs bumped fees
+	DecisionLogBumped = "Bumped"
+	// DecisionLogAnomaly logs fees reported as anomalies with
+	// EVM.GasEstimator.FeeAnomalyFactor
+	DecisionLogAnomaly = "Anomaly"
+	// decisionLogSampled is the reason of decisions logged by sampling
+	decisionLogSampled = "Sampled"
+)
+
+// decisionInputsReporter is implemented by estimators that report the inputs
+// their current fees were estimated from, e.g. the block range and raw
+// percentile prices of the BlockHistoryEstimator, as key value pairs for the
+// decision log
+type decisionInputsReporter interface {
+	decisionInputs() []interface{}
+}
+
+// decisionLogSampler selects the estimation decisions that are logged: one in
+// EVM.GasEstimator.DecisionLogSampleRate fees, and every fee that meets a
+// condition of EVM.GasEstimator.DecisionLogAlways. A rate of 0 logs only the
+// fees that meet a condition.
+type decisionLogSampler struct {
+	rate   uint64
+	always map[string]bool
+	n      atomic.Uint64
+}
+
+// newDecisionLogSampler returns the sampler of the decision log, or nil if it
+// logs nothing
+func newDecisionLogSampler(cfg Config) *decisionLogSampler {
+	rate, always := cfg.EvmGasDecisionLogSampleRate(), cfg.EvmGasDecisionLogAlways()
+	if rate == 0 && len(always) == 0 {
+		return nil
+	}
+	s := &decisionLogSampler{rate: uint64(rate), always: map[string]bool{}}
+	for _, cond := range always {
+		s.always[cond] = true
+	}
+	return s
+}
+
+// decision is the outcome of an estimate, and what the caller asked for
+type decision struct {
+	// op is the call that returned the fee, i.e. GetFee or BumpFee
+	op string
+	// feeLimit and maxFeePrice are the limits supplied by the caller
+	feeLimit    uint32
+	maxFeePrice *assets.Wei
+	// effectiveMaxFeePrice is the max fee price the fee was capped at
+	effectiveMaxFeePrice  *assets.Wei
+	fee                   EvmFee
+	chainSpecificFeeLimit uint32
+	anomaly               bool
+}
+
+// capped returns true if the gas price, or fee cap, is at the max fee price
+func (d decision) capped() bool {
+	price := d.fee.Legacy
+	if d.fee.ValidDynamic() {
+		price = d.fee.DynamicFeeCap
+	}
+	return price != nil && d.effectiveMaxFeePrice != nil && price.Cmp(d.effectiveMaxFeePrice) >= 0
+}
+
+// sample returns the reason to log the decision, or false if it isn't logged.
+// Decisions count towards the sampling rate whether or not they meet a
+// condition.
+func (s *decisionLogSampler) sample(d decision) (reason string, ok bool) {
+	if s == nil {
+		return "", false
+	}
+	n := s.n.Add(1)
+	switch {
+	case s.always[DecisionLogCapped] && d.capped():
+		return DecisionLogCapped, true
+	case s.always[DecisionLogBumped] && d.op == "BumpFee":
+		return DecisionLogBumped, true
+	case s.always[DecisionLogAnomaly] && d.anomaly:
+		return DecisionLogAnomaly, true
+	case s.rate > 0 && n%s.rate == 0:
+		return decisionLogSampled, true
+	}
+	return "", false
+}
+
+// logDecision logs the decision at debug level with the inputs of the
+// estimator, if the sampler selects it
+func (e WrappedEvmEstimator) logDecision(d decision) {
+	reason, ok := e.decisionLog.sample(d)
+	if !ok {
+		return
+	}
+	kvs := []interface{}{
+		"evmChainID", e.chainID,
+		"mode", e.Mode(),
+		"logReason", reason,
+		"fee", d.fee,
+		"chainSpecificFeeLimit", d.chainSpecificFeeLimit,
+		"feeLimit", d.feeLimit,
+		"maxFeePrice", d.maxFeePrice,
+		"effectiveMaxFeePrice", d.effectiveMaxFeePrice,
+		"capped", d.capped(),
+		"lowBalanceAdjusted", d.fee.LowBalanceAdjusted,
+		"anomaly", d.anomaly,
+	}
+	if r, ok := e.EvmEstimator.(decisionInputsReporter); ok {
+		kvs = append(kvs, r.decisionInputs()...)
+	}
+	e.lggr.Debugw("Estimation decision: "+d.op, kvs...)
+}
@@ -0,0 +1,178 @@
+package gas_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestWrappedEvmEstimator_DecisionLog(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21_000
+	newConfig := func(rate uint32, always ...string) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = "BlockHistory"
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasDecisionLogSampleRateF = rate
+		cfg.EvmGasDecisionLogAlwaysF = always
+		return cfg
+	}
+	// newEstimator returns an estimator that estimates the prices in order,
+	// and the decisions it logs
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, prices ...*assets.Wei) (gas.EvmFeeEstimator, *observer.ObservedLogs) {
+		e := mocks.NewEvmEstimator(t)
+		for _, price := range prices {
+			e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(price, gasLimit, nil).Once()
+		}
+		lggr, observed := logger.TestLoggerObserved(t, zapcore.DebugLevel)
+		return gas.NewWrappedEvmEstimator(lggr, e, cfg, nil), observed
+	}
+	estimate := func(t *testing.T, estimator gas.EvmFeeEstimator, n int) {
+		for i := 0; i < n; i++ {
+			_, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+			require.NoError(t, err)
+		}
+	}
+	decisions := func(observed *observer.ObservedLogs) []observer.LoggedEntry {
+		return observed.FilterMessageSnippet("Estimation decision").All()
+	}
+	repeat := func(price *assets.Wei, n int) (prices []*assets.Wei) {
+		for i := 0; i < n; i++ {
+			prices = append(prices, price)
+		}
+		return
+	}
+
+	t.Run("logs nothing by default", func(t *testing.T) {
+		estimator, observed := newEstimator(t, newConfig(0), repeat(assets.GWei(100), 5)...)
+		estimate(t, estimator, 5)
+		assert.Empty(t, decisions(observed))
+	})
+
+	t.Run("logs one in N fees", func(t *testing.T) {
+		estimator, observed := newEstimator(t, newConfig(4), repeat(assets.GWei(10), 12)...)
+		estimate(t, estimator, 12)
+
+		logged := decisions(observed)
+		require.Len(t, logged, 3)
+		for _, entry := range logged {
+			assert.Equal(t, zapcore.DebugLevel, entry.Level)
+			fields := entry.ContextMap()
+			assert.Equal(t, "Sampled", fields["logReason"])
+			assert.Equal(t, "BlockHistory", fields["mode"])
+			assert.Equal(t, false, fields["capped"])
+			assert.Equal(t, gasLimit, fields["feeLimit"])
+			assert.Equal(t, assets.GWei(100).String(), fields["effectiveMaxFeePrice"])
+		}
+	})
+
+	t.Run("always logs capped fees", func(t *testing.T) {
+		prices := append(repeat(assets.GWei(10), 3), repeat(assets.GWei(100), 2)...)
+		estimator, observed := newEstimator(t, newConfig(0, gas.DecisionLogCapped), prices...)
+
+		estimate(t, estimator, 3)
+		assert.Empty(t, decisions(observed))
+		estimate(t, estimator, 2)
+		logged := decisions(observed)
+		require.Len(t, logged, 2)
+		for _, entry := range logged {
+			assert.Equal(t, "Capped", entry.ContextMap()["logReason"])
+			assert.Equal(t, true, entry.ContextMap()["capped"])
+		}
+	})
+
+	t.Run("always logs bumps", func(t *testing.T) {
+		cfg := newConfig(1000, gas.DecisionLogBumped)
+		e := mocks.NewEvmEstimator(t)
+		e.On("BumpLegacyGas", mock.Anything, assets.GWei(10), gasLimit, mock.Anything, mock.Anything).Return(assets.GWei(12), gasLimit, nil).Twice()
+		lggr, observed := logger.TestLoggerObserved(t, zapcore.DebugLevel)
+		estimator := gas.NewWrappedEvmEstimator(lggr, e, cfg, nil)
+
+		for i := 0; i < 2; i++ {
+			_, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(10)}, gasLimit, nil, nil)
+			require.NoError(t, err)
+		}
+		logged := decisions(observed)
+		require.Len(t, logged, 2)
+		assert.Equal(t, "Estimation decision: BumpFee", logged[0].Message)
+		assert.Equal(t, "Bumped", logged[0].ContextMap()["logReason"])
+	})
+
+	t.Run("always logs anomalies", func(t *testing.T) {
+		cfg := newConfig(0, gas.DecisionLogAnomaly)
+		cfg.EvmGasFeeAnomalyFactorF = 5
+		cfg.EvmGasFeeAnomalyHalfLifeF = time.Minute
+		cfg.EvmGasFeeAnomalyCooldownF = 10 * time.Minute
+		estimator, observed := newEstimator(t, cfg, append(repeat(assets.GWei(1), 10), assets.GWei(50))...)
+		estimator.(*gas.WrappedEvmEstimator).SetAnomalyHook(new(anomalyRecorder))
+		now := time.Now()
+		gas.SetAnomalyClock(estimator, func() time.Time {
+			now = now.Add(time.Second)
+			return now
+		})
+
+		estimate(t, estimator, 10)
+		assert.Empty(t, decisions(observed))
+		estimate(t, estimator, 1)
+		logged := decisions(observed)
+		require.Len(t, logged, 1)
+		assert.Equal(t, "Anomaly", logged[0].ContextMap()["logReason"])
+		assert.Equal(t, true, logged[0].ContextMap()["anomaly"])
+	})
+
+	t.Run("conditions that aren't configured are sampled", func(t *testing.T) {
+		estimator, observed := newEstimator(t, newConfig(3, gas.DecisionLogBumped), repeat(assets.GWei(100), 6)...)
+		estimate(t, estimator, 6)
+
+		logged := decisions(observed)
+		require.Len(t, logged, 2)
+		assert.Equal(t, "Sampled", logged[0].ContextMap()["logReason"])
+		assert.Equal(t, true, logged[0].ContextMap()["capped"])
+	})
+
+	t.Run("logs the block range and raw percentile prices of the BlockHistoryEstimator", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.GasEstimatorModeF = "BlockHistory"
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(50)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(1)
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.EvmGasDecisionLogAlwaysF = []string{gas.DecisionLogCapped}
+
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 41, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(60, 70, 80)},
+			{Number: 42, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(70, 80, 90)},
+		})
+		bhe.Recalculate(cltest.Head(42))
+		gas.SimulateStart(t, bhe)
+		lggr, observed := logger.TestLoggerObserved(t, zapcore.DebugLevel)
+		estimator := gas.NewWrappedEvmEstimator(lggr, bhe, cfg, nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(50), fee.Legacy)
+
+		logged := decisions(observed)
+		require.Len(t, logged, 1)
+		fields := logged[0].ContextMap()
+		assert.Equal(t, "41-42", fields["blockRange"])
+		assert.Equal(t, assets.NewWeiI(70).String(), fields["rawGasPrice"])
+		assert.Equal(t, true, fields["capped"])
+	})
+}
@@ -0,0 +1,122 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// dynamicFeeSupportCheckInterval is how often the latest block is checked for
+// a base fee with EVM.GasEstimator.EIP1559DynamicFees
+const dynamicFeeSupportCheckInterval = time.Minute
+
+// dynamicFeeSupport detects whether the chain supports EIP-1559 dynamic fees,
+// i.e. it has activated London and its blocks have a base fee, for estimators
+// with EVM.GasEstimator.EIP1559DynamicFees. Nodes of chains without London
+// reject dynamic fee transactions, so while the latest block has no base fee
+// the chain is legacy-only and dynamic fees fail with
+// ErrDynamicFeesNotSupported, rather than with an opaque error of the node
+// once the transaction is sent.
+//
+// The latest block is checked on start, and then periodically so that a chain
+// that activates London is picked up without a restart. If a check fails the
+// previous result is kept. Until the first check succeeds the chain is assumed
+// to support dynamic fees, as configured.
+type dynamicFeeSupport struct {
+	client   rpcClient
+	chainID  *big.Int
+	mode     string
+	interval time.Duration
+	lggr     logger.SugaredLogger
+
+	legacyOnly atomic.Bool
+	chStop     utils.StopChan
+	wg         sync.WaitGroup
+}
+
+func newDynamicFeeSupport(lggr logger.Logger, client rpcClient, chainID *big.Int, mode string) *dynamicFeeSupport {
+	return &dynamicFeeSupport{
+		client:   client,
+		chainID:  chainID,
+		mode:     mode,
+		interval: dynamicFeeSupportCheckInterval,
+		lggr:     logger.Sugared(lggr.Named("DynamicFeeSupport")),
+		chStop:   make(chan struct{}),
+	}
+}
+
+// start checks the latest block and then keeps checking it in the background
+// until close
+func (d *dynamicFeeSupport) start(ctx context.Context) {
+	if d == nil {
+		return
+	}
+	d.check(ctx)
+	d.wg.Add(1)
+	go d.run()
+}
+
+func (d *dynamicFeeSupport) close() {
+	if d == nil {
+		return
+	}
+	close(d.chStop)
+	d.wg.Wait()
+}
+
+func (d *dynamicFeeSupport) run() {
+	defer d.wg.Done()
+	t := time.NewTicker(utils.WithJitter(d.interval))
+	defer t.Stop()
+	for {
+		select {
+		case <-d.chStop:
+			return
+		case <-t.C:
+			ctx, cancel := d.chStop.CtxCancel(evmclient.ContextWithDefaultTimeout())
+			d.check(ctx)
+			cancel()
+		}
+	}
+}
+
+func (d *dynamicFeeSupport) check(ctx context.Context) {
+	var head *evmtypes.Head
+	if err := d.client.CallContext(ctx, &head, "eth_getBlockByNumber", "latest", false); err != nil {
+		d.lggr.Warnw("Failed to fetch the latest block to check for EIP-1559 support", "err", err)
+		return
+	}
+	if head == nil {
+		d.lggr.Warn("Failed to fetch the latest block to check for EIP-1559 support: the node returned no block")
+		return
+	}
+	legacyOnly := head.BaseFeePerGas == nil
+	if d.legacyOnly.Swap(legacyOnly) == legacyOnly {
+		return
+	}
+	if legacyOnly {
+		d.lggr.Warnw("EVM.GasEstimator.EIP1559DynamicFees is enabled but the chain does not support EIP-1559: the latest block has no base fee. "+
+			"Dynamic fees will fail until the chain activates London; set EIP1559DynamicFees = false to send legacy transactions", "blockNumber", head.Number)
+	} else {
+		d.lggr.Infow("The chain now supports EIP-1559, dynamic fees are enabled", "blockNumber", head.Number, "baseFee", head.BaseFeePerGas)
+	}
+}
+
+// err returns an ErrDynamicFeesNotSupported error if the chain is
+// legacy-only, or nil if it supports dynamic fees
+func (d *dynamicFeeSupport) err() error {
+	if d == nil || !d.legacyOnly.Load() {
+		return nil
+	}
+	return annotateError(&EstimationError{Reason: ErrDynamicFeesNotSupported,
+		Err: errors.New("EVM.GasEstimator.EIP1559DynamicFees is enabled but the chain does not support EIP-1559, the latest block has no base fee")}, d.chainID, d.mode)
+}
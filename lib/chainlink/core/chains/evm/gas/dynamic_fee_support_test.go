@@ -0,0 +1,87 @@
+package gas_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_DynamicFeeSupport(t *testing.T) {
+	t.Parallel()
+
+	newEstimator := func(t *testing.T, london *atomic.Bool) gas.EvmFeeEstimator {
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = "FixedPrice"
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasTipCapDefaultF = assets.GWei(1)
+		cfg.EvmGasPriceDefaultF = assets.GWei(20)
+		cfg.EvmGasFeeCapDefaultF = assets.GWei(100)
+
+		// the latest block has a base fee once the chain has activated London
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		ethClient.On("CallContext", mock.Anything, mock.Anything, "eth_getBlockByNumber", "latest", false).Run(func(args mock.Arguments) {
+			head := &evmtypes.Head{Number: 42}
+			if london.Load() {
+				head.BaseFeePerGas = assets.GWei(5)
+			}
+			*args.Get(1).(**evmtypes.Head) = head
+		}).Return(nil)
+
+		estimator := gas.NewEstimator(logger.TestLogger(t), ethClient, cfg, nil)
+		gas.SetDynamicFeeSupportCheckInterval(estimator, 10*time.Millisecond)
+		require.NoError(t, estimator.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, estimator.Close()) })
+		return estimator
+	}
+	getFee := func(t *testing.T, estimator gas.EvmFeeEstimator) error {
+		_, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil)
+		return err
+	}
+
+	t.Run("fails dynamic fees until the chain activates London", func(t *testing.T) {
+		var london atomic.Bool
+		estimator := newEstimator(t, &london)
+
+		err := getFee(t, estimator)
+		require.ErrorIs(t, err, gas.ErrDynamicFeesNotSupported)
+		var eErr *gas.EstimationError
+		require.ErrorAs(t, err, &eErr)
+		assert.Equal(t, testutils.FixtureChainID, eErr.ChainID)
+		assert.Equal(t, "FixedPrice", eErr.Mode)
+		assert.False(t, gas.IsBumpErr(err))
+
+		_, _, err = estimator.BumpFee(testutils.Context(t), gas.EvmFee{DynamicFeeCap: assets.GWei(10), DynamicTipCap: assets.GWei(1)}, 21000, nil, nil)
+		assert.ErrorIs(t, err, gas.ErrDynamicFeesNotSupported)
+		_, _, err = estimator.(*gas.WrappedEvmEstimator).GetDynamicFees(testutils.Context(t), 2, 21000, nil)
+		assert.ErrorIs(t, err, gas.ErrDynamicFeesNotSupported)
+
+		london.Store(true)
+		require.Eventually(t, func() bool { return getFee(t, estimator) == nil }, testutils.WaitTimeout(t), 10*time.Millisecond)
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(1), fee.DynamicTipCap)
+	})
+
+	t.Run("fails dynamic fees once the latest block has no base fee", func(t *testing.T) {
+		var london atomic.Bool
+		london.Store(true)
+		estimator := newEstimator(t, &london)
+		require.NoError(t, getFee(t, estimator))
+
+		london.Store(false)
+		require.Eventually(t, func() bool { return getFee(t, estimator) != nil }, testutils.WaitTimeout(t), 10*time.Millisecond)
+		assert.ErrorIs(t, getFee(t, estimator), gas.ErrDynamicFeesNotSupported)
+	})
+}
@@ -0,0 +1,110 @@
+package gas
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+var (
+	// ErrBumpLimitExceeded is returned when a bumped fee would exceed the
+	// configured max gas price. It is the same error as ErrBumpGasExceedsLimit.
+	ErrBumpLimitExceeded = ErrBumpGasExceedsLimit
+	// ErrStalePrice is returned when the estimator has no up to date price to
+	// estimate with, e.g. because it has not fetched one yet or failed to
+	// refresh a stale one
+	ErrStalePrice = errors.New("no up to date gas price available")
+	// ErrRPCFailure is returned when an RPC call needed for the estimate failed
+	ErrRPCFailure = errors.New("estimator RPC call failed")
+	// ErrWouldRevert is returned by BumpFee when the transaction reverts if
+	// simulated at the latest block, see EVM.GasEstimator.SimulateBeforeBump.
+	// Bumping its fee would only make the revert more expensive, so the caller
+	// should consider cancelling it instead.
+	ErrWouldRevert = errors.New("transaction would revert")
+	// ErrNoData is returned by estimators of a FallbackEstimator that have no
+	// data to estimate from, e.g. a BlockHistoryEstimator whose block history
+	// is empty, rather than estimating from the configured defaults
+	ErrNoData = errors.New("estimator has no data to estimate from")
+	// ErrTxCostExceedsBudget is returned when even the lowest viable fee would
+	// make the transaction cost more than EVM.GasEstimator.MaxTxCost, or when
+	// a bumped fee would
+	ErrTxCostExceedsBudget = errors.New("transaction cost exceeds budget")
+	// ErrDynamicFeesNotSupported is returned for dynamic fees when
+	// EVM.GasEstimator.EIP1559DynamicFees is enabled but the chain doesn't
+	// support EIP-1559, i.e. its latest block has no base fee. The caller should
+	// send a legacy transaction instead.
+	ErrDynamicFeesNotSupported = errors.New("dynamic fees are not supported by the chain")
+	// ErrInsufficientBalance is returned when even the minimum viable fee
+	// would make the transaction cost more than the balance given with
+	// WithAvailableBalance, or when a bumped fee would
+	ErrInsufficientBalance = errors.New("insufficient balance for the transaction fee")
+	// ErrEstimatorStopped is returned by calls made once the estimator has
+	// begun closing. They return immediately rather than racing the teardown.
+	ErrEstimatorStopped = errors.New("estimator is stopped")
+)
+
+// reasons are the sentinel errors that an EstimationError can carry, in order
+// of precedence
+var reasons = []error{ErrBumpLimitExceeded, ErrConnectivity, ErrBump, ErrStalePrice, ErrRPCFailure, ErrWouldRevert, ErrFeeOverflow, ErrNoData, ErrTxCostExceedsBudget, ErrDynamicFeesNotSupported, ErrInsufficientBalance, ErrEstimatorStopped}
+
+// EstimationError is the error returned by the estimators. Its message is the
+// message of the wrapped error, so it reads the same in logs as before, while
+// callers can use errors.Is to check its Reason and errors.As to inspect the
+// estimate that failed.
+type EstimationError struct {
+	// ChainID is the chain of the estimator, nil if the estimator is not chain aware
+	ChainID *big.Int
+	// Mode is the estimator mode, as in EVM.GasEstimator.Mode
+	Mode string
+	// Price is the price the estimator attempted, if any. With
+	// ErrTxCostExceedsBudget it is the total cost of the transaction instead.
+	Price *assets.Wei
+	// Limit is the configured limit the attempted price was checked against,
+	// if any, i.e. EVM.GasEstimator.MaxTxCost with ErrTxCostExceedsBudget
+	Limit *assets.Wei
+	// Reason is one of the sentinel errors of this package, or nil if the
+	// failure doesn't have a machine-readable reason
+	Reason error
+	// Err is the underlying error
+	Err error
+}
+
+func (e *EstimationError) Error() string {
+	if e.Err == nil && e.Reason != nil {
+		return e.Reason.Error()
+	}
+	return e.Err.Error()
+}
+
+func (e *EstimationError) Unwrap() error { return e.Err }
+
+// Cause implements the causer interface of github.com/pkg/errors
+func (e *EstimationError) Cause() error { return e.Err }
+
+func (e *EstimationError) Is(target error) bool { return e.Reason != nil && target == e.Reason }
+
+// annotateError wraps err in an EstimationError, if it isn't one already,
+// and records the chain ID and mode of the estimator that returned it
+func annotateError(err error, chainID *big.Int, mode string) error {
+	if err == nil {
+		return nil
+	}
+	var e *EstimationError
+	if !errors.As(err, &e) {
+		e = &EstimationError{Err: err}
+		err = e
+	}
+	e.ChainID = chainID
+	e.Mode = mode
+	if e.Reason == nil {
+		for _, reason := range reasons {
+			if errors.Is(e.Err, reason) {
+				e.Reason = reason
+				break
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,68 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+)
+
+// requireEstimationError asserts that err is an EstimationError returned by
+// an estimator in the given mode, with the given reason and message
+func requireEstimationError(t *testing.T, err error, mode string, reason error, msg string) *gas.EstimationError {
+	t.Helper()
+	require.Error(t, err)
+	var e *gas.EstimationError
+	require.True(t, errors.As(err, &e), "expected an EstimationError, got %T: %v", err, err)
+	assert.Equal(t, mode, e.Mode)
+	assert.Equal(t, reason, e.Reason)
+	if reason != nil {
+		assert.ErrorIs(t, err, reason)
+	}
+	assert.EqualError(t, err, msg)
+	return e
+}
+
+func TestEstimationError(t *testing.T) {
+	t.Parallel()
+
+	t.Run("preserves the message and cause of the wrapped error", func(t *testing.T) {
+		cause := errors.New("kaboom")
+		err := &gas.EstimationError{Reason: gas.ErrRPCFailure, Err: errors.Wrap(cause, "failed to fetch")}
+		assert.EqualError(t, err, "failed to fetch: kaboom")
+		assert.ErrorIs(t, err, gas.ErrRPCFailure)
+		assert.ErrorIs(t, err, cause)
+		assert.Equal(t, cause, errors.Cause(err))
+		assert.NotErrorIs(t, err, gas.ErrStalePrice)
+	})
+
+	t.Run("can be found through further wrapping", func(t *testing.T) {
+		err := errors.Wrap(&gas.EstimationError{
+			ChainID: big.NewInt(42),
+			Mode:    "BlockHistory",
+			Price:   assets.GWei(2),
+			Limit:   assets.GWei(1),
+			Reason:  gas.ErrBumpLimitExceeded,
+			Err:     gas.ErrBumpGasExceedsLimit,
+		}, "failed to bump")
+		assert.ErrorIs(t, err, gas.ErrBumpLimitExceeded)
+		assert.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+
+		var e *gas.EstimationError
+		require.True(t, errors.As(err, &e))
+		assert.Equal(t, big.NewInt(42), e.ChainID)
+		assert.Equal(t, assets.GWei(2), e.Price)
+		assert.Equal(t, assets.GWei(1), e.Limit)
+	})
+
+	t.Run("without a reason only matches the wrapped error", func(t *testing.T) {
+		err := &gas.EstimationError{Err: gas.ErrConnectivity}
+		assert.ErrorIs(t, err, gas.ErrConnectivity)
+		assert.NotErrorIs(t, err, gas.ErrBumpLimitExceeded)
+	})
+}
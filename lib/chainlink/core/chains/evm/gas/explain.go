@@ -0,0 +1,148 @@
+package gas
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// The limits that can clamp an explained fee, see FeeExplanation.Clamps
+const (
+	// ClampPriceMin is EVM.GasEstimator.PriceMin
+	ClampPriceMin = "PriceMin"
+	// ClampPriceMax is EVM.GasEstimator.PriceMax
+	ClampPriceMax = "PriceMax"
+	// ClampTipCapMin is EVM.GasEstimator.TipCapMin
+	ClampTipCapMin = "TipCapMin"
+	// ClampMaxGasPrice is the max gas price of the call, i.e. the lower of
+	// the max gas price of the sending key and EVM.GasEstimator.PriceMax
+	ClampMaxGasPrice = "MaxGasPrice"
+	// ClampLimitMultiplier is EVM.GasEstimator.LimitMultiplier
+	ClampLimitMultiplier = "LimitMultiplier"
+	// ClampNodeMinPrice is the minimum gas price of the node, see
+	// EVM.GasEstimator.NodeMinPriceSync
+	ClampNodeMinPrice = "NodeMinPrice"
+	// ClampFeeProfile is the fee profile selected with WithProfile
+	ClampFeeProfile = "FeeProfile"
+	// ClampInclusionTarget is the inclusion target of WithInclusionBlocks
+	ClampInclusionTarget = "InclusionTarget"
+	// ClampMaxTxCost is EVM.GasEstimator.MaxTxCost
+	ClampMaxTxCost = "MaxTxCost"
+	// ClampAvailableBalance is the balance given with WithAvailableBalance
+	ClampAvailableBalance = "AvailableBalance"
+	// ClampFeeRounding is EVM.GasEstimator.FeeRounding
+	ClampFeeRounding = "FeeRounding"
+)
+
+// FeeExplanation is the breakdown of how an estimator computed a fee
+type FeeExplanation struct {
+	// Mode is the estimator mode, as in EVM.GasEstimator.Mode
+	Mode string
+	// Fee and ChainSpecificFeeLimit are the fee and limit that GetFee would
+	// currently return
+	Fee                   EvmFee
+	ChainSpecificFeeLimit uint32
+
+	// Percentile is EVM.GasEstimator.BlockHistory.TransactionPercentile, and
+	// BlocksSampled and TransactionsSampled are the number of blocks and
+	// transactions the percentile was taken of. They are only set by the
+	// BlockHistory estimator.
+	Percentile          uint16
+	BlocksSampled       int
+	TransactionsSampled int
+
+	// GasPrice and TipCap are the raw prices before any limits were applied,
+	// i.e. the percentile price of the sampled transactions or the price
+	// suggested by the node. They are nil if there was no such price.
+	GasPrice *assets.Wei
+	TipCap   *assets.Wei
+	// BaseFee is the base fee the dynamic fee cap was computed from
+	BaseFee *assets.Wei
+	// UsedDefault is true if there was no raw price, and
+	// EVM.GasEstimator.PriceDefault or TipCapDefault was used instead
+	UsedDefault bool
+	// Clamps are the limits that changed the fee or fee limit, in the order
+	// they were applied, e.g. ClampPriceMax
+	Clamps []string
+}
+
+// FeeExplainer is implemented by the estimators that can explain their fees.
+// ExplainFee must not change the state of the estimator, so it estimates from
+// the prices the estimator currently has and never refreshes them.
+type FeeExplainer interface {
+	ExplainFee(ctx context.Context, calldata []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, dynamic bool) (FeeExplanation, error)
+}
+
+// EvmFeeExplainer is implemented by the EvmFeeEstimator returned by
+// NewEstimator
+type EvmFeeExplainer interface {
+	ExplainFee(ctx context.Context, calldata []byte, feeLimit uint32, maxFeePrice *assets.Wei) (FeeExplanation, error)
+}
+
+var _ EvmFeeExplainer = (*WrappedEvmEstimator)(nil)
+
+// ExplainFee returns the fee that GetFee would currently return along with a
+// breakdown of how it was computed. The fee of the estimator goes through the
+// same steps as with GetFee, i.e. the fee profile selected with WithProfile,
+// EVM.GasEstimator.MaxTxCost, WithAvailableBalance and rounding, each of which
+// is recorded in Clamps if it changes the fee, and fails where GetFee would.
+//
+// It is read-only: it neither shares nor caches estimates like GetFee, nor
+// estimates the gas limit with EVM.GasEstimator.EstimateGasLimit.
+func (e WrappedEvmEstimator) ExplainFee(ctx context.Context, calldata []byte, feeLimit uint32, maxFeePrice *assets.Wei) (FeeExplanation, error) {
+	if !e.calls.enter() {
+		return FeeExplanation{}, errStopped("WrappedEvmEstimator")
+	}
+	defer e.calls.exit()
+	explainer, ok := e.EvmEstimator.(FeeExplainer)
+	if !ok {
+		return FeeExplanation{}, errors.Errorf("estimator %s does not support fee explanations", e.EvmEstimator.Name())
+	}
+	ctx, _, profile, maxFeePrice := e.resolveFeeConfig(ctx, maxFeePrice)
+	ex, err := explainer.ExplainFee(ctx, calldata, feeLimit, maxFeePrice, e.EIP1559Enabled)
+	if err != nil {
+		return ex, err
+	}
+
+	if limit := e.profileFeeLimit(profile, ex.ChainSpecificFeeLimit); limit != ex.ChainSpecificFeeLimit {
+		ex.Clamps = append(ex.Clamps, ClampFeeProfile)
+		ex.ChainSpecificFeeLimit = limit
+	}
+	fee, err := e.applyTxCostBudget(ex.Fee, ex.ChainSpecificFeeLimit)
+	if err != nil {
+		return ex, err
+	}
+	ex.setFee(ClampMaxTxCost, fee)
+	if fee, err = e.applyAvailableBalance(ctx, ex.Fee, ex.ChainSpecificFeeLimit); err != nil {
+		return ex, err
+	}
+	ex.setFee(ClampAvailableBalance, fee)
+	ex.setFee(ClampFeeRounding, e.roundFee(ex.Fee, maxFeePrice, e.maxCostPrice(ctx, ex.ChainSpecificFeeLimit)))
+	ex.Fee = e.withValidity(ex.Fee)
+	return ex, nil
+}
+
+// setFee sets the explained fee to fee, recording clamp if it changed the
+// prices
+func (ex *FeeExplanation) setFee(clamp string, fee EvmFee) {
+	if weiChanged(ex.Fee.Legacy, fee.Legacy) || weiChanged(ex.Fee.DynamicFeeCap, fee.DynamicFeeCap) || weiChanged(ex.Fee.DynamicTipCap, fee.DynamicTipCap) {
+		ex.Clamps = append(ex.Clamps, clamp)
+	}
+	ex.Fee = fee
+}
+
+func weiChanged(before, after *assets.Wei) bool {
+	if before == nil || after == nil {
+		return before != after
+	}
+	return before.Cmp(after) != 0
+}
+
+// clampIfChanged records clamp if applying it changed the value
+func (ex *FeeExplanation) clampIfChanged(clamp string, before, after *assets.Wei) {
+	if before != nil && after != nil && before.Cmp(after) != 0 {
+		ex.Clamps = append(ex.Clamps, clamp)
+	}
+}
@@ -0,0 +1,319 @@
+package gas_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestBlockHistoryEstimator_ExplainFee(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 100_000
+	maxGasPrice := assets.NewWeiI(1000)
+
+	newLegacyEstimator := func(t *testing.T, cfg *gas.MockConfig) *gas.BlockHistoryEstimator {
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), cfg)
+		gas.SimulateStart(t, bhe)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 2, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(40, 200)},
+			{Number: 1, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(5, 20, 30)},
+		})
+		bhe.Recalculate(cltest.Head(2))
+		return bhe
+	}
+	newLegacyConfig := func() *gas.MockConfig {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasLimitMultiplierF = 1
+		return cfg
+	}
+
+	t.Run("explains the legacy gas price from the sampled transactions", func(t *testing.T) {
+		bhe := newLegacyEstimator(t, newLegacyConfig())
+
+		ex, err := bhe.ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, false)
+		require.NoError(t, err)
+		assert.Equal(t, "BlockHistory", ex.Mode)
+		assert.Equal(t, uint16(50), ex.Percentile)
+		assert.Equal(t, 2, ex.BlocksSampled)
+		assert.Equal(t, 5, ex.TransactionsSampled)
+		assert.Equal(t, assets.NewWeiI(30), ex.GasPrice)
+		assert.Nil(t, ex.TipCap)
+		assert.False(t, ex.UsedDefault)
+		assert.Empty(t, ex.Clamps)
+		assert.Equal(t, assets.NewWeiI(30), ex.Fee.Legacy)
+		assert.Equal(t, gasLimit, ex.ChainSpecificFeeLimit)
+
+		gasPrice, limit, err := bhe.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gasPrice, ex.Fee.Legacy)
+		assert.Equal(t, limit, ex.ChainSpecificFeeLimit)
+	})
+
+	t.Run("reports the limits that clamped the legacy gas price", func(t *testing.T) {
+		cfg := newLegacyConfig()
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(35)
+		cfg.EvmGasLimitMultiplierF = 1.5
+		bhe := newLegacyEstimator(t, cfg)
+
+		ex, err := bhe.ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, false)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), ex.GasPrice)
+		assert.Equal(t, assets.NewWeiI(35), ex.Fee.Legacy)
+		assert.Equal(t, uint32(150_000), ex.ChainSpecificFeeLimit)
+		assert.Equal(t, []string{gas.ClampPriceMin, gas.ClampLimitMultiplier}, ex.Clamps)
+
+		ex, err = bhe.ExplainFee(testutils.Context(t), nil, gasLimit, assets.NewWeiI(32), false)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(32), ex.Fee.Legacy)
+		assert.Equal(t, []string{gas.ClampPriceMin, gas.ClampMaxGasPrice, gas.ClampLimitMultiplier}, ex.Clamps)
+
+		cfg = newLegacyConfig()
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(25)
+		bhe = newLegacyEstimator(t, cfg)
+
+		ex, err = bhe.ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, false)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(25), ex.Fee.Legacy)
+		assert.Equal(t, []string{gas.ClampPriceMax}, ex.Clamps)
+	})
+
+	t.Run("explains the dynamic fee from the sampled transactions and latest base fee", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(4)
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.BlockHistoryEstimatorInclusionPercentilesF = []string{"1:100"}
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), cfg)
+		gas.SimulateStart(t, bhe)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 1, Hash: utils.NewHash(), BaseFeePerGas: assets.NewWeiI(100), Transactions: cltest.DynamicFeeTransactionsFromTipCaps(1, 2, 3, 4, 5)},
+		})
+		bhe.Recalculate(cltest.Head(1))
+		head := cltest.Head(2)
+		head.BaseFeePerGas = assets.NewWeiI(200)
+		bhe.OnNewLongestChain(testutils.Context(t), head)
+
+		ex, err := bhe.ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, true)
+		require.NoError(t, err)
+		assert.Equal(t, 1, ex.BlocksSampled)
+		assert.Equal(t, 5, ex.TransactionsSampled)
+		assert.Equal(t, assets.NewWeiI(3), ex.TipCap)
+		assert.Equal(t, assets.NewWeiI(200), ex.BaseFee)
+		assert.Equal(t, assets.NewWeiI(4), ex.Fee.DynamicTipCap)
+		assert.Equal(t, assets.NewWeiI(204), ex.Fee.DynamicFeeCap)
+		assert.Equal(t, []string{gas.ClampTipCapMin}, ex.Clamps)
+
+		fee, limit, err := bhe.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, fee.TipCap, ex.Fee.DynamicTipCap)
+		assert.Equal(t, fee.FeeCap, ex.Fee.DynamicFeeCap)
+		assert.Equal(t, limit, ex.ChainSpecificFeeLimit)
+
+		ex, err = bhe.ExplainFee(testutils.Context(t), nil, gasLimit, assets.NewWeiI(150), true)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(150), ex.Fee.DynamicFeeCap)
+		assert.Equal(t, []string{gas.ClampTipCapMin, gas.ClampMaxGasPrice}, ex.Clamps)
+
+		// the tip cap of the inclusion target replaces the estimator's
+		ctx := gas.WithInclusionBlocks(testutils.Context(t), 1)
+		ex, err = bhe.ExplainFee(ctx, nil, gasLimit, maxGasPrice, true)
+		require.NoError(t, err)
+		fee, _, err = bhe.GetDynamicFee(ctx, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(5), ex.Fee.DynamicTipCap)
+		assert.Equal(t, fee.TipCap, ex.Fee.DynamicTipCap)
+		assert.Equal(t, fee.FeeCap, ex.Fee.DynamicFeeCap)
+		assert.Equal(t, uint32(1), ex.Fee.InclusionBlocks)
+		assert.Equal(t, []string{gas.ClampTipCapMin, gas.ClampInclusionTarget}, ex.Clamps)
+	})
+
+	t.Run("does not change the estimator state", func(t *testing.T) {
+		bhe := newLegacyEstimator(t, newLegacyConfig())
+		blocks := gas.GetRollingBlockHistory(bhe)
+
+		_, err := bhe.ExplainFee(testutils.Context(t), nil, gasLimit, assets.NewWeiI(10), false)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), gas.GetGasPrice(bhe))
+		assert.Equal(t, blocks, gas.GetRollingBlockHistory(bhe))
+	})
+
+	t.Run("fails if not started or before the first estimation", func(t *testing.T) {
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), newLegacyConfig())
+
+		_, err := bhe.ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, false)
+		requireEstimationError(t, err, "BlockHistory", nil, "BlockHistoryEstimator is not started; cannot estimate gas")
+
+		gas.SimulateStart(t, bhe)
+		_, err = bhe.ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, false)
+		requireEstimationError(t, err, "BlockHistory", gas.ErrStalePrice, "BlockHistoryEstimator has not finished the first gas estimation yet, likely because a failure on start")
+	})
+}
+
+func TestL2SuggestedEstimator_ExplainFee(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.NewWeiI(100000)
+
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmGasBaseFeeBufferMultiplierF = 1
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(5)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		return cfg
+	}
+	// newEstimator starts an estimator whose only refresh suggests the given
+	// prices, so ExplainFee refreshing would fail the strict mock
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, gasPrice, tipCap, baseFee int64) gas.EvmEstimator {
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			gas.SetRPCPrice(elems[0].Result, gasPrice)
+			gas.SetRPCPrice(elems[1].Result, tipCap)
+			require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{"baseFeePerGas":"%s"}`, hexutil.EncodeBig(big.NewInt(baseFee)))), elems[2].Result))
+		}).Once()
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		return o
+	}
+
+	t.Run("explains the suggested gas price", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 42, 7, 100)
+
+		ex, err := o.(gas.FeeExplainer).ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, false)
+		require.NoError(t, err)
+		assert.Equal(t, "L2Suggested", ex.Mode)
+		assert.Equal(t, assets.NewWeiI(42), ex.GasPrice)
+		assert.Equal(t, assets.NewWeiI(42), ex.Fee.Legacy)
+		assert.Equal(t, gasLimit, ex.ChainSpecificFeeLimit)
+		assert.Empty(t, ex.Clamps)
+
+		_, err = o.(gas.FeeExplainer).ExplainFee(testutils.Context(t), nil, gasLimit, assets.NewWeiI(40), false)
+		requireEstimationError(t, err, "L2Suggested", nil, "estimated gas price: 42 wei is greater than the maximum gas price configured: 40 wei")
+	})
+
+	t.Run("explains the dynamic fee from the suggested tip cap and latest base fee", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 42, 7, 100)
+
+		ex, err := o.(gas.FeeExplainer).ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, true)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(7), ex.TipCap)
+		assert.Equal(t, assets.NewWeiI(100), ex.BaseFee)
+		assert.Equal(t, assets.NewWeiI(7), ex.Fee.DynamicTipCap)
+		assert.Equal(t, assets.NewWeiI(107), ex.Fee.DynamicFeeCap)
+		assert.Empty(t, ex.Clamps)
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, fee.TipCap, ex.Fee.DynamicTipCap)
+		assert.Equal(t, fee.FeeCap, ex.Fee.DynamicFeeCap)
+
+		ex, err = o.(gas.FeeExplainer).ExplainFee(testutils.Context(t), nil, gasLimit, assets.NewWeiI(50), true)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(50), ex.Fee.DynamicFeeCap)
+		assert.Equal(t, []string{gas.ClampMaxGasPrice}, ex.Clamps)
+	})
+
+	t.Run("reports the default tip cap used for a zero suggestion", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 42, 0, 100)
+
+		ex, err := o.(gas.FeeExplainer).ExplainFee(testutils.Context(t), nil, gasLimit, maxGasPrice, true)
+		require.NoError(t, err)
+		assert.True(t, ex.UsedDefault)
+		assert.True(t, ex.TipCap.IsZero())
+		assert.Equal(t, assets.NewWeiI(5), ex.Fee.DynamicTipCap)
+	})
+}
+
+func TestWrappedEvmEstimator_ExplainFee(t *testing.T) {
+	t.Parallel()
+
+	t.Run("explains with the wrapper's max price and fee type", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(25)
+		cfg.EvmGasLimitMultiplierF = 1
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), cfg)
+		gas.SimulateStart(t, bhe)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 1, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(10, 20, 30)},
+		})
+		bhe.Recalculate(cltest.Head(1))
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+
+		ex, err := estimator.(gas.EvmFeeExplainer).ExplainFee(testutils.Context(t), nil, 100_000, assets.NewWeiI(15))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(15), ex.Fee.Legacy)
+		assert.Equal(t, []string{gas.ClampMaxGasPrice}, ex.Clamps)
+	})
+
+	t.Run("explains the steps applied after the estimator like GetFee", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(25)
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.EvmGasFeeRoundingF = assets.NewWeiI(15)
+		// 18 wei for the fee limit
+		cfg.EvmGasMaxTxCostF = assets.NewWeiI(1_800_000)
+		bhe := newBlockHistoryEstimator(t, evmtest.NewEthClientMockWithDefaultChain(t), cfg)
+		gas.SimulateStart(t, bhe)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{Number: 1, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(10, 20, 30)},
+		})
+		bhe.Recalculate(cltest.Head(1))
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+
+		ex, err := estimator.(gas.EvmFeeExplainer).ExplainFee(testutils.Context(t), nil, 100_000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(20), ex.GasPrice)
+		// lowered to the budget, then rounded down to fit it
+		assert.Equal(t, assets.NewWeiI(15), ex.Fee.Legacy)
+		assert.Equal(t, []string{gas.ClampMaxTxCost, gas.ClampFeeRounding}, ex.Clamps)
+
+		fee, limit, err := estimator.GetFee(testutils.Context(t), nil, 100_000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, fee.Legacy, ex.Fee.Legacy)
+		assert.Equal(t, limit, ex.ChainSpecificFeeLimit)
+
+		// and fails where GetFee would
+		_, err = estimator.(gas.EvmFeeExplainer).ExplainFee(gas.WithAvailableBalance(testutils.Context(t), assets.NewWeiI(1000), nil), nil, 100_000, nil)
+		assert.ErrorIs(t, err, gas.ErrInsufficientBalance)
+	})
+
+	t.Run("fails for estimators that can't explain their fees", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil)
+
+		_, err := estimator.(gas.EvmFeeExplainer).ExplainFee(testutils.Context(t), nil, 100_000, nil)
+		assert.ErrorContains(t, err, "FixedPriceEstimator does not support fee explanations")
+	})
+}
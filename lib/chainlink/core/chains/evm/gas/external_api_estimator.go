@@ -0,0 +1,463 @@
+package gas
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	commonfee "github.com/smartcontractkit/chainlink/v2/common/fee"
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// The speed tiers of EVM.GasEstimator.ExternalAPI.Speed
+const (
+	ExternalAPISpeedSafeLow  = "SafeLow"
+	ExternalAPISpeedStandard = "Standard"
+	ExternalAPISpeedFast     = "Fast"
+)
+
+// maxExternalAPIResponseSize is the largest response read from the API, gas
+// oracles respond with a few hundred bytes
+const maxExternalAPIResponseSize = 1 << 20
+
+// ExternalAPIConfig is the config needed by the ExternalAPIEstimator
+type ExternalAPIConfig interface {
+	dynamicFeeBumpConfig
+	feeCapConfig
+	EvmEIP1559DynamicFees() bool
+	EvmGasBumpThreshold() uint64
+	EvmGasLimitMultiplier() float32
+	EvmGasPriceStaleThreshold() time.Duration
+	EvmMinGasPriceWei() *assets.Wei
+	ExternalAPIEstimatorBaseFeePath() string
+	ExternalAPIEstimatorFastPath() string
+	ExternalAPIEstimatorPricesIncludeBaseFee() bool
+	ExternalAPIEstimatorSafeLowPath() string
+	ExternalAPIEstimatorSpeed() string
+	ExternalAPIEstimatorStandardPath() string
+	ExternalAPIEstimatorURL() *url.URL
+	ExternalAPIEstimatorUnit() string
+}
+
+var _ EvmEstimator = (*externalAPIEstimator)(nil)
+
+// externalAPIEstimator estimates fees with the prices published by a gas
+// oracle HTTP API, such as the Polygon gas station or the Etherscan gas
+// tracker, at EVM.GasEstimator.ExternalAPI.URL. It polls the API for the price
+// of the EVM.GasEstimator.ExternalAPI.Speed tier and the base fee, which it
+// reads from the JSON response at the configured paths.
+//
+// If the API is down or responds with malformed prices, the last prices it
+// returned are served until they are older than
+// EVM.GasEstimator.PriceStaleThreshold, after which estimation fails with
+// ErrStalePrice. Bumping never depends on the API: the original fee is bumped
+// locally, and fresh prices of the API can only raise the bump.
+type externalAPIEstimator struct {
+	utils.StartStopOnce
+
+	cfg        ExternalAPIConfig
+	client     *http.Client
+	pollPeriod time.Duration
+	lggr       logger.SugaredLogger
+	chainID    big.Int
+	metrics    *estimatorMetrics
+	now        func() time.Time
+
+	pricesMu  sync.RWMutex
+	gasPrice  *assets.Wei
+	tipCap    *assets.Wei
+	baseFee   *assets.Wei
+	updatedAt time.Time
+	lastErr   error
+
+	health refreshHealth
+
+	chInitialised chan struct{}
+	chStop        utils.StopChan
+	chDone        chan struct{}
+}
+
+// NewExternalAPIEstimator returns a new "ExternalAPI" estimator
+func NewExternalAPIEstimator(lggr logger.Logger, cfg ExternalAPIConfig, chainID big.Int) EvmEstimator {
+	return &externalAPIEstimator{
+		cfg:           cfg,
+		client:        &http.Client{},
+		pollPeriod:    10 * time.Second,
+		lggr:          logger.Sugared(lggr.Named("ExternalAPIEstimator")),
+		chainID:       chainID,
+		metrics:       newEstimatorMetrics(chainID, "ExternalAPI"),
+		now:           time.Now,
+		chInitialised: make(chan struct{}),
+		chStop:        make(chan struct{}),
+		chDone:        make(chan struct{}),
+	}
+}
+
+func (e *externalAPIEstimator) Name() string {
+	return e.lggr.Name()
+}
+
+func (e *externalAPIEstimator) Start(context.Context) error {
+	return e.StartOnce("ExternalAPIEstimator", func() error {
+		go e.run()
+		<-e.chInitialised
+		return nil
+	})
+}
+
+func (e *externalAPIEstimator) Close() error {
+	return e.StopOnce("ExternalAPIEstimator", func() error {
+		close(e.chStop)
+		<-e.chDone
+		return nil
+	})
+}
+
+// HealthReport reports the estimator unhealthy if the last
+// healthFailureThreshold requests to the API failed or the prices are older
+// than EVM.GasEstimator.PriceStaleThreshold
+func (e *externalAPIEstimator) HealthReport() map[string]error {
+	err := e.StartStopOnce.Healthy()
+	if err == nil {
+		err = e.health.check(e.cfg.EvmGasPriceStaleThreshold())
+	}
+	return map[string]error{e.Name(): err}
+}
+
+func (e *externalAPIEstimator) run() {
+	defer close(e.chDone)
+
+	_ = e.refresh()
+	close(e.chInitialised)
+
+	for {
+		t := time.NewTimer(utils.WithJitter(e.pollPeriod))
+		select {
+		case <-e.chStop:
+			t.Stop()
+			return
+		case <-t.C:
+			_ = e.refresh()
+		}
+	}
+}
+
+// refresh fetches the prices from the API, keeping the previous prices if it
+// fails
+func (e *externalAPIEstimator) refresh() (err error) {
+	defer func() { e.health.record(err) }()
+
+	ctx, cancel := e.chStop.CtxCancel(evmclient.ContextWithDefaultTimeout())
+	defer cancel()
+
+	gasPrice, tipCap, baseFee, err := e.fetchPrices(ctx)
+
+	e.pricesMu.Lock()
+	defer e.pricesMu.Unlock()
+	if err != nil {
+		e.lggr.Warnw("Failed to refresh prices from the external gas API, keeping the previous prices", "err", err, "updatedAt", e.updatedAt)
+		e.lastErr = err
+		return err
+	}
+	e.gasPrice, e.tipCap, e.baseFee = gasPrice, tipCap, baseFee
+	e.updatedAt, e.lastErr = e.now(), nil
+	e.metrics.setGasPrice(gasPrice)
+	e.metrics.setTipCap(tipCap)
+	e.metrics.setBaseFee(baseFee)
+	e.lggr.Debugw("Refreshed prices from the external gas API", "speed", e.cfg.ExternalAPIEstimatorSpeed(), "gasPrice", gasPrice, "tipCap", tipCap, "baseFee", baseFee)
+	return nil
+}
+
+// fetchPrices queries the API for the gas price and tip cap of the
+// EVM.GasEstimator.ExternalAPI.Speed tier and the base fee. The tier's price
+// is a tip, unless EVM.GasEstimator.ExternalAPI.PricesIncludeBaseFee is set,
+// in which case it is a gas price and the tip is what it leaves over the base
+// fee.
+func (e *externalAPIEstimator) fetchPrices(ctx context.Context) (gasPrice, tipCap, baseFee *assets.Wei, err error) {
+	u := e.cfg.ExternalAPIEstimatorURL()
+	if u == nil {
+		return nil, nil, nil, errors.New("EVM.GasEstimator.ExternalAPI.URL is not set")
+	}
+	body, err := getJSON(ctx, e.client, u, "external gas API")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	speed, unit := e.cfg.ExternalAPIEstimatorSpeed(), e.cfg.ExternalAPIEstimatorUnit()
+	price, err := externalAPIPrice(body, e.speedPath(speed), unit)
+	if err != nil {
+		return nil, nil, nil, errors.Wrapf(err, "malformed %s price from the external gas API", speed)
+	}
+	if baseFee, err = externalAPIPrice(body, e.cfg.ExternalAPIEstimatorBaseFeePath(), unit); err != nil {
+		return nil, nil, nil, errors.Wrap(err, "malformed base fee from the external gas API")
+	}
+	if !e.cfg.ExternalAPIEstimatorPricesIncludeBaseFee() {
+		return baseFee.Add(price), price, baseFee, nil
+	}
+	tipCap = assets.NewWeiI(0)
+	if price.Cmp(baseFee) > 0 {
+		tipCap = price.Sub(baseFee)
+	}
+	return price, tipCap, baseFee, nil
+}
+
+// speedPath returns the path of the price of the speed tier in the response
+func (e *externalAPIEstimator) speedPath(speed string) string {
+	switch speed {
+	case ExternalAPISpeedSafeLow:
+		return e.cfg.ExternalAPIEstimatorSafeLowPath()
+	case ExternalAPISpeedFast:
+		return e.cfg.ExternalAPIEstimatorFastPath()
+	default:
+		return e.cfg.ExternalAPIEstimatorStandardPath()
+	}
+}
+
+// getJSON queries u and decodes its JSON response, keeping numbers as
+// json.Number. name is the name of the API in errors.
+func getJSON(ctx context.Context, client *http.Client, u *url.URL, name string) (interface{}, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create request to the %s", name)
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := client.Do(req)
+	if err != nil {
+		// the URL may contain an API key, so it is left out of the error
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return nil, errors.Wrapf(err, "failed to query the %s", name)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("%s responded with status %s", name, res.Status)
+	}
+
+	var body interface{}
+	dec := json.NewDecoder(io.LimitReader(res.Body, maxExternalAPIResponseSize))
+	dec.UseNumber()
+	if err = dec.Decode(&body); err != nil {
+		return nil, errors.Wrapf(err, "malformed response from the %s", name)
+	}
+	return body, nil
+}
+
+// externalAPIPrice returns the price at the dot separated path of the decoded
+// JSON body, in unit. Numeric keys index into arrays. The price may be a JSON
+// number or a string of a decimal number, as with the Etherscan gas tracker,
+// and fractions of a wei are truncated.
+func externalAPIPrice(body interface{}, path, unit string) (*assets.Wei, error) {
+	d, err := jsonDecimal(body, path)
+	if err != nil {
+		return nil, err
+	}
+	if d.IsNegative() {
+		return nil, errors.Errorf("field %s is negative: %s", path, d)
+	}
+	if unit == "gwei" {
+		d = d.Shift(9)
+	}
+	return assets.NewWei(d.BigInt()), nil
+}
+
+// jsonDecimal returns the number at the dot separated path of the decoded JSON
+// body, see externalAPIPrice
+func jsonDecimal(body interface{}, path string) (decimal.Decimal, error) {
+	v := body
+	for _, key := range strings.Split(path, ".") {
+		found := false
+		switch node := v.(type) {
+		case map[string]interface{}:
+			v, found = node[key]
+		case []interface{}:
+			if i, err := strconv.Atoi(key); err == nil && i >= 0 && i < len(node) {
+				v, found = node[i], true
+			}
+		}
+		if !found {
+			return decimal.Decimal{}, errors.Errorf("field %s not found", path)
+		}
+	}
+
+	var s string
+	switch n := v.(type) {
+	case json.Number:
+		s = n.String()
+	case string:
+		s = n
+	default:
+		return decimal.Decimal{}, errors.Errorf("field %s is not a number: %v", path, v)
+	}
+	d, err := decimal.NewFromString(s)
+	if err != nil {
+		return decimal.Decimal{}, errors.Errorf("field %s is not a number: %q", path, s)
+	}
+	return d, nil
+}
+
+// getPrices returns the last prices fetched from the API, or an ErrStalePrice
+// error if there are none or they are older than
+// EVM.GasEstimator.PriceStaleThreshold
+func (e *externalAPIEstimator) getPrices() (gasPrice, tipCap, baseFee *assets.Wei, err error) {
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+	if e.updatedAt.IsZero() {
+		return nil, nil, nil, &EstimationError{Reason: ErrStalePrice, Err: e.withLastErr("ExternalAPIEstimator has not fetched any prices from the external gas API yet")}
+	}
+	if threshold := e.cfg.EvmGasPriceStaleThreshold(); threshold > 0 {
+		if age := e.now().Sub(e.updatedAt); age > threshold {
+			return nil, nil, nil, &EstimationError{Reason: ErrStalePrice, Err: e.withLastErr(
+				"prices were last fetched from the external gas API %s ago, more than EVM.GasEstimator.PriceStaleThreshold of %s", age, threshold)}
+		}
+	}
+	return e.gasPrice, e.tipCap, e.baseFee, nil
+}
+
+// withLastErr returns an error with the message, wrapping the error of the
+// last failed refresh if there is one. pricesMu must be held.
+func (e *externalAPIEstimator) withLastErr(format string, args ...interface{}) error {
+	if e.lastErr == nil {
+		return errors.Errorf(format, args...)
+	}
+	return errors.Wrapf(e.lastErr, format, args...)
+}
+
+// decisionInputs returns the time of the last refresh and the prices of the
+// API for the decision log
+func (e *externalAPIEstimator) decisionInputs() []interface{} {
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+	return []interface{}{"refreshedAt", e.updatedAt, "speed", e.cfg.ExternalAPIEstimatorSpeed(), "apiGasPrice", e.gasPrice, "apiTipCap", e.tipCap, "apiBaseFee", e.baseFee}
+}
+
+func (e *externalAPIEstimator) OnNewLongestChain(context.Context, *evmtypes.Head) {}
+
+// GetLegacyGas returns the gas price of the speed tier, within
+// EVM.GasEstimator.PriceMin and the max gas price
+func (e *externalAPIEstimator) GetLegacyGas(_ context.Context, _ []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, _ ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &e.chainID, "ExternalAPI") }()
+	ok := e.IfStarted(func() {
+		gasPrice, _, _, err = e.getPrices()
+	})
+	if !ok {
+		return nil, 0, errors.New("ExternalAPIEstimator is not started; cannot estimate gas")
+	} else if err != nil {
+		return nil, 0, err
+	}
+	if min := e.cfg.EvmMinGasPriceWei(); min != nil {
+		gasPrice = assets.WeiMax(gasPrice, min)
+	}
+	estimatedGasPrice := gasPrice
+	gasPrice, chainSpecificGasLimit = capGasPrice(gasPrice, maxGasPriceWei, e.cfg.EvmMaxGasPriceWei(), gasLimit, e.cfg.EvmGasLimitMultiplier())
+	e.metrics.recordCap(estimatedGasPrice, gasPrice)
+	return
+}
+
+// GetDynamicFee returns the tip cap of the speed tier, and a fee cap of the
+// base fee of the API buffered for
+// EVM.GasEstimator.BlockHistory.EIP1559FeeCapBufferBlocks plus the tip cap.
+// As with the other estimators, the tip cap is capped at the max gas price and
+// raised to at least EVM.GasEstimator.TipCapMin.
+func (e *externalAPIEstimator) GetDynamicFee(_ context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &e.chainID, "ExternalAPI") }()
+	if !e.cfg.EvmEIP1559DynamicFees() {
+		return fee, 0, errors.New("Can't get dynamic fee, EIP1559 is disabled")
+	}
+
+	var tipCap, baseFee *assets.Wei
+	ok := e.IfStarted(func() {
+		_, tipCap, baseFee, err = e.getPrices()
+	})
+	if !ok {
+		return fee, 0, errors.New("ExternalAPIEstimator is not started; cannot estimate gas")
+	} else if err != nil {
+		return fee, 0, err
+	}
+
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, e.cfg.EvmMaxGasPriceWei())
+	if tipCap.Cmp(maxGasPrice) > 0 {
+		e.lggr.Warnw("Tip cap of the external gas API exceeds the max gas price, capping it", "tipCap", tipCap, "maxGasPrice", maxGasPrice)
+		tipCap = maxGasPrice
+		e.metrics.maxPriceCapped.Inc()
+	}
+	var feeCap *assets.Wei
+	if e.cfg.EvmGasBumpThreshold() == 0 {
+		// just use the max gas price if gas bumping is disabled
+		feeCap = maxGasPrice
+	} else if feeCap, err = calcFeeCap(baseFee, e.cfg, tipCap, maxGasPrice); err != nil {
+		return fee, 0, err
+	}
+	if fee, err = applyTipCapMin(e.cfg, DynamicFee{FeeCap: feeCap, TipCap: tipCap}, maxGasPrice); err != nil {
+		return fee, 0, err
+	}
+	return fee, commonfee.ApplyMultiplier(gasLimit, e.cfg.EvmGasLimitMultiplier()), nil
+}
+
+// BumpLegacyGas bumps the original gas price by EVM.GasEstimator.BumpStrategy,
+// or to the current gas price of the API if that is higher and not stale, so
+// that bumping carries on while the API is down
+func (e *externalAPIEstimator) BumpLegacyGas(_ context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &e.chainID, "ExternalAPI") }()
+	var currentGasPrice *assets.Wei
+	ok := e.IfStarted(func() {
+		currentGasPrice, _, _, _ = e.getPrices()
+	})
+	if !ok {
+		return nil, 0, errors.New("ExternalAPIEstimator is not started; cannot estimate gas")
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, e.cfg.EvmMaxGasPriceWei())
+	bumpedGasPrice, err = bumpGasPrice(e.cfg, e.lggr, capAt(currentGasPrice, maxGasPrice), originalGasPrice, maxGasPriceWei)
+	e.metrics.recordLegacyBump(err)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bumpedGasPrice, commonfee.ApplyMultiplier(gasLimit, e.cfg.EvmGasLimitMultiplier()), nil
+}
+
+// BumpDynamicFee bumps the original fee as by bumpDynamicFee, with the current
+// tip cap and base fee of the API if they are not stale
+func (e *externalAPIEstimator) BumpDynamicFee(_ context.Context, originalFee DynamicFee, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &e.chainID, "ExternalAPI") }()
+	if !e.cfg.EvmEIP1559DynamicFees() {
+		return bumped, 0, errors.New("Can't bump dynamic fee, EIP1559 is disabled")
+	}
+	var tipCap, baseFee *assets.Wei
+	ok := e.IfStarted(func() {
+		_, tipCap, baseFee, _ = e.getPrices()
+	})
+	if !ok {
+		return bumped, 0, errors.New("ExternalAPIEstimator is not started; cannot estimate gas")
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, e.cfg.EvmMaxGasPriceWei())
+	bumped, err = bumpDynamicFee(e.cfg, bufferedFeeCap(e.cfg), e.lggr, capAt(tipCap, maxGasPrice), baseFee, originalFee, maxGasPriceWei)
+	e.metrics.recordDynamicBump(err)
+	if err != nil {
+		return bumped, 0, err
+	}
+	return bumped, commonfee.ApplyMultiplier(gasLimit, e.cfg.EvmGasLimitMultiplier()), nil
+}
+
+// capAt returns price capped at max, or nil if price is nil
+func capAt(price, max *assets.Wei) *assets.Wei {
+	if price == nil {
+		return nil
+	}
+	return assets.WeiMin(price, max)
+}
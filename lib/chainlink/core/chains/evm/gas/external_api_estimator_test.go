@@ -0,0 +1,247 @@
+package gas_test
+
+import (
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+const (
+	polygonGasStationResponse   = `{"safeLow":{"maxPriorityFee":30.5,"maxFee":30.6},"standard":{"maxPriorityFee":32.25,"maxFee":32.35},"fast":{"maxPriorityFee":40,"maxFee":40.1},"estimatedBaseFee":0.1,"blockTime":2,"blockNumber":49000000}`
+	etherscanGasTrackerResponse = `{"status":"1","message":"OK","result":{"LastBlock":"19000000","SafeGasPrice":"21","ProposeGasPrice":"22","FastGasPrice":"25","suggestBaseFee":"20.5","gasUsedRatio":"0.4,0.5"}}`
+)
+
+func TestExternalAPIEstimator(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21000
+	maxGasPrice := assets.GWei(100)
+
+	// newServer returns a server that responds with the body, or status
+	// 503 while down is set
+	newServer := func(t *testing.T, body string, down *atomic.Bool) *url.URL {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if down != nil && down.Load() {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(body))
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		return u
+	}
+	polygonConfig := func(u *url.URL, speed string, eip1559 bool) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = eip1559
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmMinGasPriceWeiF = assets.GWei(1)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapDefaultF = assets.GWei(1)
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmGasBumpPercentF = 20
+		cfg.EvmGasBumpWeiF = assets.GWei(1)
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.EvmGasPriceStaleThresholdF = time.Minute
+		cfg.ExternalAPIEstimatorURLF = u
+		cfg.ExternalAPIEstimatorSpeedF = speed
+		cfg.ExternalAPIEstimatorSafeLowPathF = "safeLow.maxPriorityFee"
+		cfg.ExternalAPIEstimatorStandardPathF = "standard.maxPriorityFee"
+		cfg.ExternalAPIEstimatorFastPathF = "fast.maxPriorityFee"
+		cfg.ExternalAPIEstimatorBaseFeePathF = "estimatedBaseFee"
+		cfg.ExternalAPIEstimatorUnitF = "gwei"
+		return cfg
+	}
+	etherscanConfig := func(u *url.URL, speed string, eip1559 bool) *gas.MockConfig {
+		cfg := polygonConfig(u, speed, eip1559)
+		cfg.ExternalAPIEstimatorSafeLowPathF = "result.SafeGasPrice"
+		cfg.ExternalAPIEstimatorStandardPathF = "result.ProposeGasPrice"
+		cfg.ExternalAPIEstimatorFastPathF = "result.FastGasPrice"
+		cfg.ExternalAPIEstimatorBaseFeePathF = "result.suggestBaseFee"
+		cfg.ExternalAPIEstimatorPricesIncludeBaseFeeF = true
+		return cfg
+	}
+	start := func(t *testing.T, cfg *gas.MockConfig, now func() time.Time) gas.EvmEstimator {
+		e := gas.NewExternalAPIEstimator(logger.TestLogger(t), cfg, *big.NewInt(137))
+		if now != nil {
+			gas.SetExternalAPIClock(e, now)
+		}
+		require.NoError(t, e.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, e.Close()) })
+		return e
+	}
+
+	t.Run("Polygon gas station", func(t *testing.T) {
+		u := newServer(t, polygonGasStationResponse, nil)
+
+		for _, test := range []struct {
+			speed  string
+			tipCap *assets.Wei
+		}{
+			{gas.ExternalAPISpeedSafeLow, assets.NewWeiI(30_500_000_000)},
+			{gas.ExternalAPISpeedStandard, assets.NewWeiI(32_250_000_000)},
+			{gas.ExternalAPISpeedFast, assets.GWei(40)},
+		} {
+			test := test
+			t.Run(test.speed, func(t *testing.T) {
+				baseFee := assets.NewWeiI(100_000_000)
+
+				e := start(t, polygonConfig(u, test.speed, true), nil)
+				fee, limit, err := e.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+				require.NoError(t, err)
+				assert.Equal(t, gasLimit, limit)
+				assert.Equal(t, test.tipCap, fee.TipCap)
+				assert.Equal(t, baseFee.Add(test.tipCap), fee.FeeCap)
+
+				e = start(t, polygonConfig(u, test.speed, false), nil)
+				price, _, err := e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+				require.NoError(t, err)
+				assert.Equal(t, baseFee.Add(test.tipCap), price)
+			})
+		}
+	})
+
+	t.Run("Etherscan gas tracker", func(t *testing.T) {
+		u := newServer(t, etherscanGasTrackerResponse, nil)
+
+		e := start(t, etherscanConfig(u, gas.ExternalAPISpeedStandard, false), nil)
+		price, _, err := e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(22), price)
+
+		// the tip is what the gas price leaves over the base fee
+		e = start(t, etherscanConfig(u, gas.ExternalAPISpeedFast, true), nil)
+		fee, _, err := e.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(4_500_000_000), fee.TipCap)
+		assert.Equal(t, assets.GWei(25), fee.FeeCap)
+	})
+
+	t.Run("prices in wei", func(t *testing.T) {
+		u := newServer(t, `{"data":[{"tip":"1500000000","baseFee":2000000000}]}`, nil)
+		cfg := polygonConfig(u, gas.ExternalAPISpeedStandard, true)
+		cfg.ExternalAPIEstimatorStandardPathF = "data.0.tip"
+		cfg.ExternalAPIEstimatorBaseFeePathF = "data.0.baseFee"
+		cfg.ExternalAPIEstimatorUnitF = "wei"
+
+		e := start(t, cfg, nil)
+		fee, _, err := e.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(1_500_000_000), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(3_500_000_000), fee.FeeCap)
+	})
+
+	t.Run("enforces PriceMax and TipCapMin", func(t *testing.T) {
+		u := newServer(t, `{"standard":{"maxPriorityFee":0},"fast":{"maxPriorityFee":500},"estimatedBaseFee":10}`, nil)
+
+		e := start(t, polygonConfig(u, gas.ExternalAPISpeedFast, false), nil)
+		price, _, err := e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, maxGasPrice, price)
+
+		e = start(t, polygonConfig(u, gas.ExternalAPISpeedFast, true), nil)
+		fee, _, err := e.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, maxGasPrice, fee.TipCap)
+		assert.Equal(t, maxGasPrice, fee.FeeCap)
+
+		cfg := polygonConfig(u, gas.ExternalAPISpeedStandard, true)
+		cfg.EvmGasTipCapMinimumF = assets.GWei(2)
+		e = start(t, cfg, nil)
+		fee, _, err = e.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(2), fee.TipCap)
+		assert.Equal(t, assets.GWei(12), fee.FeeCap)
+	})
+
+	t.Run("malformed responses", func(t *testing.T) {
+		for _, test := range []struct {
+			name string
+			body string
+		}{
+			{"truncated JSON", `{"standard":{"maxPriorityFee":32.25`},
+			{"not JSON", `<html>Bad Gateway</html>`},
+			{"missing field", `{"fast":{"maxPriorityFee":40},"estimatedBaseFee":0.1}`},
+			{"missing base fee", `{"standard":{"maxPriorityFee":32.25}}`},
+			{"non-numeric price", `{"standard":{"maxPriorityFee":"soon"},"estimatedBaseFee":0.1}`},
+			{"negative price", `{"standard":{"maxPriorityFee":-1},"estimatedBaseFee":0.1}`},
+		} {
+			test := test
+			t.Run(test.name, func(t *testing.T) {
+				u := newServer(t, test.body, nil)
+				e := start(t, polygonConfig(u, gas.ExternalAPISpeedStandard, false), nil)
+
+				assert.Error(t, gas.RefreshExternalAPI(e))
+				_, _, err := e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+				require.ErrorIs(t, err, gas.ErrStalePrice)
+				assert.Contains(t, err.Error(), "has not fetched any prices from the external gas API yet")
+				assert.Contains(t, err.Error(), "malformed")
+			})
+		}
+	})
+
+	t.Run("serves the last good prices while the API is down, until they are stale", func(t *testing.T) {
+		var down atomic.Bool
+		u := newServer(t, polygonGasStationResponse, &down)
+		now := time.Now()
+		e := start(t, polygonConfig(u, gas.ExternalAPISpeedStandard, false), func() time.Time { return now })
+		expected := assets.NewWeiI(32_350_000_000)
+
+		down.Store(true)
+		require.Error(t, gas.RefreshExternalAPI(e))
+		now = now.Add(59 * time.Second)
+		price, _, err := e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, expected, price)
+
+		now = now.Add(2 * time.Second)
+		_, _, err = e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.ErrorIs(t, err, gas.ErrStalePrice)
+		assert.Contains(t, err.Error(), "more than EVM.GasEstimator.PriceStaleThreshold")
+		assert.Contains(t, err.Error(), "503 Service Unavailable")
+
+		down.Store(false)
+		require.NoError(t, gas.RefreshExternalAPI(e))
+		price, _, err = e.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, expected, price)
+	})
+
+	t.Run("bumps locally", func(t *testing.T) {
+		var down atomic.Bool
+		u := newServer(t, polygonGasStationResponse, &down)
+		now := time.Now()
+		e := start(t, polygonConfig(u, gas.ExternalAPISpeedStandard, true), func() time.Time { return now })
+
+		// a fresh price of the API raises the bump
+		bumped, _, err := e.BumpLegacyGas(testutils.Context(t), assets.GWei(20), gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(32_350_000_000), bumped)
+
+		// a stale price doesn't prevent the bump
+		down.Store(true)
+		require.Error(t, gas.RefreshExternalAPI(e))
+		now = now.Add(2 * time.Minute)
+		bumped, _, err = e.BumpLegacyGas(testutils.Context(t), assets.GWei(20), gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(24), bumped)
+
+		bumpedFee, _, err := e.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.GWei(10), FeeCap: assets.GWei(20)}, gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(12), bumpedFee.TipCap)
+		assert.Equal(t, assets.GWei(24), bumpedFee.FeeCap)
+	})
+}
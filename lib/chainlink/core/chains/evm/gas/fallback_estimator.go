@@ -0,0 +1,384 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/services"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+const (
+	// fallbackCooldown is how long the FallbackEstimator keeps using the
+	// estimator it failed over to before trying the preferred ones again
+	fallbackCooldown = time.Minute
+	// fallbackMaxOrigins bounds the number of estimated fees whose estimator
+	// the FallbackEstimator remembers for bumping
+	fallbackMaxOrigins = 1000
+)
+
+var (
+	_ EvmEstimator         = (*fallbackEstimator)(nil)
+	_ FeeExplainer         = (*fallbackEstimator)(nil)
+	_ BlobFeeEstimator     = (*fallbackEstimator)(nil)
+	_ FeeCurrencyEstimator = (*fallbackEstimator)(nil)
+	_ feeOriginTracker     = (*fallbackEstimator)(nil)
+)
+
+// feeOriginTracker is implemented by estimators that bump fees with the
+// estimator that produced them. The WrappedEvmEstimator tells them the fee it
+// returned for each fee they estimated, as rounding, budgets and the like
+// change the fee after the estimator.
+type feeOriginTracker interface {
+	trackFee(estimated, returned EvmFee)
+}
+
+// noDataReporter is implemented by estimators that can fail with ErrNoData
+// instead of estimating from defaults when they have no data, so that the
+// FallbackEstimator can fail over
+type noDataReporter interface {
+	reportNoData()
+}
+
+// fallbackEstimator tries its estimators in order of preference, failing over
+// to the next one when an estimator returns an error, e.g. ErrNoData
+type fallbackEstimator struct {
+	utils.StartStopOnce
+	estimators []EvmEstimator
+	cooldown   time.Duration
+	now        func() time.Time
+	lggr       logger.SugaredLogger
+	ms         services.MultiStart
+
+	mu sync.Mutex
+	// active is the estimator failed over to, used until activeUntil
+	active      int
+	activeUntil time.Time
+	// origins maps estimated fees to the estimator that produced them, so
+	// that they are bumped by the same estimator. originOrder is the
+	// insertion order, to evict the oldest.
+	origins     map[string]int
+	originOrder []string
+}
+
+// NewFallbackEstimator returns an estimator that tries estimators in order
+// for each estimate, and returns the first one that succeeds. Estimators
+// that have no data to estimate from, e.g. a BlockHistoryEstimator with an
+// empty block history, fail with ErrNoData rather than estimating from the
+// defaults.
+//
+// An estimator that was failed over to keeps being tried first for a cooldown
+// period, to avoid flapping between estimators. Fees are always bumped by the
+// estimator that estimated them.
+//
+// The optional features of the estimators, e.g. fee explanations, fee
+// validity and fee subscriptions, are forwarded to the active estimator, i.e.
+// the one tried first.
+func NewFallbackEstimator(lggr logger.Logger, estimators ...EvmEstimator) EvmEstimator {
+	for _, e := range estimators {
+		if r, ok := e.(noDataReporter); ok {
+			r.reportNoData()
+		}
+	}
+	return &fallbackEstimator{
+		estimators: estimators,
+		cooldown:   fallbackCooldown,
+		now:        time.Now,
+		lggr:       logger.Sugared(lggr.Named("FallbackEstimator")),
+		origins:    make(map[string]int),
+	}
+}
+
+func (f *fallbackEstimator) Name() string {
+	return f.lggr.Name()
+}
+
+func (f *fallbackEstimator) Start(ctx context.Context) error {
+	return f.StartOnce("FallbackEstimator", func() error {
+		if len(f.estimators) == 0 {
+			return errors.New("FallbackEstimator needs at least one estimator")
+		}
+		srvcs := make([]services.StartClose, len(f.estimators))
+		for i, e := range f.estimators {
+			srvcs[i] = e
+		}
+		return f.ms.Start(ctx, srvcs...)
+	})
+}
+
+func (f *fallbackEstimator) Close() error {
+	return f.StopOnce("FallbackEstimator", f.ms.Close)
+}
+
+func (f *fallbackEstimator) HealthReport() map[string]error {
+	report := map[string]error{f.Name(): f.StartStopOnce.Healthy()}
+	for _, e := range f.estimators {
+		for name, err := range e.HealthReport() {
+			report[name] = err
+		}
+	}
+	return report
+}
+
+func (f *fallbackEstimator) OnNewLongestChain(ctx context.Context, head *evmtypes.Head) {
+	for _, e := range f.estimators {
+		e.OnNewLongestChain(ctx, head)
+	}
+}
+
+func (f *fallbackEstimator) GetLegacyGas(ctx context.Context, calldata []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, opts ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	i, err := f.try(func(e EvmEstimator) (err error) {
+		gasPrice, chainSpecificGasLimit, err = e.GetLegacyGas(ctx, calldata, gasLimit, maxGasPriceWei, opts...)
+		return
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	f.setOrigin(gasPrice.String(), i)
+	return
+}
+
+func (f *fallbackEstimator) BumpLegacyGas(ctx context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, attempts []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	i := f.origin(originalGasPrice.String())
+	bumpedGasPrice, chainSpecificGasLimit, err = f.estimators[i].BumpLegacyGas(ctx, originalGasPrice, gasLimit, maxGasPriceWei, attempts)
+	if err != nil {
+		return nil, 0, err
+	}
+	f.setOrigin(bumpedGasPrice.String(), i)
+	return
+}
+
+func (f *fallbackEstimator) GetDynamicFee(ctx context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	i, err := f.try(func(e EvmEstimator) (err error) {
+		fee, chainSpecificGasLimit, err = e.GetDynamicFee(ctx, gasLimit, maxGasPriceWei)
+		return
+	})
+	if err != nil {
+		return fee, 0, err
+	}
+	f.setOrigin(dynamicFeeOriginKey(fee), i)
+	return
+}
+
+func (f *fallbackEstimator) BumpDynamicFee(ctx context.Context, original DynamicFee, gasLimit uint32, maxGasPriceWei *assets.Wei, attempts []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	i := f.origin(dynamicFeeOriginKey(original))
+	bumped, chainSpecificGasLimit, err = f.estimators[i].BumpDynamicFee(ctx, original, gasLimit, maxGasPriceWei, attempts)
+	if err != nil {
+		return bumped, 0, err
+	}
+	f.setOrigin(dynamicFeeOriginKey(bumped), i)
+	return
+}
+
+// trackFee records that the returned fee was derived from the estimated one,
+// so that it is bumped by the estimator that estimated it
+func (f *fallbackEstimator) trackFee(estimated, returned EvmFee) {
+	key, returnedKey := feeOriginKey(estimated), feeOriginKey(returned)
+	if key == "" || returnedKey == "" || key == returnedKey {
+		return
+	}
+	f.mu.Lock()
+	i, ok := f.origins[key]
+	f.mu.Unlock()
+	if ok {
+		f.setOrigin(returnedKey, i)
+	}
+}
+
+// ExplainFee explains the fee of the active estimator
+func (f *fallbackEstimator) ExplainFee(ctx context.Context, calldata []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, dynamic bool) (FeeExplanation, error) {
+	e, err := f.activeEstimator()
+	if err != nil {
+		return FeeExplanation{}, err
+	}
+	explainer, ok := e.(FeeExplainer)
+	if !ok {
+		return FeeExplanation{}, errors.Errorf("estimator %s does not support fee explanations", e.Name())
+	}
+	return explainer.ExplainFee(ctx, calldata, gasLimit, maxGasPriceWei, dynamic)
+}
+
+// GetBlobFee returns the blob fee of the active estimator
+func (f *fallbackEstimator) GetBlobFee(ctx context.Context) (*assets.Wei, error) {
+	e, err := f.activeEstimator()
+	if err != nil {
+		return nil, err
+	}
+	blobEstimator, ok := e.(BlobFeeEstimator)
+	if !ok {
+		return nil, errors.Errorf("estimator %s does not support blob fee estimation", e.Name())
+	}
+	return blobEstimator.GetBlobFee(ctx)
+}
+
+// FeeCurrency returns the fee currency of the active estimator
+func (f *fallbackEstimator) FeeCurrency() *common.Address {
+	e, err := f.activeEstimator()
+	if err != nil {
+		return nil
+	}
+	if fc, ok := e.(FeeCurrencyEstimator); ok {
+		return fc.FeeCurrency()
+	}
+	return nil
+}
+
+// withValidity sets the validity of fee as the estimator that produced it
+// would, or leaves it without one if that estimator doesn't know
+func (f *fallbackEstimator) withValidity(fee EvmFee) EvmFee {
+	if len(f.estimators) == 0 {
+		return fee
+	}
+	if v, ok := f.estimators[f.origin(feeOriginKey(fee))].(feeValidityEstimator); ok {
+		return v.withValidity(fee)
+	}
+	return fee
+}
+
+// feeUpdates returns the fee updates of the active estimator, or nil if it
+// doesn't publish them. Subscriptions stay with that estimator if the
+// FallbackEstimator fails over.
+func (f *fallbackEstimator) feeUpdates() *feeFeed {
+	e, err := f.activeEstimator()
+	if err != nil {
+		return nil
+	}
+	if p, ok := e.(feeUpdatePublisher); ok {
+		return p.feeUpdates()
+	}
+	return nil
+}
+
+// decisionInputs returns the decision inputs of the active estimator
+func (f *fallbackEstimator) decisionInputs() []interface{} {
+	e, err := f.activeEstimator()
+	if err != nil {
+		return nil
+	}
+	kvs := []interface{}{"activeEstimator", e.Name()}
+	if r, ok := e.(decisionInputsReporter); ok {
+		kvs = append(kvs, r.decisionInputs()...)
+	}
+	return kvs
+}
+
+// activeEstimator returns the estimator to try first
+func (f *fallbackEstimator) activeEstimator() (EvmEstimator, error) {
+	if len(f.estimators) == 0 {
+		return nil, errors.New("FallbackEstimator has no estimators")
+	}
+	return f.estimators[f.first()], nil
+}
+
+// try calls estimate with each estimator, starting from the one failed over
+// to during its cooldown and from the most preferred one otherwise, until
+// one succeeds. It returns the index of that estimator, or the errors of all
+// of them.
+func (f *fallbackEstimator) try(estimate func(EvmEstimator) error) (int, error) {
+	if len(f.estimators) == 0 {
+		return 0, errors.New("FallbackEstimator has no estimators")
+	}
+	first := f.first()
+	order := make([]int, 0, len(f.estimators))
+	order = append(order, first)
+	for i := range f.estimators {
+		if i != first {
+			order = append(order, i)
+		}
+	}
+
+	var merr error
+	for _, i := range order {
+		e := f.estimators[i]
+		err := estimate(e)
+		if err == nil {
+			if i != first {
+				f.failOver(i)
+			}
+			return i, nil
+		}
+		lvl := f.lggr.Warnw
+		if errors.Is(err, ErrNoData) {
+			lvl = f.lggr.Debugw
+		}
+		lvl(fmt.Sprintf("%s failed to estimate, trying the next estimator", e.Name()), "err", err, "estimator", e.Name())
+		merr = multierr.Append(merr, errors.Wrap(err, e.Name()))
+	}
+	return 0, errors.Wrap(merr, "all estimators failed")
+}
+
+// first returns the estimator to try first
+func (f *fallbackEstimator) first() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.active != 0 && f.now().After(f.activeUntil) {
+		f.lggr.Infow(fmt.Sprintf("Fallback cooldown expired, trying %s again", f.estimators[0].Name()), "estimator", f.estimators[0].Name())
+		f.active = 0
+	}
+	return f.active
+}
+
+// failOver makes i the estimator to try first for the cooldown period. Going
+// back to the most preferred estimator ends the cooldown.
+func (f *fallbackEstimator) failOver(i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if i != 0 {
+		f.lggr.Warnw(fmt.Sprintf("Failing over to %s for %s", f.estimators[i].Name(), f.cooldown), "estimator", f.estimators[i].Name(), "cooldown", f.cooldown)
+	}
+	f.active = i
+	f.activeUntil = f.now().Add(f.cooldown)
+}
+
+// origin returns the estimator that produced the fee with key, or the
+// estimator to try first if the fee wasn't estimated by this estimator,
+// e.g. because the node restarted since
+func (f *fallbackEstimator) origin(key string) int {
+	f.mu.Lock()
+	i, ok := f.origins[key]
+	f.mu.Unlock()
+	if ok {
+		return i
+	}
+	return f.first()
+}
+
+func (f *fallbackEstimator) setOrigin(key string, i int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.origins[key]; !ok {
+		f.originOrder = append(f.originOrder, key)
+		if len(f.originOrder) > fallbackMaxOrigins {
+			delete(f.origins, f.originOrder[0])
+			f.originOrder = f.originOrder[1:]
+		}
+	}
+	f.origins[key] = i
+}
+
+func dynamicFeeOriginKey(fee DynamicFee) string {
+	return fmt.Sprintf("%s/%s", fee.FeeCap, fee.TipCap)
+}
+
+// feeOriginKey returns the origin key of fee, as set by the estimate or bump
+// that produced it, or "" if it has no price
+func feeOriginKey(fee EvmFee) string {
+	switch {
+	case fee.ValidDynamic():
+		return dynamicFeeOriginKey(DynamicFee{FeeCap: fee.DynamicFeeCap, TipCap: fee.DynamicTipCap})
+	case fee.Legacy != nil:
+		return fee.Legacy.String()
+	default:
+		return ""
+	}
+}
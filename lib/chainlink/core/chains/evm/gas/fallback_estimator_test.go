@@ -0,0 +1,220 @@
+package gas_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestFallbackEstimator(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.NewWeiI(1000)
+
+	// clock is a manually advanced clock for the cooldown
+	type clock struct {
+		mu  sync.Mutex
+		now time.Time
+	}
+	newClock := func() *clock { return &clock{now: time.Unix(1_700_000_000, 0)} }
+	now := func(c *clock) func() time.Time {
+		return func() time.Time {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			return c.now
+		}
+	}
+	advance := func(c *clock, d time.Duration) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.now = c.now.Add(d)
+	}
+
+	// newPrimary returns a BlockHistoryEstimator with an empty block history
+	newPrimary := func(t *testing.T) *gas.BlockHistoryEstimator {
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(50)
+		cfg.EvmGasPriceDefaultF = assets.NewWeiI(100)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmGasBumpPercentF = uint16(20)
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SimulateStart(t, bhe)
+		return bhe
+	}
+	fillHistory := func(bhe *gas.BlockHistoryEstimator, gasPrice int64) {
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{{
+			Number:       1,
+			Hash:         utils.NewHash(),
+			Transactions: cltest.LegacyTransactionsFromGasPrices(gasPrice),
+		}})
+		bhe.Recalculate(cltest.Head(1))
+	}
+	newSecondary := func(t *testing.T) *mocks.EvmEstimator {
+		e := mocks.NewEvmEstimator(t)
+		e.On("Name").Return("L2SuggestedEstimator").Maybe()
+		return e
+	}
+
+	t.Run("fails over to the next estimator while the primary has no data, until the cooldown expires", func(t *testing.T) {
+		primary := newPrimary(t)
+		secondary := newSecondary(t)
+		secondary.On("GetLegacyGas", mock.Anything, mock.Anything, uint32(21000), maxGasPrice).Return(assets.NewWeiI(42), uint32(21000), nil).Twice()
+		c := newClock()
+		estimator := gas.NewFallbackEstimator(logger.TestLogger(t), primary, secondary)
+		gas.SetFallbackClock(estimator, now(c))
+
+		// the BlockHistoryEstimator would otherwise use the default price of 100
+		price, limit, err := estimator.GetLegacyGas(testutils.Context(t), nil, 21000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), price)
+		assert.Equal(t, uint32(21000), limit)
+
+		// during the cooldown the secondary keeps being used, even once the
+		// primary has data again
+		fillHistory(primary, 30)
+		advance(c, gas.FallbackCooldown)
+		price, _, err = estimator.GetLegacyGas(testutils.Context(t), nil, 21000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), price)
+
+		advance(c, time.Second)
+		price, _, err = estimator.GetLegacyGas(testutils.Context(t), nil, 21000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), price)
+	})
+
+	t.Run("fails over dynamic fees", func(t *testing.T) {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		primary := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SimulateStart(t, primary)
+		secondary := newSecondary(t)
+		fee := gas.DynamicFee{FeeCap: assets.NewWeiI(200), TipCap: assets.NewWeiI(2)}
+		secondary.On("GetDynamicFee", mock.Anything, uint32(21000), maxGasPrice).Return(fee, uint32(21000), nil).Once()
+		estimator := gas.NewFallbackEstimator(logger.TestLogger(t), primary, secondary)
+
+		got, _, err := estimator.GetDynamicFee(testutils.Context(t), 21000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, fee, got)
+	})
+
+	t.Run("bumps with the estimator that estimated the original price", func(t *testing.T) {
+		primary := newPrimary(t)
+		secondary := newSecondary(t)
+		secondary.On("GetLegacyGas", mock.Anything, mock.Anything, uint32(21000), maxGasPrice).Return(assets.NewWeiI(42), uint32(21000), nil).Once()
+		secondary.On("BumpLegacyGas", mock.Anything, assets.NewWeiI(42), uint32(21000), maxGasPrice, mock.Anything).Return(assets.NewWeiI(50), uint32(21000), nil).Once()
+		secondary.On("BumpLegacyGas", mock.Anything, assets.NewWeiI(50), uint32(21000), maxGasPrice, mock.Anything).Return(assets.NewWeiI(60), uint32(21000), nil).Once()
+		c := newClock()
+		estimator := gas.NewFallbackEstimator(logger.TestLogger(t), primary, secondary)
+		gas.SetFallbackClock(estimator, now(c))
+
+		price, _, err := estimator.GetLegacyGas(testutils.Context(t), nil, 21000, maxGasPrice)
+		require.NoError(t, err)
+		require.Equal(t, assets.NewWeiI(42), price)
+
+		// the primary is used for new estimates again, but not for bumps of
+		// the secondary's prices
+		fillHistory(primary, 30)
+		advance(c, 2*gas.FallbackCooldown)
+		price, _, err = estimator.GetLegacyGas(testutils.Context(t), nil, 21000, maxGasPrice)
+		require.NoError(t, err)
+		require.Equal(t, assets.NewWeiI(30), price)
+
+		bumped, _, err := estimator.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), 21000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(50), bumped)
+		bumped, _, err = estimator.BumpLegacyGas(testutils.Context(t), bumped, 21000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(60), bumped)
+
+		// the primary's own prices are bumped by the primary
+		bumped, _, err = estimator.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(30), 21000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(36), bumped)
+	})
+
+	t.Run("bumps rounded fees with the estimator that estimated them", func(t *testing.T) {
+		primary := newPrimary(t)
+		secondary := newSecondary(t)
+		secondary.On("GetLegacyGas", mock.Anything, mock.Anything, uint32(21000), maxGasPrice).Return(assets.NewWeiI(42), uint32(21000), nil).Once()
+		secondary.On("BumpLegacyGas", mock.Anything, assets.NewWeiI(50), uint32(21000), maxGasPrice, mock.Anything).Return(assets.NewWeiI(57), uint32(21000), nil).Once()
+		c := newClock()
+		fallback := gas.NewFallbackEstimator(logger.TestLogger(t), primary, secondary)
+		gas.SetFallbackClock(fallback, now(c))
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasFeeRoundingF = assets.NewWeiI(10)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), fallback, cfg, nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil)
+		require.NoError(t, err)
+		require.Equal(t, assets.NewWeiI(50), fee.Legacy)
+
+		fillHistory(primary, 30)
+		advance(c, 2*gas.FallbackCooldown)
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), fee, 21000, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(60), bumped.Legacy)
+	})
+
+	t.Run("forwards fee explanations to the active estimator", func(t *testing.T) {
+		primary := newPrimary(t)
+		fillHistory(primary, 30)
+		secondary := newSecondary(t)
+		estimator := gas.NewFallbackEstimator(logger.TestLogger(t), primary, secondary)
+
+		explainer, ok := estimator.(gas.FeeExplainer)
+		require.True(t, ok)
+		explanation, err := explainer.ExplainFee(testutils.Context(t), nil, 21000, maxGasPrice, false)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), explanation.Fee.Legacy)
+		assert.Equal(t, 1, explanation.BlocksSampled)
+	})
+
+	t.Run("returns the errors of all estimators if all fail", func(t *testing.T) {
+		primary := newPrimary(t)
+		secondary := newSecondary(t)
+		secondary.On("GetLegacyGas", mock.Anything, mock.Anything, uint32(21000), maxGasPrice).Return(nil, uint32(0), errors.New("kaboom")).Once()
+		estimator := gas.NewFallbackEstimator(logger.TestLogger(t), primary, secondary)
+
+		_, _, err := estimator.GetLegacyGas(testutils.Context(t), nil, 21000, maxGasPrice)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, gas.ErrNoData)
+		assert.Contains(t, err.Error(), "all estimators failed")
+		assert.Contains(t, err.Error(), "L2SuggestedEstimator: kaboom")
+	})
+
+	t.Run("is selected with the Fallback mode", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = "Fallback"
+		cfg.GasEstimatorFallbackModesF = []string{"FixedPrice", "FixedPrice"}
+		cfg.EvmGasPriceDefaultF = assets.NewWeiI(42)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasLimitMultiplierF = float32(1)
+
+		estimator := gas.NewEstimator(logger.TestLogger(t), nil, cfg, nil)
+		assert.Contains(t, estimator.(*gas.WrappedEvmEstimator).EvmEstimator.Name(), "FallbackEstimator")
+		require.NoError(t, estimator.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, estimator.Close()) })
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), fee.Legacy)
+	})
+}
@@ -0,0 +1,187 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"golang.org/x/sync/singleflight"
+
+	commonfee "github.com/smartcontractkit/chainlink/v2/common/fee"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+)
+
+// feeCache shares fee estimates between concurrent callers of the
+// WrappedEvmEstimator and reuses them for EVM.GasEstimator.FeeCacheTTL, so
+// that many transactions created at once don't each trigger an estimation.
+// Calls share an estimate if their gas limits are in the same bucket, see
+// gasLimitBucket.
+// The cache is invalidated on every new head, so a fee is never quoted
+// against the base fee of a previous block.
+//
+// A nil *feeCache is valid and disables caching.
+type feeCache struct {
+	ttl time.Duration
+	// limitMultiplier is EVM.GasEstimator.LimitMultiplier, see limitFor
+	limitMultiplier float32
+	group           singleflight.Group
+
+	mu sync.Mutex
+	// generation is incremented on every invalidation, so that estimates
+	// started before a new head are neither shared with nor cached for
+	// callers after it
+	generation uint64
+	entries    map[string]feeCacheEntry
+}
+
+type feeCacheEntry struct {
+	fee EvmFee
+	// feeLimit is the fee limit the fee was estimated for, and
+	// chainSpecificFeeLimit the limit the estimator returned for it
+	feeLimit              uint32
+	chainSpecificFeeLimit uint32
+	expires               time.Time
+}
+
+// limitFor returns the chain-specific fee limit of the entry for a caller in
+// the same gas limit bucket. The estimators scale the fee limit with
+// EVM.GasEstimator.LimitMultiplier and may add a fixed amount to it, e.g. the
+// L1 gas on Arbitrum, so the caller's limit is scaled likewise and gets the
+// same addition as the estimated one.
+func (e feeCacheEntry) limitFor(feeLimit uint32, multiplier float32) uint32 {
+	if feeLimit == e.feeLimit {
+		return e.chainSpecificFeeLimit
+	}
+	added := int64(e.chainSpecificFeeLimit) - int64(commonfee.ApplyMultiplier(e.feeLimit, multiplier))
+	limit := int64(commonfee.ApplyMultiplier(feeLimit, multiplier)) + added
+	if limit < 0 {
+		return 0
+	}
+	return uint32(limit)
+}
+
+func newFeeCache(ttl time.Duration, limitMultiplier float32) *feeCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &feeCache{ttl: ttl, limitMultiplier: limitMultiplier, entries: make(map[string]feeCacheEntry)}
+}
+
+// gasLimitBucket returns the bucket of gasLimit, i.e. the next power of two,
+// so that calls with nearly the same gas limit share an estimate
+func gasLimitBucket(gasLimit uint32) uint32 {
+	if gasLimit <= 1 {
+		return gasLimit
+	}
+	return 1 << bits.Len32(gasLimit-1)
+}
+
+// dynamicFeeKey returns the cache key for a GetDynamicFee call. The fee
+// profile and inclusion target are part of the key since they can change the
+// estimate.
+func dynamicFeeKey(profile string, inclusionBlocks uint32, gasLimit uint32, maxGasPriceWei *assets.Wei) string {
+	return fmt.Sprintf("dynamic/%s/%d/%d/%s", profile, inclusionBlocks, gasLimitBucket(gasLimit), maxGasPriceWei)
+}
+
+// legacyGasKey returns the cache key for a GetLegacyGas call. The calldata is
+// part of the key since some estimators (e.g. Arbitrum) price it.
+func legacyGasKey(profile string, calldata []byte, gasLimit uint32, maxGasPriceWei *assets.Wei) string {
+	return fmt.Sprintf("legacy/%s/%d/%s/%s", profile, gasLimitBucket(gasLimit), maxGasPriceWei, crypto.Keccak256Hash(calldata))
+}
+
+// get returns the cached estimate for key if there is an unexpired one, and
+// otherwise calls estimate, sharing a single in-flight call between
+// concurrent callers. Errors are shared with concurrent callers but never
+// cached.
+//
+// The shared call isn't bound to the context of the caller that started it,
+// as the other callers would fail with it if that caller gave up. It runs
+// with the values of that context but the default RPC timeout instead, while
+// each caller stops waiting for it once its own context is done.
+func (c *feeCache) get(ctx context.Context, key string, feeLimit uint32, estimate func(ctx context.Context) (EvmFee, uint32, error)) (EvmFee, uint32, error) {
+	if c == nil {
+		return estimate(ctx)
+	}
+
+	c.mu.Lock()
+	generation := c.generation
+	e, ok := c.lookup(key)
+	c.mu.Unlock()
+	if ok {
+		return e.fee, e.limitFor(feeLimit, c.limitMultiplier), nil
+	}
+
+	res := c.group.DoChan(fmt.Sprintf("%d/%s", generation, key), func() (interface{}, error) {
+		// a flight that just finished may have cached an estimate after the
+		// lookup above
+		c.mu.Lock()
+		e, ok := c.lookup(key)
+		ok = ok && c.generation == generation
+		c.mu.Unlock()
+		if ok {
+			return e, nil
+		}
+
+		estimateCtx, cancel := evmclient.ContextWithDefaultTimeout()
+		defer cancel()
+		fee, chainSpecificFeeLimit, err := estimate(valuesContext{Context: estimateCtx, values: ctx})
+		if err != nil {
+			return nil, err
+		}
+		e = feeCacheEntry{fee: fee, feeLimit: feeLimit, chainSpecificFeeLimit: chainSpecificFeeLimit, expires: time.Now().Add(c.ttl)}
+		c.mu.Lock()
+		if c.generation == generation {
+			c.entries[key] = e
+		}
+		c.mu.Unlock()
+		return e, nil
+	})
+	select {
+	case r := <-res:
+		if r.Err != nil {
+			return EvmFee{}, 0, r.Err
+		}
+		e := r.Val.(feeCacheEntry)
+		return e.fee, e.limitFor(feeLimit, c.limitMultiplier), nil
+	case <-ctx.Done():
+		return EvmFee{}, 0, ctx.Err()
+	}
+}
+
+// lookup returns the unexpired entry for key. The caller must hold mu.
+func (c *feeCache) lookup(key string) (feeCacheEntry, bool) {
+	e, ok := c.entries[key]
+	if !ok || !time.Now().Before(e.expires) {
+		return feeCacheEntry{}, false
+	}
+	return e, true
+}
+
+// valuesContext is a context with the values of another one, but not its
+// deadline or cancellation
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c valuesContext) Value(key interface{}) interface{} {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.values.Value(key)
+}
+
+// invalidate drops all cached estimates
+func (c *feeCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	c.entries = make(map[string]feeCacheEntry)
+}
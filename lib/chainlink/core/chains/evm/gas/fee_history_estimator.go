@@ -0,0 +1,331 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	commonfee "github.com/smartcontractkit/chainlink/v2/common/fee"
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+var _ EvmEstimator = &feeHistoryEstimator{}
+
+// feeHistoryResult is the response of eth_feeHistory
+// See: https://ethereum.github.io/execution-apis/api-documentation/
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big  `json:"oldestBlock"`
+	Reward       [][]*rpcPrice `json:"reward,omitempty"`
+	BaseFee      []*rpcPrice   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64     `json:"gasUsedRatio"`
+}
+
+// feeHistoryEstimator is an Estimator which uses eth_feeHistory to derive the
+// base fee and tip cap from the last EVM.GasEstimator.FeeHistory.BlockCount
+// blocks, without having to fetch full blocks like the BlockHistoryEstimator
+// does. The fee cap is the base fee of the pending block multiplied by
+// EVM.GasEstimator.BaseFeeBufferMultiplier, plus the tip cap.
+type feeHistoryEstimator struct {
+	utils.StartStopOnce
+
+	config     Config
+	quorum     quorum
+	pollPeriod time.Duration
+	logger     logger.SugaredLogger
+	chainID    big.Int
+	metrics    *estimatorMetrics
+
+	// refreshMu serializes refreshes between the poller and bumps with the
+	// Rebase strategy
+	refreshMu sync.Mutex
+	priceMu   sync.RWMutex
+	baseFee   *assets.Wei
+	tipCap    *assets.Wei
+
+	chInitialised chan struct{}
+	chStop        utils.StopChan
+	chDone        chan struct{}
+}
+
+// NewFeeHistoryEstimator returns a new "FeeHistory" estimator which polls
+// eth_feeHistory for the configured number of blocks and reward percentile
+func NewFeeHistoryEstimator(lggr logger.Logger, client rpcClient, cfg Config, chainID big.Int) EvmEstimator {
+	return newFeeHistoryEstimator(lggr, singleClient(client), cfg, chainID)
+}
+
+// NewQuorumFeeHistoryEstimator returns a new "FeeHistory" estimator which
+// polls eth_feeHistory from all of the clients and uses the median of the
+// prices derived from each of them. Unless at least minQuorum of the clients
+// respond, the previous prices are kept. minQuorum must be between 1 and the
+// number of clients.
+func NewQuorumFeeHistoryEstimator(lggr logger.Logger, cfg Config, chainID big.Int, minQuorum int, clients ...rpcClient) (EvmEstimator, error) {
+	q, err := newQuorum(minQuorum, clients)
+	if err != nil {
+		return nil, err
+	}
+	return newFeeHistoryEstimator(lggr, q, cfg, chainID), nil
+}
+
+func newFeeHistoryEstimator(lggr logger.Logger, q quorum, cfg Config, chainID big.Int) *feeHistoryEstimator {
+	return &feeHistoryEstimator{
+		config:        cfg,
+		quorum:        q,
+		pollPeriod:    10 * time.Second,
+		logger:        logger.Sugared(lggr.Named("FeeHistoryEstimator")),
+		chainID:       chainID,
+		metrics:       newEstimatorMetrics(chainID, "FeeHistory"),
+		chInitialised: make(chan struct{}),
+		chStop:        make(chan struct{}),
+		chDone:        make(chan struct{}),
+	}
+}
+
+func (f *feeHistoryEstimator) Name() string {
+	return f.logger.Name()
+}
+
+func (f *feeHistoryEstimator) Start(context.Context) error {
+	return f.StartOnce("FeeHistoryEstimator", func() error {
+		if f.config.FeeHistoryEstimatorBlockCount() == 0 {
+			return errors.New("FeeHistoryEstimatorBlockCount must be set to a value greater than 0")
+		}
+		go f.run()
+		<-f.chInitialised
+		return nil
+	})
+}
+
+func (f *feeHistoryEstimator) Close() error {
+	return f.StopOnce("FeeHistoryEstimator", func() error {
+		close(f.chStop)
+		<-f.chDone
+		return nil
+	})
+}
+
+func (f *feeHistoryEstimator) HealthReport() map[string]error {
+	return map[string]error{f.Name(): f.StartStopOnce.Healthy()}
+}
+
+func (f *feeHistoryEstimator) OnNewLongestChain(context.Context, *evmtypes.Head) {}
+
+func (f *feeHistoryEstimator) run() {
+	defer close(f.chDone)
+
+	t := f.refreshFeeHistory()
+	close(f.chInitialised)
+
+	for {
+		select {
+		case <-f.chStop:
+			return
+		case <-t.C:
+			t = f.refreshFeeHistory()
+		}
+	}
+}
+
+func (f *feeHistoryEstimator) refreshFeeHistory() (t *time.Timer) {
+	t = time.NewTimer(utils.WithJitter(f.pollPeriod))
+
+	ctx, cancel := f.chStop.CtxCancel(evmclient.ContextWithDefaultTimeout())
+	defer cancel()
+	f.refresh(ctx)
+	return
+}
+
+// refresh fetches the fee history and updates the prices
+func (f *feeHistoryEstimator) refresh(ctx context.Context) {
+	f.refreshMu.Lock()
+	defer f.refreshMu.Unlock()
+	blockCount := int64(f.config.FeeHistoryEstimatorBlockCount())
+	percentile := float64(f.config.FeeHistoryEstimatorRewardPercentile())
+
+	results, errs := queryQuorum(ctx, f.quorum, func(ctx context.Context, client rpcClient) (res feeHistoryResult, err error) {
+		err = client.CallContext(ctx, &res, "eth_feeHistory", Int64ToHex(blockCount), "latest", []float64{percentile})
+		return
+	})
+	for _, err := range errs {
+		f.metrics.recordRPCError(err)
+	}
+	if err := f.quorum.checkQuorum(len(results), errs); err != nil {
+		f.logger.Warnw("Failed to refresh fee history", "err", err, "responded", len(results), "quorum", f.quorum.min, "failedClients", failedClients(errs))
+		return
+	} else if len(errs) > 0 {
+		f.logger.Warnw("Some clients failed to respond", "failedClients", failedClients(errs))
+	}
+
+	var baseFees, tipCaps []*assets.Wei
+	for _, res := range results {
+		baseFee, tipCap := f.pricesFromFeeHistory(res)
+		if baseFee != nil {
+			baseFees = append(baseFees, baseFee)
+		}
+		tipCaps = append(tipCaps, tipCap)
+	}
+	baseFee, tipCap := medianWei(baseFees), medianWei(tipCaps)
+	f.logger.Debugw("refreshFeeHistory", "baseFee", baseFee, "tipCap", tipCap, "responded", len(results))
+
+	f.priceMu.Lock()
+	defer f.priceMu.Unlock()
+	if baseFee != nil {
+		f.baseFee = baseFee
+		f.metrics.setBaseFee(baseFee)
+	}
+	f.tipCap = tipCap
+	f.metrics.setTipCap(tipCap)
+	if f.baseFee != nil && f.tipCap != nil {
+		f.metrics.setGasPrice(f.baseFee.Add(f.tipCap))
+	}
+}
+
+// rebase refreshes the prices before a bump with the Rebase strategy, which
+// re-prices the transaction at the current market price rather than the one
+// of the last poll
+func (f *feeHistoryEstimator) rebase(ctx context.Context) {
+	if f.config.EvmGasBumpStrategy() == BumpStrategyRebase {
+		f.refresh(ctx)
+	}
+}
+
+// pricesFromFeeHistory returns the base fee of the pending block and the
+// tip cap at EVM.GasEstimator.FeeHistory.RewardPercentile of the per-block
+// rewards, which the node computes at the same percentile.
+//
+// baseFeePerGas contains one more entry than there are blocks in the
+// response, the last entry being the base fee of the next block.
+func (f *feeHistoryEstimator) pricesFromFeeHistory(res feeHistoryResult) (baseFee, tipCap *assets.Wei) {
+	if l := len(res.BaseFee); l > 0 && res.BaseFee[l-1] != nil {
+		baseFee = (*assets.Wei)(res.BaseFee[l-1])
+	} else {
+		f.logger.Warn("eth_feeHistory response did not include baseFeePerGas")
+	}
+
+	var rewards []*assets.Wei
+	for _, blockRewards := range res.Reward {
+		// Nodes which don't track percentiles return empty reward arrays
+		if len(blockRewards) == 0 || blockRewards[0] == nil {
+			continue
+		}
+		rewards = append(rewards, (*assets.Wei)(blockRewards[0]))
+	}
+	if len(rewards) == 0 {
+		f.logger.Warnw("eth_feeHistory response did not include any rewards. Using EvmGasTipCapDefault as fallback.", "oldestBlock", res.OldestBlock)
+		return baseFee, f.config.EvmGasTipCapDefault()
+	}
+	sort.Slice(rewards, func(i, j int) bool { return rewards[i].Cmp(rewards[j]) < 0 })
+	percentile := int(f.config.FeeHistoryEstimatorRewardPercentile())
+	tipCap = rewards[((len(rewards)-1)*percentile)/100]
+
+	max := f.config.EvmMaxGasPriceWei()
+	min := f.config.EvmGasTipCapMinimum()
+	if tipCap.Cmp(max) > 0 {
+		f.logger.Warnw(fmt.Sprintf("Calculated gas tip cap of %s exceeds EVM.GasEstimator.PriceMax=%[2]s, setting gas tip cap to the maximum allowed value of %[2]s instead", tipCap.String(), max.String()), "tipCapWei", tipCap, "minTipCapWei", min, "maxTipCapWei", max)
+		tipCap = max
+		f.metrics.maxPriceCapped.Inc()
+	} else if tipCap.Cmp(min) < 0 {
+		f.logger.Warnw(fmt.Sprintf("Calculated gas tip cap of %s falls below EVM.GasEstimator.TipCapMin=%[2]s, setting gas tip cap to the minimum allowed value of %[2]s instead", tipCap.String(), min.String()), "tipCapWei", tipCap, "minTipCapWei", min, "maxTipCapWei", max)
+		tipCap = min
+	}
+	return baseFee, tipCap
+}
+
+func (f *feeHistoryEstimator) getPrices() (baseFee, tipCap *assets.Wei) {
+	f.priceMu.RLock()
+	defer f.priceMu.RUnlock()
+	return f.baseFee, f.tipCap
+}
+
+// getGasPrice returns the legacy gas price as the sum of the base fee and tip cap
+func (f *feeHistoryEstimator) getGasPrice() *assets.Wei {
+	baseFee, tipCap := f.getPrices()
+	if baseFee == nil || tipCap == nil {
+		return nil
+	}
+	return baseFee.Add(tipCap)
+}
+
+func (f *feeHistoryEstimator) GetLegacyGas(_ context.Context, _ []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, _ ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &f.chainID, "FeeHistory") }()
+	ok := f.IfStarted(func() {
+		gasPrice = f.getGasPrice()
+	})
+	if !ok {
+		return nil, 0, errors.New("FeeHistoryEstimator is not started; cannot estimate gas")
+	}
+	if gasPrice == nil {
+		return nil, 0, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate gas; fee history not fetched yet")}
+	}
+	estimatedGasPrice := gasPrice
+	gasPrice, chainSpecificGasLimit = capGasPrice(gasPrice, maxGasPriceWei, f.config.EvmMaxGasPriceWei(), gasLimit, f.config.EvmGasLimitMultiplier())
+	f.metrics.recordCap(estimatedGasPrice, gasPrice)
+	return
+}
+
+func (f *feeHistoryEstimator) BumpLegacyGas(ctx context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &f.chainID, "FeeHistory") }()
+	f.rebase(ctx)
+	bumpedGasPrice, chainSpecificGasLimit, err = BumpLegacyGasPriceOnly(f.config, f.logger, f.getGasPrice(), originalGasPrice, gasLimit, maxGasPriceWei)
+	f.metrics.recordLegacyBump(err)
+	return
+}
+
+func (f *feeHistoryEstimator) GetDynamicFee(_ context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &f.chainID, "FeeHistory") }()
+	var baseFee, tipCap *assets.Wei
+	ok := f.IfStarted(func() {
+		baseFee, tipCap = f.getPrices()
+	})
+	if !ok {
+		return fee, 0, errors.New("FeeHistoryEstimator is not started; cannot estimate gas")
+	}
+	if tipCap == nil {
+		return fee, 0, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate dynamic fee; fee history not fetched yet")}
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, f.config.EvmMaxGasPriceWei())
+	if f.config.EvmGasBumpThreshold() == 0 {
+		// just use the max gas price if gas bumping is disabled
+		fee.FeeCap = maxGasPrice
+	} else if baseFee != nil {
+		if fee.FeeCap, err = f.calcFeeCap(baseFee, tipCap, maxGasPrice); err != nil {
+			return fee, 0, err
+		}
+	} else {
+		return fee, 0, errors.New("FeeHistoryEstimator: no value for latest block base fee; cannot estimate EIP-1559 base fee. Are you trying to run with EIP1559 enabled on a non-EIP1559 chain?")
+	}
+	fee.TipCap = tipCap
+	if fee, err = applyTipCapMin(f.config, fee, maxGasPrice); err != nil {
+		return fee, 0, err
+	}
+	chainSpecificGasLimit = commonfee.ApplyMultiplier(gasLimit, f.config.EvmGasLimitMultiplier())
+	return
+}
+
+func (f *feeHistoryEstimator) BumpDynamicFee(ctx context.Context, originalFee DynamicFee, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &f.chainID, "FeeHistory") }()
+	f.rebase(ctx)
+	baseFee, tipCap := f.getPrices()
+	bumped, err = bumpDynamicFee(f.config, f.calcFeeCap, f.logger, tipCap, baseFee, originalFee, maxGasPriceWei)
+	f.metrics.recordDynamicBump(err)
+	if err != nil {
+		return bumped, 0, err
+	}
+	chainSpecificGasLimit = commonfee.ApplyMultiplier(gasLimit, f.config.EvmGasLimitMultiplier())
+	return
+}
+
+// calcFeeCap returns the multipliedFeeCap of baseFee
+func (f *feeHistoryEstimator) calcFeeCap(baseFee, tipCap, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
+	return multipliedFeeCap(baseFee, f.config.EvmGasBaseFeeBufferMultiplier(), tipCap, maxGasPriceWei)
+}
@@ -0,0 +1,254 @@
+package gas_test
+
+import (
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func newFeeHistoryConfig() *gas.MockConfig {
+	cfg := gas.NewMockConfig()
+	cfg.FeeHistoryEstimatorBlockCountF = 4
+	cfg.FeeHistoryEstimatorRewardPercentileF = 50
+	cfg.EvmGasBaseFeeBufferMultiplierF = 1
+	cfg.EvmEIP1559DynamicFeesF = true
+	cfg.EvmGasBumpThresholdF = 3
+	cfg.EvmGasBumpPercentF = 10
+	cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+	cfg.EvmGasLimitMultiplierF = 1
+	cfg.EvmGasTipCapDefaultF = assets.NewWeiI(5)
+	cfg.EvmGasTipCapMinimumF = assets.NewWeiI(1)
+	cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000)
+	return cfg
+}
+
+func mockFeeHistory(client *mocks.RPCClient, response string) *mock.Call {
+	return client.On("CallContext", mock.Anything, mock.Anything, "eth_feeHistory", "0x4", "latest", []float64{50}).Return(nil).Run(func(args mock.Arguments) {
+		if err := json.Unmarshal([]byte(response), args.Get(1)); err != nil {
+			panic(err)
+		}
+	})
+}
+
+func TestFeeHistoryEstimator(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.NewWeiI(1000)
+	const gasLimit uint32 = 80000
+
+	t.Run("calling GetDynamicFee on unstarted estimator returns error", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, newFeeHistoryConfig(), *testutils.FixtureChainID)
+		_, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		assert.EqualError(t, err, "FeeHistoryEstimator is not started; cannot estimate gas")
+	})
+
+	t.Run("fails to start with zero block count", func(t *testing.T) {
+		cfg := newFeeHistoryConfig()
+		cfg.FeeHistoryEstimatorBlockCountF = 0
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), mocks.NewRPCClient(t), cfg, *testutils.FixtureChainID)
+		assert.EqualError(t, o.Start(testutils.Context(t)), "FeeHistoryEstimatorBlockCount must be set to a value greater than 0")
+	})
+
+	t.Run("computes tip cap from reward percentile and fee cap from pending base fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockFeeHistory(client, `{
+			"oldestBlock": "0x10",
+			"baseFeePerGas": ["0x64", "0x64", "0x64", "0x64", "0x6e"],
+			"gasUsedRatio": [0.5, 0.5, 0.5, 0.5],
+			"reward": [["0xa"], ["0x14"], ["0x1e"], ["0x28"]]
+		}`)
+
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, newFeeHistoryConfig(), *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		fee, chainSpecificGasLimit, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(20), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(130), fee.FeeCap) // 110 base fee + 20 tip cap
+		assert.Equal(t, gasLimit, chainSpecificGasLimit)
+
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(130), gasPrice)
+	})
+
+	t.Run("buffers the base fee of the fee cap by BaseFeeBufferMultiplier", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockFeeHistory(client, `{
+			"oldestBlock": "0x10",
+			"baseFeePerGas": ["0x64", "0x64", "0x64", "0x64", "0x6e"],
+			"gasUsedRatio": [0.5, 0.5, 0.5, 0.5],
+			"reward": [["0xa"], ["0x14"], ["0x1e"], ["0x28"]]
+		}`)
+
+		cfg := newFeeHistoryConfig()
+		cfg.EvmGasBaseFeeBufferMultiplierF = 1.5
+		// the BlockHistory buffer doesn't apply to the FeeHistory mode
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = 4
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, cfg, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(20), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(185), fee.FeeCap) // 110 base fee * 1.5 + 20 tip cap
+	})
+
+	t.Run("falls back to EvmGasTipCapDefault when node returns empty rewards", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockFeeHistory(client, `{
+			"oldestBlock": "0x10",
+			"baseFeePerGas": ["0x64", "0x64", "0x64", "0x64", "0x64"],
+			"gasUsedRatio": [0.5, 0.5, 0.5, 0.5],
+			"reward": [[], [], [], []]
+		}`)
+
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, newFeeHistoryConfig(), *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(5), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(105), fee.FeeCap)
+	})
+
+	t.Run("handles chains with zero base fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockFeeHistory(client, `{
+			"oldestBlock": "0x10",
+			"baseFeePerGas": ["0x0", "0x0", "0x0", "0x0", "0x0"],
+			"gasUsedRatio": [0, 0, 0, 0],
+			"reward": [["0x2"], ["0x2"], ["0x2"], ["0x2"]]
+		}`)
+
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, newFeeHistoryConfig(), *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(2), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(2), fee.FeeCap)
+	})
+
+	t.Run("returns error if initial fee history call failed", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_feeHistory", "0x4", "latest", []float64{50}).Return(errors.New("kaboom"))
+
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, newFeeHistoryConfig(), *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		_, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		assert.EqualError(t, err, "failed to estimate dynamic fee; fee history not fetched yet")
+		_, _, err = o.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		assert.EqualError(t, err, "failed to estimate gas; fee history not fetched yet")
+	})
+
+	t.Run("BumpDynamicFee bumps from the original fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockFeeHistory(client, `{
+			"oldestBlock": "0x10",
+			"baseFeePerGas": ["0x64", "0x64", "0x64", "0x64", "0x64"],
+			"gasUsedRatio": [0.5, 0.5, 0.5, 0.5],
+			"reward": [["0xa"], ["0xa"], ["0xa"], ["0xa"]]
+		}`)
+
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, newFeeHistoryConfig(), *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		original := gas.DynamicFee{TipCap: assets.NewWeiI(20), FeeCap: assets.NewWeiI(200)}
+		bumped, _, err := o.BumpDynamicFee(testutils.Context(t), original, gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(22), bumped.TipCap)
+		assert.Equal(t, assets.NewWeiI(220), bumped.FeeCap)
+	})
+
+	t.Run("bumps with the Rebase strategy refresh one at a time with the poller", func(t *testing.T) {
+		var inFlight atomic.Int32
+		var concurrent atomic.Bool
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_feeHistory", "0x4", "latest", []float64{50}).Return(nil).Run(func(args mock.Arguments) {
+			if inFlight.Add(1) > 1 {
+				concurrent.Store(true)
+			}
+			defer inFlight.Add(-1)
+			time.Sleep(time.Millisecond)
+			assert.NoError(t, json.Unmarshal([]byte(`{
+				"oldestBlock": "0x10",
+				"baseFeePerGas": ["0x64", "0x64", "0x64", "0x64", "0x64"],
+				"gasUsedRatio": [0.5, 0.5, 0.5, 0.5],
+				"reward": [["0xa"], ["0xa"], ["0xa"], ["0xa"]]
+			}`), args.Get(1)))
+		})
+
+		cfg := newFeeHistoryConfig()
+		cfg.EvmGasBumpStrategyF = gas.BumpStrategyRebase
+		o := gas.NewFeeHistoryEstimator(logger.TestLogger(t), client, cfg, *testutils.FixtureChainID)
+		gas.SetFeeHistoryPollPeriod(o, time.Millisecond)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					_, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(100), gasLimit, maxGasPrice, nil)
+					assert.NoError(t, err)
+				}
+			}()
+		}
+		wg.Wait()
+		assert.False(t, concurrent.Load(), "fee history was refreshed concurrently")
+	})
+}
+
+func TestQuorumFeeHistoryEstimator(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.NewWeiI(1_000_000)
+	feeHistory := func(baseFee, reward string) string {
+		return `{
+			"oldestBlock": "0x10",
+			"baseFeePerGas": ["0x64", "0x64", "0x64", "0x64", "` + baseFee + `"],
+			"gasUsedRatio": [0.5, 0.5, 0.5, 0.5],
+			"reward": [["` + reward + `"], ["` + reward + `"], ["` + reward + `"], ["` + reward + `"]]
+		}`
+	}
+	c1, c2, c3 := mocks.NewRPCClient(t), mocks.NewRPCClient(t), mocks.NewRPCClient(t)
+	mockFeeHistory(c1, feeHistory("0x6e", "0x14")).Once()        // 110 base fee, 20 tip cap
+	mockFeeHistory(c2, feeHistory("0x10c8e0", "0x30d40")).Once() // 10000x outliers
+	mockFeeHistory(c3, feeHistory("0x64", "0xa")).Once()         // 100 base fee, 10 tip cap
+	cfg := newFeeHistoryConfig()
+	cfg.EvmMaxGasPriceWeiF = maxGasPrice
+
+	o, err := gas.NewQuorumFeeHistoryEstimator(logger.TestLogger(t), cfg, *testutils.FixtureChainID, 2, c1, c2, c3)
+	require.NoError(t, err)
+	require.NoError(t, o.Start(testutils.Context(t)))
+	t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+	fee, _, err := o.GetDynamicFee(testutils.Context(t), 80000, maxGasPrice)
+	require.NoError(t, err)
+	assert.Equal(t, assets.NewWeiI(20), fee.TipCap)
+	assert.Equal(t, assets.NewWeiI(130), fee.FeeCap) // 110 base fee + 20 tip cap
+}
@@ -0,0 +1,105 @@
+package gas
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// ErrFeeOverflow is returned when a fee computation yields a value that does
+// not fit the 256 bit fee fields of a transaction, e.g. because of an extreme
+// multiplier in the config
+var ErrFeeOverflow = errors.New("fee overflows uint256")
+
+// maxFee is the largest fee a transaction can carry, 2^256 - 1 wei
+var maxFee = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+// roundingMode is the rounding of the division of mulDiv
+type roundingMode int
+
+const (
+	roundDown roundingMode = iota
+	roundUp
+)
+
+// checkedFee returns x as a Wei, or an error if it is negative or overflows
+// uint256
+func checkedFee(x *big.Int, op string) (*assets.Wei, error) {
+	if x.Sign() < 0 {
+		return nil, errors.Errorf("%s is negative: %s", op, x)
+	}
+	if x.Cmp(maxFee) > 0 {
+		return nil, errors.Wrapf(ErrFeeOverflow, "%s has %d bits", op, x.BitLen())
+	}
+	return assets.NewWei(x), nil
+}
+
+// mulDiv returns x * mul / div, rounded by mode
+func mulDiv(x *assets.Wei, mul, div *big.Int, mode roundingMode) (*assets.Wei, error) {
+	if x == nil {
+		return nil, errors.New("mulDiv of nil fee")
+	}
+	if div.Sign() <= 0 {
+		return nil, errors.Errorf("mulDiv by non-positive divisor %s", div)
+	}
+	product := new(big.Int).Mul(x.ToInt(), mul)
+	quo, rem := new(big.Int).QuoRem(product, div, new(big.Int))
+	if mode == roundUp && rem.Sign() > 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	return checkedFee(quo, "fee of "+x.String()+" * "+mul.String()+" / "+div.String())
+}
+
+// mulMultiplier returns x * multiplier, rounded up
+func mulMultiplier(x *assets.Wei, multiplier float32) (*assets.Wei, error) {
+	if x == nil {
+		return nil, errors.New("mulMultiplier of nil fee")
+	}
+	m := decimal.NewFromFloat32(multiplier)
+	if m.IsNegative() {
+		return nil, errors.Errorf("mulMultiplier by negative multiplier %s", m)
+	}
+	product := decimal.NewFromBigInt(x.ToInt(), 0).Mul(m).Ceil()
+	return checkedFee(product.BigInt(), "fee of "+x.String()+" * "+m.String())
+}
+
+// addCapped returns x + y, or max if the sum is greater. A nil max leaves the
+// sum uncapped.
+func addCapped(x, y, max *assets.Wei) (*assets.Wei, error) {
+	if x == nil || y == nil {
+		return nil, errors.New("addCapped of nil fee")
+	}
+	sum, err := checkedFee(new(big.Int).Add(x.ToInt(), y.ToInt()), "fee of "+x.String()+" + "+y.String())
+	if err != nil {
+		return nil, err
+	}
+	if max != nil && sum.Cmp(max) > 0 {
+		return max, nil
+	}
+	return sum, nil
+}
+
+// roundUpCapped returns x rounded up to a multiple of step, or max if that is
+// greater. An x that is already greater than max is returned as is.
+func roundUpCapped(x, step, max *assets.Wei) *assets.Wei {
+	rounded := new(big.Int).Add(x.ToInt(), new(big.Int).Sub(step.ToInt(), big.NewInt(1)))
+	rounded.Sub(rounded, new(big.Int).Mod(rounded, step.ToInt()))
+	if max != nil && rounded.Cmp(max.ToInt()) > 0 {
+		return assets.WeiMax(x, max)
+	}
+	return assets.NewWei(rounded)
+}
+
+// roundDownTo returns x rounded down to a multiple of step
+func roundDownTo(x, step *assets.Wei) *assets.Wei {
+	return assets.NewWei(new(big.Int).Sub(x.ToInt(), new(big.Int).Mod(x.ToInt(), step.ToInt())))
+}
+
+// percentBump returns x increased by percent, rounded down as by
+// assets.Wei.AddPercentage
+func percentBump(x *assets.Wei, percent uint16) (*assets.Wei, error) {
+	return mulDiv(x, big.NewInt(100+int64(percent)), big.NewInt(100), roundDown)
+}
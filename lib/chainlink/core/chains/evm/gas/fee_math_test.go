@@ -0,0 +1,146 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+var maxUint256 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+
+func TestFeeMath(t *testing.T) {
+	t.Parallel()
+
+	t.Run("MulDiv rounds by the rounding mode", func(t *testing.T) {
+		down, err := gas.MulDiv(assets.NewWeiI(100), big.NewInt(9), big.NewInt(8), gas.RoundDown)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(112), down)
+
+		up, err := gas.MulDiv(assets.NewWeiI(100), big.NewInt(9), big.NewInt(8), gas.RoundUp)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(113), up)
+
+		exact, err := gas.MulDiv(assets.NewWeiI(80), big.NewInt(9), big.NewInt(8), gas.RoundUp)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(90), exact)
+	})
+
+	t.Run("MulDiv returns ErrFeeOverflow beyond uint256", func(t *testing.T) {
+		_, err := gas.MulDiv(assets.NewWei(maxUint256), big.NewInt(2), big.NewInt(1), gas.RoundDown)
+		require.ErrorIs(t, err, gas.ErrFeeOverflow)
+
+		max, err := gas.MulDiv(assets.NewWei(maxUint256), big.NewInt(2), big.NewInt(2), gas.RoundDown)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWei(maxUint256), max)
+	})
+
+	t.Run("MulDiv rejects negative results and non-positive divisors", func(t *testing.T) {
+		_, err := gas.MulDiv(assets.NewWeiI(1), big.NewInt(-1), big.NewInt(1), gas.RoundDown)
+		require.EqualError(t, err, "fee of 1 wei * -1 / 1 is negative: -1")
+		_, err = gas.MulDiv(assets.NewWeiI(1), big.NewInt(1), big.NewInt(0), gas.RoundDown)
+		require.EqualError(t, err, "mulDiv by non-positive divisor 0")
+	})
+
+	t.Run("AddCapped caps the sum at max and checks for overflow", func(t *testing.T) {
+		sum, err := gas.AddCapped(assets.NewWeiI(1), assets.NewWeiI(2), assets.NewWeiI(10))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(3), sum)
+
+		sum, err = gas.AddCapped(assets.NewWeiI(8), assets.NewWeiI(3), assets.NewWeiI(10))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(10), sum)
+
+		sum, err = gas.AddCapped(assets.NewWeiI(8), assets.NewWeiI(3), nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(11), sum)
+
+		_, err = gas.AddCapped(assets.NewWei(maxUint256), assets.NewWeiI(1), assets.NewWeiI(10))
+		require.ErrorIs(t, err, gas.ErrFeeOverflow)
+	})
+
+	t.Run("PercentBump rounds down like AddPercentage", func(t *testing.T) {
+		bumped, err := gas.PercentBump(assets.NewWeiI(15), 10)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(15).AddPercentage(10), bumped)
+		assert.Equal(t, assets.NewWeiI(16), bumped)
+	})
+
+	t.Run("calcFeeCap returns ErrFeeOverflow for extreme buffer blocks", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = 2000
+		_, err := gas.CalcFeeCap(assets.GWei(1), cfg, assets.GWei(1), assets.GWei(1000))
+		require.ErrorIs(t, err, gas.ErrFeeOverflow)
+
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = 200
+		feeCap, err := gas.CalcFeeCap(assets.GWei(1), cfg, assets.GWei(1), assets.GWei(1000))
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(1000), feeCap)
+	})
+}
+
+// fuzzWei returns the big-endian integer of b, truncated to bits bits
+func fuzzWei(b []byte, bits uint) *assets.Wei {
+	x := new(big.Int).SetBytes(b)
+	x.And(x, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bits), big.NewInt(1)))
+	return assets.NewWei(x)
+}
+
+// requireFeeWithinMax asserts that the fee is in [0, max], or that err is an
+// overflow or bump error
+func requireFeeWithinMax(t *testing.T, fee, max *assets.Wei, err error) {
+	if err != nil {
+		require.True(t, errors.Is(err, gas.ErrFeeOverflow) || errors.Is(err, gas.ErrBumpLimitExceeded) || errors.Is(err, gas.ErrBump), "unexpected error: %v", err)
+		return
+	}
+	require.True(t, fee.Cmp(assets.NewWeiI(0)) >= 0, "fee %s is negative", fee)
+	require.True(t, fee.Cmp(max) <= 0, "fee %s exceeds max %s", fee, max)
+}
+
+func FuzzBumpLegacyGasPriceOnly(f *testing.F) {
+	f.Add([]byte{0x04, 0xa8, 0x17, 0xc8, 0x00}, uint16(10), []byte{0x01}, []byte{0x17, 0x48, 0x76, 0xe8, 0x00})
+	f.Add([]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint16(900), []byte{0x01}, maxUint256.Bytes())
+	f.Add([]byte{0x01}, uint16(900), maxUint256.Bytes(), maxUint256.Bytes())
+	f.Add([]byte{}, uint16(0), []byte{}, []byte{0x01})
+	f.Fuzz(func(t *testing.T, price []byte, bumpPercent uint16, bumpWei []byte, max []byte) {
+		cfg := gas.NewMockConfig()
+		// Up to 10x
+		cfg.EvmGasBumpPercentF = bumpPercent % 901
+		cfg.EvmGasBumpWeiF = fuzzWei(bumpWei, 256)
+		cfg.EvmMaxGasPriceWeiF = fuzzWei(max, 256)
+		cfg.EvmGasLimitMultiplierF = 1
+
+		bumped, _, err := gas.BumpLegacyGasPriceOnly(cfg, logger.TestLogger(t), nil, fuzzWei(price, 200), 21_000, cfg.EvmMaxGasPriceWeiF)
+		requireFeeWithinMax(t, bumped, cfg.EvmMaxGasPriceWeiF, err)
+	})
+}
+
+func FuzzBumpDynamicFeeOnly(f *testing.F) {
+	f.Add([]byte{0x3b, 0x9a, 0xca, 0x00}, []byte{0x04, 0xa8, 0x17, 0xc8, 0x00}, []byte{0x04, 0xa8, 0x17, 0xc8, 0x00}, uint16(10), uint16(4), []byte{0x17, 0x48, 0x76, 0xe8, 0x00})
+	f.Add([]byte{0x01}, []byte{0x01}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, uint16(900), uint16(2000), maxUint256.Bytes())
+	f.Add([]byte{}, []byte{}, []byte{}, uint16(0), uint16(0), []byte{})
+	f.Fuzz(func(t *testing.T, tipCap []byte, feeCap []byte, baseFee []byte, bumpPercent uint16, bufferBlocks uint16, max []byte) {
+		cfg := gas.NewMockConfig()
+		// Up to 10x
+		cfg.EvmGasBumpPercentF = bumpPercent % 901
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(0)
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = bufferBlocks % 2048
+		cfg.EvmMaxGasPriceWeiF = fuzzWei(max, 256)
+		cfg.EvmGasLimitMultiplierF = 1
+
+		original := gas.DynamicFee{TipCap: fuzzWei(tipCap, 200), FeeCap: fuzzWei(feeCap, 200)}
+		currentBaseFee := fuzzWei(baseFee, 200)
+		bumped, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), nil, currentBaseFee, original, 21_000, cfg.EvmMaxGasPriceWeiF)
+		requireFeeWithinMax(t, bumped.TipCap, cfg.EvmMaxGasPriceWeiF, err)
+		if err == nil {
+			requireFeeWithinMax(t, bumped.FeeCap, cfg.EvmMaxGasPriceWeiF, nil)
+		}
+	})
+}
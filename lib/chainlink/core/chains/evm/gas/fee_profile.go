@@ -0,0 +1,143 @@
+package gas
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	commonfee "github.com/smartcontractkit/chainlink/v2/common/fee"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/label"
+)
+
+// FeeProfile overrides the chain's fee config for the transactions estimated
+// with WithProfile, e.g. to price liquidations more aggressively than upkeeps
+// that can wait. Unset fields use the chain's config.
+type FeeProfile struct {
+	// TipCapPercentile replaces EVM.GasEstimator.BlockHistory.TransactionPercentile
+	// for the tip caps of the BlockHistory estimator
+	TipCapPercentile *uint16
+	// BumpPercent is the minimum percentage by which bumps increase the fee.
+	// Since the estimator still bumps by EVM.GasEstimator.BumpStrategy, it can
+	// only make bumps more aggressive.
+	BumpPercent *uint16
+	// PriceMax lowers EVM.GasEstimator.PriceMax. It can't raise it, since the
+	// estimators never exceed the chain's maximum.
+	PriceMax *assets.Wei
+	// LimitMultiplier replaces EVM.GasEstimator.LimitMultiplier
+	LimitMultiplier *float32
+}
+
+// Validate returns an error if the profile has invalid overrides
+func (p FeeProfile) Validate() error {
+	if p.TipCapPercentile != nil && *p.TipCapPercentile > 100 {
+		return errors.Errorf("TipCapPercentile of %d is greater than 100", *p.TipCapPercentile)
+	}
+	if p.LimitMultiplier != nil && *p.LimitMultiplier <= 0 {
+		return errors.Errorf("LimitMultiplier of %v must be greater than 0", *p.LimitMultiplier)
+	}
+	if p.PriceMax != nil && p.PriceMax.IsZero() {
+		return errors.New("PriceMax must be greater than 0")
+	}
+	return nil
+}
+
+type profileNameKey struct{}
+
+type feeProfileKey struct{}
+
+// WithProfile returns a context that makes GetFee and BumpFee use the fee
+// profile with the given name, from WrappedEvmEstimator.Profiles
+func WithProfile(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, profileNameKey{}, name)
+}
+
+func profileNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(profileNameKey{}).(string)
+	return name
+}
+
+// feeProfileFromContext returns the profile resolved by the
+// WrappedEvmEstimator, for the estimators that apply profile overrides
+// themselves
+func feeProfileFromContext(ctx context.Context) FeeProfile {
+	profile, _ := ctx.Value(feeProfileKey{}).(FeeProfile)
+	return profile
+}
+
+// resolveProfile returns the profile named in ctx, and a context carrying it
+// to the estimator. An unknown or invalid profile falls back to the chain's
+// config with a warning, since failing the estimate would block the
+// transaction.
+func (e WrappedEvmEstimator) resolveProfile(ctx context.Context) (context.Context, string, FeeProfile) {
+	name := profileNameFromContext(ctx)
+	if name == "" {
+		return ctx, "", FeeProfile{}
+	}
+	profile, ok := e.Profiles[name]
+	if !ok {
+		e.lggr.Warnw("Unknown fee profile, using the chain's fee config", "profile", name)
+		return ctx, "", FeeProfile{}
+	}
+	if err := profile.Validate(); err != nil {
+		e.lggr.Warnw("Invalid fee profile, using the chain's fee config", "profile", name, "err", err)
+		return ctx, "", FeeProfile{}
+	}
+	return context.WithValue(ctx, feeProfileKey{}, profile), name, profile
+}
+
+// profileMaxPrice returns the lower of maxFeePrice and the PriceMax of the profile
+func profileMaxPrice(profile FeeProfile, maxFeePrice *assets.Wei) *assets.Wei {
+	if profile.PriceMax == nil {
+		return maxFeePrice
+	}
+	return getMaxGasPrice(profile.PriceMax, maxFeePrice)
+}
+
+// profileFeeLimit rescales the fee limit returned by the estimator from
+// EVM.GasEstimator.LimitMultiplier to the LimitMultiplier of the profile
+func (e WrappedEvmEstimator) profileFeeLimit(profile FeeProfile, chainSpecificFeeLimit uint32) uint32 {
+	if profile.LimitMultiplier == nil {
+		return chainSpecificFeeLimit
+	}
+	chainMultiplier := e.cfg.EvmGasLimitMultiplier()
+	if chainMultiplier <= 0 || *profile.LimitMultiplier == chainMultiplier {
+		return chainSpecificFeeLimit
+	}
+	return commonfee.ApplyMultiplier(chainSpecificFeeLimit, *profile.LimitMultiplier/chainMultiplier)
+}
+
+// profileBump raises the fee bumped by the estimator to at least the
+// original fee bumped by the BumpPercent of the profile
+func (e WrappedEvmEstimator) profileBump(profile FeeProfile, originalFee, bumpedFee EvmFee, maxFeePrice *assets.Wei) (EvmFee, error) {
+	if profile.BumpPercent == nil {
+		return bumpedFee, nil
+	}
+	atLeast := func(bumped, original *assets.Wei, name string) (*assets.Wei, error) {
+		min, err := bumpFeePrice(original, *profile.BumpPercent, e.cfg.EvmGasBumpWei())
+		if err != nil {
+			return nil, err
+		}
+		bumped = assets.WeiMax(bumped, min)
+		if bumped.Cmp(maxFeePrice) > 0 {
+			return maxFeePrice, &EstimationError{Price: bumped, Limit: maxFeePrice, Reason: ErrBumpLimitExceeded,
+				Err: errors.Wrapf(ErrBumpGasExceedsLimit, "bumped %s of %s would exceed configured max gas price of %s (original %s was %s). %s",
+					name, bumped, maxFeePrice, name, original, label.NodeConnectivityProblemWarning)}
+		}
+		return bumped, nil
+	}
+	var err error
+	if originalFee.ValidDynamic() {
+		if bumpedFee.DynamicTipCap, err = atLeast(bumpedFee.DynamicTipCap, originalFee.DynamicTipCap, "tip cap"); err != nil {
+			return EvmFee{}, err
+		}
+		if bumpedFee.DynamicFeeCap, err = atLeast(bumpedFee.DynamicFeeCap, originalFee.DynamicFeeCap, "fee cap"); err != nil {
+			return EvmFee{}, err
+		}
+		// A fee cap below the tip cap would make the transaction invalid
+		bumpedFee.DynamicFeeCap = assets.WeiMax(bumpedFee.DynamicFeeCap, bumpedFee.DynamicTipCap)
+		return bumpedFee, nil
+	}
+	bumpedFee.Legacy, err = atLeast(bumpedFee.Legacy, originalFee.Legacy, "gas price")
+	return bumpedFee, err
+}
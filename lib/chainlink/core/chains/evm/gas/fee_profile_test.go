@@ -0,0 +1,165 @@
+package gas_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestWrappedEvmEstimator_FeeProfiles(t *testing.T) {
+	t.Parallel()
+
+	newBHE := func(t *testing.T) (*gas.BlockHistoryEstimator, *gas.MockConfig) {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(35)
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = uint16(0)
+		cfg.EvmGasBumpThresholdF = uint64(1)
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000000)
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasFeeCacheTTLF = time.Minute
+
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{
+				BaseFeePerGas: assets.NewWeiI(100000),
+				Number:        1,
+				Hash:          utils.NewHash(),
+				Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000, 9000, 10000),
+			},
+		})
+		bhe.Recalculate(cltest.Head(1))
+		gas.SimulateStart(t, bhe)
+		h := cltest.Head(1)
+		h.BaseFeePerGas = assets.NewWeiI(100000)
+		bhe.OnNewLongestChain(testutils.Context(t), h)
+		return bhe, cfg
+	}
+
+	t.Run("concurrent calls with different profiles get tip caps from their own percentiles", func(t *testing.T) {
+		bhe, cfg := newBHE(t)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+		estimator.(*gas.WrappedEvmEstimator).Profiles = map[string]gas.FeeProfile{
+			"upkeep":      {TipCapPercentile: testutils.Ptr[uint16](10)},
+			"liquidation": {TipCapPercentile: testutils.Ptr[uint16](90)},
+		}
+
+		var wg sync.WaitGroup
+		fees := make(map[string]gas.EvmFee)
+		var mu sync.Mutex
+		for _, profile := range []string{"upkeep", "liquidation", ""} {
+			profile := profile
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx := testutils.Context(t)
+				if profile != "" {
+					ctx = gas.WithProfile(ctx, profile)
+				}
+				fee, _, err := estimator.GetFee(ctx, nil, 100000, nil)
+				assert.NoError(t, err)
+				mu.Lock()
+				defer mu.Unlock()
+				fees[profile] = fee
+			}()
+		}
+		wg.Wait()
+
+		assert.Equal(t, gas.EvmFee{DynamicFeeCap: assets.NewWeiI(101000), DynamicTipCap: assets.NewWeiI(1000), ValidUntilBlock: 65}, fees["upkeep"])
+		assert.Equal(t, gas.EvmFee{DynamicFeeCap: assets.NewWeiI(109000), DynamicTipCap: assets.NewWeiI(9000), ValidUntilBlock: 65}, fees["liquidation"])
+		// the chain's percentile of 35
+		assert.Equal(t, gas.EvmFee{DynamicFeeCap: assets.NewWeiI(104000), DynamicTipCap: assets.NewWeiI(4000), ValidUntilBlock: 65}, fees[""])
+	})
+
+	t.Run("unknown profiles fall back to the chain's fee config", func(t *testing.T) {
+		bhe, cfg := newBHE(t)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+
+		fee, limit, err := estimator.GetFee(gas.WithProfile(testutils.Context(t), "unknown"), nil, 100000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gas.EvmFee{DynamicFeeCap: assets.NewWeiI(104000), DynamicTipCap: assets.NewWeiI(4000), ValidUntilBlock: 65}, fee)
+		assert.Equal(t, uint32(100000), limit)
+	})
+
+	t.Run("invalid profiles fall back to the chain's fee config", func(t *testing.T) {
+		bhe, cfg := newBHE(t)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+		estimator.(*gas.WrappedEvmEstimator).Profiles = map[string]gas.FeeProfile{
+			"invalid": {TipCapPercentile: testutils.Ptr[uint16](101)},
+		}
+
+		fee, _, err := estimator.GetFee(gas.WithProfile(testutils.Context(t), "invalid"), nil, 100000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(4000), fee.DynamicTipCap)
+	})
+
+	t.Run("PriceMax and LimitMultiplier override the chain's fee config", func(t *testing.T) {
+		bhe, cfg := newBHE(t)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+		estimator.(*gas.WrappedEvmEstimator).Profiles = map[string]gas.FeeProfile{
+			"capped": {PriceMax: assets.NewWeiI(102000), LimitMultiplier: testutils.Ptr[float32](1.5)},
+		}
+
+		fee, limit, err := estimator.GetFee(gas.WithProfile(testutils.Context(t), "capped"), nil, 100000, nil)
+		require.NoError(t, err)
+		// the capped fee cap doesn't cover the base fee with the full tip cap
+		assert.Equal(t, gas.EvmFee{DynamicFeeCap: assets.NewWeiI(102000), DynamicTipCap: assets.NewWeiI(4000), ValidUntilBlock: 1}, fee)
+		assert.Equal(t, uint32(150000), limit)
+	})
+
+	t.Run("BumpPercent makes bumps more aggressive", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceDefaultF = assets.NewWeiI(1000)
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000000)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil)
+		estimator.(*gas.WrappedEvmEstimator).Profiles = map[string]gas.FeeProfile{
+			"aggressive": {BumpPercent: testutils.Ptr[uint16](50)},
+			"capped":     {BumpPercent: testutils.Ptr[uint16](50), PriceMax: assets.NewWeiI(1200)},
+		}
+		original := gas.EvmFee{Legacy: assets.NewWeiI(1000)}
+		var attempts []txmgrtypes.PriorAttempt[gas.EvmFee, common.Hash]
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), original, 100000, nil, attempts)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(1100), bumped.Legacy)
+
+		bumped, _, err = estimator.BumpFee(gas.WithProfile(testutils.Context(t), "aggressive"), original, 100000, nil, attempts)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(1500), bumped.Legacy)
+
+		bumped, _, err = estimator.BumpFee(gas.WithProfile(testutils.Context(t), "capped"), original, 100000, nil, attempts)
+		require.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+		assert.True(t, gas.IsBumpErr(err))
+		assert.Equal(t, assets.NewWeiI(1200), bumped.Legacy)
+	})
+}
+
+func TestFeeProfile_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, gas.FeeProfile{}.Validate())
+	assert.NoError(t, gas.FeeProfile{TipCapPercentile: testutils.Ptr[uint16](100), LimitMultiplier: testutils.Ptr[float32](0.5), PriceMax: assets.NewWeiI(1)}.Validate())
+	assert.EqualError(t, gas.FeeProfile{TipCapPercentile: testutils.Ptr[uint16](101)}.Validate(), "TipCapPercentile of 101 is greater than 100")
+	assert.EqualError(t, gas.FeeProfile{LimitMultiplier: testutils.Ptr[float32](0)}.Validate(), "LimitMultiplier of 0 must be greater than 0")
+	assert.EqualError(t, gas.FeeProfile{PriceMax: assets.NewWeiI(0)}.Validate(), "PriceMax must be greater than 0")
+}
@@ -0,0 +1,41 @@
+package gas
+
+import (
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// roundFee rounds the prices of fee up to a multiple of
+// EVM.GasEstimator.FeeRounding, if set, so that they don't stand out as exact
+// wei values. Rounding up never drops a price below the estimate, and never
+// takes it past maxFeePrice, where it is capped instead. The tip cap is
+// rounded within the rounded fee cap.
+//
+// maxCostPrice is the highest price at which the transaction stays within its
+// cost limits, see maxCostPrice, or nil if there are none. A price that would
+// be rounded up past it is rounded down instead, unless that would take it
+// below minViableFee, in which case it is left as is.
+func (e WrappedEvmEstimator) roundFee(fee EvmFee, maxFeePrice, maxCostPrice *assets.Wei) EvmFee {
+	step := e.cfg.EvmGasFeeRounding()
+	if step == nil || step.IsZero() {
+		return fee
+	}
+	round := func(x *assets.Wei, dynamic bool) *assets.Wei {
+		rounded := roundUpCapped(x, step, maxFeePrice)
+		if maxCostPrice == nil || rounded.Cmp(maxCostPrice) <= 0 {
+			return rounded
+		}
+		floor, _ := e.minViableFee(dynamic)
+		if down := roundDownTo(x, step); down.Cmp(floor) >= 0 {
+			return down
+		}
+		return x
+	}
+	if fee.Legacy != nil {
+		fee.Legacy = round(fee.Legacy, false)
+	}
+	if fee.ValidDynamic() {
+		fee.DynamicFeeCap = round(fee.DynamicFeeCap, true)
+		fee.DynamicTipCap = assets.WeiMin(roundUpCapped(fee.DynamicTipCap, step, fee.DynamicFeeCap), fee.DynamicFeeCap)
+	}
+	return fee
+}
@@ -0,0 +1,191 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_FeeRounding(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21_000
+	newConfig := func(rounding, maxGasPrice *assets.Wei, dynamic bool) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasFeeRoundingF = rounding
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmEIP1559DynamicFeesF = dynamic
+		return cfg
+	}
+
+	t.Run("rounds legacy fees up", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			rounding *assets.Wei
+			expected *assets.Wei
+		}{
+			{"off", assets.NewWeiI(0), assets.NewWeiI(23_456_789_123)},
+			{"1 gwei", assets.GWei(1), assets.GWei(24)},
+			{"0.1 gwei", assets.NewWeiI(100_000_000), assets.NewWeiI(23_500_000_000)},
+			{"N wei", assets.NewWeiI(1000), assets.NewWeiI(23_456_790_000)},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				e := mocks.NewEvmEstimator(t)
+				e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(assets.NewWeiI(23_456_789_123), gasLimit, nil).Once()
+				estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(tc.rounding, assets.GWei(100), false), nil)
+
+				fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, fee.Legacy)
+			})
+		}
+	})
+
+	t.Run("leaves rounded fees as they are", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(assets.GWei(20), gasLimit, nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(assets.GWei(1), assets.GWei(100), false), nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(20), fee.Legacy)
+	})
+
+	t.Run("caps rounded fees at the max price", func(t *testing.T) {
+		maxGasPrice := assets.NewWeiI(99_700_000_000)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, maxGasPrice).Return(assets.NewWeiI(99_500_000_000), gasLimit, nil).Once()
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, assets.NewWeiI(49_800_000_000)).Return(assets.NewWeiI(49_500_000_000), gasLimit, nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(assets.GWei(1), maxGasPrice, false), nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, maxGasPrice, fee.Legacy)
+
+		// the per-call max caps rounding likewise
+		fee, _, err = estimator.GetFee(testutils.Context(t), nil, gasLimit, assets.NewWeiI(49_800_000_000))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(49_800_000_000), fee.Legacy)
+	})
+
+	t.Run("rounds dynamic fees up with the tip cap within the fee cap", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetDynamicFee", mock.Anything, gasLimit, mock.Anything).Return(gas.DynamicFee{
+			FeeCap: assets.NewWeiI(40_200_000_000),
+			TipCap: assets.NewWeiI(1_230_000_000),
+		}, gasLimit, nil).Once()
+		e.On("GetDynamicFee", mock.Anything, gasLimit, mock.Anything).Return(gas.DynamicFee{
+			FeeCap: assets.NewWeiI(99_600_000_000),
+			TipCap: assets.NewWeiI(99_600_000_000),
+		}, gasLimit, nil).Once()
+		maxGasPrice := assets.NewWeiI(99_700_000_000)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(assets.GWei(1), maxGasPrice, true), nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(41), fee.DynamicFeeCap)
+		assert.Equal(t, assets.GWei(2), fee.DynamicTipCap)
+
+		fee, _, err = estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, maxGasPrice, fee.DynamicFeeCap)
+		assert.Equal(t, maxGasPrice, fee.DynamicTipCap)
+	})
+
+	t.Run("rounds fees lowered to the budget down to fit it", func(t *testing.T) {
+		for _, tc := range []struct {
+			name     string
+			priceMin *assets.Wei
+			expected *assets.Wei
+		}{
+			{"rounded down", assets.NewWeiI(0), assets.GWei(20)},
+			// rounding down would drop below the minimum viable price
+			{"above the floor", assets.NewWeiI(20_200_000_000), assets.NewWeiI(20_500_000_000)},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				cfg := newConfig(assets.GWei(1), assets.GWei(100), false)
+				cfg.EvmMinGasPriceWeiF = tc.priceMin
+				// 20.5 gwei for the gas limit
+				cfg.EvmGasMaxTxCostF = assets.NewWeiI(20_500_000_000 * int64(gasLimit))
+				e := mocks.NewEvmEstimator(t)
+				e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(assets.NewWeiI(23_456_789_123), gasLimit, nil).Once()
+				estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
+
+				fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, fee.Legacy)
+			})
+		}
+	})
+
+	t.Run("rounds fees lowered to the available balance down to fit it", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetDynamicFee", mock.Anything, gasLimit, mock.Anything).Return(gas.DynamicFee{
+			FeeCap: assets.NewWeiI(40_200_000_000),
+			TipCap: assets.NewWeiI(1_230_000_000),
+		}, gasLimit, nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(assets.GWei(1), assets.GWei(100), true), nil)
+
+		// 30.7 gwei for the gas limit
+		ctx := gas.WithAvailableBalance(testutils.Context(t), assets.NewWeiI(30_700_000_000*int64(gasLimit)), nil)
+		fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(30), fee.DynamicFeeCap)
+		assert.Equal(t, assets.GWei(2), fee.DynamicTipCap)
+		assert.True(t, fee.LowBalanceAdjusted)
+	})
+
+	t.Run("rounds legacy bumps up after the replacement rule", func(t *testing.T) {
+		for _, tc := range []struct {
+			name        string
+			original    *assets.Wei
+			bumped      *assets.Wei
+			maxGasPrice *assets.Wei
+			expected    *assets.Wei
+		}{
+			// an original fee of 31 gwei would itself round to 40 gwei, but the
+			// bump is rounded rather than the original, so it still clears the
+			// 10% replacement rule
+			{"original and bump in the same bucket", assets.GWei(31), assets.NewWeiI(34_100_000_000), assets.GWei(100), assets.GWei(40)},
+			{"rounded original", assets.GWei(40), assets.GWei(44), assets.GWei(100), assets.GWei(50)},
+			{"capped at the max price", assets.GWei(31), assets.NewWeiI(34_100_000_000), assets.GWei(35), assets.GWei(35)},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				e := mocks.NewEvmEstimator(t)
+				e.On("BumpLegacyGas", mock.Anything, tc.original, gasLimit, tc.maxGasPrice, mock.Anything).Return(tc.bumped, gasLimit, nil).Once()
+				estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(assets.GWei(10), tc.maxGasPrice, false), nil)
+
+				bumped, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: tc.original}, gasLimit, nil, nil)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, bumped.Legacy)
+				assert.True(t, bumped.Legacy.Cmp(tc.original.AddPercentage(10)) >= 0, "bump of %s to %s is underpriced", tc.original, bumped.Legacy)
+			})
+		}
+	})
+
+	t.Run("rounds dynamic bumps up after the replacement rule", func(t *testing.T) {
+		original := gas.DynamicFee{FeeCap: assets.GWei(31), TipCap: assets.NewWeiI(3_100_000_000)}
+		e := mocks.NewEvmEstimator(t)
+		e.On("BumpDynamicFee", mock.Anything, original, gasLimit, assets.GWei(100), mock.Anything).Return(gas.DynamicFee{
+			FeeCap: assets.NewWeiI(34_100_000_000),
+			TipCap: assets.NewWeiI(3_410_000_000),
+		}, gasLimit, nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(assets.GWei(10), assets.GWei(100), true), nil)
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{DynamicFeeCap: original.FeeCap, DynamicTipCap: original.TipCap}, gasLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(40), bumped.DynamicFeeCap)
+		assert.Equal(t, assets.GWei(10), bumped.DynamicTipCap)
+	})
+}
@@ -0,0 +1,205 @@
+package gas
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+)
+
+// feeUpdateBufferSize is the number of updates buffered for each subscriber
+// of SubscribeFees. Once the buffer is full the oldest update is dropped, so
+// that slow subscribers never block the estimator.
+const feeUpdateBufferSize = 16
+
+// FeeUpdate carries the prices computed by an estimator, sent to the
+// subscribers of SubscribeFees whenever the estimator recomputes them. Prices
+// the estimator doesn't compute, e.g. the tip cap with EIP-1559 disabled, are
+// nil.
+type FeeUpdate struct {
+	LegacyPrice *assets.Wei
+	TipCap      *assets.Wei
+	FeeCap      *assets.Wei
+	BaseFee     *assets.Wei
+	// BlockNumber is the number of the head the prices were derived from, or
+	// 0 if the estimator doesn't derive its prices from heads
+	BlockNumber int64
+}
+
+var _ FeeSubscriber = (*WrappedEvmEstimator)(nil)
+
+// FeeSubscriber is implemented by fee estimators that stream the prices they
+// compute
+type FeeSubscriber interface {
+	SubscribeFees(ctx context.Context) (<-chan FeeUpdate, func(), error)
+}
+
+// feeUpdatePublisher is implemented by the estimators that publish their
+// recomputed prices
+type feeUpdatePublisher interface {
+	feeUpdates() *feeFeed
+}
+
+// SubscribeFees returns a channel that receives an update whenever the
+// estimator recomputes its prices: on each processed block in BlockHistory
+// mode, and on each refresh in SuggestedPrice mode. Unlike GetFee, it
+// doesn't touch the fee cache.
+//
+// Slow subscribers miss the oldest updates rather than blocking the
+// estimator. The channel is closed when ctx is done, when the returned
+// function is called, or when the estimator is closed.
+func (e WrappedEvmEstimator) SubscribeFees(ctx context.Context) (<-chan FeeUpdate, func(), error) {
+	var feed *feeFeed
+	if p, ok := e.EvmEstimator.(feeUpdatePublisher); ok {
+		feed = p.feeUpdates()
+	}
+	if feed == nil {
+		return nil, nil, errors.Errorf("%s does not publish fee updates", e.EvmEstimator.Name())
+	}
+	return feed.subscribe(ctx)
+}
+
+// feeFeed fans fee updates out to the subscribers of an estimator. The zero
+// value is ready to use.
+type feeFeed struct {
+	mu     sync.Mutex
+	subs   map[*feeSubscription]struct{}
+	closed bool
+}
+
+type feeSubscription struct {
+	ch   chan FeeUpdate
+	done chan struct{}
+}
+
+func (f *feeFeed) subscribe(ctx context.Context) (<-chan FeeUpdate, func(), error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil, nil, errors.New("estimator is stopped")
+	}
+	if f.subs == nil {
+		f.subs = make(map[*feeSubscription]struct{})
+	}
+	sub := &feeSubscription{ch: make(chan FeeUpdate, feeUpdateBufferSize), done: make(chan struct{})}
+	f.subs[sub] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			if _, ok := f.subs[sub]; ok {
+				delete(f.subs, sub)
+				sub.close()
+			}
+		})
+	}
+	go func() {
+		select {
+		case <-ctx.Done():
+			unsubscribe()
+		case <-sub.done:
+		}
+	}()
+	return sub.ch, unsubscribe, nil
+}
+
+// publish sends update to all subscribers, dropping their oldest update if
+// their buffer is full
+func (f *feeFeed) publish(update FeeUpdate) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		select {
+		case sub.ch <- update:
+			continue
+		default:
+		}
+		// Only publish sends, under f.mu, so after dropping the oldest update
+		// there is room for this one
+		select {
+		case <-sub.ch:
+		default:
+		}
+		sub.ch <- update
+	}
+}
+
+func (f *feeFeed) hasSubscribers() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs) > 0
+}
+
+// close closes the channels of all subscribers, and fails any further
+// subscriptions
+func (f *feeFeed) close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.closed = true
+	for sub := range f.subs {
+		sub.close()
+	}
+	f.subs = nil
+}
+
+func (s *feeSubscription) close() {
+	close(s.ch)
+	close(s.done)
+}
+
+func (b *BlockHistoryEstimator) feeUpdates() *feeFeed {
+	return &b.fees
+}
+
+// publishFees publishes the prices recalculated for head, along with the fee
+// cap that GetDynamicFee would return for EVM.GasEstimator.PriceMax
+func (b *BlockHistoryEstimator) publishFees(head *evmtypes.Head, eip1559 bool) {
+	if !b.fees.hasSubscribers() {
+		return
+	}
+	update := FeeUpdate{
+		LegacyPrice: b.getGasPrice(),
+		BaseFee:     head.BaseFeePerGas,
+		BlockNumber: head.Number,
+	}
+	if eip1559 {
+		update.TipCap = b.getTipCap()
+		baseFee, bufferBlocks := b.getFeeCapBaseFee()
+		if baseFee == nil {
+			baseFee, bufferBlocks = head.BaseFeePerGas, int(b.config.BlockHistoryEstimatorEIP1559FeeCapBufferBlocks())
+		}
+		if update.TipCap != nil && baseFee != nil {
+			if feeCap, err := feeCapAfterBlocks(baseFee, bufferBlocks, update.TipCap, b.config.EvmMaxGasPriceWei()); err == nil {
+				update.FeeCap = feeCap
+			}
+		}
+	}
+	b.fees.publish(update)
+}
+
+func (o *l2SuggestedPriceEstimator) feeUpdates() *feeFeed {
+	return &o.fees
+}
+
+// publishFees publishes the refreshed prices, along with the fee cap that
+// GetDynamicFee would return for EVM.GasEstimator.PriceMax
+func (o *l2SuggestedPriceEstimator) publishFees(eip1559 bool) {
+	if !o.fees.hasSubscribers() {
+		return
+	}
+	update := FeeUpdate{LegacyPrice: o.getGasPrice()}
+	if eip1559 {
+		update.TipCap, update.BaseFee = o.getDynamicPrices()
+		if update.TipCap != nil && update.BaseFee != nil {
+			if feeCap, err := o.calcFeeCap(update.BaseFee, update.TipCap, o.cfg.EvmMaxGasPriceWei()); err == nil {
+				update.FeeCap = feeCap
+			}
+		}
+	}
+	o.fees.publish(update)
+}
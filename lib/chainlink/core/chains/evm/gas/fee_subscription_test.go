@@ -0,0 +1,180 @@
+package gas_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestWrappedEvmEstimator_SubscribeFees(t *testing.T) {
+	t.Parallel()
+
+	newEstimator := func(t *testing.T) (*gas.BlockHistoryEstimator, gas.FeeSubscriber, *gas.MockConfig) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorBlockDelayF = uint16(0)
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = uint16(1)
+		cfg.BlockHistoryEstimatorBatchSizeF = uint32(0)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(50)
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = uint16(0)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(100000)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+
+		// each block's transaction pays its number in tips
+		ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			for i, elem := range args.Get(1).([]rpc.BatchElem) {
+				num := gas.HexToInt64(elem.Args[0])
+				args.Get(1).([]rpc.BatchElem)[i].Result = &evmtypes.Block{
+					Number:        num,
+					Hash:          utils.NewHash(),
+					BaseFeePerGas: assets.NewWeiI(1000),
+					Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(num),
+				}
+			}
+		}).Maybe()
+
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+		gas.SimulateStart(t, bhe)
+		return bhe, gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil).(gas.FeeSubscriber), cfg
+	}
+
+	newHead := func(t *testing.T, bhe *gas.BlockHistoryEstimator, num int64) {
+		h := cltest.Head(num)
+		h.BaseFeePerGas = assets.NewWeiI(1000)
+		bhe.OnNewLongestChain(testutils.Context(t), h)
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), h)
+	}
+
+	t.Run("sends the prices recomputed for each new head", func(t *testing.T) {
+		bhe, estimator, _ := newEstimator(t)
+		updates, unsubscribe, err := estimator.SubscribeFees(testutils.Context(t))
+		require.NoError(t, err)
+		t.Cleanup(unsubscribe)
+
+		for _, num := range []int64{42, 43} {
+			newHead(t, bhe, num)
+
+			select {
+			case update := <-updates:
+				assert.Equal(t, num, update.BlockNumber)
+				assert.Equal(t, assets.NewWeiI(num), update.TipCap)
+				assert.Equal(t, assets.NewWeiI(1000), update.BaseFee)
+				assert.Equal(t, assets.NewWeiI(1000+num), update.FeeCap)
+				assert.NotNil(t, update.LegacyPrice)
+			case <-time.After(testutils.WaitTimeout(t)):
+				t.Fatal("timed out waiting for a fee update")
+			}
+		}
+	})
+
+	t.Run("drops the oldest updates of a stalled subscriber and closes it on Close", func(t *testing.T) {
+		bhe, estimator, _ := newEstimator(t)
+		updates, _, err := estimator.SubscribeFees(testutils.Context(t))
+		require.NoError(t, err)
+
+		const heads = 100
+		for num := int64(1); num <= heads; num++ {
+			newHead(t, bhe, num)
+		}
+
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			assert.NoError(t, bhe.Close())
+		}()
+		select {
+		case <-closed:
+		case <-time.After(testutils.WaitTimeout(t)):
+			t.Fatal("Close deadlocked on a stalled subscriber")
+		}
+
+		var received []int64
+		for update := range updates {
+			received = append(received, update.BlockNumber)
+		}
+		require.Len(t, received, gas.FeeUpdateBufferSize)
+		assert.Equal(t, int64(heads-gas.FeeUpdateBufferSize+1), received[0])
+		assert.Equal(t, int64(heads), received[len(received)-1])
+
+		_, _, err = estimator.SubscribeFees(testutils.Context(t))
+		assert.EqualError(t, err, "estimator is stopped")
+	})
+
+	t.Run("closes the channel on unsubscribe or when the context is done", func(t *testing.T) {
+		bhe, estimator, _ := newEstimator(t)
+
+		updates, unsubscribe, err := estimator.SubscribeFees(testutils.Context(t))
+		require.NoError(t, err)
+		unsubscribe()
+		unsubscribe()
+		_, open := <-updates
+		assert.False(t, open)
+
+		ctx, cancel := context.WithCancel(testutils.Context(t))
+		updates, _, err = estimator.SubscribeFees(ctx)
+		require.NoError(t, err)
+		cancel()
+		select {
+		case _, open = <-updates:
+			assert.False(t, open)
+		case <-time.After(testutils.WaitTimeout(t)):
+			t.Fatal("timed out waiting for the channel to be closed")
+		}
+
+		// publishing without subscribers is fine
+		newHead(t, bhe, 42)
+	})
+
+	t.Run("sends the prices of each refresh of the suggested price estimator", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		var price int64 = 42
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		})
+		cfg := gas.NewMockConfig()
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), o, cfg, nil).(gas.FeeSubscriber)
+		require.NoError(t, o.Start(testutils.Context(t)))
+
+		updates, unsubscribe, err := estimator.SubscribeFees(testutils.Context(t))
+		require.NoError(t, err)
+		defer unsubscribe()
+
+		price = 43
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		select {
+		case update := <-updates:
+			assert.Equal(t, gas.FeeUpdate{LegacyPrice: assets.NewWeiI(43)}, update)
+		case <-time.After(testutils.WaitTimeout(t)):
+			t.Fatal("timed out waiting for a fee update")
+		}
+
+		require.NoError(t, o.Close())
+		_, open := <-updates
+		assert.False(t, open)
+	})
+
+	t.Run("fails for estimators that don't recompute prices", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil).(gas.FeeSubscriber)
+		_, _, err := estimator.SubscribeFees(testutils.Context(t))
+		assert.EqualError(t, err, "FixedPriceEstimator does not publish fee updates")
+	})
+}
@@ -0,0 +1,115 @@
+package gas
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+)
+
+const (
+	// maxBaseFeeGrowth is the largest increase of the base fee from one block
+	// to the next allowed by EIP-1559, 12.5%
+	maxBaseFeeGrowth = 0.125
+	// maxFeeValidityBlocks is the most blocks a fee is considered valid for,
+	// since the block history says little about base fees further out
+	maxFeeValidityBlocks = 64
+)
+
+// IsStale returns true if fee is no longer expected to be included at
+// currentHead, in which case it should be estimated again rather than sent.
+// Fees without a validity, e.g. those of the FixedPrice estimator, never go
+// stale.
+func IsStale(fee EvmFee, currentHead *evmtypes.Head) bool {
+	if currentHead == nil {
+		return false
+	}
+	if fee.ValidUntilBlock != 0 && currentHead.Number >= fee.ValidUntilBlock {
+		return true
+	}
+	return !fee.ValidUntil.IsZero() && !currentHead.Timestamp.IsZero() && currentHead.Timestamp.After(fee.ValidUntil)
+}
+
+// feeValidityEstimator is implemented by the estimators that know how long
+// their fees remain valid
+type feeValidityEstimator interface {
+	withValidity(fee EvmFee) EvmFee
+}
+
+// withValidity sets how long fee is valid for, if the estimator knows. The
+// fees of other estimators, e.g. FixedPrice, have no validity.
+func (e WrappedEvmEstimator) withValidity(fee EvmFee) EvmFee {
+	fee.ValidUntilBlock, fee.ValidUntil = 0, time.Time{}
+	if v, ok := e.EvmEstimator.(feeValidityEstimator); ok {
+		return v.withValidity(fee)
+	}
+	return fee
+}
+
+// withValidity sets the last block that the fee covers the base fee of,
+// assuming the base fee keeps increasing at the fastest rate seen in the block
+// history. The fee cap covers the base fee with the full tip cap and a legacy
+// gas price covers it with any tip.
+func (b *BlockHistoryEstimator) withValidity(fee EvmFee) EvmFee {
+	b.latestMu.RLock()
+	latest := b.latest
+	b.latestMu.RUnlock()
+	if latest == nil || latest.BaseFeePerGas == nil {
+		return fee
+	}
+
+	var headroom *assets.Wei
+	switch {
+	case fee.ValidDynamic():
+		headroom = fee.DynamicFeeCap.Sub(fee.DynamicTipCap)
+	case fee.Legacy != nil:
+		headroom = fee.Legacy
+	default:
+		return fee
+	}
+
+	// Like the base fees themselves, the projected base fees are rounded down
+	projected := new(big.Int).Set(latest.BaseFeePerGas.ToInt())
+	growth := new(big.Float).SetFloat64(1 + baseFeeGrowth(b.getBlocks()))
+	var blocks int64
+	for blocks < maxFeeValidityBlocks {
+		projected, _ = new(big.Float).Mul(new(big.Float).SetInt(projected), growth).Int(nil)
+		if projected.Cmp(headroom.ToInt()) > 0 {
+			break
+		}
+		blocks++
+	}
+	fee.ValidUntilBlock = latest.Number + blocks
+	return fee
+}
+
+// baseFeeGrowth returns the largest relative increase of the base fee between
+// consecutive blocks, between 0 and the 12.5% allowed by EIP-1559
+func baseFeeGrowth(blocks []evmtypes.Block) float64 {
+	var growth float64
+	for i := 1; i < len(blocks); i++ {
+		prev, cur := blocks[i-1], blocks[i]
+		if cur.Number != prev.Number+1 || prev.BaseFeePerGas == nil || cur.BaseFeePerGas == nil || prev.BaseFeePerGas.IsZero() {
+			continue
+		}
+		increase := new(big.Float).SetInt(cur.BaseFeePerGas.Sub(prev.BaseFeePerGas).ToInt())
+		g, _ := increase.Quo(increase, new(big.Float).SetInt(prev.BaseFeePerGas.ToInt())).Float64()
+		if g > growth {
+			growth = g
+		}
+	}
+	if growth > maxBaseFeeGrowth {
+		return maxBaseFeeGrowth
+	}
+	return growth
+}
+
+// withValidity sets the time of the next refresh of the prices as the
+// validity of the fee, since the fee may be outdated after it
+func (o *l2SuggestedPriceEstimator) withValidity(fee EvmFee) EvmFee {
+	if refreshed := o.health.lastRefreshed(); !refreshed.IsZero() {
+		fee.ValidUntil = refreshed.Add(o.pollPeriod)
+	}
+	return fee
+}
@@ -0,0 +1,140 @@
+package gas_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestWrappedEvmEstimator_FeeValidity(t *testing.T) {
+	t.Parallel()
+
+	// getFee returns the fee of a BlockHistoryEstimator with the given base
+	// fees of blocks 1 to len(baseFees)
+	getFee := func(t *testing.T, baseFees ...int64) gas.EvmFee {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(50)
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = uint16(4)
+		cfg.EvmGasBumpThresholdF = uint64(1)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(1000)
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+
+		var blocks []evmtypes.Block
+		for i, baseFee := range baseFees {
+			blocks = append(blocks, evmtypes.Block{
+				BaseFeePerGas: assets.NewWeiI(baseFee),
+				Number:        int64(i + 1),
+				Hash:          utils.NewHash(),
+				Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(1000),
+			})
+		}
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SetRollingBlockHistory(bhe, blocks)
+		h := cltest.Head(len(baseFees))
+		h.BaseFeePerGas = assets.NewWeiI(baseFees[len(baseFees)-1])
+		bhe.Recalculate(h)
+		gas.SimulateStart(t, bhe)
+		bhe.OnNewLongestChain(testutils.Context(t), h)
+
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, 100000, nil)
+		require.NoError(t, err)
+		return fee
+	}
+
+	t.Run("a volatile base fee gives a shorter validity than a flat one", func(t *testing.T) {
+		flat := getFee(t, 100000, 100000, 100000, 100000)
+		// 5% per block
+		rising := getFee(t, 100000, 105000, 110250, 115762)
+		// the maximum of 12.5% per block
+		volatile := getFee(t, 100000, 112500, 126562, 142382)
+
+		// a flat base fee is valid for as long as the estimator looks ahead
+		assert.Equal(t, int64(4+64), flat.ValidUntilBlock)
+		// the fee cap covers 4 blocks of 12.5% increases, so 9 of 5%
+		assert.Equal(t, int64(4+9), rising.ValidUntilBlock)
+		assert.Equal(t, int64(4+4), volatile.ValidUntilBlock)
+
+		assert.False(t, gas.IsStale(volatile, cltest.Head(7)))
+		assert.True(t, gas.IsStale(volatile, cltest.Head(8)))
+		assert.False(t, gas.IsStale(flat, cltest.Head(8)))
+	})
+
+	t.Run("falling base fees count as flat", func(t *testing.T) {
+		fee := getFee(t, 100000, 90000, 80000)
+		assert.Equal(t, int64(3+64), fee.ValidUntilBlock)
+	})
+
+	t.Run("the suggested price estimator's fees are valid until its next refresh", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(100)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 42)
+		})
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		before := time.Now()
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		after := time.Now()
+
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), o, cfg, nil)
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), fee.Legacy)
+		assert.Zero(t, fee.ValidUntilBlock)
+		assert.False(t, fee.ValidUntil.Before(before.Add(10*time.Second)))
+		assert.False(t, fee.ValidUntil.After(after.Add(10*time.Second)))
+
+		h := cltest.Head(1)
+		h.Timestamp = fee.ValidUntil
+		assert.False(t, gas.IsStale(fee, h))
+		h.Timestamp = fee.ValidUntil.Add(time.Second)
+		assert.True(t, gas.IsStale(fee, h))
+	})
+
+	t.Run("FixedPrice fees have no validity", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceDefaultF = assets.NewWeiI(42)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(100)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gas.EvmFee{Legacy: assets.NewWeiI(42)}, fee)
+		assert.False(t, gas.IsStale(fee, cltest.Head(1_000_000)))
+	})
+}
+
+func TestIsStale(t *testing.T) {
+	t.Parallel()
+
+	head := cltest.Head(10)
+	head.Timestamp = time.Unix(1_700_000_000, 0)
+
+	assert.False(t, gas.IsStale(gas.EvmFee{}, head))
+	assert.False(t, gas.IsStale(gas.EvmFee{ValidUntilBlock: 10}, nil))
+	assert.False(t, gas.IsStale(gas.EvmFee{ValidUntilBlock: 11}, head))
+	assert.True(t, gas.IsStale(gas.EvmFee{ValidUntilBlock: 10}, head))
+	assert.False(t, gas.IsStale(gas.EvmFee{ValidUntil: head.Timestamp}, head))
+	assert.True(t, gas.IsStale(gas.EvmFee{ValidUntil: head.Timestamp.Add(-time.Second)}, head))
+	// heads without a timestamp can't tell
+	assert.False(t, gas.IsStale(gas.EvmFee{ValidUntil: head.Timestamp.Add(-time.Second)}, cltest.Head(10)))
+}
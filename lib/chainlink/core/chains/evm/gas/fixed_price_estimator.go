@@ -47,32 +47,29 @@ func (f *fixedPriceEstimator) GetLegacyGas(_ context.Context, _ []byte, gasLimit
 }
 
 func (f *fixedPriceEstimator) BumpLegacyGas(_ context.Context, originalGasPrice *assets.Wei, originalGasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (gasPrice *assets.Wei, gasLimit uint32, err error) {
-	return BumpLegacyGasPriceOnly(f.config, f.lggr, f.config.EvmGasPriceDefault(), originalGasPrice, originalGasLimit, maxGasPriceWei)
+	gasPrice, gasLimit, err = BumpLegacyGasPriceOnly(f.config, f.lggr, f.config.EvmGasPriceDefault(), originalGasPrice, originalGasLimit, maxGasPriceWei)
+	return gasPrice, gasLimit, annotateError(err, nil, "FixedPrice")
 }
 
 func (f *fixedPriceEstimator) GetDynamicFee(_ context.Context, originalGasLimit uint32, maxGasPriceWei *assets.Wei) (d DynamicFee, chainSpecificGasLimit uint32, err error) {
 	gasTipCap := f.config.EvmGasTipCapDefault()
 
 	if gasTipCap == nil {
-		return d, 0, errors.New("cannot calculate dynamic fee: EthGasTipCapDefault was not set")
+		return d, 0, annotateError(errors.New("cannot calculate dynamic fee: EthGasTipCapDefault was not set"), nil, "FixedPrice")
 	}
 	chainSpecificGasLimit = commonfee.ApplyMultiplier(originalGasLimit, f.config.EvmGasLimitMultiplier())
 
-	var feeCap *assets.Wei
-	if f.config.EvmGasBumpThreshold() == 0 {
-		// Gas bumping is disabled, just use the max fee cap
-		feeCap = getMaxGasPrice(maxGasPriceWei, f.config.EvmMaxGasPriceWei())
-	} else {
-		// Need to leave headroom for bumping so we fallback to the default value here
-		feeCap = f.config.EvmGasFeeCapDefault()
-	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, f.config.EvmMaxGasPriceWei())
+	feeCap := effectiveFeeCapDefault(f.config, maxGasPrice)
 
-	return DynamicFee{
-		FeeCap: feeCap,
-		TipCap: gasTipCap,
-	}, chainSpecificGasLimit, nil
+	d, err = applyTipCapMin(f.config, DynamicFee{FeeCap: feeCap, TipCap: gasTipCap}, maxGasPrice)
+	if err != nil {
+		return d, 0, annotateError(err, nil, "FixedPrice")
+	}
+	return d, chainSpecificGasLimit, nil
 }
 
 func (f *fixedPriceEstimator) BumpDynamicFee(_ context.Context, originalFee DynamicFee, originalGasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
-	return BumpDynamicFeeOnly(f.config, f.lggr, f.config.EvmGasTipCapDefault(), nil, originalFee, originalGasLimit, maxGasPriceWei)
+	bumped, chainSpecificGasLimit, err = BumpDynamicFeeOnly(f.config, f.lggr, f.config.EvmGasTipCapDefault(), nil, originalFee, originalGasLimit, maxGasPriceWei)
+	return bumped, chainSpecificGasLimit, annotateError(err, nil, "FixedPrice")
 }
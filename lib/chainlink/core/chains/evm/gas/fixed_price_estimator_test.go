@@ -1,6 +1,7 @@
 package gas_test
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -9,6 +10,7 @@ import (
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/label"
 	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
 	"github.com/smartcontractkit/chainlink/v2/core/logger"
 )
@@ -66,6 +68,7 @@ func Test_FixedPriceEstimator(t *testing.T) {
 
 		config.On("EvmGasPriceDefault").Return(assets.NewWeiI(42))
 		config.On("EvmGasBumpPercent").Return(uint16(10))
+		config.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
 		config.On("EvmGasBumpWei").Return(assets.NewWeiI(150))
 		config.On("EvmMaxGasPriceWei").Return(maxGasPrice)
 		config.On("EvmGasLimitMultiplier").Return(float32(1.1))
@@ -87,6 +90,7 @@ func Test_FixedPriceEstimator(t *testing.T) {
 
 		config.On("EvmGasLimitMultiplier").Return(float32(1.1))
 		config.On("EvmGasTipCapDefault").Return(assets.NewWeiI(52))
+		config.On("EvmGasTipCapMinimum").Return(assets.NewWeiI(1))
 		config.On("EvmGasFeeCapDefault").Return(assets.NewWeiI(100))
 		config.On("EvmMaxGasPriceWei").Return(maxGasPrice)
 
@@ -125,6 +129,8 @@ func Test_FixedPriceEstimator(t *testing.T) {
 		f := gas.NewFixedPriceEstimator(config, lggr)
 
 		config.On("EvmGasBumpPercent").Return(uint16(10))
+		config.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
+		config.On("EvmGasTipCapMinimum").Return(assets.NewWeiI(0))
 		config.On("EvmGasBumpWei").Return(assets.NewWeiI(150))
 		config.On("EvmMaxGasPriceWei").Return(maxGasPrice)
 		config.On("EvmGasLimitMultiplier").Return(float32(1.1))
@@ -140,4 +146,94 @@ func Test_FixedPriceEstimator(t *testing.T) {
 		assert.Equal(t, expectedGasLimit, gasLimit)
 		assert.Equal(t, expectedFee, fee)
 	})
+
+	t.Run("GetDynamicFee and BumpDynamicFee never go below TipCapMin", func(t *testing.T) {
+		config := gas.NewMockConfig()
+		config.EvmGasLimitMultiplierF = 1
+		config.EvmGasTipCapDefaultF = assets.GWei(1)
+		config.EvmGasTipCapMinimumF = assets.GWei(30)
+		config.EvmGasFeeCapDefaultF = assets.GWei(100)
+		config.EvmGasBumpThresholdF = 3
+		config.EvmGasBumpPercentF = 10
+		config.EvmGasBumpWeiF = assets.GWei(1)
+		config.EvmMaxGasPriceWeiF = assets.GWei(500)
+		f := gas.NewFixedPriceEstimator(config, logger.TestLogger(t))
+
+		fee, _, err := f.GetDynamicFee(testutils.Context(t), 100000, assets.GWei(500))
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(30), fee.TipCap)
+		assert.Equal(t, assets.GWei(129), fee.FeeCap)
+
+		bumped, _, err := f.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.GWei(1), FeeCap: assets.GWei(100)}, 100000, assets.GWei(500), nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(30), bumped.TipCap)
+		assert.Equal(t, assets.GWei(110), bumped.FeeCap)
+
+		// the fee cap is capped at the max gas price, but the tip cap never is
+		fee, _, err = f.GetDynamicFee(testutils.Context(t), 100000, assets.GWei(110))
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(30), fee.TipCap)
+		assert.Equal(t, assets.GWei(110), fee.FeeCap)
+
+		_, _, err = f.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.GWei(1), FeeCap: assets.GWei(10)}, 100000, assets.GWei(20), nil)
+		require.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+		assert.Contains(t, err.Error(), "bumped tip cap of 30 gwei would exceed configured max gas price of 20 gwei")
+	})
+}
+
+func Test_FixedPriceEstimator_Errors(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceDefaultF = assets.NewWeiI(42)
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(52)
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(6)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(55)
+		cfg.EvmGasLimitMultiplierF = 1
+		return cfg
+	}
+
+	t.Run("BumpLegacyGas above max returns ErrBumpLimitExceeded", func(t *testing.T) {
+		f := gas.NewFixedPriceEstimator(newConfig(), logger.TestLogger(t))
+
+		_, _, err := f.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(50), 100000, assets.NewWeiI(55), nil)
+		e := requireEstimationError(t, err, "FixedPrice", gas.ErrBumpLimitExceeded,
+			fmt.Sprintf("bumped gas price of 56 wei would exceed configured max gas price of 55 wei (original price was 50 wei). %s: gas bump exceeds limit", label.NodeConnectivityProblemWarning))
+		assert.Nil(t, e.ChainID)
+		assert.Equal(t, assets.NewWeiI(56), e.Price)
+		assert.Equal(t, assets.NewWeiI(55), e.Limit)
+	})
+
+	t.Run("BumpLegacyGas to the original price returns ErrBump", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.EvmGasBumpPercentF = 0
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(0)
+		f := gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t))
+
+		_, _, err := f.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), 100000, assets.NewWeiI(55), nil)
+		requireEstimationError(t, err, "FixedPrice", gas.ErrBump,
+			"bumped gas price of 42 wei is equal to original gas price of 42 wei. ACTION REQUIRED: This is a configuration error, you must increase either EVM.GasEstimator.BumpPercent or EVM.GasEstimator.BumpMin: gas bump failed")
+	})
+
+	t.Run("BumpDynamicFee above max returns ErrBumpLimitExceeded", func(t *testing.T) {
+		f := gas.NewFixedPriceEstimator(newConfig(), logger.TestLogger(t))
+
+		originalFee := gas.DynamicFee{FeeCap: assets.NewWeiI(50), TipCap: assets.NewWeiI(40)}
+		_, _, err := f.BumpDynamicFee(testutils.Context(t), originalFee, 100000, assets.NewWeiI(55), nil)
+		e := requireEstimationError(t, err, "FixedPrice", gas.ErrBumpLimitExceeded,
+			fmt.Sprintf("bumped tip cap of 58 wei would exceed configured max gas price of 55 wei (original fee: tip cap 40 wei, fee cap 50 wei). %s: gas bump exceeds limit", label.NodeConnectivityProblemWarning))
+		assert.Equal(t, assets.NewWeiI(58), e.Price)
+		assert.Equal(t, assets.NewWeiI(55), e.Limit)
+	})
+
+	t.Run("GetDynamicFee without a default tip cap returns an EstimationError without reason", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.EvmGasTipCapDefaultF = nil
+		f := gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t))
+
+		_, _, err := f.GetDynamicFee(testutils.Context(t), 100000, assets.NewWeiI(55))
+		requireEstimationError(t, err, "FixedPrice", nil, "cannot calculate dynamic fee: EthGasTipCapDefault was not set")
+	})
 }
@@ -0,0 +1,162 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// EstimateGasCall is the call that the gas limit is estimated for when
+// EVM.GasEstimator.EstimateGasLimit is enabled, and that the ZkSync estimator
+// estimates the fee of
+type EstimateGasCall struct {
+	From  common.Address
+	To    common.Address
+	Value *big.Int
+	// Data is the calldata of the call. If nil, GetFee sets it to its
+	// calldata; it must be set for BumpFee.
+	Data []byte
+}
+
+// args returns the call as the transaction call object of eth_estimateGas and
+// eth_call
+func (call EstimateGasCall) args() map[string]interface{} {
+	args := map[string]interface{}{
+		"from": call.From,
+		"to":   call.To,
+		"data": hexutil.Bytes(call.Data),
+	}
+	if call.Value != nil && call.Value.Sign() > 0 {
+		args["value"] = (*hexutil.Big)(call.Value)
+	}
+	return args
+}
+
+type estimateGasCallKey struct{}
+
+// WithEstimateGasCall returns a context that makes GetFee estimate the gas
+// limit of the given call with eth_estimateGas, if
+// EVM.GasEstimator.EstimateGasLimit is enabled, and BumpFee simulate it with
+// eth_call, if EVM.GasEstimator.SimulateBeforeBump is enabled.
+func WithEstimateGasCall(ctx context.Context, call EstimateGasCall) context.Context {
+	return context.WithValue(ctx, estimateGasCallKey{}, call)
+}
+
+func estimateGasCallFromContext(ctx context.Context) (call EstimateGasCall, ok bool) {
+	call, ok = ctx.Value(estimateGasCallKey{}).(EstimateGasCall)
+	return
+}
+
+type originalFeeLimitKey struct{}
+
+// WithOriginalFeeLimit returns a context that makes BumpFee keep the fee limit
+// of the attempt being bumped, which GetFee may have estimated, rather than
+// recomputing it from the transaction's fee limit. The bumped fee limit is
+// never lower than it.
+func WithOriginalFeeLimit(ctx context.Context, feeLimit uint32) context.Context {
+	return context.WithValue(ctx, originalFeeLimitKey{}, feeLimit)
+}
+
+// bumpedFeeLimit returns the fee limit of a bump, given the one computed by
+// the estimator from the transaction's fee limit. With
+// EVM.GasEstimator.EstimateGasLimit, the limit of the original attempt is kept
+// as it is, since bumps don't estimate it again. Otherwise the higher of the
+// two is used, so that a replacement never has less gas than the attempt it
+// replaces.
+func (e WrappedEvmEstimator) bumpedFeeLimit(ctx context.Context, chainSpecificFeeLimit uint32) uint32 {
+	original, ok := ctx.Value(originalFeeLimitKey{}).(uint32)
+	if !ok || original == 0 {
+		return chainSpecificFeeLimit
+	}
+	if e.EstimateGasLimit || original > chainSpecificFeeLimit {
+		return original
+	}
+	return chainSpecificFeeLimit
+}
+
+// estimateGasLimit returns the gas limit estimated by the node for the call in
+// ctx, multiplied by EVM.GasEstimator.LimitMultiplier (or the LimitMultiplier
+// of the fee profile) and clamped to
+// [EVM.GasEstimator.LimitMin, EVM.GasEstimator.LimitMax]. ok is false if ctx
+// has no call or the node fails to estimate it, e.g. because the call reverts,
+// in which case the caller should fall back to its own gas limit.
+//
+// With EVM.GasEstimator.EstimateAccessList and withAccessList, i.e. for
+// transactions that can carry an access list, the estimate is the gas used with
+// the access list of eth_createAccessList, which is returned along with it. If
+// the node can't create one, the gas limit is estimated without it.
+//
+// If the estimator prices the fees in a fee currency, the call is estimated
+// with its fee paid in that currency, which costs more gas on Celo.
+func (e WrappedEvmEstimator) estimateGasLimit(ctx context.Context, withAccessList bool) (gasLimit uint32, accessList types.AccessList, ok bool) {
+	call, ok := estimateGasCallFromContext(ctx)
+	if !ok {
+		return 0, nil, false
+	}
+	if e.client == nil {
+		e.lggr.Warn("EstimateGasLimit is enabled but the estimator has no client; using the provided gas limit")
+		return 0, nil, false
+	}
+
+	var estimate uint64
+	withAccessList = withAccessList && e.accessLists != nil
+	if withAccessList {
+		estimate, accessList, withAccessList = e.estimateWithAccessList(ctx, call)
+	}
+	if !withAccessList {
+		args := call.args()
+		if currency := e.feeCurrency(); currency != nil {
+			args["feeCurrency"] = currency
+		}
+		var res hexutil.Uint64
+		if err := e.client.CallContext(ctx, &res, "eth_estimateGas", args); err != nil {
+			e.lggr.Warnw("Failed to estimate gas limit, using the provided gas limit", "err", withDecodedRevert(err), "from", call.From, "to", call.To)
+			return 0, nil, false
+		}
+		estimate = uint64(res)
+	}
+
+	multiplier := e.cfg.EvmGasLimitMultiplier()
+	if profile := feeProfileFromContext(ctx); profile.LimitMultiplier != nil {
+		multiplier = *profile.LimitMultiplier
+	}
+	gasLimit = applyGasLimitBounds(estimate, multiplier, e.cfg.EvmGasLimitMin(), e.cfg.EvmGasLimitMax())
+	e.lggr.Debugw("Estimated gas limit", "estimate", estimate, "gasLimit", gasLimit, "accessListLength", len(accessList), "to", call.To)
+	return gasLimit, accessList, true
+}
+
+// estimateWithAccessList returns the gas used by call with its access list,
+// and the list. ok is false if EVM.GasEstimator.EstimateAccessList is disabled
+// or the node fails to create the list.
+func (e WrappedEvmEstimator) estimateWithAccessList(ctx context.Context, call EstimateGasCall) (gasUsed uint64, accessList types.AccessList, ok bool) {
+	if e.accessLists == nil {
+		return 0, nil, false
+	}
+	accessList, gasUsed, err := e.accessLists.CreateAccessList(ctx, call)
+	if errors.Is(err, ErrAccessListNotSupported) {
+		return 0, nil, false
+	} else if err != nil {
+		e.lggr.Warnw("Failed to create access list, estimating the gas limit without it", "err", err, "from", call.From, "to", call.To)
+		return 0, nil, false
+	}
+	return gasUsed, accessList, true
+}
+
+// applyGasLimitBounds multiplies the estimated gas limit by multiplier,
+// rounding up so that the limit is never below the estimate, and clamps it to
+// [min, max]
+func applyGasLimitBounds(estimate uint64, multiplier float32, min, max uint32) uint32 {
+	limit := decimal.NewFromBigInt(new(big.Int).SetUint64(estimate), 0).Mul(decimal.NewFromFloat32(multiplier)).Ceil()
+	if limit.LessThan(decimal.NewFromInt(int64(min))) {
+		return min
+	}
+	if limit.GreaterThan(decimal.NewFromInt(int64(max))) {
+		return max
+	}
+	return uint32(limit.IntPart())
+}
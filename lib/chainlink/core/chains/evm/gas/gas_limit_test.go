@@ -0,0 +1,186 @@
+package gas_test
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_EstimateGasLimit(t *testing.T) {
+	t.Parallel()
+
+	const feeLimit uint32 = 100_000
+	calldata := []byte{0x01, 0x02, 0x03}
+	call := gas.EstimateGasCall{
+		From:  testutils.NewAddress(),
+		To:    testutils.NewAddress(),
+		Value: big.NewInt(42),
+	}
+
+	newConfig := func(eip1559 bool) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = eip1559
+		cfg.EvmGasEstimateGasLimitF = true
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.EvmGasLimitMinF = 21_000
+		cfg.EvmGasLimitMaxF = 500_000
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		return cfg
+	}
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig) *mocks.EvmEstimator {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, calldata, feeLimit, mock.Anything).Return(assets.GWei(1), feeLimit, nil).Maybe()
+		e.On("GetDynamicFee", mock.Anything, feeLimit, mock.Anything).Return(gas.DynamicFee{FeeCap: assets.GWei(2), TipCap: assets.GWei(1)}, feeLimit, nil).Maybe()
+		return e
+	}
+	mockEstimateGas := func(client *mocks.RPCClient, estimate uint64, err error) {
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_estimateGas", mock.MatchedBy(func(args map[string]interface{}) bool {
+			return args["from"] == call.From && args["to"] == call.To &&
+				assert.ObjectsAreEqual(hexutil.Bytes(calldata), args["data"]) &&
+				assert.ObjectsAreEqual((*hexutil.Big)(call.Value), args["value"])
+		})).Run(func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = hexutil.Uint64(estimate)
+		}).Return(err).Once()
+	}
+
+	for _, eip1559 := range []bool{false, true} {
+		eip1559 := eip1559
+		for _, test := range []struct {
+			name       string
+			multiplier float32
+			estimate   uint64
+			expected   uint32
+		}{
+			{"returns the estimate", 1, 150_000, 150_000},
+			{"applies the multiplier", 1.5, 100_000, 150_000},
+			{"rounds the multiplied limit up", 1.5, 100_001, 150_002},
+			{"clamps at LimitMin", 1.2, 10_000, 21_000},
+			{"clamps at LimitMax", 1.2, 450_000, 500_000},
+		} {
+			test := test
+			t.Run(fmt.Sprintf("%s with EIP1559 %t", test.name, eip1559), func(t *testing.T) {
+				cfg := newConfig(eip1559)
+				cfg.EvmGasLimitMultiplierF = test.multiplier
+				client := mocks.NewRPCClient(t)
+				mockEstimateGas(client, test.estimate, nil)
+				estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg), cfg, client)
+
+				fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+				require.NoError(t, err)
+				assert.Equal(t, test.expected, gasLimit)
+				if eip1559 {
+					assert.Equal(t, assets.GWei(2), fee.DynamicFeeCap)
+				} else {
+					assert.Equal(t, assets.GWei(1), fee.Legacy)
+				}
+			})
+		}
+	}
+
+	t.Run("falls back to the provided limit if the node fails to estimate", func(t *testing.T) {
+		cfg := newConfig(false)
+		client := mocks.NewRPCClient(t)
+		mockEstimateGas(client, 0, errors.New("execution reverted"))
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg), cfg, client)
+
+		fee, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, feeLimit, gasLimit)
+		assert.Equal(t, assets.GWei(1), fee.Legacy)
+	})
+
+	t.Run("omits the value if zero", func(t *testing.T) {
+		cfg := newConfig(false)
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_estimateGas", mock.MatchedBy(func(args map[string]interface{}) bool {
+			_, ok := args["value"]
+			return !ok
+		})).Run(func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = 30_000
+		}).Return(nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg), cfg, client)
+
+		_, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), gas.EstimateGasCall{From: call.From, To: call.To}), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(30_000), gasLimit)
+	})
+
+	t.Run("does not estimate without a call", func(t *testing.T) {
+		cfg := newConfig(false)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg), cfg, mocks.NewRPCClient(t))
+
+		_, gasLimit, err := estimator.GetFee(testutils.Context(t), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, feeLimit, gasLimit)
+	})
+
+	t.Run("does not estimate if disabled", func(t *testing.T) {
+		cfg := newConfig(false)
+		cfg.EvmGasEstimateGasLimitF = false
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg), cfg, mocks.NewRPCClient(t))
+
+		_, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, feeLimit, gasLimit)
+	})
+
+	t.Run("does not estimate if the fee estimation fails", func(t *testing.T) {
+		cfg := newConfig(false)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, calldata, feeLimit, mock.Anything).Return(nil, uint32(0), errors.New("kaboom")).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, mocks.NewRPCClient(t))
+
+		_, _, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+		require.EqualError(t, err, "kaboom")
+	})
+
+	t.Run("uses the provided limit without a client", func(t *testing.T) {
+		cfg := newConfig(false)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg), cfg, nil)
+
+		_, gasLimit, err := estimator.GetFee(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, feeLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, feeLimit, gasLimit)
+	})
+
+	t.Run("bumps keep the fee limit of the original attempt", func(t *testing.T) {
+		for _, tc := range []struct {
+			name             string
+			estimateGasLimit bool
+			original         uint32
+			expected         uint32
+		}{
+			{"estimated limit below the transaction's", true, 60_000, 60_000},
+			{"estimated limit above the transaction's", true, 120_000, 120_000},
+			{"never lowers the limit", false, 120_000, 120_000},
+			{"raises the limit to the transaction's", false, 60_000, feeLimit},
+			{"without an original limit", true, 0, feeLimit},
+		} {
+			tc := tc
+			t.Run(tc.name, func(t *testing.T) {
+				cfg := newConfig(false)
+				cfg.EvmGasEstimateGasLimitF = tc.estimateGasLimit
+				e := mocks.NewEvmEstimator(t)
+				e.On("BumpLegacyGas", mock.Anything, assets.GWei(1), feeLimit, mock.Anything, mock.Anything).Return(assets.GWei(2), feeLimit, nil).Once()
+				estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, mocks.NewRPCClient(t))
+
+				ctx := gas.WithOriginalFeeLimit(testutils.Context(t), tc.original)
+				_, gasLimit, err := estimator.BumpFee(ctx, gas.EvmFee{Legacy: assets.GWei(1)}, feeLimit, nil, nil)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, gasLimit)
+			})
+		}
+	})
+}
@@ -93,6 +93,7 @@ func Test_BumpLegacyGasPriceOnly(t *testing.T) {
 		t.Run(test.name, func(t *testing.T) {
 			cfg := gasmocks.NewConfig(t)
 			cfg.On("EvmGasBumpPercent").Return(test.bumpPercent)
+			cfg.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
 			cfg.On("EvmGasBumpWei").Return(test.bumpWei)
 			cfg.On("EvmMaxGasPriceWei").Return(test.maxGasPriceWei)
 			cfg.On("EvmGasLimitMultiplier").Return(test.limitMultiplierPercent)
@@ -112,6 +113,7 @@ func Test_BumpLegacyGasPriceOnly_HitsMaxError(t *testing.T) {
 	cfg := gasmocks.NewConfig(t)
 	maxGasPriceWei := assets.GWei(40)
 	cfg.On("EvmGasBumpPercent").Return(uint16(50))
+	cfg.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
 	cfg.On("EvmGasBumpWei").Return(assets.NewWeiI(5000000000))
 	cfg.On("EvmMaxGasPriceWei").Return(maxGasPriceWei)
 
@@ -128,6 +130,7 @@ func Test_BumpLegacyGasPriceOnly_NoBumpError(t *testing.T) {
 	lggr := logger.TestLogger(t)
 	cfg := gasmocks.NewConfig(t)
 	cfg.On("EvmGasBumpPercent").Return(uint16(0))
+	cfg.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
 	cfg.On("EvmGasBumpWei").Return(assets.NewWeiI(0))
 	cfg.On("EvmMaxGasPriceWei").Return(maxGasPriceWei)
 
@@ -159,6 +162,7 @@ func Test_BumpDynamicFeeOnly(t *testing.T) {
 		originalLimit          uint32
 		limitMultiplierPercent float32
 		expectedLimit          uint64
+		bumpFeeCapFromBaseFee  bool
 	}{
 		{
 			name:                   "defaults",
@@ -288,16 +292,50 @@ func Test_BumpDynamicFeeOnly(t *testing.T) {
 			limitMultiplierPercent: 1.0,
 			expectedLimit:          100000,
 		},
+		{
+			name:           "with BumpFeeCapFromBaseFee, bumps previous fee cap only by the replacement minimum if calculated fee cap would be lower",
+			currentTipCap:  assets.GWei(20),
+			currentBaseFee: assets.GWei(100),
+			originalFee:    gas.DynamicFee{TipCap: assets.GWei(30), FeeCap: assets.GWei(400)},
+			tipCapDefault:  assets.GWei(20),
+			bumpPercent:    20,
+			bumpWei:        toWei("5e9"), // 0.5 GWei
+			maxGasPriceWei: assets.GWei(5000),
+			// 400 * 1.1, since 100 * (1.125 ^ 4) + 36 ~= 196
+			expectedFee:            gas.DynamicFee{TipCap: assets.GWei(36), FeeCap: assets.GWei(440)},
+			originalLimit:          100000,
+			limitMultiplierPercent: 1.0,
+			expectedLimit:          100000,
+			bumpFeeCapFromBaseFee:  true,
+		},
+		{
+			name:                   "with BumpFeeCapFromBaseFee, uses current base fee to calculate fee cap",
+			currentTipCap:          assets.GWei(20),
+			currentBaseFee:         assets.GWei(1000),
+			originalFee:            gas.DynamicFee{TipCap: assets.GWei(30), FeeCap: assets.GWei(400)},
+			tipCapDefault:          assets.GWei(20),
+			bumpPercent:            20,
+			bumpWei:                toWei("5e9"), // 0.5 GWei
+			maxGasPriceWei:         assets.GWei(5000),
+			expectedFee:            gas.DynamicFee{TipCap: assets.GWei(36), FeeCap: assets.NewWeiI(1637806640625)},
+			originalLimit:          100000,
+			limitMultiplierPercent: 1.0,
+			expectedLimit:          100000,
+			bumpFeeCapFromBaseFee:  true,
+		},
 	} {
 		t.Run(test.name, func(t *testing.T) {
 			cfg := gasmocks.NewConfig(t)
 			cfg.On("EvmGasBumpPercent").Return(test.bumpPercent)
+			cfg.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
+			cfg.On("EvmGasTipCapMinimum").Return(assets.NewWeiI(0))
 			cfg.On("EvmGasTipCapDefault").Return(test.tipCapDefault)
 			cfg.On("EvmGasBumpWei").Return(test.bumpWei)
 			cfg.On("EvmMaxGasPriceWei").Return(test.maxGasPriceWei)
 			cfg.On("EvmGasLimitMultiplier").Return(test.limitMultiplierPercent)
 			if test.currentBaseFee != nil {
 				cfg.On("BlockHistoryEstimatorEIP1559FeeCapBufferBlocks").Return(uint16(4))
+				cfg.On("EvmGasBumpFeeCapFromBaseFee").Return(test.bumpFeeCapFromBaseFee)
 			}
 			actual, limit, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), test.currentTipCap, test.currentBaseFee, test.originalFee, test.originalLimit, test.maxGasPriceWei)
 			require.NoError(t, err)
@@ -312,12 +350,64 @@ func Test_BumpDynamicFeeOnly(t *testing.T) {
 	}
 }
 
+func Test_BumpDynamicFeeOnly_BumpFeeCapFromBaseFee(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(reanchor bool) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasBumpFeeCapFromBaseFeeF = reanchor
+		cfg.EvmGasBumpPercentF = 20
+		cfg.EvmGasBumpStrategyF = gas.BumpStrategyPercent
+		cfg.EvmGasBumpWeiF = assets.GWei(1)
+		cfg.EvmGasTipCapDefaultF = assets.GWei(1)
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(5000)
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = 4
+		return cfg
+	}
+	// bumps the original fee once per base fee, returning the fee caps
+	bump := func(t *testing.T, cfg gas.Config, baseFees ...int64) (feeCaps []*assets.Wei) {
+		fee := gas.DynamicFee{TipCap: assets.GWei(2), FeeCap: assets.GWei(300)}
+		for _, baseFee := range baseFees {
+			bumped, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), nil, assets.GWei(baseFee), fee, 21000, assets.GWei(5000))
+			require.NoError(t, err)
+			assert.True(t, bumped.TipCap.Cmp(fee.TipCap) > 0)
+			// geth only accepts replacements with a fee cap at least 10% higher
+			assert.True(t, bumped.FeeCap.Cmp(fee.FeeCap.AddPercentage(10)) >= 0)
+			feeCaps = append(feeCaps, bumped.FeeCap)
+			fee = bumped
+		}
+		return
+	}
+
+	t.Run("falling base fee", func(t *testing.T) {
+		plain := bump(t, newConfig(false), 100, 50, 25)
+		reanchored := bump(t, newConfig(true), 100, 50, 25)
+		// the fee cap is only bumped by the replacement minimum of 10%
+		assert.Equal(t, []*assets.Wei{assets.GWei(330), assets.GWei(363), assets.NewWeiI(399_300_000_000)}, reanchored)
+		for i := range plain {
+			assert.True(t, reanchored[i].Cmp(plain[i]) < 0, "bump %d: expected fee cap %s to be lower than %s", i, reanchored[i], plain[i])
+		}
+	})
+
+	t.Run("rising base fee", func(t *testing.T) {
+		reanchored := bump(t, newConfig(true), 400, 800)
+		// base fee * 4 blocks * 1.125 % plus the bumped tip cap
+		// 400 * (1.125 ^ 4) + 3 ~= 643.7, 800 * (1.125 ^ 4) + 4 ~= 1285.4
+		assert.Equal(t, []*assets.Wei{assets.NewWeiI(643_722_656_250), assets.NewWeiI(1_285_445_312_500)}, reanchored)
+		// plain percentage bumping would give 360 and 432 gwei
+		assert.True(t, reanchored[1].Cmp(assets.GWei(432)) > 0)
+	})
+}
+
 func Test_BumpDynamicFeeOnly_HitsMaxError(t *testing.T) {
 	t.Parallel()
 
 	maxGasPriceWei := assets.GWei(40)
 	cfg := gasmocks.NewConfig(t)
 	cfg.On("EvmGasBumpPercent").Return(uint16(50))
+	cfg.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
+	cfg.On("EvmGasTipCapMinimum").Return(assets.NewWeiI(0))
 	cfg.On("EvmGasTipCapDefault").Return(assets.GWei(0))
 	cfg.On("EvmGasBumpWei").Return(assets.NewWeiI(5000000000))
 	cfg.On("EvmMaxGasPriceWei").Return(maxGasPriceWei)
@@ -337,6 +427,105 @@ func Test_BumpDynamicFeeOnly_HitsMaxError(t *testing.T) {
 	})
 }
 
+func Test_BumpDynamicFeeOnly_BlobFeeCap(t *testing.T) {
+	t.Parallel()
+
+	maxGasPriceWei := assets.GWei(5000)
+	cfg := gasmocks.NewConfig(t)
+	cfg.On("EvmGasBumpPercent").Return(uint16(20))
+	cfg.On("EvmGasBumpStrategy").Return(gas.BumpStrategyPercent)
+	cfg.On("EvmGasTipCapMinimum").Return(assets.NewWeiI(0))
+	cfg.On("EvmGasTipCapDefault").Return(assets.GWei(0))
+	cfg.On("EvmGasBumpWei").Return(assets.GWei(5))
+	cfg.On("EvmMaxGasPriceWei").Return(maxGasPriceWei)
+	cfg.On("EvmMaxBlobGasPriceWei").Return(assets.GWei(100)).Maybe()
+	cfg.On("EvmGasLimitMultiplier").Return(float32(1)).Maybe()
+
+	t.Run("does not set blob fee cap if original fee has none", func(t *testing.T) {
+		originalFee := gas.DynamicFee{TipCap: assets.GWei(30), FeeCap: assets.GWei(100)}
+		bumped, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), nil, nil, originalFee, 42, maxGasPriceWei)
+		require.NoError(t, err)
+		assert.Nil(t, bumped.BlobFeeCap)
+	})
+
+	t.Run("bumps blob fee cap by percentage", func(t *testing.T) {
+		originalFee := gas.DynamicFee{TipCap: assets.GWei(30), FeeCap: assets.GWei(100), BlobFeeCap: assets.GWei(50)}
+		bumped, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), nil, nil, originalFee, 42, maxGasPriceWei)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(60), bumped.BlobFeeCap)
+		assert.Equal(t, assets.GWei(120), bumped.FeeCap)
+	})
+
+	t.Run("bumps tiny blob fee cap by at least 1 wei", func(t *testing.T) {
+		originalFee := gas.DynamicFee{TipCap: assets.GWei(30), FeeCap: assets.GWei(100), BlobFeeCap: assets.NewWeiI(1)}
+		bumped, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), nil, nil, originalFee, 42, maxGasPriceWei)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(2), bumped.BlobFeeCap)
+	})
+
+	t.Run("blob fee cap hits PriceMaxBlob", func(t *testing.T) {
+		originalFee := gas.DynamicFee{TipCap: assets.GWei(30), FeeCap: assets.GWei(100), BlobFeeCap: assets.GWei(90)}
+		_, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), nil, nil, originalFee, 42, maxGasPriceWei)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+		assert.Contains(t, err.Error(), "bumped blob fee cap of 108 gwei would exceed configured max blob gas price of 100 gwei (original blob fee cap: 90 gwei)")
+	})
+}
+
+func Test_BumpStrategy(t *testing.T) {
+	t.Parallel()
+
+	maxGasPriceWei := assets.GWei(1000)
+	newConfig := func(strategy string, bumpPercent uint16, bumpWei *assets.Wei) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasBumpStrategyF = strategy
+		cfg.EvmGasBumpPercentF = bumpPercent
+		cfg.EvmGasBumpWeiF = bumpWei
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(0)
+		cfg.EvmMaxGasPriceWeiF = maxGasPriceWei
+		cfg.EvmGasLimitMultiplierF = 1
+		return cfg
+	}
+
+	for _, test := range []struct {
+		name           string
+		strategy       string
+		bumpPercent    uint16
+		bumpWei        *assets.Wei
+		current        *assets.Wei
+		expected       *assets.Wei
+		expectedTip    *assets.Wei
+		expectedFeeCap *assets.Wei
+	}{
+		{"percent", gas.BumpStrategyPercent, 10, assets.NewWeiI(1), nil, assets.GWei(110), assets.GWei(11), assets.GWei(220)},
+		{"percent with higher fixed bump", gas.BumpStrategyPercent, 10, assets.GWei(30), nil, assets.GWei(130), assets.GWei(40), assets.GWei(230)},
+		{"additive", gas.BumpStrategyAdditive, 50, assets.GWei(30), nil, assets.GWei(130), assets.GWei(40), assets.GWei(230)},
+		{"additive below the minimum replacement bump", gas.BumpStrategyAdditive, 50, assets.GWei(1), nil, assets.GWei(110), assets.GWei(11), assets.GWei(220)},
+		{"rebase without a current price", gas.BumpStrategyRebase, 50, assets.GWei(30), nil, assets.GWei(110), assets.GWei(11), assets.GWei(220)},
+		{"rebase to a lower current price", gas.BumpStrategyRebase, 50, assets.GWei(30), assets.GWei(5), assets.GWei(110), assets.GWei(11), assets.GWei(220)},
+		{"rebase to a higher current price", gas.BumpStrategyRebase, 50, assets.GWei(30), assets.GWei(150), assets.GWei(150), assets.GWei(150), assets.GWei(220)},
+	} {
+		test := test
+		t.Run(test.name, func(t *testing.T) {
+			cfg := newConfig(test.strategy, test.bumpPercent, test.bumpWei)
+			original := assets.GWei(100)
+
+			gasPrice, _, err := gas.BumpLegacyGasPriceOnly(cfg, logger.TestLogger(t), test.current, original, 100000, maxGasPriceWei)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, gasPrice)
+			assert.True(t, gasPrice.Cmp(original.AddPercentage(10)) >= 0, "bumped gas price must satisfy the replacement rules")
+
+			originalFee := gas.DynamicFee{TipCap: assets.GWei(10), FeeCap: assets.GWei(200)}
+			fee, _, err := gas.BumpDynamicFeeOnly(cfg, logger.TestLogger(t), test.current, nil, originalFee, 100000, maxGasPriceWei)
+			require.NoError(t, err)
+			assert.Equal(t, test.expectedTip, fee.TipCap)
+			assert.Equal(t, test.expectedFeeCap, fee.FeeCap)
+			assert.True(t, fee.TipCap.Cmp(originalFee.TipCap.AddPercentage(10)) >= 0, "bumped tip cap must satisfy the replacement rules")
+			assert.True(t, fee.FeeCap.Cmp(originalFee.FeeCap.AddPercentage(10)) >= 0, "bumped fee cap must satisfy the replacement rules")
+		})
+	}
+}
+
 // toWei is used to convert scientific notation string to a *assets.Wei
 func toWei(input string) *assets.Wei {
 	flt, _, err := big.ParseFloat(input, 10, 0, big.ToNearestEven)
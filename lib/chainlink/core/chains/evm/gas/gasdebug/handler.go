@@ -0,0 +1,258 @@
+// Package gasdebug serves the fees that the gas estimators would currently
+// price a transaction at, so that operators can check them without creating
+// a job.
+package gasdebug
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+const (
+	// DefaultRequestTimeout is the default Config.RequestTimeout
+	DefaultRequestTimeout = 10 * time.Second
+	// DefaultColdStartTimeout is the default Config.ColdStartTimeout
+	DefaultColdStartTimeout = 2 * time.Second
+	// defaultGasLimit is the gas limit of requests without a limit parameter,
+	// i.e. that of a plain transfer
+	defaultGasLimit = 21_000
+	// maxBodySize bounds the body of POST requests
+	maxBodySize = 1 << 20
+)
+
+// Config bounds how long a request may take. Zero values use the defaults.
+type Config struct {
+	// RequestTimeout bounds each request, including the gas limit estimation
+	// of POST requests
+	RequestTimeout time.Duration
+	// ColdStartTimeout bounds how long a request waits for the estimator's
+	// fee, e.g. of an estimator that is still fetching its first prices. If
+	// it is exceeded the request fails with 503 Service Unavailable.
+	ColdStartTimeout time.Duration
+}
+
+// Estimate is the response of a successful request
+type Estimate struct {
+	ChainID string `json:"chainID"`
+	// Estimator is the estimator mode, as in EVM.GasEstimator.Mode
+	Estimator string `json:"estimator"`
+	// Dynamic is true if the fee is an EIP-1559 dynamic fee, with TipCap and
+	// FeeCap set, rather than a legacy GasPrice
+	Dynamic  bool        `json:"dynamic"`
+	GasLimit uint32      `json:"gasLimit"`
+	GasPrice *assets.Wei `json:"gasPrice,omitempty"`
+	TipCap   *assets.Wei `json:"tipCap,omitempty"`
+	FeeCap   *assets.Wei `json:"feeCap,omitempty"`
+	// BaseFee and Head are those of the latest head the estimator was given,
+	// and are omitted if it hasn't been given one yet
+	BaseFee *assets.Wei `json:"baseFee,omitempty"`
+	Head    *Head       `json:"head,omitempty"`
+}
+
+// Head identifies the head an estimate was based on
+type Head struct {
+	Number int64       `json:"number"`
+	Hash   common.Hash `json:"hash"`
+}
+
+// Error is the response of a failed request
+type Error struct {
+	Error string `json:"error"`
+}
+
+// Call is the body of a POST request, the call to estimate the gas limit of
+// with EVM.GasEstimator.EstimateGasLimit
+type Call struct {
+	From  common.Address `json:"from"`
+	To    common.Address `json:"to"`
+	Data  hexutil.Bytes  `json:"data"`
+	Value *hexutil.Big   `json:"value"`
+}
+
+// Handler serves GET and POST /chains/{id}/gas/estimate. The query parameter
+// mode selects a dynamic or legacy fee, defaulting to that of the chain, and
+// limit is the gas limit to price. POST requests give the call, see Call, to
+// estimate the gas limit of if EVM.GasEstimator.EstimateGasLimit is enabled.
+type Handler struct {
+	cfg        Config
+	estimators map[string]*gas.WrappedEvmEstimator
+	lggr       logger.Logger
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+// NewHandler returns a Handler for the estimators, keyed by chain ID. The map
+// must not be changed afterwards.
+func NewHandler(lggr logger.Logger, cfg Config, estimators map[string]*gas.WrappedEvmEstimator) *Handler {
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = DefaultRequestTimeout
+	}
+	if cfg.ColdStartTimeout <= 0 {
+		cfg.ColdStartTimeout = DefaultColdStartTimeout
+	}
+	return &Handler{cfg: cfg, estimators: estimators, lggr: lggr.Named("GasDebugHandler")}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	chainID, ok := parsePath(r.URL.Path)
+	if !ok {
+		h.writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		h.writeError(w, http.StatusMethodNotAllowed, errors.Errorf("method %s not allowed", r.Method))
+		return
+	}
+	estimator, ok := h.estimators[chainID]
+	if !ok {
+		h.writeError(w, http.StatusNotFound, errors.Errorf("no gas estimator for chain %s", chainID))
+		return
+	}
+
+	// estimate with a copy, so that the mode of the request doesn't change
+	// that of the chain
+	e := *estimator
+	query := r.URL.Query()
+	switch mode := query.Get("mode"); mode {
+	case "":
+	case "dynamic":
+		e.EIP1559Enabled = true
+	case "legacy":
+		e.EIP1559Enabled = false
+	default:
+		h.writeError(w, http.StatusBadRequest, errors.Errorf("invalid mode %q, must be dynamic or legacy", mode))
+		return
+	}
+	gasLimit, err := parseGasLimit(query.Get("limit"))
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.cfg.RequestTimeout)
+	defer cancel()
+	var calldata []byte
+	if r.Method == http.MethodPost {
+		if !e.EstimateGasLimit {
+			h.writeError(w, http.StatusBadRequest, errors.New("gas limit estimation is disabled, see EVM.GasEstimator.EstimateGasLimit"))
+			return
+		}
+		var call Call
+		if err = json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&call); err != nil {
+			h.writeError(w, http.StatusBadRequest, errors.Wrap(err, "invalid call"))
+			return
+		}
+		calldata = call.Data
+		ctx = gas.WithEstimateGasCall(ctx, gas.EstimateGasCall{From: call.From, To: call.To, Value: call.Value.ToInt(), Data: calldata})
+	}
+
+	fee, limit, err := h.getFee(ctx, &e, calldata, gasLimit)
+	if err != nil {
+		h.writeError(w, statusOf(err), err)
+		return
+	}
+	estimate := Estimate{
+		ChainID:   chainID,
+		Estimator: e.Mode(),
+		Dynamic:   e.EIP1559Enabled,
+		GasLimit:  limit,
+		GasPrice:  fee.Legacy,
+		TipCap:    fee.DynamicTipCap,
+		FeeCap:    fee.DynamicFeeCap,
+	}
+	if head := e.LatestHead(); head != nil {
+		estimate.BaseFee = head.BaseFeePerGas
+		estimate.Head = &Head{Number: head.Number, Hash: head.Hash}
+	}
+	h.writeJSON(w, http.StatusOK, estimate)
+}
+
+// errColdStart is returned when the estimator doesn't return a fee within
+// Config.ColdStartTimeout
+var errColdStart = errors.New("estimator has not returned a fee yet")
+
+// getFee returns the fee of the estimator, without waiting for it longer than
+// Config.ColdStartTimeout
+func (h *Handler) getFee(ctx context.Context, e *gas.WrappedEvmEstimator, calldata []byte, gasLimit uint32) (gas.EvmFee, uint32, error) {
+	type result struct {
+		fee   gas.EvmFee
+		limit uint32
+		err   error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		fee, limit, err := e.GetFee(ctx, calldata, gasLimit, nil)
+		ch <- result{fee, limit, err}
+	}()
+
+	t := time.NewTimer(h.cfg.ColdStartTimeout)
+	defer t.Stop()
+	select {
+	case res := <-ch:
+		return res.fee, res.limit, res.err
+	case <-t.C:
+		return gas.EvmFee{}, 0, errors.Wrapf(errColdStart, "no fee within %s, the estimator may still be starting", h.cfg.ColdStartTimeout)
+	case <-ctx.Done():
+		return gas.EvmFee{}, 0, ctx.Err()
+	}
+}
+
+// statusOf returns the status code of a failed estimate. Estimators without
+// prices yet are unavailable rather than failed.
+func statusOf(err error) int {
+	switch {
+	case errors.Is(err, errColdStart), errors.Is(err, gas.ErrStalePrice), errors.Is(err, gas.ErrNoData):
+		return http.StatusServiceUnavailable
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// parsePath returns the chain ID of a /chains/{id}/gas/estimate path
+func parsePath(path string) (chainID string, ok bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "chains" || parts[1] == "" || parts[2] != "gas" || parts[3] != "estimate" {
+		return "", false
+	}
+	return parts[1], true
+}
+
+// parseGasLimit parses the limit query parameter, a positive uint32
+func parseGasLimit(s string) (uint32, error) {
+	if s == "" {
+		return defaultGasLimit, nil
+	}
+	limit, err := strconv.ParseUint(s, 10, 32)
+	if err != nil || limit == 0 {
+		return 0, errors.Errorf("invalid limit %q, must be an integer between 1 and %d", s, uint32(math.MaxUint32))
+	}
+	return uint32(limit), nil
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	h.writeJSON(w, status, Error{Error: err.Error()})
+}
+
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		h.lggr.Errorw("Failed to write response", "err", err)
+	}
+}
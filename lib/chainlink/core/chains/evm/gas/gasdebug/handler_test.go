@@ -0,0 +1,193 @@
+package gasdebug_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/gasdebug"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.GWei(100)
+	newConfig := func(t *testing.T, estimateGasLimit bool) *mocks.Config {
+		cfg := mocks.NewConfig(t)
+		cfg.On("GasEstimatorMode").Return("BlockHistory").Maybe()
+		cfg.On("EvmEIP1559DynamicFees").Return(false).Maybe()
+		cfg.On("EvmMaxGasPriceWei").Return(maxGasPrice).Maybe()
+		cfg.On("EvmGasFeeCacheTTL").Return(time.Duration(0)).Maybe()
+		cfg.On("EvmGasEstimateGasLimit").Return(estimateGasLimit).Maybe()
+		cfg.On("EvmGasEstimateAccessList").Return(false).Maybe()
+		cfg.On("EvmGasLimitMultiplier").Return(float32(1)).Maybe()
+		cfg.On("EvmGasLimitMin").Return(uint32(21_000)).Maybe()
+		cfg.On("EvmGasLimitMax").Return(uint32(1_000_000)).Maybe()
+		cfg.On("EvmGasMaxTxCost").Return((*assets.Wei)(nil)).Maybe()
+		cfg.On("EvmGasFeeRounding").Return((*assets.Wei)(nil)).Maybe()
+		cfg.On("EvmGasDecisionLogSampleRate").Return(uint32(0)).Maybe()
+		cfg.On("EvmGasDecisionLogAlways").Return([]string(nil)).Maybe()
+		return cfg
+	}
+	newServer := func(t *testing.T, estimator gas.EvmFeeEstimator, cfg gasdebug.Config) *httptest.Server {
+		h := gasdebug.NewHandler(logger.TestLogger(t), cfg, map[string]*gas.WrappedEvmEstimator{
+			"1": estimator.(*gas.WrappedEvmEstimator),
+		})
+		s := httptest.NewServer(h)
+		t.Cleanup(s.Close)
+		return s
+	}
+	get := func(t *testing.T, url string, v interface{}) int {
+		resp, err := http.Get(url) //nolint:gosec
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(v))
+		return resp.StatusCode
+	}
+
+	t.Run("returns the legacy gas price", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, []byte(nil), uint32(500_000), maxGasPrice).Return(assets.GWei(7), uint32(500_000), nil).Once()
+		s := newServer(t, gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, false), nil), gasdebug.Config{})
+
+		var estimate gasdebug.Estimate
+		require.Equal(t, http.StatusOK, get(t, s.URL+"/chains/1/gas/estimate?limit=500000", &estimate))
+		assert.Equal(t, gasdebug.Estimate{
+			ChainID:   "1",
+			Estimator: "BlockHistory",
+			GasLimit:  500_000,
+			GasPrice:  assets.GWei(7),
+		}, estimate)
+	})
+
+	t.Run("returns the dynamic fee and the head it was based on", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("OnNewLongestChain", mock.Anything, mock.Anything).Once()
+		e.On("GetDynamicFee", mock.Anything, uint32(21_000), maxGasPrice).Return(gas.DynamicFee{FeeCap: assets.GWei(20), TipCap: assets.GWei(2)}, uint32(21_000), nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, false), nil)
+		head := evmtypes.NewHead(assets.NewWeiI(42).ToInt(), utils.NewHash(), utils.NewHash(), 0, nil)
+		head.BaseFeePerGas = assets.GWei(9)
+		estimator.OnNewLongestChain(testutils.Context(t), &head)
+		s := newServer(t, estimator, gasdebug.Config{})
+
+		// the mode of the request overrides that of the chain
+		var estimate gasdebug.Estimate
+		require.Equal(t, http.StatusOK, get(t, s.URL+"/chains/1/gas/estimate?mode=dynamic", &estimate))
+		assert.Equal(t, gasdebug.Estimate{
+			ChainID:   "1",
+			Estimator: "BlockHistory",
+			Dynamic:   true,
+			GasLimit:  21_000,
+			TipCap:    assets.GWei(2),
+			FeeCap:    assets.GWei(20),
+			BaseFee:   assets.GWei(9),
+			Head:      &gasdebug.Head{Number: 42, Hash: head.Hash},
+		}, estimate)
+		assert.False(t, estimator.(*gas.WrappedEvmEstimator).EIP1559Enabled)
+	})
+
+	t.Run("returns 503 with the reason if the estimator has no prices yet", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, uint32(0), &gas.EstimationError{Reason: gas.ErrStalePrice, Err: errors.New("BlockHistoryEstimator has not finished the first gas estimation yet")}).Once()
+		s := newServer(t, gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, false), nil), gasdebug.Config{})
+
+		var resp gasdebug.Error
+		require.Equal(t, http.StatusServiceUnavailable, get(t, s.URL+"/chains/1/gas/estimate", &resp))
+		assert.Equal(t, "BlockHistoryEstimator has not finished the first gas estimation yet", resp.Error)
+	})
+
+	t.Run("returns 503 instead of waiting for a cold estimator", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		returned := make(chan struct{})
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			defer close(returned)
+			<-args.Get(0).(context.Context).Done()
+		}).Return(nil, uint32(0), context.Canceled).Once()
+		s := newServer(t, gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, false), nil), gasdebug.Config{ColdStartTimeout: 100 * time.Millisecond})
+
+		var resp gasdebug.Error
+		start := time.Now()
+		require.Equal(t, http.StatusServiceUnavailable, get(t, s.URL+"/chains/1/gas/estimate", &resp))
+		assert.Less(t, time.Since(start), gasdebug.DefaultRequestTimeout)
+		assert.Equal(t, "no fee within 100ms, the estimator may still be starting: estimator has not returned a fee yet", resp.Error)
+		// the estimate is cancelled with the request
+		select {
+		case <-returned:
+		case <-time.After(testutils.WaitTimeout(t)):
+			t.Fatal("estimate was not cancelled")
+		}
+	})
+
+	t.Run("rejects invalid parameters", func(t *testing.T) {
+		s := newServer(t, gas.NewWrappedEvmEstimator(logger.TestLogger(t), mocks.NewEvmEstimator(t), newConfig(t, false), nil), gasdebug.Config{})
+
+		for query, exp := range map[string]string{
+			"limit=abc":        `invalid limit "abc", must be an integer between 1 and 4294967295`,
+			"limit=0":          `invalid limit "0", must be an integer between 1 and 4294967295`,
+			"limit=-1":         `invalid limit "-1", must be an integer between 1 and 4294967295`,
+			"limit=4294967296": `invalid limit "4294967296", must be an integer between 1 and 4294967295`,
+			"limit=1.5":        `invalid limit "1.5", must be an integer between 1 and 4294967295`,
+			"mode=eip1559":     `invalid mode "eip1559", must be dynamic or legacy`,
+		} {
+			var resp gasdebug.Error
+			require.Equal(t, http.StatusBadRequest, get(t, s.URL+"/chains/1/gas/estimate?"+query, &resp), query)
+			assert.Equal(t, exp, resp.Error, query)
+		}
+
+		var resp gasdebug.Error
+		require.Equal(t, http.StatusNotFound, get(t, s.URL+"/chains/2/gas/estimate", &resp))
+		assert.Equal(t, "no gas estimator for chain 2", resp.Error)
+		require.Equal(t, http.StatusNotFound, get(t, s.URL+"/chains/1/gas", &resp))
+	})
+
+	t.Run("estimates the gas limit of posted calls", func(t *testing.T) {
+		cfg := newConfig(t, true)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, []byte{0x01, 0x02}, uint32(500_000), maxGasPrice).Return(assets.GWei(7), uint32(500_000), nil).Once()
+		client := mocks.NewRPCClient(t)
+		to := testutils.NewAddress()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_estimateGas", mock.MatchedBy(func(args map[string]interface{}) bool {
+			return args["to"] == to && assert.ObjectsAreEqual(hexutil.Bytes{0x01, 0x02}, args["data"])
+		})).Run(func(args mock.Arguments) {
+			*args.Get(1).(*hexutil.Uint64) = 60_000
+		}).Return(nil).Once()
+		s := newServer(t, gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, client), gasdebug.Config{})
+
+		resp, err := http.Post(s.URL+"/chains/1/gas/estimate?limit=500000", "application/json", strings.NewReader(`{"to":"`+to.Hex()+`","data":"0x0102"}`)) //nolint:gosec
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var estimate gasdebug.Estimate
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&estimate))
+		assert.Equal(t, uint32(60_000), estimate.GasLimit)
+		assert.Equal(t, assets.GWei(7), estimate.GasPrice)
+	})
+
+	t.Run("rejects posted calls if gas limit estimation is disabled", func(t *testing.T) {
+		s := newServer(t, gas.NewWrappedEvmEstimator(logger.TestLogger(t), mocks.NewEvmEstimator(t), newConfig(t, false), nil), gasdebug.Config{})
+
+		resp, err := http.Post(s.URL+"/chains/1/gas/estimate", "application/json", strings.NewReader(`{"data":"0x01"}`)) //nolint:gosec
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
@@ -0,0 +1,280 @@
+package gastestutils
+
+import (
+	"math"
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmclimocks "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// TipDistributionKind is the distribution that the tips of generated
+// transactions are drawn from
+type TipDistributionKind int
+
+const (
+	// TipsConstant gives every transaction the same tip
+	TipsConstant TipDistributionKind = iota
+	// TipsNormal draws the tips from a normal distribution, truncated at 0
+	TipsNormal
+	// TipsPareto draws the tips from a Pareto distribution, i.e. mostly low
+	// tips with a long tail of high ones
+	TipsPareto
+)
+
+// TipDistribution describes the tips of generated transactions
+type TipDistribution struct {
+	Kind TipDistributionKind
+	// Value is the tip of TipsConstant, the mean of TipsNormal and the
+	// minimum tip of TipsPareto
+	Value *assets.Wei
+	// StdDev is the standard deviation of TipsNormal
+	StdDev *assets.Wei
+	// Alpha is the shape of TipsPareto, where lower is heavier tailed. It
+	// defaults to 1.16, for which 20% of the transactions pay 80% of the tips.
+	Alpha float64
+}
+
+// BaseFeeKind is the trajectory of the base fee of generated blocks
+type BaseFeeKind int
+
+const (
+	// BaseFeeFlat keeps the base fee at its start
+	BaseFeeFlat BaseFeeKind = iota
+	// BaseFeeRising raises the base fee by Rate every block
+	BaseFeeRising
+	// BaseFeeSpiky keeps the base fee at its start, except for a spike every
+	// SpikeEvery blocks
+	BaseFeeSpiky
+)
+
+// BaseFeeTrajectory describes the base fees of generated blocks
+type BaseFeeTrajectory struct {
+	Kind BaseFeeKind
+	// Start is the base fee of the first block. If nil, the blocks have no
+	// base fee, as before EIP-1559, and all transactions are legacy
+	// transactions whose gas price is their tip.
+	Start *assets.Wei
+	// Rate is the increase per block of BaseFeeRising. It defaults to 0.125,
+	// the most that EIP-1559 allows.
+	Rate float64
+	// SpikeEvery is the number of blocks between the spikes of BaseFeeSpiky,
+	// it defaults to 10
+	SpikeEvery int
+	// SpikeFactor is the base fee of a spike of BaseFeeSpiky over Start, it
+	// defaults to 4
+	SpikeFactor float64
+}
+
+// BlockSpec describes the blocks to generate with BlockGenerator.Generate
+type BlockSpec struct {
+	// FirstNumber is the number of the first block
+	FirstNumber int64
+	// Blocks is the number of blocks
+	Blocks int
+	// TxsPerBlock is the number of transactions in each block
+	TxsPerBlock int
+	Tips        TipDistribution
+	BaseFee     BaseFeeTrajectory
+	// ZeroTipFraction is the fraction of the transactions that pay no tip
+	ZeroTipFraction float64
+	// LegacyFraction is the fraction of the transactions that are legacy
+	// (type 0x0) rather than EIP-1559 (type 0x2) transactions
+	LegacyFraction float64
+	// GasLimit is the gas limit of every transaction, it defaults to 21000
+	GasLimit uint32
+}
+
+// BlockGenerator generates synthetic blocks for testing the
+// BlockHistoryEstimator. The blocks only depend on the seed of the generator
+// and the specs, so tests are reproducible.
+type BlockGenerator struct {
+	rng *rand.Rand
+}
+
+// NewBlockGenerator returns a generator seeded with seed
+func NewBlockGenerator(seed int64) *BlockGenerator {
+	return &BlockGenerator{rng: rand.New(rand.NewSource(seed))} //nolint:gosec // deterministic test data
+}
+
+// Generate returns the blocks described by spec, in the shape that the
+// BlockHistoryEstimator decodes from eth_getBlockByNumber. EIP-1559
+// transactions pay the base fee plus their tip, with a fee cap of twice the
+// base fee plus their tip.
+func (g *BlockGenerator) Generate(spec BlockSpec) []evmtypes.Block {
+	gasLimit := spec.GasLimit
+	if gasLimit == 0 {
+		gasLimit = 21_000
+	}
+	blocks := make([]evmtypes.Block, spec.Blocks)
+	parentHash := g.hash()
+	for i := range blocks {
+		baseFee := g.baseFee(spec.BaseFee, i)
+		txs := make([]evmtypes.Transaction, spec.TxsPerBlock)
+		for j := range txs {
+			tip := assets.NewWeiI(0)
+			if g.rng.Float64() >= spec.ZeroTipFraction {
+				tip = g.tip(spec.Tips)
+			}
+			tx := evmtypes.Transaction{GasLimit: gasLimit, Hash: g.hash()}
+			switch {
+			case baseFee == nil:
+				tx.GasPrice = tip
+			case g.rng.Float64() < spec.LegacyFraction:
+				tx.GasPrice = baseFee.Add(tip)
+			default:
+				tx.Type = 0x2
+				tx.GasPrice = baseFee.Add(tip)
+				tx.MaxPriorityFeePerGas = tip
+				tx.MaxFeePerGas = baseFee.Mul(big.NewInt(2)).Add(tip)
+			}
+			txs[j] = tx
+		}
+		blocks[i] = evmtypes.Block{
+			Number:        spec.FirstNumber + int64(i),
+			Hash:          g.hash(),
+			ParentHash:    parentHash,
+			BaseFeePerGas: baseFee,
+			Timestamp:     time.Unix(1_700_000_000+12*(spec.FirstNumber+int64(i)), 0),
+			Transactions:  txs,
+		}
+		parentHash = blocks[i].Hash
+	}
+	return blocks
+}
+
+// ShuffleTransactions returns a copy of blocks with the transactions of each
+// block in a random order
+func (g *BlockGenerator) ShuffleTransactions(blocks []evmtypes.Block) []evmtypes.Block {
+	shuffled := make([]evmtypes.Block, len(blocks))
+	for i, block := range blocks {
+		block.Transactions = append([]evmtypes.Transaction(nil), block.Transactions...)
+		g.rng.Shuffle(len(block.Transactions), func(j, k int) {
+			block.Transactions[j], block.Transactions[k] = block.Transactions[k], block.Transactions[j]
+		})
+		shuffled[i] = block
+	}
+	return shuffled
+}
+
+func (g *BlockGenerator) tip(d TipDistribution) *assets.Wei {
+	value := float64(d.Value.Int64())
+	switch d.Kind {
+	case TipsNormal:
+		value += g.rng.NormFloat64() * float64(d.StdDev.Int64())
+	case TipsPareto:
+		alpha := d.Alpha
+		if alpha == 0 {
+			alpha = 1.16
+		}
+		// inverse transform sampling, with 1-Float64 in (0, 1]
+		value /= math.Pow(1-g.rng.Float64(), 1/alpha)
+	}
+	return weiFromFloat(value)
+}
+
+func (g *BlockGenerator) baseFee(t BaseFeeTrajectory, i int) *assets.Wei {
+	if t.Start == nil {
+		return nil
+	}
+	start := float64(t.Start.Int64())
+	switch t.Kind {
+	case BaseFeeRising:
+		rate := t.Rate
+		if rate == 0 {
+			rate = 0.125
+		}
+		return weiFromFloat(start * math.Pow(1+rate, float64(i)))
+	case BaseFeeSpiky:
+		every, factor := t.SpikeEvery, t.SpikeFactor
+		if every == 0 {
+			every = 10
+		}
+		if factor == 0 {
+			factor = 4
+		}
+		if i%every == every-1 {
+			return weiFromFloat(start * factor)
+		}
+	}
+	return t.Start
+}
+
+func (g *BlockGenerator) hash() (h common.Hash) {
+	g.rng.Read(h[:])
+	return
+}
+
+// weiFromFloat rounds value to wei, clamped to [0, MaxInt64]
+func weiFromFloat(value float64) *assets.Wei {
+	switch {
+	case value <= 0 || math.IsNaN(value):
+		return assets.NewWeiI(0)
+	case value >= math.MaxInt64:
+		return assets.NewWeiI(math.MaxInt64)
+	}
+	return assets.NewWeiI(int64(math.Round(value)))
+}
+
+// NewBlockClient returns a client that serves blocks to the
+// eth_getBlockByNumber batch calls of the BlockHistoryEstimator, and the last
+// of them as the latest head. Other blocks are missing.
+func NewBlockClient(t *testing.T, blocks []evmtypes.Block) evmclient.Client {
+	byNumber := make(map[int64]evmtypes.Block, len(blocks))
+	for _, block := range blocks {
+		byNumber[block.Number] = block
+	}
+	client := evmclimocks.NewClient(t)
+	client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		reqs := args.Get(1).([]rpc.BatchElem)
+		for i := range reqs {
+			block, ok := byNumber[gas.HexToInt64(reqs[i].Args[0])]
+			if !ok {
+				reqs[i].Error = evmtypes.ErrMissingBlock
+				continue
+			}
+			*reqs[i].Result.(*evmtypes.Block) = block
+		}
+	}).Maybe()
+	if len(blocks) > 0 {
+		client.On("HeadByNumber", mock.Anything, mock.Anything).Return(HeadOf(blocks[len(blocks)-1]), nil).Maybe()
+	}
+	return client
+}
+
+// HeadOf returns the head of block
+func HeadOf(block evmtypes.Block) *evmtypes.Head {
+	return &evmtypes.Head{
+		Number:        block.Number,
+		Hash:          block.Hash,
+		ParentHash:    block.ParentHash,
+		BaseFeePerGas: block.BaseFeePerGas,
+		Timestamp:     block.Timestamp,
+		EVMChainID:    utils.NewBig(testutils.FixtureChainID),
+	}
+}
+
+// FeedBlockHistory returns a BlockHistoryEstimator that has fetched blocks
+// from NewBlockClient and recalculated its prices from them, as on a head of
+// the last block. The estimator is not started, so that no heads are fetched
+// in the background.
+func FeedBlockHistory(t *testing.T, cfg gas.Config, blocks []evmtypes.Block) *gas.BlockHistoryEstimator {
+	t.Helper()
+	client := NewBlockClient(t, blocks)
+	bhe := gas.NewBlockHistoryEstimator(logger.TestLogger(t), client, cfg, *testutils.FixtureChainID, nil).(*gas.BlockHistoryEstimator)
+	bhe.FetchBlocksAndRecalculate(testutils.Context(t), HeadOf(blocks[len(blocks)-1]))
+	return bhe
+}
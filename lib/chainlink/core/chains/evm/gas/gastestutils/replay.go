@@ -0,0 +1,102 @@
+// Package gastestutils contains helpers for testing the gas estimators
+package gastestutils
+
+import (
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// replayConfig runs an estimator in the mode of a replay, without the fee
+// cache, so that the fees follow the replayed heads
+type replayConfig struct {
+	gas.Config
+	mode string
+}
+
+func (c replayConfig) GasEstimatorMode() string { return c.mode }
+
+func (c replayConfig) EvmGasFeeCacheTTL() time.Duration { return 0 }
+
+// Replay is an estimator that was started against a file recorded with
+// gas.RecordingRPCClient or gas.NewRecordingClient
+type Replay struct {
+	Estimator gas.EvmFeeEstimator
+	RPC       *gas.ReplayRPCClient
+	// Client serves the replayed calls as an evmclient.Client, on
+	// testutils.FixtureChainID
+	Client evmclient.Client
+}
+
+// NewReplay starts an estimator of the given mode, e.g. "BlockHistory", whose
+// calls are served from the recording at path. With strict, the estimator must
+// make the calls in the order in which they were recorded. The estimator is
+// closed at the end of the test.
+func NewReplay(t *testing.T, mode string, cfg gas.Config, path string, strict bool) *Replay {
+	t.Helper()
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	replay, err := gas.NewReplayRPCClient(f, strict)
+	require.NoError(t, err)
+
+	client := gas.NewReplayClient(replay, evmtest.NewEthClientMockWithDefaultChain(t))
+	estimator := gas.NewEstimator(logger.TestLogger(t), client, replayConfig{Config: cfg, mode: mode}, nil)
+	require.NoError(t, estimator.Start(testutils.Context(t)))
+	t.Cleanup(func() { assert.NoError(t, estimator.Close()) })
+	return &Replay{Estimator: estimator, RPC: replay, Client: client}
+}
+
+// Head returns the recorded head with the given number
+func (r *Replay) Head(t *testing.T, number int64) *evmtypes.Head {
+	t.Helper()
+	head, err := r.Client.HeadByNumber(testutils.Context(t), big.NewInt(number))
+	require.NoError(t, err)
+	return head
+}
+
+// OnHead passes the recorded head with the given number to the estimator.
+// Estimators may process heads in the background, so call AssertFee after
+// each head to replay the heads in order.
+func (r *Replay) OnHead(t *testing.T, number int64) {
+	t.Helper()
+	r.Estimator.OnNewLongestChain(testutils.Context(t), r.Head(t, number))
+}
+
+// AssertFee asserts that the estimator eventually estimates want for a
+// transaction of gasLimit, comparing only the legacy gas price and the dynamic
+// fee and tip caps
+func (r *Replay) AssertFee(t *testing.T, gasLimit uint32, want gas.EvmFee) {
+	t.Helper()
+	var got gas.EvmFee
+	ok := assert.Eventually(t, func() bool {
+		fee, _, err := r.Estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		if err != nil {
+			return false
+		}
+		got = fee
+		return weiEqual(fee.Legacy, want.Legacy) && weiEqual(fee.DynamicFeeCap, want.DynamicFeeCap) && weiEqual(fee.DynamicTipCap, want.DynamicTipCap)
+	}, testutils.WaitTimeout(t), testutils.TestInterval)
+	if !ok {
+		t.Errorf("expected fee %s, got %s", want, got)
+	}
+}
+
+func weiEqual(a, b *assets.Wei) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
@@ -0,0 +1,85 @@
+package gas
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// healthFailureThreshold is the number of consecutive failed refreshes
+	// after which an estimator reports itself unhealthy. A single failed
+	// refresh is common and the previous prices keep being used, so it is
+	// only logged.
+	healthFailureThreshold = 3
+	// healthStartupGracePeriod is how long after starting the
+	// BlockHistoryEstimator may have an empty block history before it
+	// reports itself unhealthy
+	healthStartupGracePeriod = time.Minute
+)
+
+// refreshHealth tracks the outcome of the price refreshes of an estimator's
+// background loop, so that its health report reflects failures that
+// otherwise only show up in the logs
+type refreshHealth struct {
+	mu                  sync.RWMutex
+	startedAt           time.Time
+	consecutiveFailures int
+	lastErr             error
+	lastSuccess         time.Time
+}
+
+// start records the time the estimator started at
+func (h *refreshHealth) start() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.startedAt = time.Now()
+}
+
+// record records the outcome of a refresh
+func (h *refreshHealth) record(err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.consecutiveFailures++
+		h.lastErr = err
+		return
+	}
+	h.consecutiveFailures = 0
+	h.lastErr = nil
+	h.lastSuccess = time.Now()
+}
+
+// check returns an error if the last healthFailureThreshold refreshes failed,
+// or if the last successful refresh is older than staleThreshold. A zero
+// staleThreshold disables the staleness check.
+func (h *refreshHealth) check(staleThreshold time.Duration) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.consecutiveFailures >= healthFailureThreshold {
+		return errors.Wrapf(h.lastErr, "the last %d price refreshes failed", h.consecutiveFailures)
+	}
+	if staleThreshold > 0 && !h.lastSuccess.IsZero() {
+		if age := time.Since(h.lastSuccess); age > staleThreshold {
+			return errors.Errorf("prices were last refreshed %s ago, more than EVM.GasEstimator.PriceStaleThreshold of %s", age.Round(time.Millisecond), staleThreshold)
+		}
+	}
+	return nil
+}
+
+// lastRefreshed returns the time of the last successful refresh, or the zero
+// time if there was none
+func (h *refreshHealth) lastRefreshed() time.Time {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.lastSuccess
+}
+
+// pastGracePeriod returns true if the estimator started more than
+// healthStartupGracePeriod ago
+func (h *refreshHealth) pastGracePeriod() bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return !h.startedAt.IsZero() && time.Since(h.startedAt) > healthStartupGracePeriod
+}
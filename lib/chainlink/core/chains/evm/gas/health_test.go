@@ -0,0 +1,125 @@
+package gas_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestL2SuggestedEstimator_HealthReport(t *testing.T) {
+	t.Parallel()
+
+	mockGasPrice := func(client *mocks.RPCClient, price int64) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		})
+	}
+
+	t.Run("is unhealthy after consecutive failed refreshes until a refresh succeeds", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom")).Times(3)
+		mockGasPrice(client, 43).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		assert.NoError(t, o.HealthReport()[o.Name()])
+
+		for i := 0; i < 2; i++ {
+			require.Error(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+			assert.NoError(t, o.HealthReport()[o.Name()])
+		}
+		require.Error(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		assert.EqualError(t, o.HealthReport()[o.Name()], "the last 3 price refreshes failed: kaboom")
+
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		assert.NoError(t, o.HealthReport()[o.Name()])
+	})
+
+	t.Run("is unhealthy once the price is older than PriceStaleThreshold", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceStaleThresholdF = 100 * time.Millisecond
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		require.Eventually(t, func() bool { return o.HealthReport()[o.Name()] != nil }, testutils.WaitTimeout(t), 10*time.Millisecond)
+		assert.ErrorContains(t, o.HealthReport()[o.Name()], "more than EVM.GasEstimator.PriceStaleThreshold of 100ms")
+
+		mockGasPrice(client, 43).Once()
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		assert.NoError(t, o.HealthReport()[o.Name()])
+	})
+}
+
+func TestBlockHistoryEstimator_HealthReport(t *testing.T) {
+	t.Parallel()
+
+	newEstimator := func(t *testing.T) (*gas.BlockHistoryEstimator, *mock.Mock) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+		cfg.BlockHistoryEstimatorBlockDelayF = uint16(0)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(35)
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = uint16(1)
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+		cfg.BlockHistoryEstimatorBatchSizeF = uint32(0)
+
+		bhe := newBlockHistoryEstimator(t, ethClient, cfg)
+		gas.SimulateStart(t, bhe)
+		return bhe, &ethClient.Mock
+	}
+
+	t.Run("is unhealthy after consecutive failed block fetches until a fetch succeeds", func(t *testing.T) {
+		bhe, m := newEstimator(t)
+		m.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("kaboom")).Times(3)
+		m.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			elems[0].Result = &evmtypes.Block{Number: 1, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(100)}
+		}).Once()
+
+		for i := 0; i < 2; i++ {
+			bhe.FetchBlocksAndRecalculate(testutils.Context(t), cltest.Head(1))
+			assert.NoError(t, bhe.HealthReport()[bhe.Name()])
+		}
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), cltest.Head(1))
+		assert.ErrorContains(t, bhe.HealthReport()[bhe.Name()], "the last 3 price refreshes failed")
+		assert.ErrorContains(t, bhe.HealthReport()[bhe.Name()], "kaboom")
+
+		bhe.FetchBlocksAndRecalculate(testutils.Context(t), cltest.Head(1))
+		assert.NoError(t, bhe.HealthReport()[bhe.Name()])
+		assert.Equal(t, assets.NewWeiI(100), gas.GetGasPrice(bhe))
+	})
+
+	t.Run("is unhealthy if the block history is empty after the startup grace period", func(t *testing.T) {
+		bhe, _ := newEstimator(t)
+
+		gas.SetHealthStartedAt(bhe, time.Now())
+		assert.NoError(t, bhe.HealthReport()[bhe.Name()])
+
+		gas.SetHealthStartedAt(bhe, time.Now().Add(-2*time.Minute))
+		assert.EqualError(t, bhe.HealthReport()[bhe.Name()], "block history is still empty 1m0s after starting")
+
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{{Number: 1, Hash: utils.NewHash()}})
+		assert.NoError(t, bhe.HealthReport()[bhe.Name()])
+	})
+}
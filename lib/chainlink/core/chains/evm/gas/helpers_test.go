@@ -1,6 +1,10 @@
 package gas
 
 import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/url"
 	"testing"
 	"time"
 
@@ -69,10 +73,89 @@ func GetLatestBaseFee(b *BlockHistoryEstimator) *assets.Wei {
 	return b.latest.BaseFeePerGas
 }
 
+func GetL2TipCap(e EvmEstimator) *assets.Wei {
+	return e.(*l2SuggestedPriceEstimator).getTipCap()
+}
+
+var (
+	MulDiv      = mulDiv
+	AddCapped   = addCapped
+	PercentBump = percentBump
+	CalcFeeCap  = calcFeeCap
+	RoundDown   = roundDown
+	RoundUp     = roundUp
+)
+
+var (
+	PromGasEstimatorSuggestedGasPrice   = promGasEstimatorSuggestedGasPrice
+	PromGasEstimatorSuggestedTipCap     = promGasEstimatorSuggestedTipCap
+	PromGasEstimatorBaseFee             = promGasEstimatorBaseFee
+	PromGasEstimatorBumpCount           = promGasEstimatorBumpCount
+	PromGasEstimatorMaxPriceCappedCount = promGasEstimatorMaxPriceCappedCount
+	PromGasEstimatorRPCErrorCount       = promGasEstimatorRPCErrorCount
+	PromGasEstimatorRPCTimeoutCount     = promGasEstimatorRPCTimeoutCount
+	PromBlockHistoryEstimatorWindowSize = promBlockHistoryEstimatorWindowSize
+	PromGasEstimatorDecodeErrorCount    = promGasEstimatorDecodeErrorCount
+)
+
+// RPCClient is the client of the estimators' RPC calls
+type RPCClient = rpcClient
+
+var NewTimeoutClient = newTimeoutClient
+
+// NewTimeoutRPCClient applies EVM.GasEstimator.RPCCallTimeout to the calls of
+// client
+func NewTimeoutRPCClient(client rpcClient, timeout time.Duration) rpcClient {
+	return timeoutRPCClient{client: client, timeout: timeout}
+}
+
+func UnregisterEstimator(name string) {
+	estimatorRegistryMu.Lock()
+	defer estimatorRegistryMu.Unlock()
+	delete(estimatorRegistry, name)
+}
+
+// SetSimulateBeforeBumpTimeout sets the timeout of the simulation of
+// EVM.GasEstimator.SimulateBeforeBump
+func SetSimulateBeforeBumpTimeout(e EvmFeeEstimator, timeout time.Duration) {
+	e.(*WrappedEvmEstimator).simulateTimeout = timeout
+}
+
+// SetDynamicFeeSupportCheckInterval sets how often the latest block is
+// checked for a base fee with EVM.GasEstimator.EIP1559DynamicFees
+func SetDynamicFeeSupportCheckInterval(e EvmFeeEstimator, interval time.Duration) {
+	e.(*WrappedEvmEstimator).dynamicFeeSupport.interval = interval
+}
+
+// SetExternalAPIClock sets the clock that the ExternalAPIEstimator ages its
+// prices with. It must be called before Start.
+func SetExternalAPIClock(e EvmEstimator, now func() time.Time) {
+	e.(*externalAPIEstimator).now = now
+}
+
+// RefreshExternalAPI synchronously refreshes the prices of the
+// ExternalAPIEstimator from the API
+func RefreshExternalAPI(e EvmEstimator) error {
+	return e.(*externalAPIEstimator).refresh()
+}
+
+// ProcessHead processes head as runLoop does
+func ProcessHead(b *BlockHistoryEstimator, head *evmtypes.Head) {
+	b.processHead(context.Background(), head)
+}
+
 func SimulateStart(t *testing.T, b *BlockHistoryEstimator) {
 	require.NoError(t, b.StartOnce("BlockHistoryEstimatorSimulatedStart", func() error { return nil }))
 }
 
+// SetHealthStartedAt sets the time the estimator is considered started at
+// by its health report
+func SetHealthStartedAt(b *BlockHistoryEstimator, startedAt time.Time) {
+	b.health.mu.Lock()
+	defer b.health.mu.Unlock()
+	b.health.startedAt = startedAt
+}
+
 type MockConfig struct {
 	BlockHistoryEstimatorBatchSizeF                 uint32
 	BlockHistoryEstimatorBlockDelayF                uint16
@@ -86,12 +169,61 @@ type MockConfig struct {
 	EvmGasBumpPercentF                              uint16
 	EvmGasBumpThresholdF                            uint64
 	EvmGasBumpWeiF                                  *assets.Wei
+	EvmGasFeeCapDefaultF                            *assets.Wei
 	EvmGasLimitMultiplierF                          float32
 	EvmGasTipCapDefaultF                            *assets.Wei
 	EvmGasTipCapMinimumF                            *assets.Wei
 	EvmMaxGasPriceWeiF                              *assets.Wei
 	EvmMinGasPriceWeiF                              *assets.Wei
 	EvmGasPriceDefaultF                             *assets.Wei
+	EvmMaxBlobGasPriceWeiF                          *assets.Wei
+	EvmGasPriceStaleThresholdF                      time.Duration
+	EvmGasSuggestedPriceConnectivityCheckF          bool
+	GasEstimatorModeF                               string
+	BlockHistoryEstimatorTipCapTrimPercentileF      uint16
+	EvmGasFeeCacheTTLF                              time.Duration
+	EvmGasEstimateGasLimitF                         bool
+	EvmGasLimitMinF                                 uint32
+	EvmGasLimitMaxF                                 uint32
+	EvmGasBumpStrategyF                             string
+	EvmGasSimulateBeforeBumpF                       bool
+	EvmGasRPCRateLimitF                             uint32
+	EvmGasRPCRateLimitBurstF                        uint32
+	BlockHistoryEstimatorBaseFeeLookaheadBlocksF    uint16
+	BlockHistoryEstimatorMaxReorgDepthF             uint16
+	GasEstimatorFallbackModesF                      []string
+	EvmGasBatchTipIncrementF                        *assets.Wei
+	BlockHistoryEstimatorInclusionPercentilesF      []string
+	EvmGasEstimateAccessListF                       bool
+	EvmGasPriceUpdateThresholdF                     uint16
+	EvmGasMaxTxCostF                                *assets.Wei
+	EvmGasFeeAnomalyFactorF                         float32
+	EvmGasFeeAnomalyHalfLifeF                       time.Duration
+	EvmGasFeeAnomalyCooldownF                       time.Duration
+	EvmGasNodeMinPriceSyncF                         bool
+	EvmGasNodeMinPriceMethodF                       string
+	EvmGasBumpFeeCapFromBaseFeeF                    bool
+	EvmGasRPCCallTimeoutF                           time.Duration
+	EvmGasFeeCurrencyF                              *common.Address
+	BlockHistoryEstimatorHistoryDurationF           time.Duration
+	BlockHistoryEstimatorHistorySizeMinF            uint16
+	BlockHistoryEstimatorHistorySizeMaxF            uint16
+	EvmGasDecisionLogSampleRateF                    uint32
+	EvmGasDecisionLogAlwaysF                        []string
+	ExternalAPIEstimatorURLF                        *url.URL
+	ExternalAPIEstimatorSpeedF                      string
+	ExternalAPIEstimatorSafeLowPathF                string
+	ExternalAPIEstimatorStandardPathF               string
+	ExternalAPIEstimatorFastPathF                   string
+	ExternalAPIEstimatorBaseFeePathF                string
+	ExternalAPIEstimatorUnitF                       string
+	ExternalAPIEstimatorPricesIncludeBaseFeeF       bool
+	BlockHistoryEstimatorExcludedTxTypesF           []uint8
+	EvmGasDrainTimeoutF                             time.Duration
+	EvmGasFeeRoundingF                              *assets.Wei
+	EvmGasBaseFeeBufferMultiplierF                  float32
+	FeeHistoryEstimatorBlockCountF                  uint16
+	FeeHistoryEstimatorRewardPercentileF            uint16
 }
 
 func NewMockConfig() *MockConfig {
@@ -151,11 +283,11 @@ func (m *MockConfig) EvmGasBumpWei() *assets.Wei {
 }
 
 func (m *MockConfig) EvmGasFeeCapDefault() *assets.Wei {
-	panic("not implemented") // TODO: Implement
+	return m.EvmGasFeeCapDefaultF
 }
 
 func (m *MockConfig) EvmGasLimitMax() uint32 {
-	panic("not implemented") // TODO: Implement
+	return m.EvmGasLimitMaxF
 }
 
 func (m *MockConfig) EvmGasLimitMultiplier() float32 {
@@ -183,5 +315,264 @@ func (m *MockConfig) EvmMinGasPriceWei() *assets.Wei {
 }
 
 func (m *MockConfig) GasEstimatorMode() string {
-	panic("not implemented") // TODO: Implement
+	return m.GasEstimatorModeF
+}
+
+func (m *MockConfig) EvmMaxBlobGasPriceWei() *assets.Wei {
+	return m.EvmMaxBlobGasPriceWeiF
+}
+
+func (m *MockConfig) EvmGasPriceStaleThreshold() time.Duration {
+	return m.EvmGasPriceStaleThresholdF
+}
+
+func (m *MockConfig) EvmGasSuggestedPriceConnectivityCheck() bool {
+	return m.EvmGasSuggestedPriceConnectivityCheckF
+}
+
+func (m *MockConfig) BlockHistoryEstimatorTipCapTrimPercentile() uint16 {
+	return m.BlockHistoryEstimatorTipCapTrimPercentileF
+}
+
+func (m *MockConfig) EvmGasFeeCacheTTL() time.Duration {
+	return m.EvmGasFeeCacheTTLF
+}
+
+func (m *MockConfig) EvmGasEstimateGasLimit() bool {
+	return m.EvmGasEstimateGasLimitF
+}
+
+func (m *MockConfig) EvmGasLimitMin() uint32 {
+	return m.EvmGasLimitMinF
+}
+
+func (m *MockConfig) EvmGasBumpStrategy() string {
+	return m.EvmGasBumpStrategyF
+}
+
+func SetFeeHistoryPollPeriod(e EvmEstimator, pollPeriod time.Duration) {
+	e.(*feeHistoryEstimator).pollPeriod = pollPeriod
+}
+
+func SetQuorumCallTimeout(e EvmEstimator, timeout time.Duration) {
+	switch e := e.(type) {
+	case *l2SuggestedPriceEstimator:
+		e.quorum.callTimeout = timeout
+	case *feeHistoryEstimator:
+		e.quorum.callTimeout = timeout
+	}
+}
+
+func (m *MockConfig) EvmGasSimulateBeforeBump() bool {
+	return m.EvmGasSimulateBeforeBumpF
+}
+
+func (m *MockConfig) EvmGasRPCRateLimit() uint32 {
+	return m.EvmGasRPCRateLimitF
+}
+
+func (m *MockConfig) EvmGasRPCRateLimitBurst() uint32 {
+	return m.EvmGasRPCRateLimitBurstF
+}
+
+const (
+	RPCBackoffMin          = rpcBackoffMin
+	RPCRateLimitMaxRetries = rpcRateLimitMaxRetries
+)
+
+type RPCClock = rpcClock
+
+func NewRateLimitedRPCClient(client rpcClient, requestsPerSecond, burst uint32, clock RPCClock) rpcClient {
+	return rateLimitedRPCClient{client: client, limiter: newRPCLimiter(requestsPerSecond, burst, clock)}
+}
+
+func RPCLimiterFor(chainID *big.Int, cfg Config) any {
+	return rpcLimiterFor(chainID, cfg)
+}
+
+func (m *MockConfig) BlockHistoryEstimatorBaseFeeLookaheadBlocks() uint16 {
+	return m.BlockHistoryEstimatorBaseFeeLookaheadBlocksF
+}
+
+func NextBaseFee(baseFee *big.Int, gasUsed, gasLimit uint64) *big.Int {
+	return nextBaseFee(baseFee, gasUsed, gasLimit)
+}
+
+func PredictBaseFee(ctx context.Context, b *BlockHistoryEstimator, head *evmtypes.Head) {
+	b.predictBaseFee(ctx, head)
+}
+
+func (m *MockConfig) BlockHistoryEstimatorMaxReorgDepth() uint16 {
+	return m.BlockHistoryEstimatorMaxReorgDepthF
+}
+
+const FeeUpdateBufferSize = feeUpdateBufferSize
+
+func (m *MockConfig) GasEstimatorFallbackModes() []string {
+	return m.GasEstimatorFallbackModesF
+}
+
+const FallbackCooldown = fallbackCooldown
+
+func SetFallbackClock(e EvmEstimator, now func() time.Time) {
+	e.(*fallbackEstimator).now = now
+}
+
+func (m *MockConfig) EvmGasBatchTipIncrement() *assets.Wei {
+	return m.EvmGasBatchTipIncrementF
+}
+
+func (m *MockConfig) BlockHistoryEstimatorInclusionPercentiles() []string {
+	return m.BlockHistoryEstimatorInclusionPercentilesF
+}
+
+func (m *MockConfig) EvmGasEstimateAccessList() bool {
+	return m.EvmGasEstimateAccessListF
+}
+
+func (m *MockConfig) EvmGasPriceUpdateThreshold() uint16 {
+	return m.EvmGasPriceUpdateThresholdF
+}
+
+func (m *MockConfig) EvmGasMaxTxCost() *assets.Wei {
+	return m.EvmGasMaxTxCostF
+}
+
+// SetRPCPrice sets the mocked result of an RPC call for a price
+func SetRPCPrice(result interface{}, price int64) {
+	result.(*rpcPrice).ToInt().SetInt64(price)
+}
+
+func DecodeRPCPrice(input string) (*big.Int, error) {
+	var p rpcPrice
+	if err := json.Unmarshal([]byte(input), &p); err != nil {
+		return nil, err
+	}
+	return p.ToInt(), nil
+}
+
+func (m *MockConfig) EvmGasFeeAnomalyFactor() float32 {
+	return m.EvmGasFeeAnomalyFactorF
+}
+
+func (m *MockConfig) EvmGasFeeAnomalyHalfLife() time.Duration {
+	return m.EvmGasFeeAnomalyHalfLifeF
+}
+
+func (m *MockConfig) EvmGasFeeAnomalyCooldown() time.Duration {
+	return m.EvmGasFeeAnomalyCooldownF
+}
+
+var PromGasEstimatorFeeAnomalyCount = promGasEstimatorFeeAnomalyCount
+
+func SetAnomalyClock(e EvmFeeEstimator, now func() time.Time) {
+	e.(*WrappedEvmEstimator).anomalies.now = now
+}
+
+func (m *MockConfig) EvmGasNodeMinPriceSync() bool {
+	return m.EvmGasNodeMinPriceSyncF
+}
+
+func (m *MockConfig) EvmGasNodeMinPriceMethod() string {
+	return m.EvmGasNodeMinPriceMethodF
+}
+
+func (m *MockConfig) EvmGasBumpFeeCapFromBaseFee() bool {
+	return m.EvmGasBumpFeeCapFromBaseFeeF
+}
+
+func (m *MockConfig) EvmGasRPCCallTimeout() time.Duration {
+	return m.EvmGasRPCCallTimeoutF
+}
+
+func (m *MockConfig) EvmGasFeeCurrency() *common.Address {
+	return m.EvmGasFeeCurrencyF
+}
+
+func (m *MockConfig) BlockHistoryEstimatorHistoryDuration() time.Duration {
+	return m.BlockHistoryEstimatorHistoryDurationF
+}
+
+func (m *MockConfig) BlockHistoryEstimatorHistorySizeMin() uint16 {
+	return m.BlockHistoryEstimatorHistorySizeMinF
+}
+
+func (m *MockConfig) BlockHistoryEstimatorHistorySizeMax() uint16 {
+	return m.BlockHistoryEstimatorHistorySizeMaxF
+}
+
+func (m *MockConfig) EvmGasDecisionLogSampleRate() uint32 {
+	return m.EvmGasDecisionLogSampleRateF
+}
+
+func (m *MockConfig) EvmGasDecisionLogAlways() []string {
+	return m.EvmGasDecisionLogAlwaysF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorURL() *url.URL {
+	return m.ExternalAPIEstimatorURLF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorSpeed() string {
+	return m.ExternalAPIEstimatorSpeedF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorSafeLowPath() string {
+	return m.ExternalAPIEstimatorSafeLowPathF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorStandardPath() string {
+	return m.ExternalAPIEstimatorStandardPathF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorFastPath() string {
+	return m.ExternalAPIEstimatorFastPathF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorBaseFeePath() string {
+	return m.ExternalAPIEstimatorBaseFeePathF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorUnit() string {
+	return m.ExternalAPIEstimatorUnitF
+}
+
+func (m *MockConfig) ExternalAPIEstimatorPricesIncludeBaseFee() bool {
+	return m.ExternalAPIEstimatorPricesIncludeBaseFeeF
+}
+
+func (m *MockConfig) FeeHistoryEstimatorBlockCount() uint16 {
+	return m.FeeHistoryEstimatorBlockCountF
+}
+
+func (m *MockConfig) FeeHistoryEstimatorRewardPercentile() uint16 {
+	return m.FeeHistoryEstimatorRewardPercentileF
+}
+
+// SetPriceConversionTimeout sets the timeout of the calls to the
+// PriceConverter
+func SetPriceConversionTimeout(e EvmFeeEstimator, timeout time.Duration) {
+	e.(*WrappedEvmEstimator).conversionTimeout = timeout
+}
+
+// SetHTTPPriceConverterClock sets the clock that the HTTPPriceConverter ages
+// its cached price with
+func SetHTTPPriceConverterClock(c *HTTPPriceConverter, now func() time.Time) {
+	c.now = now
+}
+
+func (m *MockConfig) BlockHistoryEstimatorExcludedTxTypes() []uint8 {
+	return m.BlockHistoryEstimatorExcludedTxTypesF
+}
+
+func (m *MockConfig) EvmGasDrainTimeout() time.Duration {
+	return m.EvmGasDrainTimeoutF
+}
+
+func (m *MockConfig) EvmGasFeeRounding() *assets.Wei {
+	return m.EvmGasFeeRoundingF
+}
+
+func (m *MockConfig) EvmGasBaseFeeBufferMultiplier() float32 {
+	return m.EvmGasBaseFeeBufferMultiplierF
 }
@@ -0,0 +1,70 @@
+package gas
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type inclusionBlocksKey struct{}
+
+// WithInclusionBlocks returns a context that makes GetFee and GetDynamicFee
+// price the transaction to be included within n blocks. The BlockHistory
+// estimator maps n to the tip cap percentile of
+// EVM.GasEstimator.BlockHistory.InclusionPercentiles, so that tight deadlines
+// pay higher tips; other estimators ignore it. A target of 0 is the same as
+// none, which uses EVM.GasEstimator.BlockHistory.TransactionPercentile.
+func WithInclusionBlocks(ctx context.Context, n uint32) context.Context {
+	return context.WithValue(ctx, inclusionBlocksKey{}, n)
+}
+
+func inclusionBlocksFromContext(ctx context.Context) uint32 {
+	n, _ := ctx.Value(inclusionBlocksKey{}).(uint32)
+	return n
+}
+
+// inclusionPercentile is an entry of
+// EVM.GasEstimator.BlockHistory.InclusionPercentiles: transactions to be
+// included within blocks are priced at the percentile of recent tips
+type inclusionPercentile struct {
+	blocks     uint32
+	percentile uint16
+}
+
+// parseInclusionPercentiles parses blocks:percentile entries, which must have
+// increasing blocks
+func parseInclusionPercentiles(entries []string) ([]inclusionPercentile, error) {
+	curve := make([]inclusionPercentile, len(entries))
+	for i, entry := range entries {
+		b, p, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, errors.Errorf("entry %q must be of the form blocks:percentile", entry)
+		}
+		blocks, err := strconv.ParseUint(b, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid blocks of entry %q", entry)
+		}
+		percentile, err := strconv.ParseUint(p, 10, 16)
+		if err != nil || percentile > 100 {
+			return nil, errors.Errorf("invalid percentile of entry %q", entry)
+		}
+		if i > 0 && uint32(blocks) <= curve[i-1].blocks {
+			return nil, errors.New("blocks must be increasing")
+		}
+		curve[i] = inclusionPercentile{blocks: uint32(blocks), percentile: uint16(percentile)}
+	}
+	return curve, nil
+}
+
+// percentileForInclusion returns the percentile of the first entry of curve
+// that allows at least n blocks, or of the last entry if none does
+func percentileForInclusion(curve []inclusionPercentile, n uint32) uint16 {
+	for _, e := range curve {
+		if n <= e.blocks {
+			return e.percentile
+		}
+	}
+	return curve[len(curve)-1].percentile
+}
@@ -0,0 +1,119 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestBlockHistoryEstimator_InclusionBlocks(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.NewWeiI(1000000)
+	newBHE := func(t *testing.T) (*gas.BlockHistoryEstimator, *gas.MockConfig) {
+		cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+		cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(60)
+		cfg.BlockHistoryEstimatorInclusionPercentilesF = []string{"1:95", "2:90", "5:75", "10:60", "20:50", "50:30"}
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = uint16(0)
+		cfg.EvmGasBumpThresholdF = uint64(0)
+		cfg.EvmGasLimitMultiplierF = float32(1)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+
+		bhe := newBlockHistoryEstimator(t, nil, cfg)
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+			{
+				BaseFeePerGas: assets.NewWeiI(100000),
+				Number:        1,
+				Hash:          utils.NewHash(),
+				Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(1000, 2000, 3000, 4000, 5000, 6000, 7000, 8000, 9000, 10000),
+			},
+		})
+		bhe.Recalculate(cltest.Head(1))
+		gas.SimulateStart(t, bhe)
+		return bhe, cfg
+	}
+
+	t.Run("tight targets get strictly higher tips from the same block window", func(t *testing.T) {
+		bhe, _ := newBHE(t)
+
+		fast, _, err := bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), 1), 100000, maxGasPrice)
+		require.NoError(t, err)
+		slow, _, err := bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), 50), 100000, maxGasPrice)
+		require.NoError(t, err)
+
+		assert.True(t, fast.TipCap.Cmp(slow.TipCap) > 0, "tip for 1 block (%s) is not above the tip for 50 blocks (%s)", fast.TipCap, slow.TipCap)
+		assert.Equal(t, uint32(1), fast.InclusionBlocks)
+		assert.Equal(t, uint32(50), slow.InclusionBlocks)
+
+		// the tip doesn't decrease as the target gets tighter
+		prev := slow.TipCap
+		for _, n := range []uint32{20, 10, 5, 2, 1} {
+			fee, _, err := bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), n), 100000, maxGasPrice)
+			require.NoError(t, err)
+			assert.True(t, fee.TipCap.Cmp(prev) >= 0, "tip for %d blocks is below the tip of a looser target", n)
+			prev = fee.TipCap
+		}
+	})
+
+	t.Run("targets beyond the curve use its last percentile", func(t *testing.T) {
+		bhe, _ := newBHE(t)
+
+		slow, _, err := bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), 50), 100000, maxGasPrice)
+		require.NoError(t, err)
+		slowest, _, err := bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), 1000), 100000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, slow.TipCap, slowest.TipCap)
+		assert.Equal(t, uint32(1000), slowest.InclusionBlocks)
+	})
+
+	t.Run("without a target the tip is at TransactionPercentile", func(t *testing.T) {
+		bhe, _ := newBHE(t)
+
+		fee, _, err := bhe.GetDynamicFee(testutils.Context(t), 100000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: maxGasPrice, TipCap: gas.GetTipCap(bhe)}, fee)
+
+		// a target of 0 is the same as none
+		fee, _, err = bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), 0), 100000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: maxGasPrice, TipCap: gas.GetTipCap(bhe)}, fee)
+	})
+
+	t.Run("ignores the target with invalid InclusionPercentiles", func(t *testing.T) {
+		bhe, cfg := newBHE(t)
+		cfg.BlockHistoryEstimatorInclusionPercentilesF = []string{"1"}
+
+		fee, _, err := bhe.GetDynamicFee(gas.WithInclusionBlocks(testutils.Context(t), 1), 100000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{FeeCap: maxGasPrice, TipCap: gas.GetTipCap(bhe)}, fee)
+	})
+
+	t.Run("GetFee and BumpFee echo the target", func(t *testing.T) {
+		bhe, cfg := newBHE(t)
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), bhe, cfg, nil)
+		ctx := gas.WithInclusionBlocks(testutils.Context(t), 2)
+
+		fee, _, err := estimator.GetFee(ctx, nil, 100000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), fee.InclusionBlocks)
+
+		cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(2000000)
+		bumped, _, err := estimator.BumpFee(ctx, fee, 100000, cfg.EvmMaxGasPriceWeiF, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2), bumped.InclusionBlocks)
+	})
+}
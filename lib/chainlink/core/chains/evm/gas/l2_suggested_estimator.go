@@ -2,12 +2,14 @@ package gas
 
 import (
 	"context"
+	"math/big"
 	"sync"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	"golang.org/x/exp/slices"
+	"golang.org/x/sync/singleflight"
 
 	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
@@ -18,38 +20,120 @@ import (
 )
 
 var (
-	_ EvmEstimator = &l2SuggestedPriceEstimator{}
+	_ EvmEstimator     = &l2SuggestedPriceEstimator{}
+	_ BlobFeeEstimator = &l2SuggestedPriceEstimator{}
+	_ ForceRefresher   = &l2SuggestedPriceEstimator{}
+	_ RawPriceReporter = &l2SuggestedPriceEstimator{}
 )
 
+// ForceRefresher is implemented by estimators that cache prices fetched from
+// the node and can synchronously refresh them on demand
+type ForceRefresher interface {
+	ForceRefresh(ctx context.Context) error
+}
+
+// RawPriceReporter is implemented by estimators that filter the prices fetched
+// from the node before caching them, and report the latest prices the node
+// suggested for observability
+type RawPriceReporter interface {
+	// RawPrices returns the latest gas price and tip cap suggested by the
+	// node, nil if not fetched yet
+	RawPrices() (gasPrice, tipCap *assets.Wei)
+}
+
 //go:generate mockery --quiet --name rpcClient --output ./mocks/ --case=underscore --structname RPCClient
 type rpcClient interface {
 	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// L2SuggestedPriceConfig defines the config needed by the l2SuggestedPriceEstimator
+type L2SuggestedPriceConfig interface {
+	EvmEIP1559DynamicFees() bool
+	EvmGasBaseFeeBufferMultiplier() float32
+	EvmGasBumpFeeCapFromBaseFee() bool
+	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
+	EvmGasBumpThreshold() uint64
+	EvmGasBumpWei() *assets.Wei
+	EvmGasPriceStaleThreshold() time.Duration
+	EvmGasPriceUpdateThreshold() uint16
+	EvmGasSuggestedPriceConnectivityCheck() bool
+	EvmGasTipCapDefault() *assets.Wei
+	EvmGasTipCapMinimum() *assets.Wei
+	EvmMaxBlobGasPriceWei() *assets.Wei
+	EvmMaxGasPriceWei() *assets.Wei
 }
 
 // l2SuggestedPriceEstimator is an Estimator which uses the L2 suggested gas price from eth_gasPrice.
+// With EIP-1559 enabled it also polls the tip cap from eth_maxPriorityFeePerGas
+// and the base fee of the latest block for dynamic fee estimation.
 type l2SuggestedPriceEstimator struct {
 	utils.StartStopOnce
 
-	client     rpcClient
+	cfg        L2SuggestedPriceConfig
+	quorum     quorum
 	pollPeriod time.Duration
-	logger     logger.Logger
+	logger     logger.SugaredLogger
+	chainID    big.Int
+	mode       string
+	metrics    *estimatorMetrics
 
-	gasPriceMu sync.RWMutex
-	l2GasPrice *assets.Wei
+	gasPriceMu        sync.RWMutex
+	l2GasPrice        *assets.Wei
+	l2TipCap          *assets.Wei
+	l2BaseFee         *assets.Wei
+	l2GasPriceUpdated time.Time
+	// rawGasPrice and rawTipCap are the latest prices suggested by the node,
+	// before EVM.GasEstimator.PriceUpdateThreshold
+	rawGasPrice *assets.Wei
+	rawTipCap   *assets.Wei
+	// minPrice is only set with EVM.GasEstimator.NodeMinPriceSync
+	minPrice *nodeMinPrice
 
-	chForceRefetch chan (chan struct{})
+	health refreshHealth
+	fees   feeFeed
+
+	// refreshGroup ensures concurrent callers share a single forced refresh
+	refreshGroup singleflight.Group
+
+	chForceRefetch chan (chan error)
 	chInitialised  chan struct{}
 	chStop         utils.StopChan
 	chDone         chan struct{}
 }
 
 // NewL2SuggestedPriceEstimator returns a new Estimator which uses the L2 suggested gas price.
-func NewL2SuggestedPriceEstimator(lggr logger.Logger, client rpcClient) EvmEstimator {
+func NewL2SuggestedPriceEstimator(lggr logger.Logger, cfg L2SuggestedPriceConfig, client rpcClient, chainID big.Int) EvmEstimator {
+	return newL2SuggestedPriceEstimator(lggr, cfg, singleClient(client), chainID, "L2Suggested")
+}
+
+// NewQuorumSuggestedPriceEstimator returns a new Estimator which, like the
+// L2SuggestedPriceEstimator, uses the prices suggested by the node, but
+// queries all of the clients on each refresh and uses the median of the
+// prices they suggest. Unless at least minQuorum of the clients respond, the
+// previous prices are kept. minQuorum must be between 1 and the number of
+// clients.
+func NewQuorumSuggestedPriceEstimator(lggr logger.Logger, cfg L2SuggestedPriceConfig, chainID big.Int, minQuorum int, clients ...rpcClient) (EvmEstimator, error) {
+	q, err := newQuorum(minQuorum, clients)
+	if err != nil {
+		return nil, err
+	}
+	return newL2SuggestedPriceEstimator(lggr, cfg, q, chainID, "L2Suggested"), nil
+}
+
+// newL2SuggestedPriceEstimator returns a l2SuggestedPriceEstimator which
+// reports its metrics and errors as the given estimator mode
+func newL2SuggestedPriceEstimator(lggr logger.Logger, cfg L2SuggestedPriceConfig, q quorum, chainID big.Int, mode string) *l2SuggestedPriceEstimator {
 	return &l2SuggestedPriceEstimator{
-		client:         client,
+		cfg:            cfg,
+		quorum:         q,
 		pollPeriod:     10 * time.Second,
-		logger:         lggr.Named("L2SuggestedEstimator"),
-		chForceRefetch: make(chan (chan struct{})),
+		logger:         logger.Sugared(lggr.Named("L2SuggestedEstimator")),
+		chainID:        chainID,
+		mode:           mode,
+		metrics:        newEstimatorMetrics(chainID, mode),
+		chForceRefetch: make(chan (chan error)),
 		chInitialised:  make(chan struct{}),
 		chStop:         make(chan struct{}),
 		chDone:         make(chan struct{}),
@@ -60,8 +144,9 @@ func (o *l2SuggestedPriceEstimator) Name() string {
 	return o.logger.Name()
 }
 
-func (o *l2SuggestedPriceEstimator) Start(context.Context) error {
+func (o *l2SuggestedPriceEstimator) Start(ctx context.Context) error {
 	return o.StartOnce("L2SuggestedEstimator", func() error {
+		o.minPrice.start(ctx)
 		go o.run()
 		<-o.chInitialised
 		return nil
@@ -71,18 +156,33 @@ func (o *l2SuggestedPriceEstimator) Close() error {
 	return o.StopOnce("L2SuggestedEstimator", func() error {
 		close(o.chStop)
 		<-o.chDone
+		o.minPrice.close()
+		o.fees.close()
 		return nil
 	})
 }
 
+// useNodeMinPrice floors the legacy gas price at the minimum gas price of the
+// node, see EVM.GasEstimator.NodeMinPriceSync. It must be called before Start.
+func (o *l2SuggestedPriceEstimator) useNodeMinPrice(floor *nodeMinPrice) {
+	o.minPrice = floor
+}
+
+// HealthReport reports the estimator unhealthy if the last
+// healthFailureThreshold refreshes failed or the cached prices are older than
+// EVM.GasEstimator.PriceStaleThreshold
 func (o *l2SuggestedPriceEstimator) HealthReport() map[string]error {
-	return map[string]error{o.Name(): o.StartStopOnce.Healthy()}
+	err := o.StartStopOnce.Healthy()
+	if err == nil {
+		err = o.health.check(o.cfg.EvmGasPriceStaleThreshold())
+	}
+	return map[string]error{o.Name(): err}
 }
 
 func (o *l2SuggestedPriceEstimator) run() {
 	defer close(o.chDone)
 
-	t := o.refreshPrice()
+	t, _ := o.refreshPrice()
 	close(o.chInitialised)
 
 	for {
@@ -91,76 +191,479 @@ func (o *l2SuggestedPriceEstimator) run() {
 			return
 		case ch := <-o.chForceRefetch:
 			t.Stop()
-			t = o.refreshPrice()
-			close(ch)
+			var err error
+			t, err = o.refreshPrice()
+			ch <- err
 		case <-t.C:
-			t = o.refreshPrice()
+			t, _ = o.refreshPrice()
 		}
 	}
 }
 
-func (o *l2SuggestedPriceEstimator) refreshPrice() (t *time.Timer) {
+func (o *l2SuggestedPriceEstimator) refreshPrice() (t *time.Timer, err error) {
 	t = time.NewTimer(utils.WithJitter(o.pollPeriod))
+	defer func() { o.health.record(err) }()
 
-	var res hexutil.Big
 	ctx, cancel := o.chStop.CtxCancel(evmclient.ContextWithDefaultTimeout())
 	defer cancel()
 
-	if err := o.client.CallContext(ctx, &res, "eth_gasPrice"); err != nil {
+	if o.cfg.EvmEIP1559DynamicFees() {
+		if err = o.refreshDynamicPrices(ctx); err == nil {
+			o.publishFees(true)
+		}
+		return
+	}
+
+	bi, err := o.suggestedPrice(ctx, "eth_gasPrice")
+	if err != nil {
 		o.logger.Warnf("Failed to refresh prices, got error: %s", err)
+		err = &EstimationError{Reason: ErrRPCFailure, Err: err}
 		return
 	}
-	bi := (*assets.Wei)(&res)
 
 	o.logger.Debugw("refreshPrice", "l2GasPrice", bi)
+	o.metrics.setGasPrice(bi)
 
 	o.gasPriceMu.Lock()
-	defer o.gasPriceMu.Unlock()
-	o.l2GasPrice = bi
+	o.rawGasPrice = bi
+	o.l2GasPrice = o.withHysteresis("gasPrice", o.l2GasPrice, bi)
+	o.l2GasPriceUpdated = time.Now()
+	o.gasPriceMu.Unlock()
+	o.publishFees(false)
 	return
 }
 
-func (o *l2SuggestedPriceEstimator) OnNewLongestChain(context.Context, *evmtypes.Head) {}
+// withHysteresis returns the price to cache in place of cached, given the
+// latest price suggested by the node. With EVM.GasEstimator.PriceUpdateThreshold
+// set, small changes of the price are ignored so that the cached price doesn't
+// oscillate between refreshes on quiet chains: the cached price is kept unless
+// the latest one differs from it by more than the threshold percentage. Prices
+// that rise by more than EVM.GasEstimator.BumpPercent are always taken, so
+// that transactions aren't underpriced during spikes.
+func (o *l2SuggestedPriceEstimator) withHysteresis(name string, cached, latest *assets.Wei) *assets.Wei {
+	if cached == nil || latest.Cmp(cached) == 0 {
+		return latest
+	}
+	threshold := o.cfg.EvmGasPriceUpdateThreshold()
+	if threshold == 0 {
+		return latest
+	}
+	if latest.Cmp(cached.AddPercentage(o.cfg.EvmGasBumpPercent())) > 0 {
+		o.logger.Debugw("Price spiked, updating immediately", "price", name, "cached", cached, "latest", latest)
+		return latest
+	}
+	diff := new(big.Int).Abs(new(big.Int).Sub(latest.ToInt(), cached.ToInt()))
+	// diff/cached > threshold/100
+	if new(big.Int).Mul(diff, big.NewInt(100)).Cmp(new(big.Int).Mul(cached.ToInt(), big.NewInt(int64(threshold)))) > 0 {
+		return latest
+	}
+	o.logger.Debugw("Price changed by less than PriceUpdateThreshold, keeping the cached price", "price", name, "cached", cached, "latest", latest, "threshold", threshold)
+	return cached
+}
+
+// RawPrices returns the latest gas price and tip cap suggested by the node,
+// which differ from the cached prices that EVM.GasEstimator.PriceUpdateThreshold
+// didn't update
+func (o *l2SuggestedPriceEstimator) RawPrices() (gasPrice, tipCap *assets.Wei) {
+	o.gasPriceMu.RLock()
+	defer o.gasPriceMu.RUnlock()
+	return o.rawGasPrice, o.rawTipCap
+}
+
+// decisionInputs returns the time of the last refresh and the raw prices
+// suggested by the node for the decision log
+func (o *l2SuggestedPriceEstimator) decisionInputs() []interface{} {
+	o.gasPriceMu.RLock()
+	defer o.gasPriceMu.RUnlock()
+	return []interface{}{"refreshedAt", o.l2GasPriceUpdated, "rawGasPrice", o.rawGasPrice, "rawTipCap", o.rawTipCap, "baseFee", o.l2BaseFee}
+}
 
-func (*l2SuggestedPriceEstimator) GetDynamicFee(_ context.Context, _ uint32, _ *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
-	err = errors.New("dynamic fees are not implemented for this layer 2")
+// ForceRefresh immediately refreshes the cached prices from the node and
+// resets the poll timer. Unlike the periodic refresh, any RPC error is
+// returned to the caller instead of silently keeping the previous price.
+func (o *l2SuggestedPriceEstimator) ForceRefresh(ctx context.Context) (err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
+	ok := o.IfStarted(func() {
+		err = o.forceRefresh(ctx, false)
+	})
+	if !ok {
+		return errors.New("estimator is not started")
+	}
 	return
 }
 
-func (*l2SuggestedPriceEstimator) BumpDynamicFee(_ context.Context, _ DynamicFee, _ uint32, _ *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
-	err = errors.New("dynamic fees are not implemented for this layer 2")
+// forceRefresh asks the run loop to refresh the prices, sharing a single
+// in-flight refresh between concurrent callers. If onlyIfStale is set, the
+// refresh is skipped when another caller has already refreshed the price in
+// the meantime, so that callers racing on a stale price don't stampede the node.
+func (o *l2SuggestedPriceEstimator) forceRefresh(ctx context.Context, onlyIfStale bool) error {
+	key := "force"
+	if onlyIfStale {
+		key = "stale"
+	}
+	res := o.refreshGroup.DoChan(key, func() (interface{}, error) {
+		if onlyIfStale && !o.isStale() {
+			return nil, nil
+		}
+		ch := make(chan error, 1)
+		select {
+		case o.chForceRefetch <- ch:
+		case <-o.chStop:
+			return nil, errors.New("estimator stopped")
+		}
+		select {
+		case err := <-ch:
+			return nil, err
+		case <-o.chStop:
+			return nil, errors.New("estimator stopped")
+		}
+	})
+	select {
+	case r := <-res:
+		return r.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refreshIfStale refreshes the cached prices if they are stale
+func (o *l2SuggestedPriceEstimator) refreshIfStale(ctx context.Context) error {
+	if !o.isStale() {
+		return nil
+	}
+	o.logger.Debugw("Cached gas price is stale, refreshing", "staleThreshold", o.cfg.EvmGasPriceStaleThreshold())
+	if err := o.forceRefresh(ctx, true); err != nil {
+		return &EstimationError{Reason: ErrStalePrice, Err: errors.Wrap(err, "failed to refresh stale l2 gas price")}
+	}
+	return nil
+}
+
+// isStale returns true if the cached gas price is older than EVM.GasEstimator.PriceStaleThreshold
+func (o *l2SuggestedPriceEstimator) isStale() bool {
+	threshold := o.cfg.EvmGasPriceStaleThreshold()
+	if threshold == 0 {
+		return false
+	}
+	o.gasPriceMu.RLock()
+	defer o.gasPriceMu.RUnlock()
+	return time.Since(o.l2GasPriceUpdated) > threshold
+}
+
+// suggestedPrice returns the median of the prices suggested by the clients
+// via method, or an error if fewer than the quorum of clients responded
+func (o *l2SuggestedPriceEstimator) suggestedPrice(ctx context.Context, method string) (*assets.Wei, error) {
+	results, errs := queryQuorum(ctx, o.quorum, func(ctx context.Context, client rpcClient) (*assets.Wei, error) {
+		var res rpcPrice
+		if err := client.CallContext(ctx, &res, method); err != nil {
+			return nil, err
+		}
+		return res.Wei(), nil
+	})
+	for _, err := range errs {
+		o.metrics.recordRPCError(err)
+	}
+	prices := make([]*assets.Wei, 0, len(results))
+	for _, price := range results {
+		prices = append(prices, price)
+	}
+	return o.medianPrice(method, prices, errs)
+}
+
+// medianPrice returns the median of the prices, or the errors of the failed
+// clients if fewer than the quorum of clients returned a price
+func (o *l2SuggestedPriceEstimator) medianPrice(name string, prices []*assets.Wei, errs map[int]error) (*assets.Wei, error) {
+	if err := o.quorum.checkQuorum(len(prices), errs); err != nil {
+		if len(o.quorum.clients) > 1 {
+			o.logger.Warnw("Too few clients responded, keeping the previous price", "price", name, "responded", len(prices), "quorum", o.quorum.min, "failedClients", failedClients(errs))
+		}
+		return nil, err
+	}
+	if len(errs) > 0 {
+		o.logger.Warnw("Some clients failed to respond", "price", name, "failedClients", failedClients(errs))
+	}
+	return medianWei(prices), nil
+}
+
+// suggestedDynamicPrices are the prices fetched from a single client by
+// refreshDynamicPrices, along with the error of each of them
+type suggestedDynamicPrices struct {
+	gasPrice, tipCap, baseFee *assets.Wei
+	errs                      [3]error
+}
+
+// refreshDynamicPrices fetches the legacy gas price, the tip cap and the base
+// fee of the latest block in a single batch call to each client. Each price
+// is the median of the clients that returned it and is applied independently,
+// so clients that fail one of the methods still refresh the others.
+func (o *l2SuggestedPriceEstimator) refreshDynamicPrices(ctx context.Context) (err error) {
+	results, errs := queryQuorum(ctx, o.quorum, func(ctx context.Context, client rpcClient) (res suggestedDynamicPrices, err error) {
+		var gasPrice, tipCap rpcPrice
+		var latest struct {
+			BaseFeePerGas *rpcPrice `json:"baseFeePerGas"`
+		}
+		reqs := []rpc.BatchElem{
+			{Method: "eth_gasPrice", Result: &gasPrice},
+			{Method: "eth_maxPriorityFeePerGas", Result: &tipCap},
+			{Method: "eth_getBlockByNumber", Args: []interface{}{"latest", false}, Result: &latest},
+		}
+		if err = client.BatchCallContext(ctx, reqs); err != nil {
+			return
+		}
+		for i := range reqs {
+			res.errs[i] = reqs[i].Error
+		}
+		if res.errs[0] == nil {
+			res.gasPrice = gasPrice.Wei()
+		}
+		if res.errs[1] == nil {
+			res.tipCap = tipCap.Wei()
+		}
+		if res.errs[2] == nil && latest.BaseFeePerGas != nil {
+			res.baseFee = latest.BaseFeePerGas.Wei()
+		}
+		return
+	})
+	for _, err := range errs {
+		o.metrics.recordRPCError(err)
+	}
+	if len(results) == 0 {
+		err = o.quorum.checkQuorum(0, errs)
+		o.logger.Warnf("Failed to refresh prices, got error: %s", err)
+		err = &EstimationError{Reason: ErrRPCFailure, Err: err}
+		return
+	}
+
+	// median returns the median of the i-th price of the clients that
+	// returned it, or an error if fewer than the quorum of clients did
+	median := func(name string, i int, price func(suggestedDynamicPrices) *assets.Wei) (*assets.Wei, error) {
+		var prices []*assets.Wei
+		priceErrs := make(map[int]error, len(errs))
+		for j, err := range errs {
+			priceErrs[j] = err
+		}
+		for j, res := range results {
+			if res.errs[i] != nil {
+				o.metrics.recordRPCError(res.errs[i])
+				priceErrs[j] = res.errs[i]
+			} else if p := price(res); p != nil {
+				prices = append(prices, p)
+			}
+		}
+		if len(prices) == 0 && len(priceErrs) == 0 {
+			// no client returned the price, e.g. a block without a base fee
+			return nil, nil
+		}
+		return o.medianPrice(name, prices, priceErrs)
+	}
+
+	gasPrice, gasPriceErr := median("gasPrice", 0, func(res suggestedDynamicPrices) *assets.Wei { return res.gasPrice })
+	tipCap, tipCapErr := median("tipCap", 1, func(res suggestedDynamicPrices) *assets.Wei { return res.tipCap })
+	baseFee, baseFeeErr := median("baseFee", 2, func(res suggestedDynamicPrices) *assets.Wei { return res.baseFee })
+
+	o.gasPriceMu.Lock()
+	defer o.gasPriceMu.Unlock()
+	if gasPriceErr != nil {
+		o.logger.Warnw("Failed to refresh gas price", "err", gasPriceErr)
+		err = &EstimationError{Reason: ErrRPCFailure, Err: gasPriceErr}
+	} else if gasPrice != nil {
+		o.rawGasPrice = gasPrice
+		o.l2GasPrice = o.withHysteresis("gasPrice", o.l2GasPrice, gasPrice)
+		o.l2GasPriceUpdated = time.Now()
+		o.metrics.setGasPrice(o.l2GasPrice)
+	}
+	if tipCapErr != nil {
+		o.logger.Warnw("Failed to refresh tip cap", "err", tipCapErr)
+	} else if tipCap != nil {
+		o.rawTipCap = tipCap
+		o.l2TipCap = o.withHysteresis("tipCap", o.l2TipCap, tipCap)
+		o.metrics.setTipCap(o.l2TipCap)
+	}
+	if baseFeeErr != nil {
+		o.logger.Warnw("Failed to refresh base fee", "err", baseFeeErr)
+	} else if baseFee != nil {
+		o.l2BaseFee = baseFee
+		o.metrics.setBaseFee(o.l2BaseFee)
+	}
+
+	o.logger.Debugw("refreshDynamicPrices", "l2GasPrice", o.l2GasPrice, "l2TipCap", o.l2TipCap, "l2BaseFee", o.l2BaseFee)
 	return
 }
 
+func (o *l2SuggestedPriceEstimator) OnNewLongestChain(context.Context, *evmtypes.Head) {}
+
+// GetDynamicFee returns the tip cap suggested by the node and a fee cap of the
+// latest base fee multiplied by EVM.GasEstimator.BaseFeeBufferMultiplier plus
+// the tip cap, so that the fee is still valid if the base fee spikes between
+// the last refresh and the send.
+//
+// Some nodes suggest a tip cap of zero, in which case EVM.GasEstimator.TipCapDefault
+// is used instead, so that the fee can be bumped.
+func (o *l2SuggestedPriceEstimator) GetDynamicFee(ctx context.Context, gasLimit uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
+	if !o.cfg.EvmEIP1559DynamicFees() {
+		return fee, 0, errors.New("Can't get dynamic fee, EIP1559 is disabled")
+	}
+
+	var tipCap, baseFee *assets.Wei
+	ok := o.IfStarted(func() {
+		if err = o.refreshIfStale(ctx); err != nil {
+			return
+		}
+		tipCap, baseFee = o.getDynamicPrices()
+	})
+	if !ok {
+		return fee, 0, errors.New("estimator is not started")
+	} else if err != nil {
+		return
+	}
+	if tipCap == nil {
+		return fee, 0, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate dynamic fee; tip cap not set")}
+	}
+	if baseFee == nil {
+		return fee, 0, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate dynamic fee; base fee not set. Are you trying to run with EIP1559 enabled on a non-EIP1559 chain?")}
+	}
+	if tipCap.IsZero() {
+		o.logger.Debugw("Node suggested a zero tip cap, using EVM.GasEstimator.TipCapDefault", "tipCapDefault", o.cfg.EvmGasTipCapDefault())
+		tipCap = o.cfg.EvmGasTipCapDefault()
+	}
+
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, o.cfg.EvmMaxGasPriceWei())
+	if tipCap.Cmp(maxGasPrice) > 0 {
+		o.metrics.maxPriceCapped.Inc()
+		return fee, 0, &EstimationError{Price: tipCap, Limit: maxGasPrice,
+			Err: errors.Errorf("estimated tip cap: %s is greater than the maximum gas price configured: %s", tipCap.String(), maxGasPrice.String())}
+	}
+	if o.cfg.EvmGasBumpThreshold() == 0 {
+		// just use the max gas price if gas bumping is disabled
+		fee.FeeCap = maxGasPrice
+	} else if fee.FeeCap, err = o.calcFeeCap(baseFee, tipCap, maxGasPrice); err != nil {
+		return fee, 0, err
+	}
+	fee.TipCap = tipCap
+	if fee, err = applyTipCapMin(o.cfg, fee, maxGasPrice); err != nil {
+		return fee, 0, err
+	}
+	o.logger.Debugw("GetDynamicFee", "l2TipCap", fee.TipCap, "l2FeeCap", fee.FeeCap, "l2BaseFee", baseFee, "l2GasLimit", gasLimit)
+	return fee, gasLimit, nil
+}
+
+var _ FeeExplainer = (*l2SuggestedPriceEstimator)(nil)
+
+// ExplainFee explains the fee that GetLegacyGas or GetDynamicFee would return
+// for the prices last suggested by the node. Unlike them, it doesn't refresh
+// stale prices.
+func (o *l2SuggestedPriceEstimator) ExplainFee(_ context.Context, _ []byte, gasLimit uint32, maxGasPriceWei *assets.Wei, dynamic bool) (ex FeeExplanation, err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
+	if dynamic && !o.cfg.EvmEIP1559DynamicFees() {
+		return ex, errors.New("Can't get dynamic fee, EIP1559 is disabled")
+	}
+	var gasPrice, tipCap, baseFee *assets.Wei
+	ok := o.IfStarted(func() {
+		gasPrice = o.getGasPrice()
+		tipCap, baseFee = o.getDynamicPrices()
+	})
+	if !ok {
+		return ex, errors.New("estimator is not started")
+	}
+
+	ex.Mode = o.mode
+	ex.ChainSpecificFeeLimit = gasLimit
+	ex.GasPrice = gasPrice
+	if !dynamic {
+		if gasPrice == nil {
+			return ex, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate l2 gas; gas price not set")}
+		}
+		if gasPrice.Cmp(maxGasPriceWei) > 0 {
+			return ex, &EstimationError{Price: gasPrice, Limit: maxGasPriceWei,
+				Err: errors.Errorf("estimated gas price: %s is greater than the maximum gas price configured: %s", gasPrice.String(), maxGasPriceWei.String())}
+		}
+		ex.Fee.Legacy = gasPrice
+		return ex, nil
+	}
+
+	ex.TipCap, ex.BaseFee = tipCap, baseFee
+	if tipCap == nil {
+		return ex, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate dynamic fee; tip cap not set")}
+	}
+	if baseFee == nil {
+		return ex, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate dynamic fee; base fee not set. Are you trying to run with EIP1559 enabled on a non-EIP1559 chain?")}
+	}
+	if tipCap.IsZero() {
+		ex.UsedDefault = true
+		tipCap = o.cfg.EvmGasTipCapDefault()
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, o.cfg.EvmMaxGasPriceWei())
+	if tipCap.Cmp(maxGasPrice) > 0 {
+		return ex, &EstimationError{Price: tipCap, Limit: maxGasPrice,
+			Err: errors.Errorf("estimated tip cap: %s is greater than the maximum gas price configured: %s", tipCap.String(), maxGasPrice.String())}
+	}
+	if o.cfg.EvmGasBumpThreshold() == 0 {
+		ex.Fee.DynamicFeeCap = maxGasPrice
+	} else {
+		uncapped, err := o.calcFeeCap(baseFee, tipCap, nil)
+		if err != nil {
+			return ex, err
+		}
+		ex.Fee.DynamicFeeCap = assets.WeiMin(uncapped, maxGasPrice)
+		ex.clampIfChanged(ClampMaxGasPrice, uncapped, ex.Fee.DynamicFeeCap)
+	}
+	ex.Fee.DynamicTipCap = tipCap
+	return ex, nil
+}
+
+// BumpDynamicFee bumps the tip cap by EVM.GasEstimator.BumpPercent/BumpMin, or
+// to the currently suggested tip cap if that is higher. Rather than only
+// bumping the original fee cap, the fee cap is recomputed from the latest base
+// fee and the bumped tip cap, and is only bumped by BumpPercent if that is
+// higher, since the node requires a replacement to bump both.
+func (o *l2SuggestedPriceEstimator) BumpDynamicFee(ctx context.Context, originalFee DynamicFee, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
+	if !o.cfg.EvmEIP1559DynamicFees() {
+		return bumped, 0, errors.New("Can't bump dynamic fee, EIP1559 is disabled")
+	}
+
+	var tipCap, baseFee *assets.Wei
+	ok := o.IfStarted(func() {
+		// a stale base fee is still a better baseline than none, so a failed
+		// refresh doesn't prevent the bump
+		if refreshErr := o.refreshIfStale(ctx); refreshErr != nil {
+			o.logger.Warnw("Bumping dynamic fee with stale prices", "err", refreshErr)
+		}
+		tipCap, baseFee = o.getDynamicPrices()
+	})
+	if !ok {
+		return bumped, 0, errors.New("estimator is not started")
+	}
+	bumped, err = bumpDynamicFee(o.cfg, o.calcFeeCap, o.logger, tipCap, baseFee, originalFee, maxGasPriceWei)
+	o.metrics.recordDynamicBump(err)
+	if err != nil {
+		return bumped, 0, err
+	}
+	return bumped, gasLimit, nil
+}
+
+// calcFeeCap returns the multipliedFeeCap of baseFee
+func (o *l2SuggestedPriceEstimator) calcFeeCap(baseFee, tipCap, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
+	return multipliedFeeCap(baseFee, o.cfg.EvmGasBaseFeeBufferMultiplier(), tipCap, maxGasPriceWei)
+}
+
 func (o *l2SuggestedPriceEstimator) GetLegacyGas(ctx context.Context, _ []byte, l2GasLimit uint32, maxGasPriceWei *assets.Wei, opts ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
 	chainSpecificGasLimit = l2GasLimit
 
 	ok := o.IfStarted(func() {
 		if slices.Contains(opts, txmgrtypes.OptForceRefetch) {
-			ch := make(chan struct{})
-			select {
-			case o.chForceRefetch <- ch:
-			case <-o.chStop:
-				err = errors.New("estimator stopped")
-				return
-			case <-ctx.Done():
-				err = ctx.Err()
-				return
-			}
-			select {
-			case <-ch:
-			case <-o.chStop:
-				err = errors.New("estimator stopped")
-				return
-			case <-ctx.Done():
-				err = ctx.Err()
+			if err = o.forceRefresh(ctx, false); err != nil {
 				return
 			}
+		} else if err = o.refreshIfStale(ctx); err != nil {
+			return
 		}
 		if gasPrice = o.getGasPrice(); gasPrice == nil {
-			err = errors.New("failed to estimate l2 gas; gas price not set")
+			err = &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to estimate l2 gas; gas price not set")}
 			return
 		}
+		gasPrice = o.minPrice.apply(gasPrice, maxGasPriceWei)
 		o.logger.Debugw("GetLegacyGas", "l2GasPrice", gasPrice, "l2GasLimit", l2GasLimit)
 	})
 	if !ok {
@@ -170,13 +673,70 @@ func (o *l2SuggestedPriceEstimator) GetLegacyGas(ctx context.Context, _ []byte,
 	}
 	// For L2 chains (e.g. Optimism), submitting a transaction that is not priced high enough will cause the call to fail, so if the cap is lower than the RPC suggested gas price, this transaction cannot succeed
 	if gasPrice != nil && gasPrice.Cmp(maxGasPriceWei) > 0 {
-		return nil, 0, errors.Errorf("estimated gas price: %s is greater than the maximum gas price configured: %s", gasPrice.String(), maxGasPriceWei.String())
+		o.metrics.maxPriceCapped.Inc()
+		return nil, 0, &EstimationError{Price: gasPrice, Limit: maxGasPriceWei,
+			Err: errors.Errorf("estimated gas price: %s is greater than the maximum gas price configured: %s", gasPrice.String(), maxGasPriceWei.String())}
 	}
 	return
 }
 
-func (o *l2SuggestedPriceEstimator) BumpLegacyGas(_ context.Context, _ *assets.Wei, _ uint32, _ *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
-	return nil, 0, errors.New("bump gas is not supported for this l2")
+// BumpLegacyGas bumps the gas price to the larger of the original price bumped
+// by EVM.GasEstimator.BumpPercent/BumpMin and the current suggested gas price.
+//
+// If the node currently suggests a lower price than the original price, the
+// transaction is not underpriced and bumping would not help it get included.
+// Unless EVM.GasEstimator.SuggestedPriceConnectivityCheck is disabled, an
+// ErrConnectivity error is returned instead.
+func (o *l2SuggestedPriceEstimator) BumpLegacyGas(_ context.Context, originalGasPrice *assets.Wei, gasLimit uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
+	var currentGasPrice *assets.Wei
+	ok := o.IfStarted(func() {
+		currentGasPrice = o.getGasPrice()
+	})
+	if !ok {
+		return nil, 0, errors.New("estimator is not started")
+	}
+	if currentGasPrice == nil {
+		return nil, 0, &EstimationError{Reason: ErrStalePrice, Err: errors.New("failed to bump l2 gas; gas price not set")}
+	}
+	if o.cfg.EvmGasSuggestedPriceConnectivityCheck() && currentGasPrice.Cmp(originalGasPrice) < 0 {
+		err = errors.Wrapf(ErrConnectivity, "transaction has gas price of %s, which is above the current suggested gas price of %s", originalGasPrice.String(), currentGasPrice.String())
+		o.logger.Criticalw(BumpingHaltedLabel, "err", err)
+		o.SvcErrBuffer.Append(err)
+		return nil, 0, err
+	}
+	bumpedGasPrice, err = bumpGasPrice(o.cfg, logger.Sugared(o.logger), currentGasPrice, originalGasPrice, maxGasPriceWei)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bumpedGasPrice, gasLimit, nil
+}
+
+// GetBlobFee returns the blob base fee suggested by the node via
+// eth_blobBaseFee, for RPCs which support it
+func (o *l2SuggestedPriceEstimator) GetBlobFee(ctx context.Context) (blobFeeCap *assets.Wei, err error) {
+	defer func() { err = annotateError(err, &o.chainID, o.mode) }()
+	ok := o.IfStarted(func() {
+		ctx, cancel := o.chStop.Ctx(ctx)
+		defer cancel()
+
+		if blobFeeCap, err = o.suggestedPrice(ctx, "eth_blobBaseFee"); err != nil {
+			err = &EstimationError{Reason: ErrRPCFailure, Err: errors.Wrap(err, "failed to estimate blob fee; eth_blobBaseFee may not be supported by this RPC")}
+			return
+		}
+		o.logger.Debugw("GetBlobFee", "blobBaseFee", blobFeeCap)
+	})
+	if !ok {
+		return nil, errors.New("estimator is not started")
+	} else if err != nil {
+		return nil, err
+	}
+	// As with the gas price, a blob fee cap below the node's blob base fee cannot succeed
+	if max := o.cfg.EvmMaxBlobGasPriceWei(); blobFeeCap.Cmp(max) > 0 {
+		return nil, &EstimationError{Price: blobFeeCap, Limit: max,
+			Err: errors.Errorf("estimated blob fee: %s is greater than the maximum blob gas price configured: %s", blobFeeCap.String(), max.String())}
+	}
+	return
 }
 
 func (o *l2SuggestedPriceEstimator) getGasPrice() (l2GasPrice *assets.Wei) {
@@ -184,3 +744,15 @@ func (o *l2SuggestedPriceEstimator) getGasPrice() (l2GasPrice *assets.Wei) {
 	defer o.gasPriceMu.RUnlock()
 	return o.l2GasPrice
 }
+
+func (o *l2SuggestedPriceEstimator) getTipCap() (l2TipCap *assets.Wei) {
+	o.gasPriceMu.RLock()
+	defer o.gasPriceMu.RUnlock()
+	return o.l2TipCap
+}
+
+func (o *l2SuggestedPriceEstimator) getDynamicPrices() (l2TipCap, l2BaseFee *assets.Wei) {
+	o.gasPriceMu.RLock()
+	defer o.gasPriceMu.RUnlock()
+	return o.l2TipCap, o.l2BaseFee
+}
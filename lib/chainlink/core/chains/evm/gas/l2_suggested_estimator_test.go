@@ -1,10 +1,17 @@
 package gas_test
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"math/big"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -13,6 +20,7 @@ import (
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/label"
 	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
 	"github.com/smartcontractkit/chainlink/v2/core/logger"
 )
@@ -24,10 +32,11 @@ func TestL2SuggestedEstimator(t *testing.T) {
 
 	calldata := []byte{0x00, 0x00, 0x01, 0x02, 0x03}
 	const gasLimit uint32 = 80000
+	cfg := gas.NewMockConfig()
 
 	t.Run("calling GetLegacyGas on unstarted estimator returns error", func(t *testing.T) {
 		client := mocks.NewRPCClient(t)
-		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), client)
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
 		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
 		assert.EqualError(t, err, "estimator is not started")
 	})
@@ -35,11 +44,10 @@ func TestL2SuggestedEstimator(t *testing.T) {
 	t.Run("calling GetLegacyGas on started estimator returns prices", func(t *testing.T) {
 		client := mocks.NewRPCClient(t)
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(42)
+			gas.SetRPCPrice(args.Get(1), 42)
 		})
 
-		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), client)
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
 		require.NoError(t, o.Start(testutils.Context(t)))
 		t.Cleanup(func() { assert.NoError(t, o.Close()) })
 		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
@@ -50,11 +58,10 @@ func TestL2SuggestedEstimator(t *testing.T) {
 
 	t.Run("gas price is lower than user specified max gas price", func(t *testing.T) {
 		client := mocks.NewRPCClient(t)
-		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), client)
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
 
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(42)
+			gas.SetRPCPrice(args.Get(1), 42)
 		})
 
 		require.NoError(t, o.Start(testutils.Context(t)))
@@ -68,11 +75,10 @@ func TestL2SuggestedEstimator(t *testing.T) {
 
 	t.Run("gas price is lower than global max gas price", func(t *testing.T) {
 		client := mocks.NewRPCClient(t)
-		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), client)
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
 
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
-			res := args.Get(1).(*hexutil.Big)
-			(*big.Int)(res).SetInt64(120)
+			gas.SetRPCPrice(args.Get(1), 120)
 		})
 
 		require.NoError(t, o.Start(testutils.Context(t)))
@@ -83,16 +89,16 @@ func TestL2SuggestedEstimator(t *testing.T) {
 		assert.Equal(t, uint32(0), chainSpecificGasLimit)
 	})
 
-	t.Run("calling BumpLegacyGas always returns error", func(t *testing.T) {
+	t.Run("calling BumpLegacyGas on unstarted estimator returns error", func(t *testing.T) {
 		client := mocks.NewRPCClient(t)
-		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), client)
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
 		_, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), gasLimit, assets.NewWeiI(10), nil)
-		assert.EqualError(t, err, "bump gas is not supported for this l2")
+		assert.EqualError(t, err, "estimator is not started")
 	})
 
 	t.Run("calling GetLegacyGas on started estimator if initial call failed returns error", func(t *testing.T) {
 		client := mocks.NewRPCClient(t)
-		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), client)
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
 
 		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom"))
 
@@ -102,4 +108,820 @@ func TestL2SuggestedEstimator(t *testing.T) {
 		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
 		assert.EqualError(t, err, "failed to estimate l2 gas; gas price not set")
 	})
+
+	t.Run("calling GetLegacyGas on started estimator with EIP1559 enabled fetches prices in a single batch", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+			return len(b) == 3 &&
+				b[0].Method == "eth_gasPrice" && len(b[0].Args) == 0 &&
+				b[1].Method == "eth_maxPriorityFeePerGas" && len(b[1].Args) == 0 &&
+				b[2].Method == "eth_getBlockByNumber" && assert.ObjectsAreEqual([]interface{}{"latest", false}, b[2].Args)
+		})).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			gas.SetRPCPrice(elems[0].Result, 42)
+			gas.SetRPCPrice(elems[1].Result, 7)
+		}).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		gasPrice, chainSpecificGasLimit, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), gasPrice)
+		assert.Equal(t, gasLimit, chainSpecificGasLimit)
+		assert.Equal(t, assets.NewWeiI(7), gas.GetL2TipCap(o))
+	})
+
+	t.Run("batch with one failing element still refreshes the other", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			gas.SetRPCPrice(elems[0].Result, 42)
+			elems[1].Error = errors.New("the method eth_maxPriorityFeePerGas does not exist/is not available")
+		}).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), gasPrice)
+		assert.Nil(t, gas.GetL2TipCap(o))
+	})
+
+	t.Run("batch call failure leaves prices unset", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("kaboom")).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		assert.EqualError(t, err, "failed to estimate l2 gas; gas price not set")
+	})
+}
+
+func TestL2SuggestedEstimator_BumpLegacyGas(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.NewWeiI(100)
+
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasSuggestedPriceConnectivityCheckF = true
+		return cfg
+	}
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, suggestedPrice int64) gas.EvmEstimator {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), suggestedPrice)
+		})
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		return o
+	}
+
+	t.Run("bumps the original price by BumpPercent", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 42)
+		gasPrice, chainSpecificGasLimit, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(46), gasPrice)
+		assert.Equal(t, gasLimit, chainSpecificGasLimit)
+	})
+
+	t.Run("uses the suggested price if it is higher than the bumped price", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 60)
+		gasPrice, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(60), gasPrice)
+	})
+
+	t.Run("returns ErrConnectivity once the suggested price falls below the attempt price", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 42)
+
+		// The node keeps suggesting 42 wei while bump attempts climb
+		gasPrice, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(46), gasPrice)
+
+		_, _, err = o.BumpLegacyGas(testutils.Context(t), gasPrice, gasLimit, maxGasPrice, nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, gas.ErrConnectivity))
+		assert.Contains(t, err.Error(), "transaction has gas price of 46 wei, which is above the current suggested gas price of 42 wei")
+	})
+
+	t.Run("keeps bumping if the connectivity check is disabled", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.EvmGasSuggestedPriceConnectivityCheckF = false
+		o := newEstimator(t, cfg, 42)
+
+		gasPrice := assets.NewWeiI(42)
+		for _, expected := range []int64{46, 50, 55} {
+			var err error
+			gasPrice, _, err = o.BumpLegacyGas(testutils.Context(t), gasPrice, gasLimit, maxGasPrice, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.NewWeiI(expected), gasPrice)
+		}
+	})
+
+	t.Run("returns error if the bumped price exceeds the max gas price", func(t *testing.T) {
+		o := newEstimator(t, newConfig(), 95)
+		_, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(95), gasLimit, maxGasPrice, nil)
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, gas.ErrBumpGasExceedsLimit))
+		assert.Contains(t, err.Error(), "bumped gas price of 104 wei would exceed configured max gas price of 100 wei (original price was 95 wei)")
+	})
+}
+
+func TestL2SuggestedEstimator_GetBlobFee(t *testing.T) {
+	t.Parallel()
+
+	cfg := gas.NewMockConfig()
+	cfg.EvmMaxBlobGasPriceWeiF = assets.NewWeiI(100)
+
+	mockGasPrice := func(client *mocks.RPCClient) {
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 42)
+		})
+	}
+
+	t.Run("calling GetBlobFee on unstarted estimator returns error", func(t *testing.T) {
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, mocks.NewRPCClient(t), *testutils.FixtureChainID)
+		_, err := o.(gas.BlobFeeEstimator).GetBlobFee(testutils.Context(t))
+		assert.EqualError(t, err, "estimator is not started")
+	})
+
+	t.Run("returns blob base fee from eth_blobBaseFee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_blobBaseFee").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 7)
+		})
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		blobFee, err := o.(gas.BlobFeeEstimator).GetBlobFee(testutils.Context(t))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(7), blobFee)
+	})
+
+	t.Run("returns error if blob base fee exceeds PriceMaxBlob", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_blobBaseFee").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), 120)
+		})
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		_, err := o.(gas.BlobFeeEstimator).GetBlobFee(testutils.Context(t))
+		assert.EqualError(t, err, "estimated blob fee: 120 wei is greater than the maximum blob gas price configured: 100 wei")
+	})
+
+	t.Run("returns error if RPC does not support eth_blobBaseFee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_blobBaseFee").Return(errors.New("the method eth_blobBaseFee does not exist/is not available"))
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		_, err := o.(gas.BlobFeeEstimator).GetBlobFee(testutils.Context(t))
+		assert.EqualError(t, err, "failed to estimate blob fee; eth_blobBaseFee may not be supported by this RPC: the method eth_blobBaseFee does not exist/is not available")
+	})
+}
+
+func TestL2SuggestedEstimator_ForceRefresh(t *testing.T) {
+	t.Parallel()
+
+	calldata := []byte{0x00, 0x00, 0x01, 0x02, 0x03}
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.NewWeiI(100)
+
+	mockGasPrice := func(client *mocks.RPCClient, price int64) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		})
+	}
+
+	t.Run("calling ForceRefresh on unstarted estimator returns error", func(t *testing.T) {
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), mocks.NewRPCClient(t), *testutils.FixtureChainID)
+		err := o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t))
+		assert.EqualError(t, err, "estimator is not started")
+	})
+
+	t.Run("ForceRefresh updates the cached price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		mockGasPrice(client, 43).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(43), gasPrice)
+	})
+
+	t.Run("ForceRefresh returns the RPC error", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom")).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		assert.EqualError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)), "kaboom")
+		// The previous price is kept
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), gasPrice)
+	})
+
+	t.Run("ForceRefresh respects context cancellation", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		ctx, cancel := context.WithCancel(testutils.Context(t))
+		unblock := make(chan struct{})
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(mock.Arguments) {
+			cancel()
+			<-unblock
+		}).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		t.Cleanup(func() { close(unblock) })
+
+		assert.ErrorIs(t, o.(gas.ForceRefresher).ForceRefresh(ctx), context.Canceled)
+	})
+
+	t.Run("stale cached price triggers exactly one extra CallContext", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceStaleThresholdF = 10 * time.Millisecond
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		mockGasPrice(client, 43).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		time.Sleep(2 * cfg.EvmGasPriceStaleThresholdF)
+
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(43), gasPrice)
+		client.AssertNumberOfCalls(t, "CallContext", 2)
+	})
+
+	t.Run("concurrent callers on a stale price share a single refresh", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceStaleThresholdF = time.Second
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		var refreshes atomic.Int32
+		release := make(chan struct{})
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			refreshes.Add(1)
+			<-release
+			gas.SetRPCPrice(args.Get(1), 43)
+		}).Once()
+
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		time.Sleep(cfg.EvmGasPriceStaleThresholdF + 50*time.Millisecond)
+
+		const callers = 10
+		var wg sync.WaitGroup
+		wg.Add(callers)
+		prices := make(chan *assets.Wei, callers)
+		for i := 0; i < callers; i++ {
+			go func() {
+				defer wg.Done()
+				gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+				assert.NoError(t, err)
+				prices <- gasPrice
+			}()
+		}
+		require.Eventually(t, func() bool { return refreshes.Load() == 1 }, testutils.WaitTimeout(t), 10*time.Millisecond)
+		close(release)
+		wg.Wait()
+		close(prices)
+
+		for p := range prices {
+			assert.Equal(t, assets.NewWeiI(43), p)
+		}
+		client.AssertNumberOfCalls(t, "CallContext", 2)
+	})
+}
+
+func TestL2SuggestedEstimator_PriceUpdateThreshold(t *testing.T) {
+	t.Parallel()
+
+	calldata := []byte{0x00, 0x00, 0x01, 0x02, 0x03}
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.NewWeiI(100000)
+
+	newConfig := func(threshold uint16) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasPriceUpdateThresholdF = threshold
+		cfg.EvmGasBumpPercentF = 20
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		return cfg
+	}
+	// refreshAll starts the estimator on the first price and refreshes it with
+	// each of the others
+	refreshAll := func(t *testing.T, cfg *gas.MockConfig, prices ...int64) gas.EvmEstimator {
+		client := mocks.NewRPCClient(t)
+		for _, price := range prices {
+			price := price
+			client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+				gas.SetRPCPrice(args.Get(1), price)
+			}).Once()
+		}
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		for range prices[1:] {
+			require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		}
+		return o
+	}
+	cachedPrice := func(t *testing.T, o gas.EvmEstimator) *assets.Wei {
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		return gasPrice
+	}
+
+	t.Run("small oscillations don't update the cached price", func(t *testing.T) {
+		o := refreshAll(t, newConfig(5), 1000, 1010, 990, 1050, 960, 1030)
+
+		assert.Equal(t, assets.NewWeiI(1000), cachedPrice(t, o))
+		gasPrice, _ := o.(gas.RawPriceReporter).RawPrices()
+		assert.Equal(t, assets.NewWeiI(1030), gasPrice)
+	})
+
+	t.Run("a spike above BumpPercent updates the cached price immediately", func(t *testing.T) {
+		o := refreshAll(t, newConfig(50), 1000, 1010, 990, 1300)
+
+		assert.Equal(t, assets.NewWeiI(1300), cachedPrice(t, o))
+	})
+
+	t.Run("changes above the threshold update the cached price", func(t *testing.T) {
+		o := refreshAll(t, newConfig(5), 1000, 1080)
+		assert.Equal(t, assets.NewWeiI(1080), cachedPrice(t, o))
+
+		o = refreshAll(t, newConfig(5), 1000, 900)
+		assert.Equal(t, assets.NewWeiI(900), cachedPrice(t, o))
+	})
+
+	t.Run("without a threshold every change updates the cached price", func(t *testing.T) {
+		o := refreshAll(t, newConfig(0), 1000, 1010, 990)
+
+		assert.Equal(t, assets.NewWeiI(990), cachedPrice(t, o))
+	})
+
+	t.Run("applies to the tip cap with EIP-1559", func(t *testing.T) {
+		cfg := newConfig(5)
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(1)
+		client := mocks.NewRPCClient(t)
+		for _, tipCap := range []int64{100, 103, 98, 200} {
+			tipCap := tipCap
+			client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				elems := args.Get(1).([]rpc.BatchElem)
+				gas.SetRPCPrice(elems[0].Result, 1000)
+				gas.SetRPCPrice(elems[1].Result, tipCap)
+				require.NoError(t, json.Unmarshal([]byte(`{"baseFeePerGas":"0x3e8"}`), elems[2].Result))
+			}).Once()
+		}
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		for _, expected := range []int64{100, 100, 200} {
+			require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+			fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+			require.NoError(t, err)
+			assert.Equal(t, assets.NewWeiI(expected), fee.TipCap)
+		}
+		_, tipCap := o.(gas.RawPriceReporter).RawPrices()
+		assert.Equal(t, assets.NewWeiI(200), tipCap)
+	})
+}
+
+func TestL2SuggestedEstimator_Errors(t *testing.T) {
+	t.Parallel()
+
+	calldata := []byte{0x00, 0x00, 0x01, 0x02, 0x03}
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.NewWeiI(100)
+
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasSuggestedPriceConnectivityCheckF = true
+		return cfg
+	}
+	mockGasPrice := func(client *mocks.RPCClient, price int64) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		})
+	}
+	start := func(t *testing.T, cfg *gas.MockConfig, client *mocks.RPCClient) gas.EvmEstimator {
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		return o
+	}
+
+	t.Run("missing gas price returns ErrStalePrice", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom"))
+		o := start(t, newConfig(), client)
+
+		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		e := requireEstimationError(t, err, "L2Suggested", gas.ErrStalePrice, "failed to estimate l2 gas; gas price not set")
+		assert.Equal(t, testutils.FixtureChainID, e.ChainID)
+
+		_, _, err = o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), gasLimit, maxGasPrice, nil)
+		requireEstimationError(t, err, "L2Suggested", gas.ErrStalePrice, "failed to bump l2 gas; gas price not set")
+	})
+
+	t.Run("failed refresh returns ErrRPCFailure", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom")).Once()
+		o := start(t, newConfig(), client)
+
+		err := o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t))
+		requireEstimationError(t, err, "L2Suggested", gas.ErrRPCFailure, "kaboom")
+	})
+
+	t.Run("failed refresh of a stale price returns ErrStalePrice", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.EvmGasPriceStaleThresholdF = time.Nanosecond
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom")).Once()
+		o := start(t, cfg, client)
+
+		time.Sleep(time.Millisecond)
+		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, maxGasPrice)
+		requireEstimationError(t, err, "L2Suggested", gas.ErrStalePrice, "failed to refresh stale l2 gas price: kaboom")
+		assert.ErrorIs(t, err, gas.ErrRPCFailure)
+	})
+
+	t.Run("gas price above max returns the attempted price and limit", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42)
+		o := start(t, newConfig(), client)
+
+		_, _, err := o.GetLegacyGas(testutils.Context(t), calldata, gasLimit, assets.NewWeiI(40))
+		e := requireEstimationError(t, err, "L2Suggested", nil, "estimated gas price: 42 wei is greater than the maximum gas price configured: 40 wei")
+		assert.Equal(t, assets.NewWeiI(42), e.Price)
+		assert.Equal(t, assets.NewWeiI(40), e.Limit)
+	})
+
+	t.Run("bump above max returns ErrBumpLimitExceeded", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 95)
+		o := start(t, newConfig(), client)
+
+		_, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(95), gasLimit, maxGasPrice, nil)
+		e := requireEstimationError(t, err, "L2Suggested", gas.ErrBumpLimitExceeded,
+			fmt.Sprintf("bumped gas price of 104 wei would exceed configured max gas price of 100 wei (original price was 95 wei). %s: gas bump exceeds limit", label.NodeConnectivityProblemWarning))
+		assert.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+		assert.Equal(t, assets.NewWeiI(104), e.Price)
+		assert.Equal(t, maxGasPrice, e.Limit)
+	})
+
+	t.Run("bump above the suggested price returns ErrConnectivity", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, 42)
+		o := start(t, newConfig(), client)
+
+		_, _, err := o.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(46), gasLimit, maxGasPrice, nil)
+		requireEstimationError(t, err, "L2Suggested", gas.ErrConnectivity,
+			"transaction has gas price of 46 wei, which is above the current suggested gas price of 42 wei: transaction propagation issue: transactions are not being mined")
+	})
+}
+
+func TestL2SuggestedEstimator_DynamicFee(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.NewWeiI(100000)
+
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmGasBaseFeeBufferMultiplierF = 1
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmGasTipCapDefaultF = assets.NewWeiI(5)
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		return cfg
+	}
+	// mockPrices expects a refresh returning the given prices, a nil base fee
+	// is returned as a block without one
+	mockPrices := func(client *mocks.RPCClient, gasPrice, tipCap int64, baseFee *int64) {
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			gas.SetRPCPrice(elems[0].Result, gasPrice)
+			gas.SetRPCPrice(elems[1].Result, tipCap)
+			if baseFee != nil {
+				require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{"baseFeePerGas":"%s"}`, hexutil.EncodeBig(big.NewInt(*baseFee)))), elems[2].Result))
+			}
+		}).Once()
+	}
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, client *mocks.RPCClient) gas.EvmEstimator {
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *testutils.FixtureChainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+		return o
+	}
+
+	t.Run("GetDynamicFee returns the suggested tip cap and a fee cap from the latest base fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 7, testutils.Ptr[int64](100))
+		o := newEstimator(t, newConfig(), client)
+
+		fee, chainSpecificGasLimit, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, chainSpecificGasLimit)
+		assert.Equal(t, assets.NewWeiI(7), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(107), fee.FeeCap)
+	})
+
+	t.Run("GetDynamicFee buffers the base fee by BaseFeeBufferMultiplier", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 7, testutils.Ptr[int64](100))
+		cfg := newConfig()
+		cfg.EvmGasBaseFeeBufferMultiplierF = 1.5
+		// the buffer blocks of the BlockHistoryEstimator don't apply
+		cfg.BlockHistoryEstimatorEIP1559FeeCapBufferBlocksF = 2
+		o := newEstimator(t, cfg, client)
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		// 100 * 1.5 + 7
+		assert.Equal(t, assets.NewWeiI(157), fee.FeeCap)
+
+		// the fee cap never exceeds the max gas price
+		fee, _, err = o.GetDynamicFee(testutils.Context(t), gasLimit, assets.NewWeiI(120))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(120), fee.FeeCap)
+	})
+
+	t.Run("GetDynamicFee uses TipCapDefault if the node suggests a zero tip cap", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 0, testutils.Ptr[int64](100))
+		o := newEstimator(t, newConfig(), client)
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(5), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(105), fee.FeeCap)
+	})
+
+	t.Run("GetDynamicFee raises the tip cap and fee cap to TipCapMin", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 7, testutils.Ptr[int64](100))
+		cfg := newConfig()
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(30)
+		o := newEstimator(t, cfg, client)
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(130), fee.FeeCap)
+
+		// bumping never goes below the floor either
+		bumped, _, err := o.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.NewWeiI(7), FeeCap: assets.NewWeiI(107)}, gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), bumped.TipCap)
+		assert.Equal(t, assets.NewWeiI(130), bumped.FeeCap)
+
+		// a floor above the max gas price is an error rather than a lower tip cap
+		_, _, err = o.GetDynamicFee(testutils.Context(t), gasLimit, assets.NewWeiI(20))
+		e := requireEstimationError(t, err, "L2Suggested", nil, "EVM.GasEstimator.TipCapMin of 30 wei is greater than the maximum gas price configured: 20 wei")
+		assert.Equal(t, assets.NewWeiI(30), e.Price)
+	})
+
+	t.Run("GetDynamicFee uses the max gas price as fee cap if bumping is disabled", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 7, testutils.Ptr[int64](100))
+		cfg := newConfig()
+		cfg.EvmGasBumpThresholdF = 0
+		o := newEstimator(t, cfg, client)
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, maxGasPrice, fee.FeeCap)
+	})
+
+	t.Run("GetDynamicFee returns an error if the tip cap exceeds the max gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 7, testutils.Ptr[int64](100))
+		o := newEstimator(t, newConfig(), client)
+
+		_, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, assets.NewWeiI(6))
+		e := requireEstimationError(t, err, "L2Suggested", nil, "estimated tip cap: 7 wei is greater than the maximum gas price configured: 6 wei")
+		assert.Equal(t, assets.NewWeiI(7), e.Price)
+	})
+
+	t.Run("GetDynamicFee returns an error without a base fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 7, nil)
+		o := newEstimator(t, newConfig(), client)
+
+		_, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		requireEstimationError(t, err, "L2Suggested", gas.ErrStalePrice, "failed to estimate dynamic fee; base fee not set. Are you trying to run with EIP1559 enabled on a non-EIP1559 chain?")
+	})
+
+	t.Run("GetDynamicFee returns an error with EIP1559 disabled", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Once()
+		cfg := newConfig()
+		cfg.EvmEIP1559DynamicFeesF = false
+		o := newEstimator(t, cfg, client)
+
+		_, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		assert.EqualError(t, err, "Can't get dynamic fee, EIP1559 is disabled")
+		_, _, err = o.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.NewWeiI(1), FeeCap: assets.NewWeiI(2)}, gasLimit, maxGasPrice, nil)
+		assert.EqualError(t, err, "Can't bump dynamic fee, EIP1559 is disabled")
+	})
+
+	t.Run("BumpDynamicFee recomputes the fee cap from the latest base fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 1000, testutils.Ptr[int64](10000))
+		o := newEstimator(t, newConfig(), client)
+
+		original, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, gas.DynamicFee{TipCap: assets.NewWeiI(1000), FeeCap: assets.NewWeiI(11000)}, original)
+
+		// the base fee spikes after the fee was estimated
+		mockPrices(client, 42, 1000, testutils.Ptr[int64](20000))
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+
+		bumped, chainSpecificGasLimit, err := o.BumpDynamicFee(testutils.Context(t), original, gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, chainSpecificGasLimit)
+		assert.Equal(t, assets.NewWeiI(1100), bumped.TipCap)
+		assert.Equal(t, assets.NewWeiI(21100), bumped.FeeCap)
+
+		// the base fee drops, but the fee cap must still be bumped for the replacement to be accepted
+		mockPrices(client, 42, 1000, testutils.Ptr[int64](5000))
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+
+		bumped, _, err = o.BumpDynamicFee(testutils.Context(t), original, gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(1100), bumped.TipCap)
+		assert.Equal(t, assets.NewWeiI(12100), bumped.FeeCap)
+	})
+
+	t.Run("BumpDynamicFee bumps to the suggested tip cap if higher", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 2000, testutils.Ptr[int64](20000))
+		o := newEstimator(t, newConfig(), client)
+
+		bumped, _, err := o.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.NewWeiI(1000), FeeCap: assets.NewWeiI(11000)}, gasLimit, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(2000), bumped.TipCap)
+		assert.Equal(t, assets.NewWeiI(22000), bumped.FeeCap)
+	})
+
+	t.Run("BumpDynamicFee returns ErrBumpLimitExceeded above the max gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockPrices(client, 42, 1000, testutils.Ptr[int64](10000))
+		o := newEstimator(t, newConfig(), client)
+
+		_, _, err := o.BumpDynamicFee(testutils.Context(t), gas.DynamicFee{TipCap: assets.NewWeiI(1000), FeeCap: assets.NewWeiI(11000)}, gasLimit, assets.NewWeiI(12000), nil)
+		requireEstimationError(t, err, "L2Suggested", gas.ErrBumpLimitExceeded,
+			fmt.Sprintf("bumped fee cap of 12.1 kwei would exceed configured max gas price of 12 kwei (original fee: tip cap 1 kwei, fee cap 11 kwei). %s: gas bump exceeds limit", label.NodeConnectivityProblemWarning))
+	})
+}
+
+func TestQuorumSuggestedPriceEstimator(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 80000
+	maxGasPrice := assets.GWei(1_000_000)
+
+	mockGasPrice := func(client *mocks.RPCClient, price int64) {
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		}).Once()
+	}
+	// mockTimeout makes the client hang until its call times out
+	mockTimeout := func(client *mocks.RPCClient) {
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(context.DeadlineExceeded).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Once()
+	}
+	start := func(t *testing.T, o gas.EvmEstimator) {
+		gas.SetQuorumCallTimeout(o, 100*time.Millisecond)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+	}
+
+	t.Run("uses the median of the suggested gas prices", func(t *testing.T) {
+		c1, c2, c3 := mocks.NewRPCClient(t), mocks.NewRPCClient(t), mocks.NewRPCClient(t)
+		mockGasPrice(c1, 40)
+		mockGasPrice(c2, 420_000) // a 10000x outlier
+		mockGasPrice(c3, 42)
+		o, err := gas.NewQuorumSuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), *testutils.FixtureChainID, 2, c1, c2, c3)
+		require.NoError(t, err)
+		start(t, o)
+
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), gasPrice)
+	})
+
+	t.Run("uses the median of each of the suggested dynamic prices", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmGasBaseFeeBufferMultiplierF = 1
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		mockPrices := func(client *mocks.RPCClient, gasPrice, tipCap, baseFee int64) {
+			client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+				elems := args.Get(1).([]rpc.BatchElem)
+				gas.SetRPCPrice(elems[0].Result, gasPrice)
+				gas.SetRPCPrice(elems[1].Result, tipCap)
+				require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(`{"baseFeePerGas":"%s"}`, hexutil.EncodeBig(big.NewInt(baseFee)))), elems[2].Result))
+			}).Once()
+		}
+		c1, c2, c3 := mocks.NewRPCClient(t), mocks.NewRPCClient(t), mocks.NewRPCClient(t)
+		mockPrices(c1, 42, 7, 100)
+		mockPrices(c2, 420_000, 70_000, 1_000_000)
+		c3.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("kaboom")).Once()
+		o, err := gas.NewQuorumSuggestedPriceEstimator(logger.TestLogger(t), cfg, *testutils.FixtureChainID, 2, c1, c2, c3)
+		require.NoError(t, err)
+		start(t, o)
+
+		fee, _, err := o.GetDynamicFee(testutils.Context(t), gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(7), fee.TipCap)
+		assert.Equal(t, assets.NewWeiI(107), fee.FeeCap)
+	})
+
+	t.Run("keeps the previous price if the quorum isn't met", func(t *testing.T) {
+		c1, c2, c3 := mocks.NewRPCClient(t), mocks.NewRPCClient(t), mocks.NewRPCClient(t)
+		mockGasPrice(c1, 40)
+		mockGasPrice(c2, 42)
+		mockGasPrice(c3, 44)
+		o, err := gas.NewQuorumSuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), *testutils.FixtureChainID, 2, c1, c2, c3)
+		require.NoError(t, err)
+		start(t, o)
+
+		mockTimeout(c1)
+		mockGasPrice(c2, 1000)
+		mockTimeout(c3)
+		err = o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t))
+		requireEstimationError(t, err, "L2Suggested", gas.ErrRPCFailure, "1 of 3 clients responded, fewer than the minimum quorum of 2; context deadline exceeded; context deadline exceeded")
+
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), nil, gasLimit, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), gasPrice)
+	})
+	t.Run("rejects quorums out of range of the clients", func(t *testing.T) {
+		c1, c2 := mocks.NewRPCClient(t), mocks.NewRPCClient(t)
+		_, err := gas.NewQuorumSuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), *testutils.FixtureChainID, 0, c1, c2)
+		assert.EqualError(t, err, "minimum quorum of 0 must be between 1 and the number of clients, 2")
+		_, err = gas.NewQuorumSuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), *testutils.FixtureChainID, 3, c1, c2)
+		assert.EqualError(t, err, "minimum quorum of 3 must be between 1 and the number of clients, 2")
+		_, err = gas.NewQuorumSuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), *testutils.FixtureChainID, 1)
+		assert.EqualError(t, err, "quorum must have at least one client")
+	})
 }
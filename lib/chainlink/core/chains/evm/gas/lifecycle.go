@@ -0,0 +1,94 @@
+package gas
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// inFlight tracks the in-flight calls of an estimator, so that Close can wait
+// for them to return before tearing down the state they use. Once stopped, it
+// rejects further calls. The zero value is ready to use, and a nil inFlight
+// admits every call.
+type inFlight struct {
+	mu      sync.Mutex
+	stopped bool
+	calls   int
+	// idle is closed by stop, or by the last admitted call to return after it
+	idle chan struct{}
+}
+
+// enter admits a call, which must then call exit when it returns, or returns
+// false if the estimator is stopping
+func (f *inFlight) enter() bool {
+	if f == nil {
+		return true
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return false
+	}
+	f.calls++
+	return true
+}
+
+func (f *inFlight) exit() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls--
+	if f.stopped && f.calls == 0 {
+		close(f.idle)
+	}
+}
+
+// stop rejects further calls
+func (f *inFlight) stop() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.stopped {
+		return
+	}
+	f.stopped = true
+	f.idle = make(chan struct{})
+	if f.calls == 0 {
+		close(f.idle)
+	}
+}
+
+// drain waits up to timeout, i.e. EVM.GasEstimator.DrainTimeout, for the
+// admitted calls to return. It must be called after stop.
+func (f *inFlight) drain(timeout time.Duration) error {
+	if f == nil {
+		return nil
+	}
+	f.mu.Lock()
+	idle, calls := f.idle, f.calls
+	f.mu.Unlock()
+	if calls == 0 {
+		return nil
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-idle:
+		return nil
+	case <-timer.C:
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return errors.Errorf("timed out after %s waiting for %d in-flight estimations to return", timeout, f.calls)
+	}
+}
+
+// errStopped is the error of calls rejected because the named estimator is
+// stopping
+func errStopped(name string) error {
+	return &EstimationError{Reason: ErrEstimatorStopped, Err: errors.Errorf("%s is stopped; cannot estimate gas", name)}
+}
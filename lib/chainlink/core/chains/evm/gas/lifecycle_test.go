@@ -0,0 +1,148 @@
+package gas_test
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestBlockHistoryEstimator_Close(t *testing.T) {
+	t.Parallel()
+
+	cfg := newConfigWithEIP1559DynamicFeesDisabled(t)
+	cfg.BlockHistoryEstimatorTransactionPercentileF = uint16(35)
+	cfg.BlockHistoryEstimatorBlockHistorySizeF = uint16(8)
+	cfg.EvmGasLimitMultiplierF = float32(1)
+	cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000000)
+	cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+	cfg.EvmGasDrainTimeoutF = 5 * time.Second
+
+	bhe := newBlockHistoryEstimator(t, nil, cfg)
+	gas.SetRollingBlockHistory(bhe, []evmtypes.Block{
+		{Number: 0, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(1000)},
+		{Number: 1, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(1200)},
+	})
+	bhe.Recalculate(cltest.Head(1))
+	gas.SimulateStart(t, bhe)
+
+	var closed atomic.Bool
+	var estimated atomic.Int64
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				afterClose := closed.Load()
+				price, _, err := bhe.GetLegacyGas(testutils.Context(t), nil, 10000, cfg.EvmMaxGasPriceWeiF)
+				if afterClose {
+					assert.ErrorIs(t, err, gas.ErrEstimatorStopped)
+					return
+				}
+				if err == nil {
+					assert.Equal(t, assets.NewWeiI(1000), price)
+					estimated.Add(1)
+				}
+			}
+		}()
+	}
+
+	// let the goroutines estimate before closing under them
+	require.Eventually(t, func() bool { return estimated.Load() >= 50 }, testutils.WaitTimeout(t), time.Millisecond)
+	require.NoError(t, bhe.Close())
+	closed.Store(true)
+	wg.Wait()
+}
+
+func TestWrappedEvmEstimator_Close(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21_000
+
+	// newEstimator returns an estimator whose legacy estimations block until
+	// release is closed, and a channel that receives when one has begun
+	newEstimator := func(t *testing.T, drainTimeout time.Duration, release chan struct{}) (gas.EvmFeeEstimator, *mocks.EvmEstimator, chan struct{}) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasDrainTimeoutF = drainTimeout
+		began := make(chan struct{}, 1)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(assets.GWei(20), gasLimit, nil).Run(func(mock.Arguments) {
+			began <- struct{}{}
+			<-release
+		}).Maybe()
+		e.On("Name").Return("EvmEstimator").Maybe()
+		e.On("Close").Return(nil).Once()
+		return gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil), e, began
+	}
+
+	t.Run("waits for in-flight estimations before closing the estimator", func(t *testing.T) {
+		release := make(chan struct{})
+		estimator, e, began := newEstimator(t, testutils.WaitTimeout(t), release)
+
+		done := make(chan error)
+		go func() {
+			_, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+			done <- err
+		}()
+		<-began
+
+		closed := make(chan error)
+		go func() { closed <- estimator.Close() }()
+
+		// ExplainFee is rejected like GetFee once Close has begun, and fails
+		// without estimating before, as the mock doesn't explain fees
+		require.Eventually(t, func() bool {
+			_, err := estimator.(*gas.WrappedEvmEstimator).ExplainFee(testutils.Context(t), nil, gasLimit, nil)
+			return errors.Is(err, gas.ErrEstimatorStopped)
+		}, testutils.WaitTimeout(t), time.Millisecond)
+		_, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.ErrorIs(t, err, gas.ErrEstimatorStopped)
+		_, _, err = estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(20)}, gasLimit, nil, nil)
+		require.ErrorIs(t, err, gas.ErrEstimatorStopped)
+		select {
+		case <-closed:
+			t.Fatal("closed before the in-flight estimation returned")
+		case <-time.After(10 * time.Millisecond):
+		}
+		e.AssertNotCalled(t, "Close")
+
+		close(release)
+		require.NoError(t, <-done)
+		require.NoError(t, <-closed)
+	})
+
+	t.Run("closes the estimator once the drain times out", func(t *testing.T) {
+		release, done := make(chan struct{}), make(chan struct{})
+		t.Cleanup(func() {
+			close(release)
+			<-done
+		})
+		estimator, _, began := newEstimator(t, 10*time.Millisecond, release)
+
+		go func() {
+			defer close(done)
+			_, _, _ = estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		}()
+		<-began
+
+		err := estimator.Close()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "timed out after 10ms waiting for 1 in-flight estimations to return")
+	})
+}
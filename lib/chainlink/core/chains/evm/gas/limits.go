@@ -0,0 +1,88 @@
+package gas
+
+import (
+	"context"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// Limits are the fee limits that the estimators enforce, after the per-call
+// max fee price and the fee profile are layered over the chain's fee config
+type Limits struct {
+	// PriceMax is the highest gas price or fee cap that is returned: the
+	// lowest of EVM.GasEstimator.PriceMax, the per-call max fee price and the
+	// PriceMax of the profile
+	PriceMax *assets.Wei
+	// TipCapMax is the highest tip cap that is returned. The tip cap never
+	// exceeds the fee cap, so it is PriceMax.
+	TipCapMax *assets.Wei
+	// FeeCapDefault is the fee cap of the FixedPrice estimator, which is
+	// EVM.GasEstimator.FeeCapDefault, or PriceMax with gas bumping disabled,
+	// and never more than PriceMax
+	FeeCapDefault *assets.Wei
+	// LimitMax is EVM.GasEstimator.LimitMax
+	LimitMax uint32
+	// BumpPercent is the minimum percentage that bumps increase the fee by,
+	// the higher of EVM.GasEstimator.BumpPercent and the BumpPercent of the
+	// profile
+	BumpPercent uint16
+}
+
+type limitsArgs struct {
+	maxFeePrice *assets.Wei
+}
+
+// LimitsOpt is an option of EffectiveLimits
+type LimitsOpt func(*limitsArgs)
+
+// WithMaxFeePrice returns the limits for the per-call max fee price that is
+// given to GetFee and BumpFee, e.g. the max gas price of the sending key
+func WithMaxFeePrice(maxFeePrice *assets.Wei) LimitsOpt {
+	return func(a *limitsArgs) { a.maxFeePrice = maxFeePrice }
+}
+
+// EffectiveLimits returns the fee limits that GetFee, GetDynamicFees and
+// BumpFee enforce for the fee profile selected with WithProfile and the
+// given options, resolved exactly as those calls resolve them
+func (e WrappedEvmEstimator) EffectiveLimits(ctx context.Context, opts ...LimitsOpt) (Limits, error) {
+	var args limitsArgs
+	for _, opt := range opts {
+		opt(&args)
+	}
+	_, _, profile, maxFeePrice := e.resolveFeeConfig(ctx, args.maxFeePrice)
+	limits := Limits{
+		PriceMax:      maxFeePrice,
+		TipCapMax:     maxFeePrice,
+		FeeCapDefault: effectiveFeeCapDefault(e.cfg, maxFeePrice),
+		LimitMax:      e.cfg.EvmGasLimitMax(),
+		BumpPercent:   e.cfg.EvmGasBumpPercent(),
+	}
+	if profile.BumpPercent != nil && *profile.BumpPercent > limits.BumpPercent {
+		limits.BumpPercent = *profile.BumpPercent
+	}
+	return limits, nil
+}
+
+// resolveFeeConfig resolves the fee profile named in ctx, see resolveProfile,
+// and the max fee price that the estimator is given for the per-call
+// maxFeePrice, which is the lowest of it, EVM.GasEstimator.PriceMax and the
+// PriceMax of the profile
+func (e WrappedEvmEstimator) resolveFeeConfig(ctx context.Context, maxFeePrice *assets.Wei) (context.Context, string, FeeProfile, *assets.Wei) {
+	ctx, profileName, profile := e.resolveProfile(ctx)
+	return ctx, profileName, profile, profileMaxPrice(profile, e.effectiveMaxPrice(maxFeePrice))
+}
+
+type feeCapDefaultConfig interface {
+	EvmGasBumpThreshold() uint64
+	EvmGasFeeCapDefault() *assets.Wei
+}
+
+// effectiveFeeCapDefault returns EVM.GasEstimator.FeeCapDefault, which leaves
+// headroom for bumping, or maxGasPrice if gas bumping is disabled, capped at
+// maxGasPrice
+func effectiveFeeCapDefault(cfg feeCapDefaultConfig, maxGasPrice *assets.Wei) *assets.Wei {
+	if cfg.EvmGasBumpThreshold() == 0 || cfg.EvmGasFeeCapDefault() == nil {
+		return maxGasPrice
+	}
+	return assets.WeiMin(cfg.EvmGasFeeCapDefault(), maxGasPrice)
+}
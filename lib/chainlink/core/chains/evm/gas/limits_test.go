@@ -0,0 +1,109 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_EffectiveLimits(t *testing.T) {
+	t.Parallel()
+
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig) *gas.WrappedEvmEstimator {
+		e := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil).(*gas.WrappedEvmEstimator)
+		e.Profiles = map[string]gas.FeeProfile{
+			// lowers the max price below the fee cap default and raises the bump
+			"capped": {PriceMax: assets.GWei(60), BumpPercent: testutils.Ptr[uint16](30)},
+			// can't raise the max price or lower the bump
+			"loose": {PriceMax: assets.GWei(500), BumpPercent: testutils.Ptr[uint16](5)},
+		}
+		return e
+	}
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasFeeCapDefaultF = assets.GWei(80)
+		cfg.EvmGasTipCapDefaultF = assets.GWei(2)
+		cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+		cfg.EvmGasBumpThresholdF = 3
+		cfg.EvmGasBumpPercentF = 20
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(0)
+		cfg.EvmGasLimitMaxF = 500_000
+		cfg.EvmGasLimitMultiplierF = 1
+		return cfg
+	}
+
+	for _, tt := range []struct {
+		name        string
+		profile     string
+		maxFeePrice *assets.Wei
+		exp         gas.Limits
+	}{
+		{"chain config", "", nil,
+			gas.Limits{PriceMax: assets.GWei(100), TipCapMax: assets.GWei(100), FeeCapDefault: assets.GWei(80), LimitMax: 500_000, BumpPercent: 20}},
+		{"per-call max below the fee cap default", "", assets.GWei(70),
+			gas.Limits{PriceMax: assets.GWei(70), TipCapMax: assets.GWei(70), FeeCapDefault: assets.GWei(70), LimitMax: 500_000, BumpPercent: 20}},
+		{"per-call max above the chain max", "", assets.GWei(1000),
+			gas.Limits{PriceMax: assets.GWei(100), TipCapMax: assets.GWei(100), FeeCapDefault: assets.GWei(80), LimitMax: 500_000, BumpPercent: 20}},
+		{"profile below the per-call max", "capped", assets.GWei(70),
+			gas.Limits{PriceMax: assets.GWei(60), TipCapMax: assets.GWei(60), FeeCapDefault: assets.GWei(60), LimitMax: 500_000, BumpPercent: 30}},
+		{"per-call max below the profile", "capped", assets.GWei(50),
+			gas.Limits{PriceMax: assets.GWei(50), TipCapMax: assets.GWei(50), FeeCapDefault: assets.GWei(50), LimitMax: 500_000, BumpPercent: 30}},
+		{"profile can't loosen the chain config", "loose", nil,
+			gas.Limits{PriceMax: assets.GWei(100), TipCapMax: assets.GWei(100), FeeCapDefault: assets.GWei(80), LimitMax: 500_000, BumpPercent: 20}},
+		{"unknown profile", "unknown", assets.GWei(90),
+			gas.Limits{PriceMax: assets.GWei(90), TipCapMax: assets.GWei(90), FeeCapDefault: assets.GWei(80), LimitMax: 500_000, BumpPercent: 20}},
+	} {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			estimator := newEstimator(t, newConfig())
+			ctx := gas.WithProfile(testutils.Context(t), tt.profile)
+
+			limits, err := estimator.EffectiveLimits(ctx, gas.WithMaxFeePrice(tt.maxFeePrice))
+			require.NoError(t, err)
+			assert.Equal(t, tt.exp, limits)
+
+			// the dynamic fee is the fee cap default, within the limits
+			fee, _, err := estimator.GetFee(ctx, nil, 21000, tt.maxFeePrice)
+			require.NoError(t, err)
+			assert.Equal(t, limits.FeeCapDefault, fee.DynamicFeeCap)
+			assert.LessOrEqual(t, fee.DynamicTipCap.Cmp(limits.TipCapMax), 0)
+
+			// bumps are by at least BumpPercent
+			original := gas.EvmFee{DynamicFeeCap: assets.GWei(10), DynamicTipCap: assets.GWei(10)}
+			bumped, _, err := estimator.BumpFee(ctx, original, 21000, tt.maxFeePrice, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.GWei(10).AddPercentage(limits.BumpPercent), bumped.DynamicTipCap)
+
+			// and fail past PriceMax
+			atMax := gas.EvmFee{DynamicFeeCap: limits.PriceMax, DynamicTipCap: limits.TipCapMax}
+			_, _, err = estimator.BumpFee(ctx, atMax, 21000, tt.maxFeePrice, nil)
+			require.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+			var eErr *gas.EstimationError
+			require.ErrorAs(t, err, &eErr)
+			assert.Equal(t, limits.PriceMax, eErr.Limit)
+		})
+	}
+
+	t.Run("fee cap default is the max price with gas bumping disabled", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.EvmGasBumpThresholdF = 0
+		estimator := newEstimator(t, cfg)
+		ctx := gas.WithProfile(testutils.Context(t), "capped")
+
+		limits, err := estimator.EffectiveLimits(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(60), limits.FeeCapDefault)
+
+		fee, _, err := estimator.GetFee(ctx, nil, 21000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, limits.FeeCapDefault, fee.DynamicFeeCap)
+	})
+}
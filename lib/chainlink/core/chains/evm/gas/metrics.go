@@ -0,0 +1,154 @@
+package gas
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+var (
+	promGasEstimatorSuggestedGasPrice = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gas_estimator_suggested_gas_price",
+		Help: "Current suggested legacy gas price (in Wei)",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorSuggestedTipCap = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gas_estimator_suggested_tip_cap",
+		Help: "Current suggested EIP-1559 tip cap (in Wei)",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorBaseFee = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gas_estimator_base_fee",
+		Help: "Current base fee used by the estimator (in Wei)",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorBumpCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gas_estimator_bump_count",
+		Help: "Counter is incremented every time the estimator successfully bumps a fee",
+	},
+		[]string{"evmChainID", "estimator", "mode"},
+	)
+	promGasEstimatorMaxPriceCappedCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gas_estimator_max_price_capped_count",
+		Help: "Counter is incremented every time an estimated fee hits the max gas price",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorRPCErrorCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gas_estimator_rpc_error_count",
+		Help: "Counter is incremented every time an RPC call made by the estimator fails",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorRPCTimeoutCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gas_estimator_rpc_timeout_count",
+		Help: "Counter is incremented every time an RPC call made by the estimator times out after EVM.GasEstimator.RPCCallTimeout",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorDecodeErrorCount = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "gas_estimator_decode_error_count",
+		Help: "Counter is incremented every time a price returned by the node can't be decoded",
+	},
+		[]string{"evmChainID", "estimator"},
+	)
+	promGasEstimatorConversionRate = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "gas_estimator_conversion_rate",
+		Help: "Current price of one native token in the currency of the PriceConverter, as of the last estimated fee",
+	},
+		[]string{"evmChainID"},
+	)
+)
+
+// estimatorMetrics holds the metrics of a single estimator, already labelled
+// with its chain ID and mode
+type estimatorMetrics struct {
+	gasPrice       prometheus.Gauge
+	tipCap         prometheus.Gauge
+	baseFee        prometheus.Gauge
+	legacyBumps    prometheus.Counter
+	dynamicBumps   prometheus.Counter
+	maxPriceCapped prometheus.Counter
+	rpcErrors      prometheus.Counter
+	rpcTimeouts    prometheus.Counter
+	decodeErrors   prometheus.Counter
+}
+
+func newEstimatorMetrics(chainID big.Int, estimator string) *estimatorMetrics {
+	id := chainID.String()
+	return &estimatorMetrics{
+		gasPrice:       promGasEstimatorSuggestedGasPrice.WithLabelValues(id, estimator),
+		tipCap:         promGasEstimatorSuggestedTipCap.WithLabelValues(id, estimator),
+		baseFee:        promGasEstimatorBaseFee.WithLabelValues(id, estimator),
+		legacyBumps:    promGasEstimatorBumpCount.WithLabelValues(id, estimator, "legacy"),
+		dynamicBumps:   promGasEstimatorBumpCount.WithLabelValues(id, estimator, "eip1559"),
+		maxPriceCapped: promGasEstimatorMaxPriceCappedCount.WithLabelValues(id, estimator),
+		rpcErrors:      promGasEstimatorRPCErrorCount.WithLabelValues(id, estimator),
+		rpcTimeouts:    promGasEstimatorRPCTimeoutCount.WithLabelValues(id, estimator),
+		decodeErrors:   promGasEstimatorDecodeErrorCount.WithLabelValues(id, estimator),
+	}
+}
+
+// recordRPCError counts the failed RPC call, as a timeout if it didn't return
+// within EVM.GasEstimator.RPCCallTimeout and as a decode error if the node
+// returned a price that can't be decoded
+func (m *estimatorMetrics) recordRPCError(err error) {
+	switch {
+	case isRPCTimeout(err):
+		m.rpcTimeouts.Inc()
+	case isPriceDecodeError(err):
+		m.decodeErrors.Inc()
+	default:
+		m.rpcErrors.Inc()
+	}
+}
+
+func (m *estimatorMetrics) setGasPrice(gasPrice *assets.Wei) {
+	if gasPrice != nil {
+		m.gasPrice.Set(float64(gasPrice.Int64()))
+	}
+}
+
+func (m *estimatorMetrics) setTipCap(tipCap *assets.Wei) {
+	if tipCap != nil {
+		m.tipCap.Set(float64(tipCap.Int64()))
+	}
+}
+
+func (m *estimatorMetrics) setBaseFee(baseFee *assets.Wei) {
+	if baseFee != nil {
+		m.baseFee.Set(float64(baseFee.Int64()))
+	}
+}
+
+// recordLegacyBump records the outcome of a legacy gas bump
+func (m *estimatorMetrics) recordLegacyBump(err error) {
+	m.recordBump(m.legacyBumps, err)
+}
+
+// recordDynamicBump records the outcome of a dynamic fee bump
+func (m *estimatorMetrics) recordDynamicBump(err error) {
+	m.recordBump(m.dynamicBumps, err)
+}
+
+func (m *estimatorMetrics) recordBump(bumps prometheus.Counter, err error) {
+	if err == nil {
+		bumps.Inc()
+	} else if errors.Is(err, ErrBumpGasExceedsLimit) {
+		m.maxPriceCapped.Inc()
+	}
+}
+
+// recordCap records whether the estimated price was capped at the max gas price
+func (m *estimatorMetrics) recordCap(estimated, capped *assets.Wei) {
+	if estimated.Cmp(capped) > 0 {
+		m.maxPriceCapped.Inc()
+	}
+}
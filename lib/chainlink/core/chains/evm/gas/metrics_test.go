@@ -0,0 +1,138 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestEstimatorMetrics_L2Suggested(t *testing.T) {
+	t.Parallel()
+
+	// A random chain ID isolates the metrics of this estimator from other tests
+	chainID := testutils.NewRandomEVMChainID()
+	labels := []string{chainID.String(), "L2Suggested"}
+
+	client := mocks.NewRPCClient(t)
+	mockGasPrice := func(price int64) {
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		}).Once()
+	}
+	mockGasPrice(42)
+
+	o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *chainID)
+	require.NoError(t, o.Start(testutils.Context(t)))
+	t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+	gasPrice := gas.PromGasEstimatorSuggestedGasPrice.WithLabelValues(labels...)
+	rpcErrors := gas.PromGasEstimatorRPCErrorCount.WithLabelValues(labels...)
+	capped := gas.PromGasEstimatorMaxPriceCappedCount.WithLabelValues(labels...)
+	assert.Equal(t, float64(42), promtestutil.ToFloat64(gasPrice))
+
+	// refresh returns a new price
+	mockGasPrice(43)
+	require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+	assert.Equal(t, float64(43), promtestutil.ToFloat64(gasPrice))
+	assert.Equal(t, float64(0), promtestutil.ToFloat64(rpcErrors))
+
+	// refresh fails, price is kept
+	client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom")).Once()
+	require.Error(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+	assert.Equal(t, float64(43), promtestutil.ToFloat64(gasPrice))
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(rpcErrors))
+
+	// price exceeds the max gas price
+	_, _, err := o.GetLegacyGas(testutils.Context(t), nil, 10, assets.NewWeiI(40))
+	require.Error(t, err)
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(capped))
+}
+
+func TestEstimatorMetrics_L2SuggestedBatch(t *testing.T) {
+	t.Parallel()
+
+	chainID := testutils.NewRandomEVMChainID()
+	labels := []string{chainID.String(), "L2Suggested"}
+
+	cfg := gas.NewMockConfig()
+	cfg.EvmEIP1559DynamicFeesF = true
+	client := mocks.NewRPCClient(t)
+	client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		elems := args.Get(1).([]rpc.BatchElem)
+		gas.SetRPCPrice(elems[0].Result, 42)
+		elems[1].Error = errors.New("the method eth_maxPriorityFeePerGas does not exist/is not available")
+	}).Once()
+
+	o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), cfg, client, *chainID)
+	require.NoError(t, o.Start(testutils.Context(t)))
+	t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+	assert.Equal(t, float64(42), promtestutil.ToFloat64(gas.PromGasEstimatorSuggestedGasPrice.WithLabelValues(labels...)))
+	assert.Equal(t, float64(0), promtestutil.ToFloat64(gas.PromGasEstimatorSuggestedTipCap.WithLabelValues(labels...)))
+	assert.Equal(t, float64(1), promtestutil.ToFloat64(gas.PromGasEstimatorRPCErrorCount.WithLabelValues(labels...)))
+}
+
+func TestEstimatorMetrics_BlockHistory(t *testing.T) {
+	t.Parallel()
+
+	chainID := testutils.NewRandomEVMChainID()
+	labels := []string{chainID.String(), "BlockHistory"}
+
+	cfg := newConfigWithEIP1559DynamicFeesEnabled(t)
+	cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+	cfg.EvmGasBumpPercentF = 10
+	cfg.EvmGasBumpWeiF = assets.NewWeiI(150)
+	cfg.EvmMaxGasPriceWeiF = assets.NewWeiI(1000)
+	cfg.EvmMinGasPriceWeiF = assets.NewWeiI(0)
+	cfg.EvmGasTipCapMinimumF = assets.NewWeiI(0)
+	cfg.EvmGasLimitMultiplierF = 1
+
+	bhe := gas.BlockHistoryEstimatorFromInterface(newBlockHistoryEstimatorWithChainID(t, nil, cfg, *chainID))
+	gas.SimulateStart(t, bhe)
+
+	t.Run("base fee, gas price and tip cap gauges are set on recalculation", func(t *testing.T) {
+		gas.SetRollingBlockHistory(bhe, []evmtypes.Block{{
+			Number:        1,
+			Hash:          utils.NewHash(),
+			BaseFeePerGas: assets.NewWeiI(100),
+			Transactions:  cltest.DynamicFeeTransactionsFromTipCaps(200, 300),
+		}})
+		head := cltest.Head(1)
+		head.BaseFeePerGas = assets.NewWeiI(120)
+		bhe.OnNewLongestChain(testutils.Context(t), head)
+		bhe.Recalculate(head)
+
+		assert.Equal(t, float64(120), promtestutil.ToFloat64(gas.PromGasEstimatorBaseFee.WithLabelValues(labels...)))
+		assert.Equal(t, float64(gas.GetGasPrice(bhe).Int64()), promtestutil.ToFloat64(gas.PromGasEstimatorSuggestedGasPrice.WithLabelValues(labels...)))
+		assert.Equal(t, float64(gas.GetTipCap(bhe).Int64()), promtestutil.ToFloat64(gas.PromGasEstimatorSuggestedTipCap.WithLabelValues(labels...)))
+	})
+
+	t.Run("bumps and max price capping are counted", func(t *testing.T) {
+		bumps := gas.PromGasEstimatorBumpCount.WithLabelValues(append(labels, "legacy")...)
+		capped := gas.PromGasEstimatorMaxPriceCappedCount.WithLabelValues(labels...)
+		cappedBefore := promtestutil.ToFloat64(capped)
+
+		_, _, err := bhe.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(42), 100000, assets.NewWeiI(1000), nil)
+		require.NoError(t, err)
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(bumps))
+
+		_, _, err = bhe.BumpLegacyGas(testutils.Context(t), assets.NewWeiI(990), 100000, assets.NewWeiI(1000), nil)
+		require.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(bumps))
+		assert.Equal(t, cappedBefore+1, promtestutil.ToFloat64(capped))
+	})
+}
@@ -3,10 +3,16 @@
 package mocks
 
 import (
+	common "github.com/ethereum/go-ethereum/common"
 	assets "github.com/smartcontractkit/chainlink/v2/core/assets"
+
 	config "github.com/smartcontractkit/chainlink/v2/core/config"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
+
+	url "net/url"
 )
 
 // Config is an autogenerated mock type for the Config type
@@ -14,6 +20,20 @@ type Config struct {
 	mock.Mock
 }
 
+// BlockHistoryEstimatorBaseFeeLookaheadBlocks provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorBaseFeeLookaheadBlocks() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
 // BlockHistoryEstimatorBatchSize provides a mock function with given fields:
 func (_m *Config) BlockHistoryEstimatorBatchSize() uint32 {
 	ret := _m.Called()
@@ -98,6 +118,108 @@ func (_m *Config) BlockHistoryEstimatorEIP1559FeeCapBufferBlocks() uint16 {
 	return r0
 }
 
+// BlockHistoryEstimatorExcludedTxTypes provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorExcludedTxTypes() []uint8 {
+	ret := _m.Called()
+
+	var r0 []uint8
+	if rf, ok := ret.Get(0).(func() []uint8); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint8)
+		}
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorHistoryDuration provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorHistoryDuration() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorHistorySizeMax provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorHistorySizeMax() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorHistorySizeMin provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorHistorySizeMin() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorInclusionPercentiles provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorInclusionPercentiles() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorMaxReorgDepth provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorMaxReorgDepth() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// BlockHistoryEstimatorTipCapTrimPercentile provides a mock function with given fields:
+func (_m *Config) BlockHistoryEstimatorTipCapTrimPercentile() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
 // BlockHistoryEstimatorTransactionPercentile provides a mock function with given fields:
 func (_m *Config) BlockHistoryEstimatorTransactionPercentile() uint16 {
 	ret := _m.Called()
@@ -154,6 +276,50 @@ func (_m *Config) EvmFinalityDepth() uint32 {
 	return r0
 }
 
+// EvmGasBaseFeeBufferMultiplier provides a mock function with given fields:
+func (_m *Config) EvmGasBaseFeeBufferMultiplier() float32 {
+	ret := _m.Called()
+
+	var r0 float32
+	if rf, ok := ret.Get(0).(func() float32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(float32)
+	}
+
+	return r0
+}
+
+// EvmGasBatchTipIncrement provides a mock function with given fields:
+func (_m *Config) EvmGasBatchTipIncrement() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasBumpFeeCapFromBaseFee provides a mock function with given fields:
+func (_m *Config) EvmGasBumpFeeCapFromBaseFee() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
 // EvmGasBumpPercent provides a mock function with given fields:
 func (_m *Config) EvmGasBumpPercent() uint16 {
 	ret := _m.Called()
@@ -168,6 +334,20 @@ func (_m *Config) EvmGasBumpPercent() uint16 {
 	return r0
 }
 
+// EvmGasBumpStrategy provides a mock function with given fields:
+func (_m *Config) EvmGasBumpStrategy() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
 // EvmGasBumpThreshold provides a mock function with given fields:
 func (_m *Config) EvmGasBumpThreshold() uint64 {
 	ret := _m.Called()
@@ -198,24 +378,24 @@ func (_m *Config) EvmGasBumpWei() *assets.Wei {
 	return r0
 }
 
-// EvmGasFeeCapDefault provides a mock function with given fields:
-func (_m *Config) EvmGasFeeCapDefault() *assets.Wei {
+// EvmGasDecisionLogAlways provides a mock function with given fields:
+func (_m *Config) EvmGasDecisionLogAlways() []string {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
 	return r0
 }
 
-// EvmGasLimitMax provides a mock function with given fields:
-func (_m *Config) EvmGasLimitMax() uint32 {
+// EvmGasDecisionLogSampleRate provides a mock function with given fields:
+func (_m *Config) EvmGasDecisionLogSampleRate() uint32 {
 	ret := _m.Called()
 
 	var r0 uint32
@@ -228,8 +408,64 @@ func (_m *Config) EvmGasLimitMax() uint32 {
 	return r0
 }
 
-// EvmGasLimitMultiplier provides a mock function with given fields:
-func (_m *Config) EvmGasLimitMultiplier() float32 {
+// EvmGasDrainTimeout provides a mock function with given fields:
+func (_m *Config) EvmGasDrainTimeout() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasEstimateAccessList provides a mock function with given fields:
+func (_m *Config) EvmGasEstimateAccessList() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasEstimateGasLimit provides a mock function with given fields:
+func (_m *Config) EvmGasEstimateGasLimit() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasFeeAnomalyCooldown provides a mock function with given fields:
+func (_m *Config) EvmGasFeeAnomalyCooldown() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasFeeAnomalyFactor provides a mock function with given fields:
+func (_m *Config) EvmGasFeeAnomalyFactor() float32 {
 	ret := _m.Called()
 
 	var r0 float32
@@ -242,24 +478,36 @@ func (_m *Config) EvmGasLimitMultiplier() float32 {
 	return r0
 }
 
-// EvmGasPriceDefault provides a mock function with given fields:
-func (_m *Config) EvmGasPriceDefault() *assets.Wei {
+// EvmGasFeeAnomalyHalfLife provides a mock function with given fields:
+func (_m *Config) EvmGasFeeAnomalyHalfLife() time.Duration {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
-		}
+		r0 = ret.Get(0).(time.Duration)
 	}
 
 	return r0
 }
 
-// EvmGasTipCapDefault provides a mock function with given fields:
-func (_m *Config) EvmGasTipCapDefault() *assets.Wei {
+// EvmGasFeeCacheTTL provides a mock function with given fields:
+func (_m *Config) EvmGasFeeCacheTTL() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasFeeCapDefault provides a mock function with given fields:
+func (_m *Config) EvmGasFeeCapDefault() *assets.Wei {
 	ret := _m.Called()
 
 	var r0 *assets.Wei
@@ -274,24 +522,24 @@ func (_m *Config) EvmGasTipCapDefault() *assets.Wei {
 	return r0
 }
 
-// EvmGasTipCapMinimum provides a mock function with given fields:
-func (_m *Config) EvmGasTipCapMinimum() *assets.Wei {
+// EvmGasFeeCurrency provides a mock function with given fields:
+func (_m *Config) EvmGasFeeCurrency() *common.Address {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 *common.Address
+	if rf, ok := ret.Get(0).(func() *common.Address); ok {
 		r0 = rf()
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
+			r0 = ret.Get(0).(*common.Address)
 		}
 	}
 
 	return r0
 }
 
-// EvmMaxGasPriceWei provides a mock function with given fields:
-func (_m *Config) EvmMaxGasPriceWei() *assets.Wei {
+// EvmGasFeeRounding provides a mock function with given fields:
+func (_m *Config) EvmGasFeeRounding() *assets.Wei {
 	ret := _m.Called()
 
 	var r0 *assets.Wei
@@ -306,16 +554,438 @@ func (_m *Config) EvmMaxGasPriceWei() *assets.Wei {
 	return r0
 }
 
-// EvmMinGasPriceWei provides a mock function with given fields:
-func (_m *Config) EvmMinGasPriceWei() *assets.Wei {
+// EvmGasLimitMax provides a mock function with given fields:
+func (_m *Config) EvmGasLimitMax() uint32 {
 	ret := _m.Called()
 
-	var r0 *assets.Wei
-	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*assets.Wei)
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasLimitMin provides a mock function with given fields:
+func (_m *Config) EvmGasLimitMin() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasLimitMultiplier provides a mock function with given fields:
+func (_m *Config) EvmGasLimitMultiplier() float32 {
+	ret := _m.Called()
+
+	var r0 float32
+	if rf, ok := ret.Get(0).(func() float32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(float32)
+	}
+
+	return r0
+}
+
+// EvmGasMaxTxCost provides a mock function with given fields:
+func (_m *Config) EvmGasMaxTxCost() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasNodeMinPriceMethod provides a mock function with given fields:
+func (_m *Config) EvmGasNodeMinPriceMethod() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// EvmGasNodeMinPriceSync provides a mock function with given fields:
+func (_m *Config) EvmGasNodeMinPriceSync() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasPriceDefault provides a mock function with given fields:
+func (_m *Config) EvmGasPriceDefault() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasPriceStaleThreshold provides a mock function with given fields:
+func (_m *Config) EvmGasPriceStaleThreshold() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasPriceUpdateThreshold provides a mock function with given fields:
+func (_m *Config) EvmGasPriceUpdateThreshold() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// EvmGasRPCCallTimeout provides a mock function with given fields:
+func (_m *Config) EvmGasRPCCallTimeout() time.Duration {
+	ret := _m.Called()
+
+	var r0 time.Duration
+	if rf, ok := ret.Get(0).(func() time.Duration); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(time.Duration)
+	}
+
+	return r0
+}
+
+// EvmGasRPCRateLimit provides a mock function with given fields:
+func (_m *Config) EvmGasRPCRateLimit() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasRPCRateLimitBurst provides a mock function with given fields:
+func (_m *Config) EvmGasRPCRateLimitBurst() uint32 {
+	ret := _m.Called()
+
+	var r0 uint32
+	if rf, ok := ret.Get(0).(func() uint32); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint32)
+	}
+
+	return r0
+}
+
+// EvmGasSimulateBeforeBump provides a mock function with given fields:
+func (_m *Config) EvmGasSimulateBeforeBump() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasSuggestedPriceConnectivityCheck provides a mock function with given fields:
+func (_m *Config) EvmGasSuggestedPriceConnectivityCheck() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// EvmGasTipCapDefault provides a mock function with given fields:
+func (_m *Config) EvmGasTipCapDefault() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmGasTipCapMinimum provides a mock function with given fields:
+func (_m *Config) EvmGasTipCapMinimum() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmMaxBlobGasPriceWei provides a mock function with given fields:
+func (_m *Config) EvmMaxBlobGasPriceWei() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmMaxGasPriceWei provides a mock function with given fields:
+func (_m *Config) EvmMaxGasPriceWei() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// EvmMinGasPriceWei provides a mock function with given fields:
+func (_m *Config) EvmMinGasPriceWei() *assets.Wei {
+	ret := _m.Called()
+
+	var r0 *assets.Wei
+	if rf, ok := ret.Get(0).(func() *assets.Wei); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*assets.Wei)
+		}
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorBaseFeePath provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorBaseFeePath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorFastPath provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorFastPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorPricesIncludeBaseFee provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorPricesIncludeBaseFee() bool {
+	ret := _m.Called()
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func() bool); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorSafeLowPath provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorSafeLowPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorSpeed provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorSpeed() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorStandardPath provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorStandardPath() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorURL provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorURL() *url.URL {
+	ret := _m.Called()
+
+	var r0 *url.URL
+	if rf, ok := ret.Get(0).(func() *url.URL); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*url.URL)
+		}
+	}
+
+	return r0
+}
+
+// ExternalAPIEstimatorUnit provides a mock function with given fields:
+func (_m *Config) ExternalAPIEstimatorUnit() string {
+	ret := _m.Called()
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func() string); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	return r0
+}
+
+// FeeHistoryEstimatorBlockCount provides a mock function with given fields:
+func (_m *Config) FeeHistoryEstimatorBlockCount() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// FeeHistoryEstimatorRewardPercentile provides a mock function with given fields:
+func (_m *Config) FeeHistoryEstimatorRewardPercentile() uint16 {
+	ret := _m.Called()
+
+	var r0 uint16
+	if rf, ok := ret.Get(0).(func() uint16); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint16)
+	}
+
+	return r0
+}
+
+// GasEstimatorFallbackModes provides a mock function with given fields:
+func (_m *Config) GasEstimatorFallbackModes() []string {
+	ret := _m.Called()
+
+	var r0 []string
+	if rf, ok := ret.Get(0).(func() []string); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
 		}
 	}
 
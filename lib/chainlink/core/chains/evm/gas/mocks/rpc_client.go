@@ -6,6 +6,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	rpc "github.com/ethereum/go-ethereum/rpc"
 )
 
 // RPCClient is an autogenerated mock type for the rpcClient type
@@ -13,6 +15,20 @@ type RPCClient struct {
 	mock.Mock
 }
 
+// BatchCallContext provides a mock function with given fields: ctx, b
+func (_m *RPCClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	ret := _m.Called(ctx, b)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []rpc.BatchElem) error); ok {
+		r0 = rf(ctx, b)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // CallContext provides a mock function with given fields: ctx, result, method, args
 func (_m *RPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
 	var _ca []interface{}
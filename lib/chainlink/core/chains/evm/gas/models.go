@@ -4,10 +4,19 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"net/url"
+	"sync/atomic"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+	"go.uber.org/multierr"
+	"golang.org/x/exp/slices"
 
 	commonfee "github.com/smartcontractkit/chainlink/v2/common/fee"
 	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
@@ -27,13 +36,24 @@ var (
 )
 
 func IsBumpErr(err error) bool {
-	return err != nil && (errors.Is(err, ErrBumpGasExceedsLimit) || errors.Is(err, ErrBump) || errors.Is(err, ErrConnectivity))
+	return err != nil && (errors.Is(err, ErrBumpGasExceedsLimit) || errors.Is(err, ErrBump) || errors.Is(err, ErrConnectivity) || errors.Is(err, ErrWouldRevert) || errors.Is(err, ErrTxCostExceedsBudget) || errors.Is(err, ErrInsufficientBalance))
 }
 
 type EvmFeeEstimator txmgrtypes.FeeEstimator[*evmtypes.Head, EvmFee, *assets.Wei, common.Hash]
 
-// NewEstimator returns the estimator for a given config
-func NewEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config) EvmFeeEstimator {
+// NewEstimator returns the estimator for a given config. The store persists
+// the block history of the BlockHistory estimator and may be nil. The RPC
+// calls of the estimator are bound by EVM.GasEstimator.RPCCallTimeout and
+// RPCRateLimit. On OP-stack chains the estimator also estimates the L1 data
+// fee of transactions, see WrappedEvmEstimator.GetTotalFee.
+func NewEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config, store BlockHistoryStore) EvmFeeEstimator {
+	// the timeout applies to each attempt, not to the wait for the rate limiter
+	if timeout := cfg.EvmGasRPCCallTimeout(); timeout > 0 {
+		ethClient = newTimeoutClient(ethClient, timeout)
+	}
+	if cfg.EvmGasRPCRateLimit() > 0 {
+		ethClient = newRateLimitedClient(ethClient, rpcLimiterFor(ethClient.ConfiguredChainID(), cfg))
+	}
 
 	s := cfg.GasEstimatorMode()
 	lggr.Infow(fmt.Sprintf("Initializing EVM gas estimator in mode: %s", s),
@@ -45,6 +65,7 @@ func NewEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config) Ev
 		"transactionPercentile", cfg.BlockHistoryEstimatorTransactionPercentile(),
 		"eip1559DynamicFees", cfg.EvmEIP1559DynamicFees(),
 		"gasBumpPercent", cfg.EvmGasBumpPercent(),
+		"gasBumpStrategy", cfg.EvmGasBumpStrategy(),
 		"gasBumpThreshold", cfg.EvmGasBumpThreshold(),
 		"gasBumpWei", cfg.EvmGasBumpWei(),
 		"feeCapDefault", cfg.EvmGasFeeCapDefault(),
@@ -54,26 +75,92 @@ func NewEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config) Ev
 		"gasTipCapMinimum", cfg.EvmGasTipCapMinimum(),
 		"maxGasPriceWei", cfg.EvmMaxGasPriceWei(),
 		"minGasPriceWei", cfg.EvmMinGasPriceWei(),
+		"rpcRateLimit", cfg.EvmGasRPCRateLimit(),
+		"rpcRateLimitBurst", cfg.EvmGasRPCRateLimitBurst(),
+		"rpcCallTimeout", cfg.EvmGasRPCCallTimeout(),
 	)
+	wrapped := NewWrappedEvmEstimator(lggr, newEvmEstimator(lggr, ethClient, cfg, store), cfg, ethClient).(*WrappedEvmEstimator)
+	if wrapped.decisionLog != nil {
+		wrapped.chainID = ethClient.ConfiguredChainID()
+	}
+	if cfg.ChainType() == config.ChainOptimismBedrock {
+		wrapped.l1Oracle = NewOptimismL1Oracle(lggr, ethClient, defaultOptimismL1OracleRefreshInterval)
+	}
+	if cfg.EvmGasFeeAnomalyFactor() > 0 {
+		wrapped.SetAnomalyHook(NewLoggingAnomalyHook(lggr, ethClient.ConfiguredChainID()))
+	}
+	if wrapped.EIP1559Enabled {
+		wrapped.dynamicFeeSupport = newDynamicFeeSupport(lggr, ethClient, ethClient.ConfiguredChainID(), s)
+	}
+	return wrapped
+}
+
+func newEvmEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config, store BlockHistoryStore) EvmEstimator {
+	if cfg.GasEstimatorMode() == "Fallback" {
+		var estimators []EvmEstimator
+		for _, s := range cfg.GasEstimatorFallbackModes() {
+			if s == "Fallback" {
+				lggr.Warn("GasEstimator: Fallback mode can't fall back to itself, skipping")
+				continue
+			}
+			estimators = append(estimators, newModeEstimator(lggr, ethClient, cfg, store, s))
+		}
+		return NewFallbackEstimator(lggr, estimators...)
+	}
+	return newModeEstimator(lggr, ethClient, cfg, store, cfg.GasEstimatorMode())
+}
+
+func newModeEstimator(lggr logger.Logger, ethClient evmclient.Client, cfg Config, store BlockHistoryStore, s string) EvmEstimator {
+	e := newModeEstimatorOf(lggr, ethClient, cfg, store, s)
+	if cfg.EvmGasNodeMinPriceSync() {
+		if u, ok := e.(nodeMinPriceUser); ok {
+			u.useNodeMinPrice(newNodeMinPrice(lggr, ethClient, cfg))
+		} else {
+			lggr.Warnf("GasEstimator: NodeMinPriceSync is not supported by mode '%s', ignoring", s)
+		}
+	}
+	return e
+}
+
+func newModeEstimatorOf(lggr logger.Logger, ethClient evmclient.Client, cfg Config, store BlockHistoryStore, s string) EvmEstimator {
+	if factory, ok := lookupEstimator(s); ok {
+		return factory(lggr, ethClient, cfg)
+	}
 	switch s {
 	case "Arbitrum":
-		return NewWrappedEvmEstimator(NewArbitrumEstimator(lggr, cfg, ethClient, ethClient), cfg)
+		return NewArbitrumEstimator(lggr, cfg, ethClient, ethClient, *ethClient.ConfiguredChainID())
 	case "BlockHistory":
-		return NewWrappedEvmEstimator(NewBlockHistoryEstimator(lggr, ethClient, cfg, *ethClient.ConfiguredChainID()), cfg)
+		return NewBlockHistoryEstimator(lggr, ethClient, cfg, *ethClient.ConfiguredChainID(), store)
+	case "Celo":
+		return NewCeloEstimator(lggr, cfg, ethClient, *ethClient.ConfiguredChainID())
+	case "ExternalAPI":
+		return NewExternalAPIEstimator(lggr, cfg, *ethClient.ConfiguredChainID())
+	case "FeeHistory":
+		return NewFeeHistoryEstimator(lggr, ethClient, cfg, *ethClient.ConfiguredChainID())
 	case "FixedPrice":
-		return NewWrappedEvmEstimator(NewFixedPriceEstimator(cfg, lggr), cfg)
+		return NewFixedPriceEstimator(cfg, lggr)
 	case "Optimism2", "L2Suggested":
-		return NewWrappedEvmEstimator(NewL2SuggestedPriceEstimator(lggr, ethClient), cfg)
+		return NewL2SuggestedPriceEstimator(lggr, cfg, ethClient, *ethClient.ConfiguredChainID())
+	case "ZkSync":
+		return NewZkSyncEstimator(lggr, cfg, ethClient, *ethClient.ConfiguredChainID())
 	default:
 		lggr.Warnf("GasEstimator: unrecognised mode '%s', falling back to FixedPriceEstimator", s)
-		return NewWrappedEvmEstimator(NewFixedPriceEstimator(cfg, lggr), cfg)
+		return NewFixedPriceEstimator(cfg, lggr)
 	}
 }
 
 // DynamicFee encompasses both FeeCap and TipCap for EIP1559 transactions
+// BlobFeeCap is only set for EIP-4844 blob transactions
+// GasPerPubdataLimit is only set for zkSync transactions
+// InclusionBlocks is only set if the fee was priced for the target of WithInclusionBlocks
+// FeeCurrency is only set if the fee is priced in a fee currency, e.g. on Celo
 type DynamicFee struct {
-	FeeCap *assets.Wei
-	TipCap *assets.Wei
+	FeeCap             *assets.Wei
+	TipCap             *assets.Wei
+	BlobFeeCap         *assets.Wei
+	GasPerPubdataLimit *big.Int
+	InclusionBlocks    uint32
+	FeeCurrency        *common.Address
 }
 
 type EvmPriorAttempt interface {
@@ -93,8 +180,11 @@ func (e evmPriorAttempt) GetGasPrice() *assets.Wei {
 
 func (e evmPriorAttempt) DynamicFee() DynamicFee {
 	return DynamicFee{
-		FeeCap: e.Fee().DynamicFeeCap,
-		TipCap: e.Fee().DynamicTipCap,
+		FeeCap:             e.Fee().DynamicFeeCap,
+		TipCap:             e.Fee().DynamicTipCap,
+		BlobFeeCap:         e.Fee().BlobFeeCap,
+		GasPerPubdataLimit: e.Fee().GasPerPubdataLimit,
+		FeeCurrency:        e.Fee().FeeCurrency,
 	}
 }
 
@@ -146,9 +236,57 @@ type EvmFee struct {
 	// dynamic/EIP1559 fees
 	DynamicFeeCap *assets.Wei
 	DynamicTipCap *assets.Wei
+
+	// blob/EIP4844 fees, only set for blob transactions
+	BlobFeeCap *assets.Wei
+
+	// zkSync fees, only set by the ZkSync estimator, to be used as the
+	// gas_per_pubdata_limit of the EIP-712 transaction
+	GasPerPubdataLimit *big.Int
+
+	// FeeCurrency is the address of the currency that the fees are priced in,
+	// only set by estimators of chains with fee currencies, e.g. the Celo
+	// estimator with EVM.GasEstimator.FeeCurrency. The transaction must pay its
+	// fees in it. Nil means the native currency.
+	FeeCurrency *common.Address
+
+	// AccessList is the EIP-2930 access list that the fee limit was estimated
+	// with, only set for dynamic fees with EVM.GasEstimator.EstimateAccessList.
+	// The transaction must be sent with it.
+	AccessList types.AccessList
+
+	// InclusionBlocks is the inclusion target of WithInclusionBlocks the
+	// dynamic fee was priced for, or 0 if it wasn't priced for a target. The
+	// caller may bump tight deadlines sooner.
+	InclusionBlocks uint32
+
+	// ValidUntilBlock is the last block the fee is expected to be included
+	// in, given the recent base fee volatility, or 0 if unknown. See IsStale.
+	ValidUntilBlock int64
+	// ValidUntil is the time after which the estimator expects the fee to be
+	// outdated, or the zero time if unknown. See IsStale.
+	ValidUntil time.Time
+
+	// LowBalanceAdjusted is set if the fee was lowered to fit the balance
+	// given with WithAvailableBalance, see WithAvailableBalance
+	LowBalanceAdjusted bool
+	// ConvertedCost is the cost of the transaction, the fee cap or gas price
+	// times the fee limit, in the currency of the converter set with
+	// WrappedEvmEstimator.SetPriceConverter, or nil if there is none or the
+	// conversion failed
+	ConvertedCost *decimal.Decimal
 }
 
 func (fee EvmFee) String() string {
+	if fee.FeeCurrency != nil {
+		return fmt.Sprintf("{Legacy: %s, DynamicFeeCap: %s, DynamicTipCap: %s, FeeCurrency: %s}", fee.Legacy, fee.DynamicFeeCap, fee.DynamicTipCap, fee.FeeCurrency)
+	}
+	if fee.GasPerPubdataLimit != nil {
+		return fmt.Sprintf("{Legacy: %s, DynamicFeeCap: %s, DynamicTipCap: %s, GasPerPubdataLimit: %s}", fee.Legacy, fee.DynamicFeeCap, fee.DynamicTipCap, fee.GasPerPubdataLimit)
+	}
+	if fee.BlobFeeCap != nil {
+		return fmt.Sprintf("{Legacy: %s, DynamicFeeCap: %s, DynamicTipCap: %s, BlobFeeCap: %s}", fee.Legacy, fee.DynamicFeeCap, fee.DynamicTipCap, fee.BlobFeeCap)
+	}
 	return fmt.Sprintf("{Legacy: %s, DynamicFeeCap: %s, DynamicTipCap: %s}", fee.Legacy, fee.DynamicFeeCap, fee.DynamicTipCap)
 }
 
@@ -159,87 +297,456 @@ func (fee EvmFee) ValidDynamic() bool {
 // WrappedEvmEstimator provides a struct that wraps the EVM specific dynamic and legacy estimators into one estimator that conforms to the generic FeeEstimator
 type WrappedEvmEstimator struct {
 	EvmEstimator
-	EIP1559Enabled bool
+	EIP1559Enabled   bool
+	EstimateGasLimit bool
+	cfg              Config
+	cache            *feeCache
+	client           rpcClient
+	// accessLists is only set with EVM.GasEstimator.EstimateAccessList
+	accessLists *AccessListEstimator
+	// l1Oracle is only set on chains that charge an L1 data fee
+	l1Oracle L1Oracle
+	// Profiles are the fee profiles that can be selected with WithProfile
+	Profiles map[string]FeeProfile
+	// simulateTimeout bounds the eth_call of EVM.GasEstimator.SimulateBeforeBump
+	simulateTimeout time.Duration
+	// latestHead is the last head passed to OnNewLongestChain
+	latestHead *atomic.Pointer[evmtypes.Head]
+	// anomalies is only set with EVM.GasEstimator.FeeAnomalyFactor
+	anomalies *feeAnomalyDetector
+	// dynamicFeeSupport is only set by NewEstimator with
+	// EVM.GasEstimator.EIP1559DynamicFees
+	dynamicFeeSupport *dynamicFeeSupport
+	// decisionLog is nil unless EVM.GasEstimator.DecisionLogSampleRate or
+	// DecisionLogAlways is set
+	decisionLog *decisionLogSampler
+	// chainID is only set by NewEstimator, for the decision log
+	chainID *big.Int
+	// priceConverter is only set with SetPriceConverter
+	priceConverter    PriceConverter
+	conversionRate    prometheus.Gauge
+	conversionTimeout time.Duration
+	// calls are the in-flight estimations, drained by Close
+	calls *inFlight
+	lggr  logger.Logger
 }
 
 var _ EvmFeeEstimator = (*WrappedEvmEstimator)(nil)
 
-func NewWrappedEvmEstimator(e EvmEstimator, cfg Config) EvmFeeEstimator {
+// callSpecificEstimator is implemented by estimators whose fee depends on the
+// call given with WithEstimateGasCall, so their fees must not be shared
+// between calls
+type callSpecificEstimator interface {
+	callSpecific()
+}
+
+// NewWrappedEvmEstimator wraps e into an EvmFeeEstimator. The client is only
+// used to estimate gas limits with EVM.GasEstimator.EstimateGasLimit, and may be
+// nil otherwise.
+func NewWrappedEvmEstimator(lggr logger.Logger, e EvmEstimator, cfg Config, client rpcClient) EvmFeeEstimator {
+	var cache *feeCache
+	if _, ok := e.(callSpecificEstimator); !ok {
+		if ttl := cfg.EvmGasFeeCacheTTL(); ttl > 0 {
+			cache = newFeeCache(ttl, cfg.EvmGasLimitMultiplier())
+		}
+	}
+	estimateGasLimit := cfg.EvmGasEstimateGasLimit()
+	var accessLists *AccessListEstimator
+	if estimateGasLimit && client != nil && cfg.EvmGasEstimateAccessList() {
+		accessLists = NewAccessListEstimator(lggr, client)
+	}
 	return &WrappedEvmEstimator{
-		EvmEstimator:   e,
-		EIP1559Enabled: cfg.EvmEIP1559DynamicFees(),
+		EvmEstimator:      e,
+		EIP1559Enabled:    cfg.EvmEIP1559DynamicFees(),
+		EstimateGasLimit:  estimateGasLimit,
+		cfg:               cfg,
+		cache:             cache,
+		client:            client,
+		accessLists:       accessLists,
+		simulateTimeout:   defaultSimulateBeforeBumpTimeout,
+		conversionTimeout: defaultPriceConversionTimeout,
+		latestHead:        new(atomic.Pointer[evmtypes.Head]),
+		decisionLog:       newDecisionLogSampler(cfg),
+		calls:             new(inFlight),
+		lggr:              lggr.Named("WrappedEvmEstimator"),
+	}
+}
+
+// Start starts the estimator, after checking whether the chain supports
+// dynamic fees with EVM.GasEstimator.EIP1559DynamicFees
+func (e WrappedEvmEstimator) Start(ctx context.Context) error {
+	e.dynamicFeeSupport.start(ctx)
+	return e.EvmEstimator.Start(ctx)
+}
+
+// Close stops checking whether the chain supports dynamic fees, then waits up to
+// EVM.GasEstimator.DrainTimeout for in-flight estimations to return before
+// closing the estimator, which drains its own in-flight estimations likewise.
+// Estimations made once Close has begun fail with ErrEstimatorStopped.
+func (e WrappedEvmEstimator) Close() error {
+	e.calls.stop()
+	e.dynamicFeeSupport.close()
+	err := e.calls.drain(e.cfg.EvmGasDrainTimeout())
+	return multierr.Combine(err, e.EvmEstimator.Close())
+}
+
+// OnNewLongestChain passes the head to the estimator and then invalidates the
+// cached fees, which may have been estimated against the previous base fee
+func (e WrappedEvmEstimator) OnNewLongestChain(ctx context.Context, head *evmtypes.Head) {
+	e.EvmEstimator.OnNewLongestChain(ctx, head)
+	if e.latestHead != nil {
+		e.latestHead.Store(head)
 	}
+	e.cache.invalidate()
 }
 
+// LatestHead returns the last head the estimator was given, i.e. the head its
+// current fees are estimated against, or nil if it hasn't been given one yet
+func (e WrappedEvmEstimator) LatestHead() *evmtypes.Head {
+	if e.latestHead == nil {
+		return nil
+	}
+	return e.latestHead.Load()
+}
+
+// SetAnomalyHook sets the hook called when an estimated fee exceeds the
+// moving average of recent fees by EVM.GasEstimator.FeeAnomalyFactor, which
+// for the estimators returned by NewEstimator logs the fee. It has no effect
+// if the factor is 0, and must be called before the estimator is used.
+func (e *WrappedEvmEstimator) SetAnomalyHook(hook AnomalyHook) {
+	if e.cfg.EvmGasFeeAnomalyFactor() <= 0 {
+		return
+	}
+	e.anomalies = newFeeAnomalyDetector(e.cfg, hook)
+}
+
+// Mode returns the estimator mode, as in EVM.GasEstimator.Mode
+func (e WrappedEvmEstimator) Mode() string {
+	return e.cfg.GasEstimatorMode()
+}
+
+// trackFee tells the estimator the fee returned for the one it estimated, see
+// feeOriginTracker
+func (e WrappedEvmEstimator) trackFee(estimated, returned EvmFee) {
+	if t, ok := e.EvmEstimator.(feeOriginTracker); ok {
+		t.trackFee(estimated, returned)
+	}
+}
+
+// feeCurrency returns the fee currency of the estimator, or nil if it prices
+// the fees in the native currency
+func (e WrappedEvmEstimator) feeCurrency() *common.Address {
+	if fc, ok := e.EvmEstimator.(FeeCurrencyEstimator); ok {
+		return fc.FeeCurrency()
+	}
+	return nil
+}
+
+// GetFee returns the fee for a new transaction.
+// maxFeePrice is an optional per-call ceiling (e.g. the max gas price of the
+// sending key); the estimator is given the lower of it and EVM.GasEstimator.PriceMax
+//
+// Concurrent calls with the same fee limit and max price share one estimation,
+// which is reused for EVM.GasEstimator.FeeCacheTTL or until the next head. The
+// fee profile selected with WithProfile is layered over the chain's fee config.
+// With EVM.GasEstimator.EstimateGasLimit enabled, the fee limit is estimated
+// for the call given with WithEstimateGasCall, see estimateGasLimit.
+//
+// The estimated fee then goes through the steps of finishFee, which may lower
+// it or fail, e.g. with ErrTxCostExceedsBudget. While the chain doesn't support
+// EIP-1559, dynamic fees fail with ErrDynamicFeesNotSupported.
 func (e WrappedEvmEstimator) GetFee(ctx context.Context, calldata []byte, feeLimit uint32, maxFeePrice *assets.Wei, opts ...txmgrtypes.Opt) (fee EvmFee, chainSpecificFeeLimit uint32, err error) {
+	if !e.calls.enter() {
+		return fee, 0, errStopped("WrappedEvmEstimator")
+	}
+	defer e.calls.exit()
+	if call, ok := estimateGasCallFromContext(ctx); ok && call.Data == nil {
+		call.Data = calldata
+		ctx = WithEstimateGasCall(ctx, call)
+	}
+	d := decision{op: "GetFee", feeLimit: feeLimit, maxFeePrice: maxFeePrice}
+	ctx, profileName, profile, maxFeePrice := e.resolveFeeConfig(ctx, maxFeePrice)
+	fee, chainSpecificFeeLimit, err = e.getFee(ctx, profileName, calldata, feeLimit, maxFeePrice, opts...)
+	if err != nil {
+		return
+	}
+	chainSpecificFeeLimit = e.profileFeeLimit(profile, chainSpecificFeeLimit)
+	if e.EstimateGasLimit {
+		if gasLimit, accessList, ok := e.estimateGasLimit(ctx, fee.ValidDynamic()); ok {
+			chainSpecificFeeLimit = gasLimit
+			fee.AccessList = accessList
+		}
+	}
+	fee, err = e.finishFee(ctx, d, fee, chainSpecificFeeLimit, maxFeePrice)
+	return
+}
+
+// finishFee applies the steps that follow the estimator to an estimated fee,
+// for GetFee and each fee of GetDynamicFees alike, in this order:
+//
+//   - With EVM.GasEstimator.MaxTxCost set, the fee is lowered so that the
+//     transaction costs no more than it, see applyTxCostBudget.
+//   - With WithAvailableBalance, it is likewise lowered to fit the balance of
+//     the sending key, see applyAvailableBalance.
+//   - With EVM.GasEstimator.FeeRounding set, the prices are rounded, as the
+//     last step that changes them, see roundFee.
+//   - The fee records how long it is expected to remain valid, see IsStale.
+//   - With EVM.GasEstimator.FeeAnomalyFactor set, fees far above the moving
+//     average of recent fees are reported to the hook set with SetAnomalyHook.
+//   - With a converter set with SetPriceConverter, the fee includes the
+//     converted cost of the transaction, see EvmFee.ConvertedCost.
+//
+// It then logs the decision d, as sampled by
+// EVM.GasEstimator.DecisionLogSampleRate and DecisionLogAlways. ExplainFee
+// explains the steps that change the prices in the same order.
+func (e WrappedEvmEstimator) finishFee(ctx context.Context, d decision, fee EvmFee, chainSpecificFeeLimit uint32, maxFeePrice *assets.Wei) (EvmFee, error) {
+	estimated := fee
+	fee, err := e.applyTxCostBudget(fee, chainSpecificFeeLimit)
+	if err != nil {
+		return fee, err
+	}
+	if fee, err = e.applyAvailableBalance(ctx, fee, chainSpecificFeeLimit); err != nil {
+		return fee, err
+	}
+	fee = e.roundFee(fee, maxFeePrice, e.maxCostPrice(ctx, chainSpecificFeeLimit))
+	e.trackFee(estimated, fee)
+	fee = e.withValidity(fee)
+	if e.anomalies != nil {
+		d.anomaly = e.anomalies.observe(ctx, fee)
+	}
+	fee = e.withConvertedCost(ctx, fee, chainSpecificFeeLimit)
+	d.effectiveMaxFeePrice, d.fee, d.chainSpecificFeeLimit = maxFeePrice, fee, chainSpecificFeeLimit
+	e.logDecision(d)
+	return fee, nil
+}
+
+// getFee returns the estimator's fee for the given effective max fee price
+func (e WrappedEvmEstimator) getFee(ctx context.Context, profileName string, calldata []byte, feeLimit uint32, maxFeePrice *assets.Wei, opts ...txmgrtypes.Opt) (fee EvmFee, chainSpecificFeeLimit uint32, err error) {
 	// get dynamic fee
 	if e.EIP1559Enabled {
-		var dynamicFee DynamicFee
-		dynamicFee, chainSpecificFeeLimit, err = e.EvmEstimator.GetDynamicFee(ctx, feeLimit, maxFeePrice)
-		fee.DynamicFeeCap = dynamicFee.FeeCap
-		fee.DynamicTipCap = dynamicFee.TipCap
+		var fees []DynamicFee
+		fees, chainSpecificFeeLimit, err = e.getDynamicFees(ctx, profileName, 1, feeLimit, maxFeePrice)
+		if err != nil {
+			return
+		}
+		fee = dynamicEvmFee(fees[0])
+		if !slices.Contains(opts, txmgrtypes.OptBlobTx) {
+			return
+		}
+		// get blob fee
+		blobEstimator, ok := e.EvmEstimator.(BlobFeeEstimator)
+		if !ok {
+			err = errors.Errorf("estimator %s does not support blob fee estimation", e.EvmEstimator.Name())
+			return
+		}
+		fee.BlobFeeCap, err = blobEstimator.GetBlobFee(ctx)
+		return
+	}
+
+	if slices.Contains(opts, txmgrtypes.OptBlobTx) {
+		err = errors.New("blob transactions require EIP1559 dynamic fees to be enabled")
 		return
 	}
 
-	// get legacy fee
-	fee.Legacy, chainSpecificFeeLimit, err = e.EvmEstimator.GetLegacyGas(ctx, calldata, feeLimit, maxFeePrice, opts...)
+	// get legacy fee, options such as OptForceRefetch bypass the cache
+	if len(opts) > 0 {
+		fee.Legacy, chainSpecificFeeLimit, err = e.EvmEstimator.GetLegacyGas(ctx, calldata, feeLimit, maxFeePrice, opts...)
+		fee.FeeCurrency = e.feeCurrency()
+		return
+	}
+	fee, chainSpecificFeeLimit, err = e.cache.get(ctx, legacyGasKey(profileName, calldata, feeLimit, maxFeePrice), feeLimit, func(ctx context.Context) (EvmFee, uint32, error) {
+		gasPrice, limit, err := e.EvmEstimator.GetLegacyGas(ctx, calldata, feeLimit, maxFeePrice)
+		return EvmFee{Legacy: gasPrice, FeeCurrency: e.feeCurrency()}, limit, err
+	})
 	return
 }
 
+// BumpFee returns the bumped fee for a transaction.
+// As with GetFee, the bumped fee never exceeds the lower of maxFeePrice and
+// EVM.GasEstimator.PriceMax. If a legacy bump would exceed it, the capped gas
+// price is returned along with an ErrBumpGasExceedsLimit error.
+//
+// Unlike the fees of GetFee, bumps are never lowered, as they would no longer
+// replace the original transaction: bumps past EVM.GasEstimator.MaxTxCost or
+// WithAvailableBalance fail instead, and FeeRounding only rounds them up once
+// they have been bumped past the original fee. With
+// EVM.GasEstimator.SimulateBeforeBump enabled, the call is simulated before
+// bumping, see simulateBeforeBump, and WithOriginalFeeLimit keeps the fee
+// limit of the attempt the bump replaces.
 func (e WrappedEvmEstimator) BumpFee(ctx context.Context, originalFee EvmFee, feeLimit uint32, maxFeePrice *assets.Wei, attempts []txmgrtypes.PriorAttempt[EvmFee, common.Hash]) (bumpedFee EvmFee, chainSpecificFeeLimit uint32, err error) {
+	if !e.calls.enter() {
+		return bumpedFee, 0, errStopped("WrappedEvmEstimator")
+	}
+	defer e.calls.exit()
 	// validate only 1 fee type is present
 	if (!originalFee.ValidDynamic() && originalFee.Legacy == nil) || (originalFee.ValidDynamic() && originalFee.Legacy != nil) {
 		err = errors.New("only one dynamic or legacy fee can be defined")
 		return
 	}
 
+	if err = e.simulateBeforeBump(ctx); err != nil {
+		return
+	}
+
+	d := decision{op: "BumpFee", feeLimit: feeLimit, maxFeePrice: maxFeePrice}
+	ctx, _, profile, maxFeePrice := e.resolveFeeConfig(ctx, maxFeePrice)
+	defer func() {
+		if err == nil {
+			bumpedFee = e.withConvertedCost(ctx, bumpedFee, chainSpecificFeeLimit)
+			d.effectiveMaxFeePrice, d.fee, d.chainSpecificFeeLimit = maxFeePrice, bumpedFee, chainSpecificFeeLimit
+			e.logDecision(d)
+		}
+	}()
+
 	// convert PriorAttempts to EvmPriorAttempts
 	evmAttempts := MakeEvmPriorAttempts(attempts)
 
 	// bump fee based on what fee the tx has previously used (not based on config)
 	// bump dynamic original
 	if originalFee.ValidDynamic() {
+		if err = e.dynamicFeeSupport.err(); err != nil {
+			return
+		}
 		var bumpedDynamic DynamicFee
 		bumpedDynamic, chainSpecificFeeLimit, err = e.EvmEstimator.BumpDynamicFee(ctx,
 			DynamicFee{
-				TipCap: originalFee.DynamicTipCap,
-				FeeCap: originalFee.DynamicFeeCap,
+				TipCap:             originalFee.DynamicTipCap,
+				FeeCap:             originalFee.DynamicFeeCap,
+				BlobFeeCap:         originalFee.BlobFeeCap,
+				GasPerPubdataLimit: originalFee.GasPerPubdataLimit,
+				FeeCurrency:        originalFee.FeeCurrency,
 			}, feeLimit, maxFeePrice, evmAttempts)
 		bumpedFee.DynamicFeeCap = bumpedDynamic.FeeCap
 		bumpedFee.DynamicTipCap = bumpedDynamic.TipCap
+		bumpedFee.BlobFeeCap = bumpedDynamic.BlobFeeCap
+		bumpedFee.GasPerPubdataLimit = bumpedDynamic.GasPerPubdataLimit
+		bumpedFee.InclusionBlocks = originalFee.InclusionBlocks
+		bumpedFee.FeeCurrency = originalFee.FeeCurrency
+		bumpedFee.AccessList = originalFee.AccessList
+		if err != nil {
+			return
+		}
+		estimated := bumpedFee
+		bumpedFee, err = e.profileBump(profile, originalFee, bumpedFee, maxFeePrice)
+		bumpedFee = e.roundFee(bumpedFee, maxFeePrice, nil)
+		e.trackFee(estimated, bumpedFee)
+		bumpedFee = e.withValidity(bumpedFee)
+		chainSpecificFeeLimit = e.bumpedFeeLimit(ctx, e.profileFeeLimit(profile, chainSpecificFeeLimit))
+		if err == nil {
+			err = e.checkTxCostBudget(bumpedFee, chainSpecificFeeLimit)
+		}
+		if err == nil {
+			err = e.checkAvailableBalance(ctx, bumpedFee, chainSpecificFeeLimit)
+		}
 		return
 	}
 
 	// bump legacy fee
 	bumpedFee.Legacy, chainSpecificFeeLimit, err = e.EvmEstimator.BumpLegacyGas(ctx, originalFee.Legacy, feeLimit, maxFeePrice, evmAttempts)
+	if errors.Is(err, ErrBumpGasExceedsLimit) {
+		bumpedFee.Legacy = maxFeePrice
+	}
+	bumpedFee.FeeCurrency = originalFee.FeeCurrency
+	bumpedFee.AccessList = originalFee.AccessList
+	if err != nil {
+		return
+	}
+	estimated := bumpedFee
+	bumpedFee, err = e.profileBump(profile, originalFee, bumpedFee, maxFeePrice)
+	bumpedFee = e.roundFee(bumpedFee, maxFeePrice, nil)
+	e.trackFee(estimated, bumpedFee)
+	bumpedFee = e.withValidity(bumpedFee)
+	chainSpecificFeeLimit = e.bumpedFeeLimit(ctx, e.profileFeeLimit(profile, chainSpecificFeeLimit))
+	if err == nil {
+		err = e.checkTxCostBudget(bumpedFee, chainSpecificFeeLimit)
+	}
+	if err == nil {
+		err = e.checkAvailableBalance(ctx, bumpedFee, chainSpecificFeeLimit)
+	}
 	return
 }
 
+// effectiveMaxPrice returns the lower of the per-call max fee price and
+// EVM.GasEstimator.PriceMax, or PriceMax alone if no per-call max is given
+func (e WrappedEvmEstimator) effectiveMaxPrice(maxFeePrice *assets.Wei) *assets.Wei {
+	chainMax := e.cfg.EvmMaxGasPriceWei()
+	if maxFeePrice == nil {
+		return chainMax
+	}
+	return getMaxGasPrice(maxFeePrice, chainMax)
+}
+
 // Config defines an interface for configuration in the gas package
 //
 //go:generate mockery --quiet --name Config --output ./mocks/ --case=underscore
 type Config interface {
+	BlockHistoryEstimatorBaseFeeLookaheadBlocks() uint16
 	BlockHistoryEstimatorBatchSize() uint32
 	BlockHistoryEstimatorBlockDelay() uint16
 	BlockHistoryEstimatorBlockHistorySize() uint16
 	BlockHistoryEstimatorCheckInclusionPercentile() uint16
 	BlockHistoryEstimatorCheckInclusionBlocks() uint16
 	BlockHistoryEstimatorEIP1559FeeCapBufferBlocks() uint16
+	BlockHistoryEstimatorExcludedTxTypes() []uint8
+	BlockHistoryEstimatorHistoryDuration() time.Duration
+	BlockHistoryEstimatorHistorySizeMax() uint16
+	BlockHistoryEstimatorHistorySizeMin() uint16
+	BlockHistoryEstimatorInclusionPercentiles() []string
+	BlockHistoryEstimatorMaxReorgDepth() uint16
+	BlockHistoryEstimatorTipCapTrimPercentile() uint16
 	BlockHistoryEstimatorTransactionPercentile() uint16
 	ChainType() config.ChainType
 	EvmEIP1559DynamicFees() bool
 	EvmFinalityDepth() uint32
+	EvmGasBaseFeeBufferMultiplier() float32
+	EvmGasBatchTipIncrement() *assets.Wei
+	EvmGasBumpFeeCapFromBaseFee() bool
 	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
 	EvmGasBumpThreshold() uint64
 	EvmGasBumpWei() *assets.Wei
+	EvmGasDecisionLogAlways() []string
+	EvmGasDecisionLogSampleRate() uint32
+	EvmGasDrainTimeout() time.Duration
+	EvmGasEstimateAccessList() bool
+	EvmGasEstimateGasLimit() bool
+	EvmGasFeeAnomalyCooldown() time.Duration
+	EvmGasFeeAnomalyFactor() float32
+	EvmGasFeeAnomalyHalfLife() time.Duration
+	EvmGasFeeCacheTTL() time.Duration
 	EvmGasFeeCapDefault() *assets.Wei
+	EvmGasFeeCurrency() *common.Address
+	EvmGasFeeRounding() *assets.Wei
 	EvmGasLimitMax() uint32
+	EvmGasLimitMin() uint32
 	EvmGasLimitMultiplier() float32
+	EvmGasMaxTxCost() *assets.Wei
+	EvmGasNodeMinPriceMethod() string
+	EvmGasNodeMinPriceSync() bool
 	EvmGasPriceDefault() *assets.Wei
+	EvmGasPriceStaleThreshold() time.Duration
+	EvmGasPriceUpdateThreshold() uint16
+	EvmGasRPCCallTimeout() time.Duration
+	EvmGasRPCRateLimit() uint32
+	EvmGasRPCRateLimitBurst() uint32
+	EvmGasSimulateBeforeBump() bool
+	EvmGasSuggestedPriceConnectivityCheck() bool
 	EvmGasTipCapDefault() *assets.Wei
 	EvmGasTipCapMinimum() *assets.Wei
+	EvmMaxBlobGasPriceWei() *assets.Wei
 	EvmMaxGasPriceWei() *assets.Wei
 	EvmMinGasPriceWei() *assets.Wei
+	ExternalAPIEstimatorBaseFeePath() string
+	ExternalAPIEstimatorFastPath() string
+	ExternalAPIEstimatorPricesIncludeBaseFee() bool
+	ExternalAPIEstimatorSafeLowPath() string
+	ExternalAPIEstimatorSpeed() string
+	ExternalAPIEstimatorStandardPath() string
+	ExternalAPIEstimatorUnit() string
+	ExternalAPIEstimatorURL() *url.URL
+	FeeHistoryEstimatorBlockCount() uint16
+	FeeHistoryEstimatorRewardPercentile() uint16
+	GasEstimatorFallbackModes() []string
 	GasEstimatorMode() string
 }
 
@@ -279,34 +786,46 @@ func BumpLegacyGasPriceOnly(cfg Config, lggr logger.SugaredLogger, currentGasPri
 	return
 }
 
-// bumpGasPrice computes the next gas price to attempt as the largest of:
-// - A configured percentage bump (EVM.GasEstimator.BumpPercent) on top of the baseline price.
-// - A configured fixed amount of Wei (ETH_GAS_PRICE_WEI) on top of the baseline price.
-// The baseline price is the maximum of the previous gas price attempt and the node's current gas price.
-func bumpGasPrice(cfg Config, lggr logger.SugaredLogger, currentGasPrice, originalGasPrice, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
+// bumpConfig is the subset of Config needed to bump a legacy gas price
+type bumpConfig interface {
+	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
+	EvmGasBumpWei() *assets.Wei
+	EvmMaxGasPriceWei() *assets.Wei
+}
+
+// bumpGasPrice computes the next gas price to attempt as the larger of the
+// previous gas price attempt bumped by EVM.GasEstimator.BumpStrategy (see
+// bumpFeePriceWithStrategy) and the node's current gas price.
+func bumpGasPrice(cfg bumpConfig, lggr logger.SugaredLogger, currentGasPrice, originalGasPrice, maxGasPriceWei *assets.Wei) (*assets.Wei, error) {
 	maxGasPrice := getMaxGasPrice(maxGasPriceWei, cfg.EvmMaxGasPriceWei())
-	bumpedGasPrice := bumpFeePrice(originalGasPrice, cfg.EvmGasBumpPercent(), cfg.EvmGasBumpWei())
+	bumpedGasPrice, err := bumpFeePriceWithStrategy(cfg, originalGasPrice)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to bump gas price")
+	}
 
 	// Update bumpedGasPrice if currentGasPrice is higher than bumpedGasPrice and within maxGasPrice
 	bumpedGasPrice = maxBumpedFee(lggr, currentGasPrice, bumpedGasPrice, maxGasPrice, "gas price")
 
 	if bumpedGasPrice.Cmp(maxGasPrice) > 0 {
-		return maxGasPrice, errors.Wrapf(ErrBumpGasExceedsLimit, "bumped gas price of %s would exceed configured max gas price of %s (original price was %s). %s",
-			bumpedGasPrice.String(), maxGasPrice, originalGasPrice.String(), label.NodeConnectivityProblemWarning)
+		return maxGasPrice, &EstimationError{Price: bumpedGasPrice, Limit: maxGasPrice, Reason: ErrBumpLimitExceeded,
+			Err: errors.Wrapf(ErrBumpGasExceedsLimit, "bumped gas price of %s would exceed configured max gas price of %s (original price was %s). %s",
+				bumpedGasPrice.String(), maxGasPrice, originalGasPrice.String(), label.NodeConnectivityProblemWarning)}
 	} else if bumpedGasPrice.Cmp(originalGasPrice) == 0 {
 		// NOTE: This really shouldn't happen since we enforce minimums for
 		// EVM.GasEstimator.BumpPercent and EVM.GasEstimator.BumpMin in the config validation,
 		// but it's here anyway for a "belts and braces" approach
-		return bumpedGasPrice, errors.Wrapf(ErrBump, "bumped gas price of %s is equal to original gas price of %s."+
-			" ACTION REQUIRED: This is a configuration error, you must increase either "+
-			"EVM.GasEstimator.BumpPercent or EVM.GasEstimator.BumpMin", bumpedGasPrice.String(), originalGasPrice.String())
+		return bumpedGasPrice, &EstimationError{Price: bumpedGasPrice, Limit: maxGasPrice, Reason: ErrBump,
+			Err: errors.Wrapf(ErrBump, "bumped gas price of %s is equal to original gas price of %s."+
+				" ACTION REQUIRED: This is a configuration error, you must increase either "+
+				"EVM.GasEstimator.BumpPercent or EVM.GasEstimator.BumpMin", bumpedGasPrice.String(), originalGasPrice.String())}
 	}
 	return bumpedGasPrice, nil
 }
 
 // BumpDynamicFeeOnly bumps the tip cap and max gas price if necessary
 func BumpDynamicFeeOnly(config Config, lggr logger.SugaredLogger, currentTipCap, currentBaseFee *assets.Wei, originalFee DynamicFee, originalGasLimit uint32, maxGasPriceWei *assets.Wei) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
-	bumped, err = bumpDynamicFee(config, lggr, currentTipCap, currentBaseFee, originalFee, maxGasPriceWei)
+	bumped, err = bumpDynamicFee(config, bufferedFeeCap(config), lggr, currentTipCap, currentBaseFee, originalFee, maxGasPriceWei)
 	if err != nil {
 		return bumped, 0, err
 	}
@@ -314,67 +833,209 @@ func BumpDynamicFeeOnly(config Config, lggr logger.SugaredLogger, currentTipCap,
 	return
 }
 
-// bumpDynamicFee computes the next tip cap to attempt as the largest of:
-// - A configured percentage bump (EVM.GasEstimator.BumpPercent) on top of the baseline tip cap.
-// - A configured fixed amount of Wei (ETH_GAS_PRICE_WEI) on top of the baseline tip cap.
-// The baseline tip cap is the maximum of the previous tip cap attempt and the node's current tip cap.
-// It increases the max fee cap by GasBumpPercent
+// tipCapMinConfig is the subset of Config needed to apply EVM.GasEstimator.TipCapMin
+type tipCapMinConfig interface {
+	EvmGasTipCapMinimum() *assets.Wei
+}
+
+// applyTipCapMin raises the tip cap of fee to at least EVM.GasEstimator.TipCapMin,
+// since validators on some chains silently drop transactions with lower tips.
+// The fee cap is raised by as much as the tip cap, so that it still covers the
+// base fee it was computed from, up to maxGasPrice. It is an error for the
+// floor to exceed maxGasPrice, since a tip below the floor is never returned.
+func applyTipCapMin(cfg tipCapMinConfig, fee DynamicFee, maxGasPrice *assets.Wei) (DynamicFee, error) {
+	min := cfg.EvmGasTipCapMinimum()
+	if min == nil || fee.TipCap == nil || fee.TipCap.Cmp(min) >= 0 {
+		return fee, nil
+	}
+	if min.Cmp(maxGasPrice) > 0 {
+		return DynamicFee{}, &EstimationError{Price: min, Limit: maxGasPrice,
+			Err: errors.Errorf("EVM.GasEstimator.TipCapMin of %s is greater than the maximum gas price configured: %s", min, maxGasPrice)}
+	}
+	if fee.FeeCap != nil {
+		fee.FeeCap = assets.WeiMin(fee.FeeCap.Add(min.Sub(fee.TipCap)), maxGasPrice)
+	}
+	fee.TipCap = min
+	return fee, nil
+}
+
+// dynamicFeeBumpConfig is the subset of Config needed to bump a dynamic fee
+type dynamicFeeBumpConfig interface {
+	tipCapMinConfig
+	EvmGasBumpFeeCapFromBaseFee() bool
+	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
+	EvmGasBumpWei() *assets.Wei
+	EvmGasTipCapDefault() *assets.Wei
+	EvmMaxBlobGasPriceWei() *assets.Wei
+	EvmMaxGasPriceWei() *assets.Wei
+}
+
+// bumpDynamicFee computes the next tip cap to attempt as the larger of the
+// previous tip cap attempt bumped by EVM.GasEstimator.BumpStrategy (see
+// bumpFeePriceWithStrategy), the node's current tip cap and
+// EVM.GasEstimator.TipCapMin.
+// It increases the max fee cap by the same strategy, and to at least the fee
+// cap feeCap computes from the current base fee and the bumped tip cap. With
+// EVM.GasEstimator.BumpFeeCapFromBaseFee the fee cap is instead recomputed
+// by feeCap, as by GetDynamicFee, so it
+// shrinks while the base fee falls, but never below the minimum geth accepts
+// for a replacement.
+// If the original fee includes a blob fee cap, it is also increased by
+// GasBumpPercent and may not exceed EVM.GasEstimator.PriceMaxBlob
 //
 // NOTE: We would prefer to have set a large FeeCap and leave it fixed, bumping
 // the Tip only. Unfortunately due to a flaw of how EIP-1559 is implemented we
 // have to bump FeeCap by at least 10% each time we bump the tip cap.
 // See: https://github.com/ethereum/go-ethereum/issues/24284
-func bumpDynamicFee(cfg Config, lggr logger.SugaredLogger, currentTipCap, currentBaseFee *assets.Wei, originalFee DynamicFee, maxGasPriceWei *assets.Wei) (bumpedFee DynamicFee, err error) {
+func bumpDynamicFee(cfg dynamicFeeBumpConfig, feeCap feeCapFunc, lggr logger.SugaredLogger, currentTipCap, currentBaseFee *assets.Wei, originalFee DynamicFee, maxGasPriceWei *assets.Wei) (bumpedFee DynamicFee, err error) {
 	maxGasPrice := getMaxGasPrice(maxGasPriceWei, cfg.EvmMaxGasPriceWei())
 	baselineTipCap := assets.MaxWei(originalFee.TipCap, cfg.EvmGasTipCapDefault())
-	bumpedTipCap := bumpFeePrice(baselineTipCap, cfg.EvmGasBumpPercent(), cfg.EvmGasBumpWei())
+	bumpedTipCap, err := bumpFeePriceWithStrategy(cfg, baselineTipCap)
+	if err != nil {
+		return bumpedFee, errors.Wrap(err, "failed to bump tip cap")
+	}
 
 	// Update bumpedTipCap if currentTipCap is higher than bumpedTipCap and within maxGasPrice
 	bumpedTipCap = maxBumpedFee(lggr, currentTipCap, bumpedTipCap, maxGasPrice, "tip cap")
+	// The floor applies after the current tip cap was capped, so the bump
+	// fails below rather than returning a tip cap below the floor
+	if min := cfg.EvmGasTipCapMinimum(); min != nil {
+		bumpedTipCap = assets.WeiMax(bumpedTipCap, min)
+	}
 
 	if bumpedTipCap.Cmp(maxGasPrice) > 0 {
-		return bumpedFee, errors.Wrapf(ErrBumpGasExceedsLimit, "bumped tip cap of %s would exceed configured max gas price of %s (original fee: tip cap %s, fee cap %s). %s",
-			bumpedTipCap.String(), maxGasPrice, originalFee.TipCap.String(), originalFee.FeeCap.String(), label.NodeConnectivityProblemWarning)
+		return bumpedFee, &EstimationError{Price: bumpedTipCap, Limit: maxGasPrice, Reason: ErrBumpLimitExceeded,
+			Err: errors.Wrapf(ErrBumpGasExceedsLimit, "bumped tip cap of %s would exceed configured max gas price of %s (original fee: tip cap %s, fee cap %s). %s",
+				bumpedTipCap.String(), maxGasPrice, originalFee.TipCap.String(), originalFee.FeeCap.String(), label.NodeConnectivityProblemWarning)}
 	} else if bumpedTipCap.Cmp(originalFee.TipCap) <= 0 {
 		// NOTE: This really shouldn't happen since we enforce minimums for
 		// EVM.GasEstimator.BumpPercent and EVM.GasEstimator.BumpMin in the config validation,
 		// but it's here anyway for a "belts and braces" approach
-		return bumpedFee, errors.Wrapf(ErrBump, "bumped gas tip cap of %s is less than or equal to original gas tip cap of %s."+
-			" ACTION REQUIRED: This is a configuration error, you must increase either "+
-			"EVM.GasEstimator.BumpPercent or EVM.GasEstimator.BumpMin", bumpedTipCap.String(), originalFee.TipCap.String())
+		return bumpedFee, &EstimationError{Price: bumpedTipCap, Limit: maxGasPrice, Reason: ErrBump,
+			Err: errors.Wrapf(ErrBump, "bumped gas tip cap of %s is less than or equal to original gas tip cap of %s."+
+				" ACTION REQUIRED: This is a configuration error, you must increase either "+
+				"EVM.GasEstimator.BumpPercent or EVM.GasEstimator.BumpMin", bumpedTipCap.String(), originalFee.TipCap.String())}
 	}
 
-	// Always bump the FeeCap by at least the bump percentage (should be greater than or
-	// equal to than geth's configured bump minimum which is 10%)
+	// Always bump the FeeCap by at least geth's configured bump minimum which is 10%
 	// See: https://github.com/ethereum/go-ethereum/blob/bff330335b94af3643ac2fb809793f77de3069d4/core/tx_list.go#L298
-	bumpedFeeCap := assets.MaxWei(
-		originalFee.FeeCap.AddPercentage(cfg.EvmGasBumpPercent()),
-		originalFee.FeeCap.Add(cfg.EvmGasBumpWei()),
-	)
+	reanchor := currentBaseFee != nil && cfg.EvmGasBumpFeeCapFromBaseFee()
+	var bumpedFeeCap *assets.Wei
+	if reanchor {
+		bumpedFeeCap, err = minReplacementFeePrice(originalFee.FeeCap)
+	} else {
+		bumpedFeeCap, err = bumpFeePriceWithStrategy(cfg, originalFee.FeeCap)
+	}
+	if err != nil {
+		return bumpedFee, errors.Wrap(err, "failed to bump fee cap")
+	}
 
 	if currentBaseFee != nil {
 		if currentBaseFee.Cmp(maxGasPrice) > 0 {
 			lggr.Warnf("Ignoring current base fee of %s which is greater than max gas price of %s", currentBaseFee.String(), maxGasPrice.String())
 		} else {
-			currentFeeCap := calcFeeCap(currentBaseFee, cfg, bumpedTipCap, maxGasPrice)
+			currentFeeCap, err := feeCap(currentBaseFee, bumpedTipCap, maxGasPrice)
+			if err != nil {
+				return bumpedFee, err
+			}
 			bumpedFeeCap = assets.WeiMax(bumpedFeeCap, currentFeeCap)
 		}
 	}
+	// A fee cap below the tip cap would make the transaction invalid
+	bumpedFeeCap = assets.WeiMax(bumpedFeeCap, bumpedTipCap)
 
 	if bumpedFeeCap.Cmp(maxGasPrice) > 0 {
-		return bumpedFee, errors.Wrapf(ErrBumpGasExceedsLimit, "bumped fee cap of %s would exceed configured max gas price of %s (original fee: tip cap %s, fee cap %s). %s",
-			bumpedFeeCap.String(), maxGasPrice, originalFee.TipCap.String(), originalFee.FeeCap.String(), label.NodeConnectivityProblemWarning)
+		return bumpedFee, &EstimationError{Price: bumpedFeeCap, Limit: maxGasPrice, Reason: ErrBumpLimitExceeded,
+			Err: errors.Wrapf(ErrBumpGasExceedsLimit, "bumped fee cap of %s would exceed configured max gas price of %s (original fee: tip cap %s, fee cap %s). %s",
+				bumpedFeeCap.String(), maxGasPrice, originalFee.TipCap.String(), originalFee.FeeCap.String(), label.NodeConnectivityProblemWarning)}
+	}
+
+	bumpedBlobFeeCap, err := bumpBlobFeeCap(cfg, originalFee.BlobFeeCap)
+	if err != nil {
+		return bumpedFee, err
 	}
 
-	return DynamicFee{FeeCap: bumpedFeeCap, TipCap: bumpedTipCap}, nil
+	return DynamicFee{FeeCap: bumpedFeeCap, TipCap: bumpedTipCap, BlobFeeCap: bumpedBlobFeeCap}, nil
 }
 
-func bumpFeePrice(originalFeePrice *assets.Wei, feeBumpPercent uint16, feeBumpUnits *assets.Wei) *assets.Wei {
-	bumpedFeePrice := assets.MaxWei(
-		originalFeePrice.AddPercentage(feeBumpPercent),
-		originalFeePrice.Add(feeBumpUnits),
-	)
-	return bumpedFeePrice
+// bumpBlobFeeCap increases the blob fee cap by GasBumpPercent, returning nil
+// if the original fee did not include one
+func bumpBlobFeeCap(cfg dynamicFeeBumpConfig, originalBlobFeeCap *assets.Wei) (*assets.Wei, error) {
+	if originalBlobFeeCap == nil {
+		return nil, nil
+	}
+	// Always bump by at least 1 wei, since the percentage alone rounds down to
+	// zero for very small blob fees
+	bumpedBlobFeeCap, err := bumpFeePrice(originalBlobFeeCap, cfg.EvmGasBumpPercent(), assets.NewWeiI(1))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to bump blob fee cap")
+	}
+	maxBlobFeeCap := cfg.EvmMaxBlobGasPriceWei()
+	if bumpedBlobFeeCap.Cmp(maxBlobFeeCap) > 0 {
+		return nil, &EstimationError{Price: bumpedBlobFeeCap, Limit: maxBlobFeeCap, Reason: ErrBumpLimitExceeded,
+			Err: errors.Wrapf(ErrBumpGasExceedsLimit, "bumped blob fee cap of %s would exceed configured max blob gas price of %s (original blob fee cap: %s). %s",
+				bumpedBlobFeeCap.String(), maxBlobFeeCap, originalBlobFeeCap.String(), label.NodeConnectivityProblemWarning)}
+	}
+	return bumpedBlobFeeCap, nil
+}
+
+// The strategies of EVM.GasEstimator.BumpStrategy
+const (
+	// BumpStrategyPercent bumps by the larger of EVM.GasEstimator.BumpPercent
+	// and BumpMin
+	BumpStrategyPercent = "Percent"
+	// BumpStrategyAdditive bumps by EVM.GasEstimator.BumpMin
+	BumpStrategyAdditive = "Additive"
+	// BumpStrategyRebase only bumps by the minimum geth accepts for a
+	// replacement transaction, relying on the estimator's current price to
+	// follow the market. Estimators that keep a history of prices refresh it
+	// before bumping with it, so that the current price is not a cached one.
+	BumpStrategyRebase = "Rebase"
+)
+
+// bumpStrategyConfig is the subset of Config needed to bump by
+// EVM.GasEstimator.BumpStrategy
+type bumpStrategyConfig interface {
+	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
+	EvmGasBumpWei() *assets.Wei
+}
+
+// bumpFeePriceWithStrategy bumps the original fee price by
+// EVM.GasEstimator.BumpStrategy. The bumped price is always at least geth's
+// default price bump of 10% above the original, so that the replacement
+// transaction is not rejected as underpriced.
+func bumpFeePriceWithStrategy(cfg bumpStrategyConfig, originalFeePrice *assets.Wei) (*assets.Wei, error) {
+	switch cfg.EvmGasBumpStrategy() {
+	case BumpStrategyAdditive:
+		return bumpFeePrice(originalFeePrice, uint16(txpool.DefaultConfig.PriceBump), cfg.EvmGasBumpWei())
+	case BumpStrategyRebase:
+		return minReplacementFeePrice(originalFeePrice)
+	default:
+		// Config validation requires BumpPercent to be at least geth's default
+		return bumpFeePrice(originalFeePrice, cfg.EvmGasBumpPercent(), cfg.EvmGasBumpWei())
+	}
+}
+
+// minReplacementFeePrice returns the lowest fee price geth accepts for a
+// transaction replacing one with the original fee price
+func minReplacementFeePrice(originalFeePrice *assets.Wei) (*assets.Wei, error) {
+	return percentBump(originalFeePrice, uint16(txpool.DefaultConfig.PriceBump))
+}
+
+// bumpFeePrice returns the larger of the original fee price increased by
+// feeBumpPercent and by feeBumpUnits
+func bumpFeePrice(originalFeePrice *assets.Wei, feeBumpPercent uint16, feeBumpUnits *assets.Wei) (*assets.Wei, error) {
+	byPercent, err := percentBump(originalFeePrice, feeBumpPercent)
+	if err != nil {
+		return nil, err
+	}
+	byUnits, err := addCapped(originalFeePrice, feeBumpUnits, nil)
+	if err != nil {
+		return nil, err
+	}
+	return assets.MaxWei(byPercent, byUnits), nil
 }
 
 func maxBumpedFee(lggr logger.SugaredLogger, currentFeePrice, bumpedFeePrice, maxGasPrice *assets.Wei, feeType string) *assets.Wei {
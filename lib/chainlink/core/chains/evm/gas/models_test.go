@@ -2,17 +2,36 @@ package gas_test
 
 import (
 	"context"
+	"math/big"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
 	"github.com/smartcontractkit/chainlink/v2/core/assets"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
 	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
 )
 
+type blobEstimator struct {
+	gas.EvmEstimator
+	blobFee *assets.Wei
+}
+
+func (b *blobEstimator) GetBlobFee(context.Context) (*assets.Wei, error) {
+	return b.blobFee, nil
+}
+
 func TestWrappedEvmEstimator(t *testing.T) {
 	t.Parallel()
 	ctx := context.Background()
@@ -26,6 +45,13 @@ func TestWrappedEvmEstimator(t *testing.T) {
 	}
 
 	cfg := mocks.NewConfig(t)
+	cfg.On("EvmMaxGasPriceWei").Return(assets.NewWeiI(100)).Maybe()
+	cfg.On("EvmGasFeeCacheTTL").Return(time.Duration(0)).Maybe()
+	cfg.On("EvmGasEstimateGasLimit").Return(false).Maybe()
+	cfg.On("EvmGasMaxTxCost").Return((*assets.Wei)(nil)).Maybe()
+	cfg.On("EvmGasFeeRounding").Return((*assets.Wei)(nil)).Maybe()
+	cfg.On("EvmGasDecisionLogSampleRate").Return(uint32(0)).Maybe()
+	cfg.On("EvmGasDecisionLogAlways").Return([]string(nil)).Maybe()
 	e := mocks.NewEvmEstimator(t)
 	e.On("GetDynamicFee", mock.Anything, mock.Anything, mock.Anything).
 		Return(dynamicFee, gasLimit, nil).Once()
@@ -40,7 +66,7 @@ func TestWrappedEvmEstimator(t *testing.T) {
 	t.Run("GetFee", func(t *testing.T) {
 		// expect legacy fee data
 		cfg.On("EvmEIP1559DynamicFees").Return(false).Once()
-		estimator := gas.NewWrappedEvmEstimator(e, cfg)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
 		fee, max, err := estimator.GetFee(ctx, nil, 0, nil)
 		require.NoError(t, err)
 		assert.Equal(t, gasLimit, max)
@@ -50,7 +76,7 @@ func TestWrappedEvmEstimator(t *testing.T) {
 
 		// expect dynamic fee data
 		cfg.On("EvmEIP1559DynamicFees").Return(true).Once()
-		estimator = gas.NewWrappedEvmEstimator(e, cfg)
+		estimator = gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
 		fee, max, err = estimator.GetFee(ctx, nil, 0, nil)
 		require.NoError(t, err)
 		assert.Equal(t, gasLimit, max)
@@ -59,10 +85,40 @@ func TestWrappedEvmEstimator(t *testing.T) {
 		assert.Nil(t, fee.Legacy)
 	})
 
+	t.Run("GetFee with OptBlobTx", func(t *testing.T) {
+		blobFee := assets.NewWeiI(7)
+
+		// expect error if estimator does not support blob fees
+		be := mocks.NewEvmEstimator(t)
+		be.On("GetDynamicFee", mock.Anything, mock.Anything, mock.Anything).Return(dynamicFee, gasLimit, nil).Once()
+		be.On("Name").Return("EvmEstimator").Once()
+		cfg.On("EvmEIP1559DynamicFees").Return(true).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), be, cfg, nil)
+		_, _, err := estimator.GetFee(ctx, nil, 0, nil, txmgrtypes.OptBlobTx)
+		assert.EqualError(t, err, "estimator EvmEstimator does not support blob fee estimation")
+
+		// expect dynamic and blob fee data
+		be.On("GetDynamicFee", mock.Anything, mock.Anything, mock.Anything).Return(dynamicFee, gasLimit, nil).Once()
+		cfg.On("EvmEIP1559DynamicFees").Return(true).Once()
+		estimator = gas.NewWrappedEvmEstimator(logger.TestLogger(t), &blobEstimator{be, blobFee}, cfg, nil)
+		fee, max, err := estimator.GetFee(ctx, nil, 0, nil, txmgrtypes.OptBlobTx)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, max)
+		assert.True(t, dynamicFee.FeeCap.Equal(fee.DynamicFeeCap))
+		assert.True(t, dynamicFee.TipCap.Equal(fee.DynamicTipCap))
+		assert.True(t, blobFee.Equal(fee.BlobFeeCap))
+
+		// expect error in legacy mode
+		cfg.On("EvmEIP1559DynamicFees").Return(false).Once()
+		estimator = gas.NewWrappedEvmEstimator(logger.TestLogger(t), &blobEstimator{be, blobFee}, cfg, nil)
+		_, _, err = estimator.GetFee(ctx, nil, 0, nil, txmgrtypes.OptBlobTx)
+		assert.EqualError(t, err, "blob transactions require EIP1559 dynamic fees to be enabled")
+	})
+
 	// BumpFee returns bumped fee type based on original fee calculation
 	t.Run("BumpFee", func(t *testing.T) {
 		cfg.On("EvmEIP1559DynamicFees").Return(false).Once().Maybe()
-		estimator := gas.NewWrappedEvmEstimator(e, cfg)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
 
 		// expect legacy fee data
 		fee, max, err := estimator.BumpFee(ctx, gas.EvmFee{Legacy: assets.NewWeiI(0)}, 0, nil, nil)
@@ -83,6 +139,18 @@ func TestWrappedEvmEstimator(t *testing.T) {
 		assert.True(t, dynamicFee.TipCap.Equal(fee.DynamicTipCap))
 		assert.Nil(t, fee.Legacy)
 
+		// expect blob fee cap to be passed through
+		blobFee := assets.NewWeiI(7)
+		e.On("BumpDynamicFee", mock.Anything, gas.DynamicFee{FeeCap: assets.NewWeiI(0), TipCap: assets.NewWeiI(0), BlobFeeCap: assets.NewWeiI(5)}, mock.Anything, mock.Anything, mock.Anything).
+			Return(gas.DynamicFee{FeeCap: dynamicFee.FeeCap, TipCap: dynamicFee.TipCap, BlobFeeCap: blobFee}, gasLimit, nil).Once()
+		fee, _, err = estimator.BumpFee(ctx, gas.EvmFee{
+			DynamicFeeCap: assets.NewWeiI(0),
+			DynamicTipCap: assets.NewWeiI(0),
+			BlobFeeCap:    assets.NewWeiI(5),
+		}, 0, nil, nil)
+		require.NoError(t, err)
+		assert.True(t, blobFee.Equal(fee.BlobFeeCap))
+
 		// expect error
 		_, _, err = estimator.BumpFee(ctx, gas.EvmFee{}, 0, nil, nil)
 		assert.Error(t, err)
@@ -94,3 +162,286 @@ func TestWrappedEvmEstimator(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestWrappedEvmEstimator_MaxPrice(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	const gasLimit uint32 = 10
+	chainMax := assets.GWei(100)
+	keyMax := assets.GWei(40)
+
+	newConfig := func(t *testing.T, eip1559 bool) *mocks.Config {
+		cfg := mocks.NewConfig(t)
+		cfg.On("EvmEIP1559DynamicFees").Return(eip1559).Once()
+		cfg.On("EvmMaxGasPriceWei").Return(chainMax)
+		cfg.On("EvmGasFeeCacheTTL").Return(time.Duration(0)).Once()
+		cfg.On("EvmGasEstimateGasLimit").Return(false).Once()
+		cfg.On("EvmGasMaxTxCost").Return((*assets.Wei)(nil)).Maybe()
+		cfg.On("EvmGasFeeRounding").Return((*assets.Wei)(nil)).Maybe()
+		cfg.On("EvmGasDecisionLogSampleRate").Return(uint32(0)).Maybe()
+		cfg.On("EvmGasDecisionLogAlways").Return([]string(nil)).Maybe()
+		return cfg
+	}
+
+	t.Run("GetFee passes the lower of the per-call and chain max price to the estimator", func(t *testing.T) {
+		for _, test := range []struct {
+			name        string
+			maxFeePrice *assets.Wei
+			expected    *assets.Wei
+		}{
+			{"no per-call max", nil, chainMax},
+			{"per-call max below chain max", keyMax, keyMax},
+			{"per-call max above chain max", assets.GWei(200), chainMax},
+		} {
+			t.Run(test.name, func(t *testing.T) {
+				e := mocks.NewEvmEstimator(t)
+				e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, test.expected).Return(assets.GWei(1), gasLimit, nil).Once()
+				e.On("GetDynamicFee", mock.Anything, gasLimit, test.expected).Return(gas.DynamicFee{FeeCap: assets.GWei(2), TipCap: assets.GWei(1)}, gasLimit, nil).Once()
+
+				_, _, err := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, false), nil).GetFee(ctx, nil, gasLimit, test.maxFeePrice)
+				require.NoError(t, err)
+				_, _, err = gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, true), nil).GetFee(ctx, nil, gasLimit, test.maxFeePrice)
+				require.NoError(t, err)
+			})
+		}
+	})
+
+	t.Run("BumpFee passes the lower of the per-call and chain max price to the estimator", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("BumpLegacyGas", mock.Anything, mock.Anything, gasLimit, keyMax, mock.Anything).Return(assets.GWei(2), gasLimit, nil).Once()
+		e.On("BumpDynamicFee", mock.Anything, mock.Anything, gasLimit, keyMax, mock.Anything).Return(gas.DynamicFee{FeeCap: assets.GWei(3), TipCap: assets.GWei(2)}, gasLimit, nil).Once()
+
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(t, false), nil)
+		_, _, err := estimator.BumpFee(ctx, gas.EvmFee{Legacy: assets.GWei(1)}, gasLimit, keyMax, nil)
+		require.NoError(t, err)
+		_, _, err = estimator.BumpFee(ctx, gas.EvmFee{DynamicFeeCap: assets.GWei(2), DynamicTipCap: assets.GWei(1)}, gasLimit, keyMax, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("legacy bump exceeding the per-call max returns the capped price", func(t *testing.T) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasBumpPercentF = 50
+		cfg.EvmGasBumpWeiF = assets.GWei(5)
+		cfg.EvmMaxGasPriceWeiF = chainMax
+		cfg.EvmGasPriceDefaultF = assets.GWei(20)
+		cfg.EvmGasLimitMultiplierF = 1
+
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), gas.NewFixedPriceEstimator(cfg, logger.TestLogger(t)), cfg, nil)
+
+		// 30 gwei bumped by 50% would be 45 gwei, which is within the chain max but not the key max
+		fee, _, err := estimator.BumpFee(ctx, gas.EvmFee{Legacy: assets.GWei(30)}, gasLimit, keyMax, nil)
+		require.Error(t, err)
+		assert.ErrorIs(t, err, gas.ErrBumpGasExceedsLimit)
+		assert.Contains(t, err.Error(), "bumped gas price of 45 gwei would exceed configured max gas price of 40 gwei (original price was 30 gwei)")
+		assert.Equal(t, keyMax, fee.Legacy)
+
+		// the same bump succeeds when only the chain max applies
+		fee, _, err = estimator.BumpFee(ctx, gas.EvmFee{Legacy: assets.GWei(30)}, gasLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(45), fee.Legacy)
+	})
+}
+
+// rpcEstimator is an estimator that fetches the tip cap from the node on every call
+type rpcEstimator struct {
+	gas.EvmEstimator
+	client *mocks.RPCClient
+}
+
+func (r *rpcEstimator) OnNewLongestChain(context.Context, *evmtypes.Head) {}
+
+func (r *rpcEstimator) GetDynamicFee(ctx context.Context, gasLimit uint32, _ *assets.Wei) (fee gas.DynamicFee, chainSpecificGasLimit uint32, err error) {
+	var tipCap hexutil.Big
+	if err = r.client.CallContext(ctx, &tipCap, "eth_maxPriorityFeePerGas"); err != nil {
+		return
+	}
+	fee.TipCap = (*assets.Wei)(&tipCap)
+	fee.FeeCap = fee.TipCap.Mul(big.NewInt(2))
+	return fee, gasLimit, nil
+}
+
+func (r *rpcEstimator) GetLegacyGas(ctx context.Context, _ []byte, gasLimit uint32, _ *assets.Wei, _ ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	var price hexutil.Big
+	if err = r.client.CallContext(ctx, &price, "eth_gasPrice"); err != nil {
+		return
+	}
+	return (*assets.Wei)(&price), gasLimit, nil
+}
+
+func mockRPCPrice(client *mocks.RPCClient, method string, price int64) *mock.Call {
+	return client.On("CallContext", mock.Anything, mock.Anything, method).Run(func(args mock.Arguments) {
+		res := args.Get(1).(*hexutil.Big)
+		(*big.Int)(res).SetInt64(price)
+	}).Return(nil).Once()
+}
+
+func TestWrappedEvmEstimator_FeeCache(t *testing.T) {
+	t.Parallel()
+	ctx := testutils.Context(t)
+
+	const gasLimit uint32 = 100
+	newEstimator := func(t *testing.T, eip1559 bool, ttl time.Duration) (gas.EvmFeeEstimator, *mocks.RPCClient) {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = eip1559
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasFeeCacheTTLF = ttl
+		cfg.EvmGasLimitMultiplierF = 1
+		client := mocks.NewRPCClient(t)
+		return gas.NewWrappedEvmEstimator(logger.TestLogger(t), &rpcEstimator{client: client}, cfg, nil), client
+	}
+
+	t.Run("concurrent GetFee calls share one estimation until the next head", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, time.Hour)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				fee, limit, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+				assert.NoError(t, err)
+				assert.Equal(t, gasLimit, limit)
+				assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+				assert.Equal(t, assets.NewWeiI(20), fee.DynamicFeeCap)
+			}()
+		}
+		wg.Wait()
+		client.AssertNumberOfCalls(t, "CallContext", 1)
+
+		estimator.OnNewLongestChain(ctx, cltest.Head(1))
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 30)
+
+		fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(30), fee.DynamicTipCap)
+		client.AssertNumberOfCalls(t, "CallContext", 2)
+	})
+
+	t.Run("calls in the same gas limit bucket share an estimate with their own limits", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, time.Hour)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10)
+
+		fee, limit, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, limit)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+
+		fee, limit, err = estimator.GetFee(ctx, nil, gasLimit+20, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit+20, limit)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+		client.AssertNumberOfCalls(t, "CallContext", 1)
+	})
+
+	t.Run("the shared estimation outlives the context of the caller that started it", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, time.Hour)
+		began, release := make(chan struct{}), make(chan struct{})
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10).Run(func(args mock.Arguments) {
+			close(began)
+			<-release
+			assert.NoError(t, args.Get(0).(context.Context).Err())
+			(*big.Int)(args.Get(1).(*hexutil.Big)).SetInt64(10)
+		})
+
+		callerCtx, cancel := context.WithCancel(ctx)
+		done := make(chan error)
+		go func() {
+			_, _, err := estimator.GetFee(callerCtx, nil, gasLimit, nil)
+			done <- err
+		}()
+		<-began
+		cancel()
+		require.ErrorIs(t, <-done, context.Canceled)
+		close(release)
+
+		require.Eventually(t, func() bool {
+			fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+			return err == nil && fee.DynamicTipCap.Equal(assets.NewWeiI(10))
+		}, testutils.WaitTimeout(t), time.Millisecond)
+		client.AssertNumberOfCalls(t, "CallContext", 1)
+	})
+
+	t.Run("estimates are keyed by fee limit bucket and max price", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, time.Hour)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 11)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 12)
+
+		fee, limit, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, limit)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+
+		fee, limit, err = estimator.GetFee(ctx, nil, 2*gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 2*gasLimit, limit)
+		assert.Equal(t, assets.NewWeiI(11), fee.DynamicTipCap)
+
+		fee, _, err = estimator.GetFee(ctx, nil, gasLimit, assets.GWei(50))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(12), fee.DynamicTipCap)
+
+		fee, _, err = estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+		client.AssertNumberOfCalls(t, "CallContext", 3)
+	})
+
+	t.Run("caches legacy gas and bypasses the cache with options", func(t *testing.T) {
+		estimator, client := newEstimator(t, false, time.Hour)
+		mockRPCPrice(client, "eth_gasPrice", 10)
+		mockRPCPrice(client, "eth_gasPrice", 20)
+
+		for i := 0; i < 2; i++ {
+			fee, _, err := estimator.GetFee(ctx, []byte{1}, gasLimit, nil)
+			require.NoError(t, err)
+			assert.Equal(t, assets.NewWeiI(10), fee.Legacy)
+		}
+		fee, _, err := estimator.GetFee(ctx, []byte{1}, gasLimit, nil, txmgrtypes.OptForceRefetch)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(20), fee.Legacy)
+		client.AssertNumberOfCalls(t, "CallContext", 2)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, time.Hour)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_maxPriorityFeePerGas").Return(errors.New("kaboom")).Once()
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10)
+
+		_, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.EqualError(t, err, "kaboom")
+		fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+	})
+
+	t.Run("expired estimates are refreshed", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, time.Millisecond)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 20)
+
+		fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+
+		time.Sleep(5 * time.Millisecond)
+		fee, _, err = estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(20), fee.DynamicTipCap)
+	})
+
+	t.Run("zero TTL disables the cache", func(t *testing.T) {
+		estimator, client := newEstimator(t, true, 0)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 10)
+		mockRPCPrice(client, "eth_maxPriorityFeePerGas", 20)
+
+		fee, _, err := estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(10), fee.DynamicTipCap)
+		fee, _, err = estimator.GetFee(ctx, nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(20), fee.DynamicTipCap)
+	})
+}
@@ -0,0 +1,111 @@
+package gas
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// nodeMinPriceSyncInterval is how often the minimum gas price of the node is
+// queried with EVM.GasEstimator.NodeMinPriceSync
+const nodeMinPriceSyncInterval = time.Minute
+
+// nodeMinPriceUser is implemented by the estimators that floor their legacy
+// gas price at the minimum gas price of the node
+type nodeMinPriceUser interface {
+	useNodeMinPrice(floor *nodeMinPrice)
+}
+
+// nodeMinPrice periodically queries the minimum gas price that the node
+// enforces, with EVM.GasEstimator.NodeMinPriceMethod, e.g. on BSC where
+// validators reject transactions priced below it. If a query fails there is no
+// floor until the next query succeeds, so estimates fall back to
+// EVM.GasEstimator.PriceMin.
+type nodeMinPrice struct {
+	client   rpcClient
+	method   string
+	interval time.Duration
+	lggr     logger.SugaredLogger
+
+	price  atomic.Pointer[assets.Wei]
+	chStop utils.StopChan
+	wg     sync.WaitGroup
+}
+
+func newNodeMinPrice(lggr logger.Logger, client rpcClient, cfg Config) *nodeMinPrice {
+	return &nodeMinPrice{
+		client:   client,
+		method:   cfg.EvmGasNodeMinPriceMethod(),
+		interval: nodeMinPriceSyncInterval,
+		lggr:     logger.Sugared(lggr.Named("NodeMinPrice")),
+		chStop:   make(chan struct{}),
+	}
+}
+
+// start queries the minimum price and then keeps querying it in the
+// background until close
+func (n *nodeMinPrice) start(ctx context.Context) {
+	if n == nil {
+		return
+	}
+	n.refresh(ctx)
+	n.wg.Add(1)
+	go n.run()
+}
+
+func (n *nodeMinPrice) close() {
+	if n == nil {
+		return
+	}
+	close(n.chStop)
+	n.wg.Wait()
+}
+
+func (n *nodeMinPrice) run() {
+	defer n.wg.Done()
+	t := time.NewTicker(utils.WithJitter(n.interval))
+	defer t.Stop()
+	for {
+		select {
+		case <-n.chStop:
+			return
+		case <-t.C:
+			ctx, cancel := n.chStop.CtxCancel(evmclient.ContextWithDefaultTimeout())
+			n.refresh(ctx)
+			cancel()
+		}
+	}
+}
+
+func (n *nodeMinPrice) refresh(ctx context.Context) {
+	var res rpcPrice
+	if err := n.client.CallContext(ctx, &res, n.method); err != nil {
+		n.lggr.Warnw("Failed to query the minimum gas price of the node, falling back to EVM.GasEstimator.PriceMin", "method", n.method, "err", err)
+		n.price.Store(nil)
+		return
+	}
+	n.lggr.Debugw("Queried the minimum gas price of the node", "method", n.method, "price", res.Wei())
+	n.price.Store(res.Wei())
+}
+
+// apply raises gasPrice to the minimum price of the node, but no higher than
+// maxGasPriceWei
+func (n *nodeMinPrice) apply(gasPrice, maxGasPriceWei *assets.Wei) *assets.Wei {
+	if n == nil {
+		return gasPrice
+	}
+	floor := n.price.Load()
+	if floor == nil || gasPrice == nil || gasPrice.Cmp(floor) >= 0 {
+		return gasPrice
+	}
+	if maxGasPriceWei != nil && floor.Cmp(maxGasPriceWei) > 0 {
+		floor = maxGasPriceWei
+	}
+	return assets.WeiMax(gasPrice, floor)
+}
@@ -0,0 +1,112 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/client/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestNodeMinPriceSync(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21_000
+	newConfig := func(mode string) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = mode
+		cfg.EvmGasNodeMinPriceSyncF = true
+		cfg.EvmGasNodeMinPriceMethodF = "eth_gasPrice"
+		cfg.BlockHistoryEstimatorBlockHistorySizeF = 1
+		cfg.BlockHistoryEstimatorTransactionPercentileF = 60
+		cfg.EvmGasLimitMultiplierF = 1
+		cfg.EvmGasPriceDefaultF = assets.GWei(1)
+		cfg.EvmMinGasPriceWeiF = assets.GWei(1)
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		return cfg
+	}
+	mockMinPrice := func(ethClient *mocks.Client, method string, price int64) *mock.Call {
+		return ethClient.On("CallContext", mock.Anything, mock.Anything, method).Run(func(args mock.Arguments) {
+			gas.SetRPCPrice(args.Get(1), price)
+		}).Return(nil)
+	}
+	// newBlockHistoryEstimator returns a started BlockHistory estimator whose
+	// block history has only an empty block
+	newBlockHistoryEstimator := func(t *testing.T, cfg *gas.MockConfig, ethClient *mocks.Client) gas.EvmFeeEstimator {
+		ethClient.On("HeadByNumber", mock.Anything, (*big.Int)(nil)).Return(&evmtypes.Head{Hash: utils.NewHash(), Number: 42}, nil)
+		ethClient.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+			elems := args.Get(1).([]rpc.BatchElem)
+			elems[0].Result = &evmtypes.Block{Number: 42, Hash: utils.NewHash()}
+		}).Once()
+		estimator := gas.NewEstimator(logger.TestLogger(t), ethClient, cfg, nil)
+		require.NoError(t, estimator.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, estimator.Close()) })
+		return estimator
+	}
+
+	t.Run("floors the gas price of an empty block history at the node's minimum", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		mockMinPrice(ethClient, "eth_gasPrice", 3_000_000_000).Once()
+		estimator := newBlockHistoryEstimator(t, newConfig("BlockHistory"), ethClient)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(3), fee.Legacy)
+
+		// the floor is explained
+		ex, err := estimator.(gas.EvmFeeExplainer).ExplainFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(3), ex.Fee.Legacy)
+		assert.Equal(t, []string{gas.ClampNodeMinPrice}, ex.Clamps)
+	})
+
+	t.Run("never raises the gas price above PriceMax", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		mockMinPrice(ethClient, "eth_gasPrice", 200_000_000_000).Once()
+		estimator := newBlockHistoryEstimator(t, newConfig("BlockHistory"), ethClient)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(100), fee.Legacy)
+		fee, _, err = estimator.GetFee(testutils.Context(t), nil, gasLimit, assets.GWei(50))
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(50), fee.Legacy)
+	})
+
+	t.Run("falls back to the static prices if querying the minimum fails", func(t *testing.T) {
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		ethClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("kaboom")).Once()
+		estimator := newBlockHistoryEstimator(t, newConfig("BlockHistory"), ethClient)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(1), fee.Legacy)
+	})
+
+	t.Run("floors the suggested gas price with a chain-specific method", func(t *testing.T) {
+		cfg := newConfig("L2Suggested")
+		cfg.EvmGasNodeMinPriceMethodF = "eth_minGasPrice"
+		ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+		mockMinPrice(ethClient, "eth_minGasPrice", 3_000_000_000).Once()
+		mockMinPrice(ethClient, "eth_gasPrice", 2_000_000_000).Once()
+		estimator := gas.NewEstimator(logger.TestLogger(t), ethClient, cfg, nil)
+		require.NoError(t, estimator.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, estimator.Close()) })
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(3), fee.Legacy)
+	})
+}
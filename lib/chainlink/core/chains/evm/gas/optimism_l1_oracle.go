@@ -0,0 +1,338 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+const (
+	// OPGasOracleAddress is the address of the GasPriceOracle predeploy of OP-stack chains.
+	// https://github.com/ethereum-optimism/optimism/blob/233ede59d16cb01bdd8e7ff662a153a4c3178bdd/packages/contracts-bedrock/src/L2/GasPriceOracle.sol
+	OPGasOracleAddress = "0x420000000000000000000000000000000000000F"
+
+	// defaultOptimismL1OracleRefreshInterval is how long the L1 fee parameters
+	// are cached, which is about one L1 block as the parameters change with
+	// the L1 base fee
+	defaultOptimismL1OracleRefreshInterval = 12 * time.Second
+)
+
+const gasPriceOracleABIJSON = `[
+{"inputs":[{"internalType":"bytes","name":"_data","type":"bytes"}],"name":"getL1Fee","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"l1BaseFee","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"overhead","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"scalar","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"decimals","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"pure","type":"function"},
+{"inputs":[],"name":"blobBaseFee","outputs":[{"internalType":"uint256","name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"baseFeeScalar","outputs":[{"internalType":"uint32","name":"","type":"uint32"}],"stateMutability":"view","type":"function"},
+{"inputs":[],"name":"blobBaseFeeScalar","outputs":[{"internalType":"uint32","name":"","type":"uint32"}],"stateMutability":"view","type":"function"}
+]`
+
+var gasPriceOracleABI = func() abi.ABI {
+	parsed, err := abi.JSON(strings.NewReader(gasPriceOracleABIJSON))
+	if err != nil {
+		panic(err)
+	}
+	return parsed
+}()
+
+// l1FeeParamGetters are the GasPriceOracle getters the L1 fee parameters are
+// read from. Which of them succeed determines the fee formula: overhead and
+// scalar revert once Ecotone is active, and baseFeeScalar, blobBaseFeeScalar
+// and blobBaseFee don't exist before the Ecotone upgrade.
+var l1FeeParamGetters = []string{"l1BaseFee", "decimals", "overhead", "scalar", "blobBaseFee", "baseFeeScalar", "blobBaseFeeScalar"}
+
+// l1FeeFormula is the formula the GasPriceOracle computes the L1 data fee with
+type l1FeeFormula int
+
+const (
+	// l1FeeFormulaUnknown means the parameters of neither formula could be
+	// read, so the fee is left for getL1Fee to compute
+	l1FeeFormulaUnknown l1FeeFormula = iota
+	// l1FeeFormulaBedrock is (calldataGas + overhead) * l1BaseFee * scalar / 10^decimals
+	l1FeeFormulaBedrock
+	// l1FeeFormulaEcotone is calldataGas * (16 * baseFeeScalar * l1BaseFee + blobBaseFeeScalar * blobBaseFee) / (16 * 10^decimals)
+	l1FeeFormulaEcotone
+)
+
+func (f l1FeeFormula) String() string {
+	switch f {
+	case l1FeeFormulaBedrock:
+		return "Bedrock"
+	case l1FeeFormulaEcotone:
+		return "Ecotone"
+	default:
+		return "Unknown"
+	}
+}
+
+// l1FeeParams are the L1 fee parameters of the GasPriceOracle, by getter name
+type l1FeeParams struct {
+	formula l1FeeFormula
+	values  map[string]*big.Int
+}
+
+// L1Oracle returns the fee that L2s charge for posting a transaction to L1, on
+// top of its L2 execution fee
+type L1Oracle interface {
+	// GetL1DataFee returns the L1 data fee of the RLP-encoded unsigned transaction payload
+	GetL1DataFee(ctx context.Context, payload []byte) (*assets.Wei, error)
+}
+
+var _ L1Oracle = (*OptimismL1Oracle)(nil)
+
+// OptimismL1Oracle returns the L1 data fee of transactions on OP-stack chains.
+// It computes the fee the same way as the GasPriceOracle predeploy's getL1Fee,
+// from the L1 fee parameters of the oracle, which are cached for the refresh
+// interval to save an eth_call per transaction. If the parameters can't be
+// read with either the Bedrock or the Ecotone getters, it calls getL1Fee
+// instead.
+type OptimismL1Oracle struct {
+	client          rpcClient
+	lggr            logger.Logger
+	refreshInterval time.Duration
+
+	mu          sync.Mutex
+	params      *l1FeeParams
+	refreshedAt time.Time
+}
+
+// NewOptimismL1Oracle returns an OptimismL1Oracle that caches the L1 fee
+// parameters for refreshInterval
+func NewOptimismL1Oracle(lggr logger.Logger, client rpcClient, refreshInterval time.Duration) *OptimismL1Oracle {
+	return &OptimismL1Oracle{
+		client:          client,
+		lggr:            lggr.Named("OptimismL1Oracle"),
+		refreshInterval: refreshInterval,
+	}
+}
+
+func (o *OptimismL1Oracle) GetL1DataFee(ctx context.Context, payload []byte) (*assets.Wei, error) {
+	params, err := o.getParams(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if params.formula == l1FeeFormulaUnknown {
+		return o.callGetL1Fee(ctx, payload)
+	}
+	return assets.NewWei(params.l1Fee(payload)), nil
+}
+
+// getParams returns the cached L1 fee parameters, refreshing them if they are
+// older than the refresh interval. If the refresh fails, the previous
+// parameters are kept until the next call.
+func (o *OptimismL1Oracle) getParams(ctx context.Context) (*l1FeeParams, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.params != nil && time.Since(o.refreshedAt) < o.refreshInterval {
+		return o.params, nil
+	}
+
+	params, err := o.fetchParams(ctx)
+	if err != nil {
+		if o.params == nil {
+			return nil, err
+		}
+		o.lggr.Warnw("Failed to refresh L1 fee parameters, using the previous parameters", "err", err, "formula", o.params.formula)
+		return o.params, nil
+	}
+	if o.params == nil || o.params.formula != params.formula {
+		o.lggr.Debugw("Detected L1 fee formula", "formula", params.formula)
+	}
+	o.params, o.refreshedAt = params, time.Now()
+	return params, nil
+}
+
+// fetchParams reads all L1 fee parameter getters in one batch, and detects
+// the fee formula by which of them succeeded
+func (o *OptimismL1Oracle) fetchParams(ctx context.Context) (*l1FeeParams, error) {
+	reqs := make([]rpc.BatchElem, len(l1FeeParamGetters))
+	for i, getter := range l1FeeParamGetters {
+		data, err := gasPriceOracleABI.Pack(getter)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to encode %s call", getter)
+		}
+		reqs[i] = rpc.BatchElem{
+			Method: "eth_call",
+			Args: []interface{}{map[string]interface{}{
+				"to":   OPGasOracleAddress,
+				"data": hexutil.Bytes(data),
+			}, "latest"},
+			Result: new(hexutil.Bytes),
+		}
+	}
+	if err := o.client.BatchCallContext(ctx, reqs); err != nil {
+		return nil, errors.Wrap(err, "failed to fetch L1 fee parameters")
+	}
+
+	params := &l1FeeParams{values: make(map[string]*big.Int, len(reqs))}
+	for i, getter := range l1FeeParamGetters {
+		if reqs[i].Error != nil {
+			continue
+		}
+		value, err := unpackUint(getter, *reqs[i].Result.(*hexutil.Bytes))
+		if err != nil {
+			continue
+		}
+		params.values[getter] = value
+	}
+	switch {
+	case params.has("l1BaseFee", "decimals", "overhead", "scalar"):
+		// Before Ecotone activates the upgraded oracle still uses the Bedrock
+		// formula, so it takes precedence while its getters succeed
+		params.formula = l1FeeFormulaBedrock
+	case params.has("l1BaseFee", "decimals", "blobBaseFee", "baseFeeScalar", "blobBaseFeeScalar"):
+		params.formula = l1FeeFormulaEcotone
+	}
+	return params, nil
+}
+
+func (p *l1FeeParams) has(getters ...string) bool {
+	for _, getter := range getters {
+		if p.values[getter] == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// l1Fee returns the L1 data fee of the payload, as computed by getL1Fee.
+// https://github.com/ethereum-optimism/optimism/blob/233ede59d16cb01bdd8e7ff662a153a4c3178bdd/packages/contracts-bedrock/src/L2/GasPriceOracle.sol#L52
+func (p *l1FeeParams) l1Fee(payload []byte) *big.Int {
+	v := p.values
+	gasUsed := big.NewInt(int64(l1CalldataGas(payload)))
+	divisor := new(big.Int).Exp(big.NewInt(10), v["decimals"], nil)
+
+	if p.formula == l1FeeFormulaEcotone {
+		scaledBaseFee := new(big.Int).Mul(v["baseFeeScalar"], v["l1BaseFee"])
+		scaledBaseFee.Mul(scaledBaseFee, big.NewInt(16))
+		scaledBlobBaseFee := new(big.Int).Mul(v["blobBaseFeeScalar"], v["blobBaseFee"])
+		fee := new(big.Int).Add(scaledBaseFee, scaledBlobBaseFee)
+		fee.Mul(fee, gasUsed)
+		return fee.Div(fee, divisor.Mul(divisor, big.NewInt(16)))
+	}
+
+	gasUsed.Add(gasUsed, v["overhead"])
+	fee := new(big.Int).Mul(gasUsed, v["l1BaseFee"])
+	fee.Mul(fee, v["scalar"])
+	return fee.Div(fee, divisor)
+}
+
+// l1CalldataGas returns the L1 gas used by the calldata of the payload,
+// including the 68 bytes the signature adds to the unsigned payload
+func l1CalldataGas(payload []byte) uint64 {
+	var gas uint64
+	for _, b := range payload {
+		if b == 0 {
+			gas += 4
+		} else {
+			gas += 16
+		}
+	}
+	return gas + 68*16
+}
+
+// callGetL1Fee calls GasPriceOracle.getL1Fee() for the payload on the
+// predeploy OPGasOracleAddress
+//
+// function getL1Fee(bytes memory _data) external view returns (uint256);
+func (o *OptimismL1Oracle) callGetL1Fee(ctx context.Context, payload []byte) (*assets.Wei, error) {
+	data, err := gasPriceOracleABI.Pack("getL1Fee", payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode getL1Fee call")
+	}
+
+	var b hexutil.Bytes
+	err = o.client.CallContext(ctx, &b, "eth_call", map[string]interface{}{
+		"to":   OPGasOracleAddress,
+		"data": hexutil.Bytes(data),
+	}, "latest")
+	if err != nil {
+		return nil, errors.Wrap(withDecodedRevert(err), "getL1Fee call failed")
+	}
+
+	fee, err := unpackUint("getL1Fee", b)
+	if err != nil {
+		return nil, err
+	}
+	return assets.NewWei(fee), nil
+}
+
+// unpackUint decodes the single unsigned integer returned by the
+// GasPriceOracle method
+func unpackUint(method string, b []byte) (*big.Int, error) {
+	out, err := gasPriceOracleABI.Unpack(method, b)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode %s result", method)
+	}
+	switch v := out[0].(type) {
+	case *big.Int:
+		return v, nil
+	case uint32:
+		return new(big.Int).SetUint64(uint64(v)), nil
+	default:
+		return nil, fmt.Errorf("unexpected %s type %T", method, out[0])
+	}
+}
+
+// TotalFee is the fee of a new transaction along with its maximum total cost
+type TotalFee struct {
+	Fee                   EvmFee
+	ChainSpecificFeeLimit uint32
+	// ExecutionFee is the maximum L2 execution fee, i.e. the fee limit times
+	// the legacy gas price or the dynamic fee cap
+	ExecutionFee *assets.Wei
+	// L1DataFee is the fee for posting the transaction to L1. It is zero on
+	// chains without an L1Oracle.
+	L1DataFee *assets.Wei
+	// Total is ExecutionFee plus L1DataFee
+	Total *assets.Wei
+}
+
+// EvmTotalFeeEstimator is implemented by the EvmFeeEstimator returned by
+// NewEstimator
+type EvmTotalFeeEstimator interface {
+	GetTotalFee(ctx context.Context, calldata []byte, feeLimit uint32, maxFeePrice *assets.Wei, opts ...txmgrtypes.Opt) (TotalFee, error)
+}
+
+var _ EvmTotalFeeEstimator = (*WrappedEvmEstimator)(nil)
+
+// GetTotalFee returns the fee that GetFee returns along with the maximum total
+// cost of the transaction. On OP-stack chains that includes the L1 data fee,
+// which dominates the cost of calldata-heavy transactions. The L1 data fee is
+// estimated from the calldata alone, so it leaves out the few bytes of RLP
+// encoding of the other transaction fields.
+func (e WrappedEvmEstimator) GetTotalFee(ctx context.Context, calldata []byte, feeLimit uint32, maxFeePrice *assets.Wei, opts ...txmgrtypes.Opt) (total TotalFee, err error) {
+	total.Fee, total.ChainSpecificFeeLimit, err = e.GetFee(ctx, calldata, feeLimit, maxFeePrice, opts...)
+	if err != nil {
+		return
+	}
+	price := total.Fee.Legacy
+	if total.Fee.ValidDynamic() {
+		price = total.Fee.DynamicFeeCap
+	}
+	total.ExecutionFee = price.Mul(big.NewInt(int64(total.ChainSpecificFeeLimit)))
+
+	total.L1DataFee = assets.NewWeiI(0)
+	if e.l1Oracle != nil {
+		total.L1DataFee, err = e.l1Oracle.GetL1DataFee(ctx, calldata)
+		if err != nil {
+			err = errors.Wrap(err, "failed to get L1 data fee")
+			return
+		}
+	}
+	total.Total = total.ExecutionFee.Add(total.L1DataFee)
+	return
+}
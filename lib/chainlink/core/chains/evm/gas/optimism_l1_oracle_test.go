@@ -0,0 +1,211 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclimocks "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// gasPriceOracleGetters maps the method IDs of the GasPriceOracle getters to
+// their names
+var gasPriceOracleGetters = func() map[string]string {
+	getters := make(map[string]string)
+	for _, name := range []string{"l1BaseFee", "decimals", "overhead", "scalar", "blobBaseFee", "baseFeeScalar", "blobBaseFeeScalar"} {
+		getters[string(crypto.Keccak256([]byte(name + "()"))[:4])] = name
+	}
+	return getters
+}()
+
+func encodeUint256(t *testing.T, v *big.Int) hexutil.Bytes {
+	uint256, err := abi.NewType("uint256", "", nil)
+	require.NoError(t, err)
+	b, err := abi.Arguments{{Type: uint256}}.Pack(v)
+	require.NoError(t, err)
+	return b
+}
+
+// mockGasPriceOracle answers the batched getter calls with the ABI-encoded
+// values, and reverts the getters without a value
+func mockGasPriceOracle(t *testing.T, m *mock.Mock, values map[string]*big.Int) *mock.Call {
+	return m.On("BatchCallContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		reqs := args.Get(1).([]rpc.BatchElem)
+		for i := range reqs {
+			require.Equal(t, "eth_call", reqs[i].Method)
+			call := reqs[i].Args[0].(map[string]interface{})
+			require.Equal(t, gas.OPGasOracleAddress, call["to"])
+			getter, ok := gasPriceOracleGetters[string(call["data"].(hexutil.Bytes)[:4])]
+			require.True(t, ok)
+			if v, ok := values[getter]; ok {
+				*reqs[i].Result.(*hexutil.Bytes) = encodeUint256(t, v)
+			} else {
+				reqs[i].Error = errors.New("execution reverted")
+			}
+		}
+	}).Return(nil)
+}
+
+var (
+	bedrockParams = map[string]*big.Int{
+		"l1BaseFee": big.NewInt(20_000_000_000),
+		"decimals":  big.NewInt(6),
+		"overhead":  big.NewInt(188),
+		"scalar":    big.NewInt(684_000),
+	}
+	ecotoneParams = map[string]*big.Int{
+		"l1BaseFee":         big.NewInt(20_000_000_000),
+		"decimals":          big.NewInt(6),
+		"blobBaseFee":       big.NewInt(1_000_000_000),
+		"baseFeeScalar":     big.NewInt(1368),
+		"blobBaseFeeScalar": big.NewInt(810_949),
+	}
+	// l1Payload uses 4 + 16 + 16 calldata gas, plus 68 * 16 for the signature
+	l1Payload = []byte{0x00, 0x01, 0x02}
+)
+
+func TestOptimismL1Oracle(t *testing.T) {
+	t.Parallel()
+
+	t.Run("computes the Bedrock fee from overhead and scalar", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPriceOracle(t, &client.Mock, bedrockParams).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, time.Minute)
+
+		fee, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		require.NoError(t, err)
+		// (1124 + 188) * 20 gwei * 684000 / 10^6
+		assert.Equal(t, assets.NewWeiI(17_948_160_000_000), fee)
+	})
+
+	t.Run("computes the Ecotone fee from the base fee and blob base fee scalars", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPriceOracle(t, &client.Mock, ecotoneParams).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, time.Minute)
+
+		fee, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		require.NoError(t, err)
+		// 1124 * (16 * 1368 * 20 gwei + 810949 * 1 gwei) / (16 * 10^6)
+		assert.Equal(t, assets.NewWeiI(87_721_807_250), fee)
+	})
+
+	t.Run("prefers the Bedrock formula while its getters succeed", func(t *testing.T) {
+		both := make(map[string]*big.Int)
+		for getter, v := range ecotoneParams {
+			both[getter] = v
+		}
+		for getter, v := range bedrockParams {
+			both[getter] = v
+		}
+		client := mocks.NewRPCClient(t)
+		mockGasPriceOracle(t, &client.Mock, both).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, time.Minute)
+
+		fee, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(17_948_160_000_000), fee)
+	})
+
+	t.Run("caches the parameters for the refresh interval", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPriceOracle(t, &client.Mock, bedrockParams).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, time.Minute)
+
+		for i := 0; i < 3; i++ {
+			_, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("keeps the previous parameters if a refresh fails", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPriceOracle(t, &client.Mock, ecotoneParams).Once()
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("kaboom")).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, 0)
+
+		fee, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(87_721_807_250), fee)
+
+		fee, err = oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(87_721_807_250), fee)
+	})
+
+	t.Run("returns an error if the parameters were never fetched", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(errors.New("kaboom")).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, time.Minute)
+
+		_, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		assert.EqualError(t, err, "failed to fetch L1 fee parameters: kaboom")
+	})
+
+	t.Run("calls getL1Fee if neither formula is detected", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockGasPriceOracle(t, &client.Mock, map[string]*big.Int{"l1BaseFee": big.NewInt(1)}).Once()
+		client.On("CallContext", mock.Anything, mock.IsType(&hexutil.Bytes{}), "eth_call", mock.Anything, "latest").Run(func(args mock.Arguments) {
+			call := args.Get(3).(map[string]interface{})
+			assert.Equal(t, gas.OPGasOracleAddress, call["to"])
+			assert.Equal(t, crypto.Keccak256([]byte("getL1Fee(bytes)"))[:4], []byte(call["data"].(hexutil.Bytes)[:4]))
+			*args.Get(1).(*hexutil.Bytes) = encodeUint256(t, big.NewInt(12345))
+		}).Return(nil).Once()
+		oracle := gas.NewOptimismL1Oracle(logger.TestLogger(t), client, time.Minute)
+
+		fee, err := oracle.GetL1DataFee(testutils.Context(t), l1Payload)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(12345), fee)
+	})
+}
+
+func TestWrappedEvmEstimator_GetTotalFee(t *testing.T) {
+	t.Parallel()
+
+	newConfig := func(chainType string) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.ChainTypeF = chainType
+		cfg.GasEstimatorModeF = "FixedPrice"
+		cfg.EvmGasPriceDefaultF = assets.GWei(1)
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmGasLimitMultiplierF = 1
+		return cfg
+	}
+
+	t.Run("adds the L1 data fee on OP-stack chains", func(t *testing.T) {
+		ethClient := evmclimocks.NewClient(t)
+		mockGasPriceOracle(t, &ethClient.Mock, bedrockParams).Once()
+		estimator := gas.NewEstimator(logger.TestLogger(t), ethClient, newConfig("optimismBedrock"), nil)
+
+		total, err := estimator.(gas.EvmTotalFeeEstimator).GetTotalFee(testutils.Context(t), l1Payload, 21_000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(1), total.Fee.Legacy)
+		assert.Equal(t, uint32(21_000), total.ChainSpecificFeeLimit)
+		assert.Equal(t, assets.GWei(21_000), total.ExecutionFee)
+		assert.Equal(t, assets.NewWeiI(17_948_160_000_000), total.L1DataFee)
+		assert.Equal(t, assets.NewWeiI(21_000_000_000_000+17_948_160_000_000), total.Total)
+	})
+
+	t.Run("is the execution fee on other chains", func(t *testing.T) {
+		estimator := gas.NewEstimator(logger.TestLogger(t), evmclimocks.NewClient(t), newConfig(""), nil)
+
+		total, err := estimator.(gas.EvmTotalFeeEstimator).GetTotalFee(testutils.Context(t), l1Payload, 21_000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(21_000), total.ExecutionFee)
+		assert.Equal(t, assets.NewWeiI(0), total.L1DataFee)
+		assert.Equal(t, assets.GWei(21_000), total.Total)
+	})
+}
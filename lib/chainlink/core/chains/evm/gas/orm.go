@@ -0,0 +1,63 @@
+package gas
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+
+	"github.com/pkg/errors"
+	"github.com/smartcontractkit/sqlx"
+
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/services/pg"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+var _ BlockHistoryStore = (*orm)(nil)
+
+type orm struct {
+	q pg.Q
+}
+
+// NewORM returns a BlockHistoryStore that persists the blocks in the
+// evm_gas_estimator_blocks table
+func NewORM(db *sqlx.DB, lggr logger.Logger, cfg pg.QConfig) BlockHistoryStore {
+	return &orm{pg.NewQ(db, lggr.Named("GasEstimatorORM"), cfg)}
+}
+
+func (o *orm) SaveBlocks(ctx context.Context, chainID *big.Int, blocks []evmtypes.Block) error {
+	q := o.q.WithOpts(pg.WithParentCtx(ctx))
+	err := q.Transaction(func(tx pg.Queryer) error {
+		if _, err := tx.Exec(`DELETE FROM evm_gas_estimator_blocks WHERE evm_chain_id = $1`, utils.NewBig(chainID)); err != nil {
+			return err
+		}
+		for _, block := range blocks {
+			b, err := json.Marshal(block)
+			if err != nil {
+				return errors.Wrapf(err, "failed to marshal block %d", block.Number)
+			}
+			if _, err = tx.Exec(`INSERT INTO evm_gas_estimator_blocks (evm_chain_id, number, hash, block, created_at) VALUES ($1, $2, $3, $4, NOW())`,
+				utils.NewBig(chainID), block.Number, block.Hash, b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return errors.Wrap(err, "SaveBlocks failed")
+}
+
+func (o *orm) LoadBlocks(ctx context.Context, chainID *big.Int) ([]evmtypes.Block, error) {
+	q := o.q.WithOpts(pg.WithParentCtx(ctx))
+	var rows [][]byte
+	if err := q.Select(&rows, `SELECT block FROM evm_gas_estimator_blocks WHERE evm_chain_id = $1 ORDER BY number ASC`, utils.NewBig(chainID)); err != nil {
+		return nil, errors.Wrap(err, "LoadBlocks failed")
+	}
+	blocks := make([]evmtypes.Block, len(rows))
+	for i, row := range rows {
+		if err := json.Unmarshal(row, &blocks[i]); err != nil {
+			return nil, errors.Wrap(err, "LoadBlocks failed to unmarshal block")
+		}
+	}
+	return blocks, nil
+}
@@ -0,0 +1,56 @@
+package gas_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/cltest"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/pgtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestORM_BlockHistory(t *testing.T) {
+	t.Parallel()
+
+	db := pgtest.NewSqlxDB(t)
+	orm := gas.NewORM(db, logger.TestLogger(t), pgtest.NewQConfig(true))
+	ctx := testutils.Context(t)
+	chainID := &cltest.FixtureChainID
+
+	blocks, err := orm.LoadBlocks(ctx, chainID)
+	require.NoError(t, err)
+	assert.Empty(t, blocks)
+
+	saved := []evmtypes.Block{
+		{Number: 41, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(10, 20)},
+		{Number: 42, Hash: utils.NewHash(), Transactions: cltest.LegacyTransactionsFromGasPrices(30)},
+	}
+	require.NoError(t, orm.SaveBlocks(ctx, chainID, saved))
+
+	blocks, err = orm.LoadBlocks(ctx, chainID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	for i := range saved {
+		assert.Equal(t, saved[i].Number, blocks[i].Number)
+		assert.Equal(t, saved[i].Hash, blocks[i].Hash)
+		require.Len(t, blocks[i].Transactions, len(saved[i].Transactions))
+		for j := range saved[i].Transactions {
+			assert.Equal(t, saved[i].Transactions[j].GasPrice, blocks[i].Transactions[j].GasPrice)
+		}
+	}
+
+	// saving replaces the persisted blocks
+	replacement := evmtypes.Block{Number: 43, Hash: utils.NewHash()}
+	require.NoError(t, orm.SaveBlocks(ctx, chainID, []evmtypes.Block{replacement}))
+
+	blocks, err = orm.LoadBlocks(ctx, chainID)
+	require.NoError(t, err)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, replacement.Hash, blocks[0].Hash)
+}
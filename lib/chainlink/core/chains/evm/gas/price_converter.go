@@ -0,0 +1,125 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// defaultPriceConversionTimeout bounds each call to the PriceConverter, which
+// is made on every estimation
+const defaultPriceConversionTimeout = 500 * time.Millisecond
+
+// PriceConverter converts amounts of the chain's native currency to another
+// currency, such as USD or the chain's wrapped gas token, to report the cost of
+// the estimated fees in it. See WrappedEvmEstimator.SetPriceConverter.
+type PriceConverter interface {
+	// NativeToFiat returns the value of wei in the converter's currency
+	NativeToFiat(ctx context.Context, wei *assets.Wei) (decimal.Decimal, error)
+}
+
+// SetPriceConverter sets the converter that the cost of the estimated fees is
+// reported in, as EvmFee.ConvertedCost, along with the conversion rate as a
+// metric labelled with chainID. It must be called before the estimator is
+// used.
+func (e *WrappedEvmEstimator) SetPriceConverter(chainID *big.Int, converter PriceConverter) {
+	e.priceConverter = converter
+	e.conversionRate = promGasEstimatorConversionRate.WithLabelValues(chainID.String())
+}
+
+// withConvertedCost sets the ConvertedCost of fee with the PriceConverter, if
+// any. The conversion is bounded by a short timeout, and if it fails the cost
+// is left out rather than failing the estimation.
+func (e WrappedEvmEstimator) withConvertedCost(ctx context.Context, fee EvmFee, gasLimit uint32) EvmFee {
+	if e.priceConverter == nil {
+		return fee
+	}
+	cost := txCost(fee, gasLimit)
+	ctx, cancel := context.WithTimeout(ctx, e.conversionTimeout)
+	defer cancel()
+	converted, err := e.priceConverter.NativeToFiat(ctx, cost)
+	if err != nil {
+		e.lggr.Debugw("Failed to convert the cost of the fee, leaving it out", "fee", fee, "cost", cost, "err", err)
+		return fee
+	}
+	fee.ConvertedCost = &converted
+	if !cost.IsZero() {
+		// the rate is per whole native token (10^18 wei)
+		rate := converted.Div(decimal.NewFromBigInt(cost.ToInt(), -18))
+		e.conversionRate.Set(rate.InexactFloat64())
+	}
+	return fee
+}
+
+var _ PriceConverter = (*HTTPPriceConverter)(nil)
+
+// HTTPPriceConverter is a PriceConverter backed by an HTTP price feed that
+// responds with the price of one native token in JSON, such as
+// https://api.coingecko.com/api/v3/simple/price?ids=ethereum&vs_currencies=usd
+// with the path "ethereum.usd". The price is cached for the TTL given to
+// NewHTTPPriceConverter.
+type HTTPPriceConverter struct {
+	client *http.Client
+	url    *url.URL
+	path   string
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	price     decimal.Decimal
+	fetchedAt time.Time
+}
+
+// NewHTTPPriceConverter returns a converter that queries u for the price of
+// one native token, found at the dot separated path of the response, as with
+// the paths of EVM.GasEstimator.ExternalAPI. The price is queried at most once
+// per ttl.
+func NewHTTPPriceConverter(u *url.URL, path string, ttl time.Duration) *HTTPPriceConverter {
+	return &HTTPPriceConverter{
+		client: &http.Client{},
+		url:    u,
+		path:   path,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+// NativeToFiat returns the value of wei at the price of the feed
+func (c *HTTPPriceConverter) NativeToFiat(ctx context.Context, wei *assets.Wei) (decimal.Decimal, error) {
+	price, err := c.getPrice(ctx)
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	return decimal.NewFromBigInt(wei.ToInt(), -18).Mul(price), nil
+}
+
+// getPrice returns the cached price, querying the feed if it is older than the
+// TTL
+func (c *HTTPPriceConverter) getPrice(ctx context.Context) (decimal.Decimal, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.fetchedAt.IsZero() && c.now().Sub(c.fetchedAt) < c.ttl {
+		return c.price, nil
+	}
+	body, err := getJSON(ctx, c.client, c.url, "price feed")
+	if err != nil {
+		return decimal.Decimal{}, err
+	}
+	price, err := jsonDecimal(body, c.path)
+	if err != nil {
+		return decimal.Decimal{}, errors.Wrap(err, "malformed price from the price feed")
+	}
+	if !price.IsPositive() {
+		return decimal.Decimal{}, errors.Errorf("price feed returned a non-positive price: %s", price)
+	}
+	c.price, c.fetchedAt = price, c.now()
+	return price, nil
+}
@@ -0,0 +1,165 @@
+package gas_test
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// stubPriceConverter converts at a fixed price per native token, or fails
+// with err
+type stubPriceConverter struct {
+	price decimal.Decimal
+	err   error
+	// block makes the conversion wait for the context to be done
+	block bool
+}
+
+func (c *stubPriceConverter) NativeToFiat(ctx context.Context, wei *assets.Wei) (decimal.Decimal, error) {
+	if c.block {
+		<-ctx.Done()
+		return decimal.Decimal{}, ctx.Err()
+	}
+	if c.err != nil {
+		return decimal.Decimal{}, c.err
+	}
+	return decimal.NewFromBigInt(wei.ToInt(), -18).Mul(c.price), nil
+}
+
+func TestWrappedEvmEstimator_PriceConverter(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 21_000
+	chainID := big.NewInt(1)
+	newEstimator := func(t *testing.T, converter gas.PriceConverter) gas.EvmFeeEstimator {
+		cfg := gas.NewMockConfig()
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(assets.GWei(20), gasLimit, nil).Maybe()
+		e.On("BumpLegacyGas", mock.Anything, assets.GWei(20), gasLimit, mock.Anything, mock.Anything).Return(assets.GWei(24), gasLimit, nil).Maybe()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil)
+		if converter != nil {
+			estimator.(*gas.WrappedEvmEstimator).SetPriceConverter(chainID, converter)
+		}
+		return estimator
+	}
+
+	t.Run("leaves out the converted cost without a converter", func(t *testing.T) {
+		fee, _, err := newEstimator(t, nil).GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Nil(t, fee.ConvertedCost)
+	})
+
+	t.Run("converts the cost of the fee", func(t *testing.T) {
+		estimator := newEstimator(t, &stubPriceConverter{price: decimal.NewFromInt(2000)})
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		require.NotNil(t, fee.ConvertedCost)
+		// 20 gwei * 21000 = 0.00042 ETH
+		assert.Equal(t, "0.84", fee.ConvertedCost.String())
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(20)}, gasLimit, nil, nil)
+		require.NoError(t, err)
+		require.NotNil(t, bumped.ConvertedCost)
+		assert.Equal(t, "1.008", bumped.ConvertedCost.String())
+	})
+
+	t.Run("swallows converter errors", func(t *testing.T) {
+		estimator := newEstimator(t, &stubPriceConverter{err: errors.New("price feed is down")})
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(20), fee.Legacy)
+		assert.Nil(t, fee.ConvertedCost)
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(20)}, gasLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(24), bumped.Legacy)
+		assert.Nil(t, bumped.ConvertedCost)
+	})
+
+	t.Run("times out slow converters", func(t *testing.T) {
+		estimator := newEstimator(t, &stubPriceConverter{block: true})
+		gas.SetPriceConversionTimeout(estimator, 10*time.Millisecond)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(20), fee.Legacy)
+		assert.Nil(t, fee.ConvertedCost)
+	})
+}
+
+func TestHTTPPriceConverter(t *testing.T) {
+	t.Parallel()
+
+	newFeed := func(t *testing.T, status int, body string) (*url.URL, *atomic.Int32) {
+		requests := new(atomic.Int32)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests.Add(1)
+			w.WriteHeader(status)
+			fmt.Fprint(w, body)
+		}))
+		t.Cleanup(srv.Close)
+		u, err := url.Parse(srv.URL)
+		require.NoError(t, err)
+		return u, requests
+	}
+
+	t.Run("converts at the price of the feed and caches it", func(t *testing.T) {
+		u, requests := newFeed(t, http.StatusOK, `{"ethereum":{"usd":2500.5}}`)
+		converter := gas.NewHTTPPriceConverter(u, "ethereum.usd", time.Minute)
+		now := time.Now()
+		gas.SetHTTPPriceConverterClock(converter, func() time.Time { return now })
+
+		converted, err := converter.NativeToFiat(testutils.Context(t), assets.Ether(2))
+		require.NoError(t, err)
+		assert.Equal(t, "5001", converted.String())
+		_, err = converter.NativeToFiat(testutils.Context(t), assets.Ether(1))
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), requests.Load())
+
+		now = now.Add(time.Minute)
+		_, err = converter.NativeToFiat(testutils.Context(t), assets.Ether(1))
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	for _, tc := range []struct {
+		name   string
+		status int
+		body   string
+		err    string
+	}{
+		{"error status", http.StatusTooManyRequests, `{}`, "price feed responded with status 429"},
+		{"malformed response", http.StatusOK, `{"ethereum":`, "malformed response from the price feed"},
+		{"missing price", http.StatusOK, `{"bitcoin":{"usd":60000}}`, "field ethereum.usd not found"},
+		{"zero price", http.StatusOK, `{"ethereum":{"usd":0}}`, "non-positive price"},
+	} {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			u, _ := newFeed(t, tc.status, tc.body)
+			_, err := gas.NewHTTPPriceConverter(u, "ethereum.usd", time.Minute).NativeToFiat(testutils.Context(t), assets.Ether(1))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tc.err)
+		})
+	}
+}
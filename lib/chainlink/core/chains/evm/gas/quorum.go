@@ -0,0 +1,123 @@
+package gas
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/multierr"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// defaultQuorumCallTimeout is how long each client of a quorum has to respond
+const defaultQuorumCallTimeout = 5 * time.Second
+
+// quorum is a set of clients that are queried for the same prices, so that a
+// single client suggesting absurd prices can't set them on its own
+type quorum struct {
+	clients []rpcClient
+	// min is the number of clients that must respond for their prices to be used
+	min         int
+	callTimeout time.Duration
+}
+
+// singleClient returns the quorum of a single client, which behaves like the
+// client on its own
+func singleClient(client rpcClient) quorum {
+	return quorum{clients: []rpcClient{client}, min: 1, callTimeout: defaultQuorumCallTimeout}
+}
+
+// newQuorum returns the quorum of the clients, or an error if minQuorum is not
+// between 1 and the number of clients
+func newQuorum(minQuorum int, clients []rpcClient) (quorum, error) {
+	if len(clients) == 0 {
+		return quorum{}, errors.New("quorum must have at least one client")
+	}
+	if minQuorum < 1 || minQuorum > len(clients) {
+		return quorum{}, errors.Errorf("minimum quorum of %d must be between 1 and the number of clients, %d", minQuorum, len(clients))
+	}
+	return quorum{clients: clients, min: minQuorum, callTimeout: defaultQuorumCallTimeout}, nil
+}
+
+// queryQuorum calls query with each of the clients concurrently, limiting each
+// call to the quorum's call timeout. It returns the results of the clients
+// that succeeded and the errors of the ones that failed, by client index.
+func queryQuorum[T any](ctx context.Context, q quorum, query func(context.Context, rpcClient) (T, error)) (results map[int]T, errs map[int]error) {
+	results = make(map[int]T, len(q.clients))
+	errs = make(map[int]error)
+	if len(q.clients) == 1 {
+		// No need for goroutines or a separate timeout with a single client
+		res, err := query(ctx, q.clients[0])
+		if err != nil {
+			errs[0] = err
+		} else {
+			results[0] = res
+		}
+		return
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, client := range q.clients {
+		wg.Add(1)
+		go func(i int, client rpcClient) {
+			defer wg.Done()
+			ctx, cancel := context.WithTimeout(ctx, q.callTimeout)
+			defer cancel()
+			res, err := query(ctx, client)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i] = res
+		}(i, client)
+	}
+	wg.Wait()
+	return
+}
+
+// checkQuorum returns an error if fewer than the minimum number of clients
+// responded, combining the errors of the failed clients. A single client's
+// error is returned as it is.
+func (q quorum) checkQuorum(responded int, errs map[int]error) error {
+	if responded >= q.min {
+		return nil
+	}
+	var err error
+	for _, i := range failedClients(errs) {
+		err = multierr.Append(err, errs[i])
+	}
+	if err == nil || len(q.clients) > 1 {
+		err = multierr.Append(errors.Errorf("%d of %d clients responded, fewer than the minimum quorum of %d", responded, len(q.clients), q.min), err)
+	}
+	return err
+}
+
+// failedClients returns the indexes of the failed clients in ascending order
+func failedClients(errs map[int]error) []int {
+	indexes := make([]int, 0, len(errs))
+	for i := range errs {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// medianWei returns the median of the prices. For an even number of prices it
+// is the lower of the two middle prices, so that it is always one of the
+// prices and an outlier can't pull it up.
+func medianWei(prices []*assets.Wei) *assets.Wei {
+	if len(prices) == 0 {
+		return nil
+	}
+	sorted := make([]*assets.Wei, len(prices))
+	copy(sorted, prices)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Cmp(sorted[j]) < 0 })
+	return sorted[(len(sorted)-1)/2]
+}
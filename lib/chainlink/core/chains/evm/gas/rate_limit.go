@@ -0,0 +1,200 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/jpillora/backoff"
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+)
+
+const (
+	// rpcBackoffMin and rpcBackoffMax bound the backoff after a rate limited
+	// RPC call
+	rpcBackoffMin = 250 * time.Millisecond
+	rpcBackoffMax = 10 * time.Second
+	// rpcRateLimitMaxRetries is how many times a rate limited RPC call is
+	// retried before its error is returned
+	rpcRateLimitMaxRetries = 5
+)
+
+// rpcClock is the time source of an rpcLimiter
+type rpcClock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// rpcLimiter limits the RPC calls of the estimators of a chain to
+// EVM.GasEstimator.RPCRateLimit requests per second, with bursts of up to
+// EVM.GasEstimator.RPCRateLimitBurst, and backs off exponentially when the
+// node rate limits them anyway
+type rpcLimiter struct {
+	limiter *rate.Limiter
+	clock   rpcClock
+
+	backoffMu sync.Mutex
+	backoff   backoff.Backoff
+}
+
+func newRPCLimiter(requestsPerSecond, burst uint32, clock rpcClock) *rpcLimiter {
+	return &rpcLimiter{
+		limiter: rate.NewLimiter(rate.Limit(requestsPerSecond), int(burst)),
+		clock:   clock,
+		backoff: backoff.Backoff{Min: rpcBackoffMin, Max: rpcBackoffMax, Factor: 2, Jitter: true},
+	}
+}
+
+// rpcLimiters are the limiters by chain ID, so that all the estimators of a
+// chain share its budget
+var rpcLimiters = struct {
+	sync.Mutex
+	m map[string]*rpcLimiter
+}{m: make(map[string]*rpcLimiter)}
+
+// rpcLimiterFor returns the limiter of the chain, updated to the limits of cfg
+func rpcLimiterFor(chainID *big.Int, cfg Config) *rpcLimiter {
+	requestsPerSecond, burst := cfg.EvmGasRPCRateLimit(), cfg.EvmGasRPCRateLimitBurst()
+
+	rpcLimiters.Lock()
+	defer rpcLimiters.Unlock()
+	l, ok := rpcLimiters.m[chainID.String()]
+	if !ok {
+		l = newRPCLimiter(requestsPerSecond, burst, realClock{})
+		rpcLimiters.m[chainID.String()] = l
+		return l
+	}
+	now := l.clock.Now()
+	l.limiter.SetLimitAt(now, rate.Limit(requestsPerSecond))
+	l.limiter.SetBurstAt(now, int(burst))
+	return l
+}
+
+// wait blocks until n requests are allowed. Batches larger than the burst
+// wait for the whole burst, since they could never be allowed otherwise.
+func (l *rpcLimiter) wait(ctx context.Context, n int) error {
+	if burst := l.limiter.Burst(); n > burst {
+		n = burst
+	}
+	now := l.clock.Now()
+	r := l.limiter.ReserveN(now, n)
+	if !r.OK() {
+		return errors.Errorf("rate limiter cannot allow %d requests", n)
+	}
+	delay := r.DelayFrom(now)
+	if delay <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		r.CancelAt(l.clock.Now())
+		return ctx.Err()
+	case <-l.clock.After(delay):
+		return nil
+	}
+}
+
+// do calls call, counting as n requests, and retries it with exponential
+// backoff while the node rate limits it
+func (l *rpcLimiter) do(ctx context.Context, n int, call func() error) error {
+	for retries := 0; ; retries++ {
+		if err := l.wait(ctx, n); err != nil {
+			return err
+		}
+		err := call()
+		if !isRateLimitError(err) {
+			if err == nil {
+				l.resetBackoff()
+			}
+			return err
+		}
+		if retries >= rpcRateLimitMaxRetries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-l.clock.After(l.nextBackoff()):
+		}
+	}
+}
+
+func (l *rpcLimiter) nextBackoff() time.Duration {
+	l.backoffMu.Lock()
+	defer l.backoffMu.Unlock()
+	return l.backoff.Duration()
+}
+
+func (l *rpcLimiter) resetBackoff() {
+	l.backoffMu.Lock()
+	defer l.backoffMu.Unlock()
+	l.backoff.Reset()
+}
+
+// isRateLimitError returns true if err is an HTTP 429, or a JSON-RPC error
+// that the node returns when it rate limits the caller
+func isRateLimitError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	if jErr := evmclient.ExtractRPCErrorOrNil(err); jErr != nil && (jErr.Code == -32005 || jErr.Code == http.StatusTooManyRequests) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "too many requests") || strings.Contains(msg, "rate limit")
+}
+
+// rateLimitedRPCClient applies a limiter to the calls of an rpcClient.
+// Batches count as one request per element.
+type rateLimitedRPCClient struct {
+	client  rpcClient
+	limiter *rpcLimiter
+}
+
+func (c rateLimitedRPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.limiter.do(ctx, 1, func() error {
+		return c.client.CallContext(ctx, result, method, args...)
+	})
+}
+
+func (c rateLimitedRPCClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.limiter.do(ctx, len(b), func() error {
+		return c.client.BatchCallContext(ctx, b)
+	})
+}
+
+// rateLimitedClient applies a limiter to the CallContext and BatchCallContext
+// of an evmclient.Client, for the estimators that need the full client
+type rateLimitedClient struct {
+	evmclient.Client
+	rpc rateLimitedRPCClient
+}
+
+func newRateLimitedClient(client evmclient.Client, limiter *rpcLimiter) evmclient.Client {
+	return rateLimitedClient{Client: client, rpc: rateLimitedRPCClient{client: client, limiter: limiter}}
+}
+
+func (c rateLimitedClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.rpc.CallContext(ctx, result, method, args...)
+}
+
+func (c rateLimitedClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.rpc.BatchCallContext(ctx, b)
+}
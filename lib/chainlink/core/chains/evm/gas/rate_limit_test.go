@@ -0,0 +1,164 @@
+package gas_test
+
+import (
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+)
+
+var _ gas.RPCClock = (*fakeClock)(nil)
+
+// fakeClock advances by the full duration as soon as it is waited for, and
+// records the waits
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	waits []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(1_700_000_000, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.waits = append(c.waits, d)
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.waits...)
+}
+
+func TestRateLimitedRPCClient(t *testing.T) {
+	t.Parallel()
+
+	rateLimited := &evmclient.JsonError{Code: -32005, Message: "limit exceeded"}
+
+	t.Run("spaces calls beyond the burst by the rate", func(t *testing.T) {
+		clock := newFakeClock()
+		start := clock.Now()
+		client := mocks.NewRPCClient(t)
+		var callTimes []time.Duration
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Run(func(mock.Arguments) {
+			callTimes = append(callTimes, clock.Now().Sub(start))
+		}).Times(5)
+		limited := gas.NewRateLimitedRPCClient(client, 10, 2, clock)
+
+		for i := 0; i < 5; i++ {
+			require.NoError(t, limited.CallContext(testutils.Context(t), nil, "eth_gasPrice"))
+		}
+		assert.Equal(t, []time.Duration{0, 0, 100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond}, callTimes)
+	})
+
+	t.Run("counts each element of a batch as a request", func(t *testing.T) {
+		clock := newFakeClock()
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Return(nil).Times(3)
+		limited := gas.NewRateLimitedRPCClient(client, 10, 4, clock)
+
+		require.NoError(t, limited.BatchCallContext(testutils.Context(t), make([]rpc.BatchElem, 3)))
+		require.NoError(t, limited.BatchCallContext(testutils.Context(t), make([]rpc.BatchElem, 3)))
+		// batches larger than the burst wait for the whole burst
+		require.NoError(t, limited.BatchCallContext(testutils.Context(t), make([]rpc.BatchElem, 10)))
+		assert.Equal(t, []time.Duration{200 * time.Millisecond, 400 * time.Millisecond}, clock.Waits())
+	})
+
+	t.Run("backs off exponentially on rate limit errors and resets after a success", func(t *testing.T) {
+		clock := newFakeClock()
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(rateLimited).Twice()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(rateLimited).Once()
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Once()
+		limited := gas.NewRateLimitedRPCClient(client, 1000, 1000, clock)
+
+		require.NoError(t, limited.CallContext(testutils.Context(t), nil, "eth_gasPrice"))
+		waits := clock.Waits()
+		require.Len(t, waits, 2)
+		assert.Equal(t, gas.RPCBackoffMin, waits[0])
+		// the second backoff is jittered between the first and twice the first
+		assert.GreaterOrEqual(t, waits[1], gas.RPCBackoffMin)
+		assert.LessOrEqual(t, waits[1], 2*gas.RPCBackoffMin)
+
+		require.NoError(t, limited.CallContext(testutils.Context(t), nil, "eth_gasPrice"))
+		waits = clock.Waits()
+		require.Len(t, waits, 3)
+		assert.Equal(t, gas.RPCBackoffMin, waits[2])
+	})
+
+	t.Run("recognizes 429-style errors", func(t *testing.T) {
+		for _, rateLimitErr := range []error{
+			rateLimited,
+			rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests"},
+			&evmclient.JsonError{Code: 429, Message: "Too Many Requests"},
+			errors.New("daily request count exceeded, request rate limited"),
+		} {
+			clock := newFakeClock()
+			client := mocks.NewRPCClient(t)
+			client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(rateLimitErr).Once()
+			client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(nil).Once()
+			limited := gas.NewRateLimitedRPCClient(client, 1000, 1000, clock)
+
+			assert.NoError(t, limited.CallContext(testutils.Context(t), nil, "eth_gasPrice"), rateLimitErr.Error())
+		}
+	})
+
+	t.Run("returns the error after the last retry", func(t *testing.T) {
+		clock := newFakeClock()
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(rateLimited).Times(gas.RPCRateLimitMaxRetries + 1)
+		limited := gas.NewRateLimitedRPCClient(client, 1000, 1000, clock)
+
+		err := limited.CallContext(testutils.Context(t), nil, "eth_gasPrice")
+		assert.Equal(t, rateLimited, err)
+		assert.Len(t, clock.Waits(), gas.RPCRateLimitMaxRetries)
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		clock := newFakeClock()
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(errors.New("connection refused")).Once()
+		limited := gas.NewRateLimitedRPCClient(client, 1000, 1000, clock)
+
+		assert.EqualError(t, limited.CallContext(testutils.Context(t), nil, "eth_gasPrice"), "connection refused")
+		assert.Empty(t, clock.Waits())
+	})
+}
+
+func TestRPCLimiterFor(t *testing.T) {
+	t.Parallel()
+
+	cfg := gas.NewMockConfig()
+	cfg.EvmGasRPCRateLimitF = 10
+	cfg.EvmGasRPCRateLimitBurstF = 5
+
+	chainID := big.NewInt(987_654_321)
+	limiter := gas.RPCLimiterFor(chainID, cfg)
+	assert.Same(t, limiter, gas.RPCLimiterFor(big.NewInt(987_654_321), cfg), "estimators of a chain share its limiter")
+	assert.NotSame(t, limiter, gas.RPCLimiterFor(big.NewInt(987_654_322), cfg))
+}
@@ -0,0 +1,55 @@
+package gas
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmcfg "github.com/smartcontractkit/chainlink/v2/core/chains/evm/config/v2"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// EstimatorFactory constructs a custom EvmEstimator for a chain. The returned
+// estimator is wrapped by NewEstimator in the same way as the built-in ones.
+type EstimatorFactory func(lggr logger.Logger, ethClient evmclient.Client, cfg Config) EvmEstimator
+
+// builtinEstimatorModes are the GasEstimator.Mode values handled by NewEstimator itself
+var builtinEstimatorModes = evmcfg.GasEstimatorModes
+
+var (
+	estimatorRegistryMu sync.RWMutex
+	estimatorRegistry   = map[string]EstimatorFactory{}
+)
+
+// RegisterEstimator makes a custom estimator available as GasEstimator.Mode
+// name. It is intended to be called at init time, before any chain is started.
+// Registering a name twice, or the name of a built-in mode, returns an error.
+func RegisterEstimator(name string, factory EstimatorFactory) error {
+	if name == "" {
+		return errors.New("estimator name must not be empty")
+	}
+	if factory == nil {
+		return errors.Errorf("estimator factory for %s must not be nil", name)
+	}
+	if slices.Contains(builtinEstimatorModes, name) {
+		return errors.Errorf("estimator %s is already registered as a built-in mode", name)
+	}
+
+	estimatorRegistryMu.Lock()
+	defer estimatorRegistryMu.Unlock()
+	if _, exists := estimatorRegistry[name]; exists {
+		return errors.Errorf("estimator %s is already registered", name)
+	}
+	estimatorRegistry[name] = factory
+	evmcfg.RegisterGasEstimatorMode(name)
+	return nil
+}
+
+func lookupEstimator(name string) (EstimatorFactory, bool) {
+	estimatorRegistryMu.RLock()
+	defer estimatorRegistryMu.RUnlock()
+	factory, ok := estimatorRegistry[name]
+	return factory, ok
+}
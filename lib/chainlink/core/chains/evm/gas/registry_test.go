@@ -0,0 +1,92 @@
+package gas_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmclimocks "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestRegisterEstimator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("custom mode is constructed by NewEstimator", func(t *testing.T) {
+		const name = "TestRegisterEstimatorStub"
+		t.Cleanup(func() { gas.UnregisterEstimator(name) })
+
+		stub := mocks.NewEvmEstimator(t)
+		stub.On("GetLegacyGas", mock.Anything, []byte{0x01}, uint32(21_000), assets.GWei(100)).Return(assets.GWei(7), uint32(21_000), nil).Once()
+
+		ethClient := evmclimocks.NewClient(t)
+		var calls int
+		require.NoError(t, gas.RegisterEstimator(name, func(lggr logger.Logger, client evmclient.Client, cfg gas.Config) gas.EvmEstimator {
+			calls++
+			assert.Equal(t, ethClient, client)
+			return stub
+		}))
+
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = name
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+
+		estimator := gas.NewEstimator(logger.TestLogger(t), ethClient, cfg, nil)
+		assert.Equal(t, 1, calls)
+
+		fee, limit, err := estimator.GetFee(testutils.Context(t), []byte{0x01}, 21_000, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(7), fee.Legacy)
+		assert.Equal(t, uint32(21_000), limit)
+	})
+
+	t.Run("duplicate name returns error", func(t *testing.T) {
+		const name = "TestRegisterEstimatorDuplicate"
+		t.Cleanup(func() { gas.UnregisterEstimator(name) })
+
+		factory := func(logger.Logger, evmclient.Client, gas.Config) gas.EvmEstimator { return nil }
+		require.NoError(t, gas.RegisterEstimator(name, factory))
+		assert.EqualError(t, gas.RegisterEstimator(name, factory), "estimator TestRegisterEstimatorDuplicate is already registered")
+	})
+
+	t.Run("built-in mode returns error", func(t *testing.T) {
+		factory := func(logger.Logger, evmclient.Client, gas.Config) gas.EvmEstimator { return nil }
+		assert.EqualError(t, gas.RegisterEstimator("BlockHistory", factory), "estimator BlockHistory is already registered as a built-in mode")
+	})
+
+	t.Run("invalid registration returns error", func(t *testing.T) {
+		factory := func(logger.Logger, evmclient.Client, gas.Config) gas.EvmEstimator { return nil }
+		assert.EqualError(t, gas.RegisterEstimator("", factory), "estimator name must not be empty")
+		assert.EqualError(t, gas.RegisterEstimator("TestRegisterEstimatorNil", nil), "estimator factory for TestRegisterEstimatorNil must not be nil")
+	})
+
+	t.Run("concurrent lookups are safe", func(t *testing.T) {
+		const name = "TestRegisterEstimatorConcurrent"
+		t.Cleanup(func() { gas.UnregisterEstimator(name) })
+		require.NoError(t, gas.RegisterEstimator(name, func(logger.Logger, evmclient.Client, gas.Config) gas.EvmEstimator {
+			return mocks.NewEvmEstimator(t)
+		}))
+
+		cfg := gas.NewMockConfig()
+		cfg.GasEstimatorModeF = name
+		ethClient := evmclimocks.NewClient(t)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NotNil(t, gas.NewEstimator(logger.TestLogger(t), ethClient, cfg, nil))
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,300 @@
+package gas
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// RPCRecord is an RPC call and its response, one line of the JSONL files
+// written by RecordingRPCClient and served by ReplayRPCClient. Each element of
+// a batch call is recorded as a separate call.
+type RPCRecord struct {
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+// encodeParams encodes the args of a call as a compact JSON array
+func encodeParams(args []interface{}) (json.RawMessage, error) {
+	if args == nil {
+		args = []interface{}{}
+	}
+	b, err := json.Marshal(args)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode params")
+	}
+	return b, nil
+}
+
+// RecordingRPCClient passes the calls of the estimators through to a client and
+// writes every request and response to w as a JSONL RPCRecord, so that they
+// can be served by a ReplayRPCClient later. The responses are recorded as the
+// node returned them, before they are decoded.
+type RecordingRPCClient struct {
+	client rpcClient
+
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewRecordingRPCClient returns a RecordingRPCClient that records the calls of
+// client to w, e.g. a file
+func NewRecordingRPCClient(client rpcClient, w io.Writer) *RecordingRPCClient {
+	return &RecordingRPCClient{client: client, enc: json.NewEncoder(w)}
+}
+
+func (c *RecordingRPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	var raw json.RawMessage
+	err := c.client.CallContext(ctx, &raw, method, args...)
+	if err == nil && result != nil {
+		err = json.Unmarshal(raw, result)
+	}
+	if rErr := c.record(method, args, raw, err); rErr != nil {
+		return rErr
+	}
+	return err
+}
+
+// BatchCallContext records each element of b separately. If the batch call
+// itself fails, its error is recorded for every element.
+func (c *RecordingRPCClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	raws := make([]json.RawMessage, len(b))
+	reqs := make([]rpc.BatchElem, len(b))
+	for i := range b {
+		reqs[i] = rpc.BatchElem{Method: b[i].Method, Args: b[i].Args, Result: &raws[i]}
+	}
+	err := c.client.BatchCallContext(ctx, reqs)
+	for i := range b {
+		elemErr := err
+		if err == nil {
+			elemErr = reqs[i].Error
+			if elemErr == nil && b[i].Result != nil {
+				elemErr = json.Unmarshal(raws[i], b[i].Result)
+			}
+			b[i].Error = elemErr
+		}
+		if rErr := c.record(b[i].Method, b[i].Args, raws[i], elemErr); rErr != nil {
+			return rErr
+		}
+	}
+	return err
+}
+
+func (c *RecordingRPCClient) record(method string, args []interface{}, result json.RawMessage, err error) error {
+	params, pErr := encodeParams(args)
+	if pErr != nil {
+		return errors.Wrapf(pErr, "failed to record %s", method)
+	}
+	rec := RPCRecord{Method: method, Params: params}
+	if err != nil {
+		rec.Error = err.Error()
+	} else {
+		rec.Result = result
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return errors.Wrapf(c.enc.Encode(rec), "failed to record %s", method)
+}
+
+// ReplayRPCClient serves the calls of the estimators from the RPCRecords
+// written by a RecordingRPCClient.
+//
+// By default a call is answered by the first unused record with the same
+// method and params, and the last of them is reused once they are all used, so
+// polling the same call converges on its last recorded response. In strict
+// mode every call must instead match the next record of the file, in order.
+type ReplayRPCClient struct {
+	strict bool
+
+	mu      sync.Mutex
+	records []RPCRecord
+	next    int
+	used    []bool
+}
+
+// NewReplayRPCClient reads the JSONL RPCRecords in r. If strict is true the
+// calls must be made in the order in which they were recorded.
+func NewReplayRPCClient(r io.Reader, strict bool) (*ReplayRPCClient, error) {
+	var records []RPCRecord
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		b := bytes.TrimSpace(scanner.Bytes())
+		if len(b) == 0 {
+			continue
+		}
+		var rec RPCRecord
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return nil, errors.Wrapf(err, "failed to decode RPC record on line %d", line)
+		}
+		params, err := compactParams(rec.Params)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid params of RPC record on line %d", line)
+		}
+		rec.Params = params
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to read RPC records")
+	}
+	return &ReplayRPCClient{strict: strict, records: records, used: make([]bool, len(records))}, nil
+}
+
+func compactParams(params json.RawMessage) (json.RawMessage, error) {
+	if len(params) == 0 || bytes.Equal(params, []byte("null")) {
+		return json.RawMessage("[]"), nil
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, params); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Pending returns the number of records that have not been served yet
+func (c *ReplayRPCClient) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, used := range c.used {
+		if !used {
+			n++
+		}
+	}
+	return n
+}
+
+func (c *ReplayRPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	rec, err := c.lookup(method, args)
+	if err != nil {
+		return err
+	}
+	return rec.decode(result)
+}
+
+func (c *ReplayRPCClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	for i := range b {
+		rec, err := c.lookup(b[i].Method, b[i].Args)
+		if err != nil {
+			return err
+		}
+		b[i].Error = rec.decode(b[i].Result)
+	}
+	return nil
+}
+
+// lookup returns the record of a call
+func (c *ReplayRPCClient) lookup(method string, args []interface{}) (RPCRecord, error) {
+	params, err := encodeParams(args)
+	if err != nil {
+		return RPCRecord{}, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.strict {
+		if c.next >= len(c.records) {
+			return RPCRecord{}, errors.Errorf("replay: unexpected call %s %s after the last record", method, params)
+		}
+		rec := c.records[c.next]
+		if rec.Method != method || !bytes.Equal(rec.Params, params) {
+			return RPCRecord{}, errors.Errorf("replay: expected call %s %s as record %d, got %s %s", rec.Method, rec.Params, c.next+1, method, params)
+		}
+		c.used[c.next] = true
+		c.next++
+		return rec, nil
+	}
+
+	last := -1
+	for i, rec := range c.records {
+		if rec.Method != method || !bytes.Equal(rec.Params, params) {
+			continue
+		}
+		if !c.used[i] {
+			c.used[i] = true
+			return rec, nil
+		}
+		last = i
+	}
+	if last < 0 {
+		return RPCRecord{}, errors.Errorf("replay: no record of call %s %s", method, params)
+	}
+	return c.records[last], nil
+}
+
+func (r RPCRecord) decode(result interface{}) error {
+	if r.Error != "" {
+		return errors.New(r.Error)
+	}
+	if result == nil {
+		return nil
+	}
+	res := r.Result
+	if len(res) == 0 {
+		res = json.RawMessage("null")
+	}
+	return errors.Wrapf(json.Unmarshal(res, result), "replay: failed to decode result of %s", r.Method)
+}
+
+// rpcOverrideClient serves the CallContext, BatchCallContext and HeadByNumber
+// of an evmclient.Client from an rpcClient
+type rpcOverrideClient struct {
+	evmclient.Client
+	rpc rpcClient
+}
+
+// NewRecordingClient returns a client that records the CallContext,
+// BatchCallContext and HeadByNumber calls of client to w with a
+// RecordingRPCClient, so that an estimator of any mode can be run with it
+// against a live node. To replay the heads too, fetch every new head with
+// HeadByNumber of the returned client before passing it to the estimator.
+func NewRecordingClient(client evmclient.Client, w io.Writer) evmclient.Client {
+	return rpcOverrideClient{Client: client, rpc: NewRecordingRPCClient(client, w)}
+}
+
+// NewReplayClient returns a client that serves the CallContext,
+// BatchCallContext and HeadByNumber calls from replay, and passes any other
+// calls, e.g. ConfiguredChainID, to client
+func NewReplayClient(replay *ReplayRPCClient, client evmclient.Client) evmclient.Client {
+	return rpcOverrideClient{Client: client, rpc: replay}
+}
+
+func (c rpcOverrideClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.rpc.CallContext(ctx, result, method, args...)
+}
+
+func (c rpcOverrideClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.rpc.BatchCallContext(ctx, b)
+}
+
+func (c rpcOverrideClient) HeadByNumber(ctx context.Context, number *big.Int) (head *evmtypes.Head, err error) {
+	err = c.rpc.CallContext(ctx, &head, "eth_getBlockByNumber", evmclient.ToBlockNumArg(number), false)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil {
+		return nil, ethereum.NotFound
+	}
+	head.EVMChainID = utils.NewBig(c.ConfiguredChainID())
+	return head, nil
+}
@@ -0,0 +1,134 @@
+package gas_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/gastestutils"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+)
+
+func TestRecordingRPCClient(t *testing.T) {
+	t.Parallel()
+
+	client := mocks.NewRPCClient(t)
+	client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Run(func(args mock.Arguments) {
+		*args.Get(1).(*json.RawMessage) = json.RawMessage(`"0x2a"`)
+	}).Return(nil).Once()
+	client.On("CallContext", mock.Anything, mock.Anything, "eth_maxPriorityFeePerGas").Return(errors.New("method not found")).Once()
+	client.On("BatchCallContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		elems := args.Get(1).([]rpc.BatchElem)
+		*elems[0].Result.(*json.RawMessage) = json.RawMessage(`{"number":"0x1"}`)
+		elems[1].Error = errors.New("block not found")
+	}).Return(nil).Once()
+
+	var buf bytes.Buffer
+	recorder := gas.NewRecordingRPCClient(client, &buf)
+	ctx := testutils.Context(t)
+
+	var price hexutil.Big
+	require.NoError(t, recorder.CallContext(ctx, &price, "eth_gasPrice"))
+	assert.Equal(t, int64(42), price.ToInt().Int64())
+	require.EqualError(t, recorder.CallContext(ctx, &price, "eth_maxPriorityFeePerGas"), "method not found")
+
+	var block1, block2 map[string]string
+	batch := []rpc.BatchElem{
+		{Method: "eth_getBlockByNumber", Args: []interface{}{"0x1", true}, Result: &block1},
+		{Method: "eth_getBlockByNumber", Args: []interface{}{"0x2", true}, Result: &block2},
+	}
+	require.NoError(t, recorder.BatchCallContext(ctx, batch))
+	assert.Equal(t, map[string]string{"number": "0x1"}, block1)
+	require.NoError(t, batch[0].Error)
+	require.EqualError(t, batch[1].Error, "block not found")
+
+	assert.Equal(t, `{"method":"eth_gasPrice","params":[],"result":"0x2a"}
+{"method":"eth_maxPriorityFeePerGas","params":[],"error":"method not found"}
+{"method":"eth_getBlockByNumber","params":["0x1",true],"result":{"number":"0x1"}}
+{"method":"eth_getBlockByNumber","params":["0x2",true],"error":"block not found"}
+`, buf.String())
+}
+
+func TestReplayRPCClient(t *testing.T) {
+	t.Parallel()
+
+	const recording = `{"method":"eth_gasPrice","params":[],"result":"0x1"}
+{"method":"eth_getBlockByNumber","params":[ "0x1", true ],"result":{"number":"0x1"}}
+
+{"method":"eth_gasPrice","result":"0x2"}
+{"method":"eth_maxPriorityFeePerGas","params":[],"error":"method not found"}
+`
+	ctx := testutils.Context(t)
+
+	t.Run("matches on method and params", func(t *testing.T) {
+		replay, err := gas.NewReplayRPCClient(strings.NewReader(recording), false)
+		require.NoError(t, err)
+		assert.Equal(t, 4, replay.Pending())
+
+		var block map[string]string
+		batch := []rpc.BatchElem{{Method: "eth_getBlockByNumber", Args: []interface{}{"0x1", true}, Result: &block}}
+		require.NoError(t, replay.BatchCallContext(ctx, batch))
+		require.NoError(t, batch[0].Error)
+		assert.Equal(t, map[string]string{"number": "0x1"}, block)
+
+		var price hexutil.Big
+		require.NoError(t, replay.CallContext(ctx, &price, "eth_gasPrice"))
+		assert.Equal(t, int64(1), price.ToInt().Int64())
+		require.NoError(t, replay.CallContext(ctx, &price, "eth_gasPrice"))
+		assert.Equal(t, int64(2), price.ToInt().Int64())
+		// the last response is reused
+		require.NoError(t, replay.CallContext(ctx, &price, "eth_gasPrice"))
+		assert.Equal(t, int64(2), price.ToInt().Int64())
+
+		require.EqualError(t, replay.CallContext(ctx, &price, "eth_maxPriorityFeePerGas"), "method not found")
+		assert.Equal(t, 0, replay.Pending())
+
+		require.EqualError(t, replay.CallContext(ctx, &price, "eth_gasPrice", "latest"), `replay: no record of call eth_gasPrice ["latest"]`)
+	})
+
+	t.Run("strict mode requires the recorded order", func(t *testing.T) {
+		replay, err := gas.NewReplayRPCClient(strings.NewReader(recording), true)
+		require.NoError(t, err)
+
+		var price hexutil.Big
+		require.NoError(t, replay.CallContext(ctx, &price, "eth_gasPrice"))
+		assert.Equal(t, int64(1), price.ToInt().Int64())
+		err = replay.CallContext(ctx, &price, "eth_gasPrice")
+		require.EqualError(t, err, `replay: expected call eth_getBlockByNumber ["0x1",true] as record 2, got eth_gasPrice []`)
+	})
+
+	t.Run("fails on invalid records", func(t *testing.T) {
+		_, err := gas.NewReplayRPCClient(strings.NewReader("{\"method\":\"eth_gasPrice\"}\nnot json\n"), false)
+		require.ErrorContains(t, err, "failed to decode RPC record on line 2")
+	})
+}
+
+func TestReplay_BlockHistoryEstimator(t *testing.T) {
+	t.Parallel()
+
+	cfg := gas.NewMockConfig()
+	cfg.BlockHistoryEstimatorBlockHistorySizeF = 2
+	cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+	cfg.EvmMaxGasPriceWeiF = assets.GWei(1000)
+	cfg.EvmMinGasPriceWeiF = assets.GWei(1)
+	cfg.EvmGasPriceDefaultF = assets.GWei(5)
+
+	replay := gastestutils.NewReplay(t, "BlockHistory", cfg, "testdata/block_history_replay.jsonl", true)
+	// blocks 1 and 2 were fetched on start
+	replay.AssertFee(t, 21000, gas.EvmFee{Legacy: assets.GWei(20)})
+
+	replay.OnHead(t, 3)
+	replay.AssertFee(t, 21000, gas.EvmFee{Legacy: assets.GWei(40)})
+	assert.Equal(t, 0, replay.RPC.Pending())
+}
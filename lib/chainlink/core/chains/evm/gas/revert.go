@@ -0,0 +1,174 @@
+package gas
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+)
+
+// panicReasons are the reasons of the standard Solidity panic codes
+//
+// https://docs.soliditylang.org/en/latest/control-structures.html#panic-via-assert-and-error-via-require
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic underflow or overflow",
+	0x12: "division or modulo by zero",
+	0x21: "conversion to an invalid enum value",
+	0x22: "incorrectly encoded storage byte array",
+	0x31: "pop on an empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to a zero-initialized internal function",
+}
+
+var (
+	revertErrorsMu sync.RWMutex
+	// revertErrors are the custom errors registered with AddABI, by selector
+	revertErrors = map[[4]byte]abi.Error{}
+)
+
+// AddABI registers the custom errors of a contract ABI, so that DecodeRevert
+// decodes their revert data. It is intended to be called at init time.
+// Registering an error whose selector is already taken by a different error
+// returns an error.
+func AddABI(contractABI abi.ABI) error {
+	revertErrorsMu.Lock()
+	defer revertErrorsMu.Unlock()
+	for _, e := range contractABI.Errors {
+		var selector [4]byte
+		copy(selector[:], e.ID[:4])
+		if bytes.Equal(selector[:], errorSelector) || bytes.Equal(selector[:], panicSelector) {
+			return errors.Errorf("custom error %s has the selector of a built-in error", e.Sig)
+		}
+		if existing, ok := revertErrors[selector]; ok && existing.Sig != e.Sig {
+			return errors.Errorf("custom error %s has the same selector %s as %s", e.Sig, hexutil.Encode(selector[:]), existing.Sig)
+		}
+		revertErrors[selector] = e
+	}
+	return nil
+}
+
+func lookupRevertError(selector [4]byte) (abi.Error, bool) {
+	revertErrorsMu.RLock()
+	defer revertErrorsMu.RUnlock()
+	e, ok := revertErrors[selector]
+	return e, ok
+}
+
+// DecodedRevertError is a revert whose data was decoded as Error(string),
+// Panic(uint256) or a custom error registered with AddABI
+type DecodedRevertError struct {
+	// Selector is the first 4 bytes of Data
+	Selector [4]byte
+	// Name is the name of the error, e.g. Error, Panic or the custom error's
+	Name string
+	// Args are the decoded arguments of the error: the reason string of
+	// Error, the *big.Int code of Panic, or the custom error's arguments
+	Args []interface{}
+	// Data is the raw revert data
+	Data []byte
+	// Err is the error of the call that reverted, if any
+	Err error
+}
+
+func (e *DecodedRevertError) Error() string {
+	return "execution reverted: " + e.Reason()
+}
+
+func (e *DecodedRevertError) Unwrap() error {
+	return e.Err
+}
+
+// Reason returns a human readable description of the revert
+func (e *DecodedRevertError) Reason() string {
+	switch {
+	case bytes.Equal(e.Selector[:], errorSelector):
+		return e.Args[0].(string)
+	case bytes.Equal(e.Selector[:], panicSelector):
+		code := e.Args[0].(*big.Int)
+		reason := "panic code " + hexutil.EncodeBig(code)
+		if code.IsUint64() {
+			if desc, ok := panicReasons[code.Uint64()]; ok {
+				reason += " (" + desc + ")"
+			}
+		}
+		return reason
+	}
+	args := make([]string, len(e.Args))
+	for i, arg := range e.Args {
+		args[i] = formatRevertArg(arg)
+	}
+	return e.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// formatRevertArg formats an argument of a custom error. Bytes that are
+// themselves revert data, e.g. the revert of an inner call forwarded by a
+// multicall, are decoded.
+func formatRevertArg(arg interface{}) string {
+	switch v := arg.(type) {
+	case []byte:
+		if inner, err := DecodeRevert(v); err == nil {
+			return inner.Reason()
+		}
+		return hexutil.Encode(v)
+	case [32]byte:
+		return hexutil.Encode(v[:])
+	}
+	return fmt.Sprint(arg)
+}
+
+// DecodeRevert decodes revert data as Error(string), Panic(uint256) or one of
+// the custom errors registered with AddABI. It returns an error if the data is
+// truncated or its selector is unknown.
+func DecodeRevert(data []byte) (*DecodedRevertError, error) {
+	if len(data) < 4 {
+		return nil, errors.Errorf("revert data of %d bytes is too short for a selector", len(data))
+	}
+	decoded := &DecodedRevertError{Data: data}
+	copy(decoded.Selector[:], data[:4])
+
+	switch {
+	case bytes.Equal(data[:4], errorSelector):
+		reason, err := abi.UnpackRevert(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decode Error(string) revert")
+		}
+		decoded.Name, decoded.Args = "Error", []interface{}{reason}
+	case bytes.Equal(data[:4], panicSelector):
+		if len(data) != 4+32 {
+			return nil, errors.Errorf("Panic(uint256) revert data must be 36 bytes, got %d", len(data))
+		}
+		decoded.Name, decoded.Args = "Panic", []interface{}{new(big.Int).SetBytes(data[4:])}
+	default:
+		e, ok := lookupRevertError(decoded.Selector)
+		if !ok {
+			return nil, errors.Errorf("unknown revert selector %s", hexutil.Encode(data[:4]))
+		}
+		args, err := e.Inputs.Unpack(data[4:])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to decode %s revert", e.Sig)
+		}
+		decoded.Name, decoded.Args = e.Name, args
+	}
+	return decoded, nil
+}
+
+// withDecodedRevert returns a DecodedRevertError wrapping err if err is the
+// revert of an eth_call or eth_estimateGas whose data can be decoded, and err
+// otherwise
+func withDecodedRevert(err error) error {
+	revertErr, ok := asRevertError(err)
+	if !ok || revertErr.Decoded == nil {
+		return err
+	}
+	decoded := *revertErr.Decoded
+	decoded.Err = err
+	return &decoded
+}
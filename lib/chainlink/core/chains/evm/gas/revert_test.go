@@ -0,0 +1,153 @@
+package gas_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// revertTestABI declares custom errors with names unique to this test, since
+// AddABI registers them for the whole package
+const revertTestABI = `[
+	{"type":"error","name":"RevertTestInsufficientBalance","inputs":[{"name":"available","type":"uint256"},{"name":"required","type":"uint256"}]},
+	{"type":"error","name":"RevertTestCallFailed","inputs":[{"name":"target","type":"address"},{"name":"reason","type":"bytes"}]}
+]`
+
+func TestDecodeRevert(t *testing.T) {
+	t.Parallel()
+
+	parsed, err := abi.JSON(strings.NewReader(revertTestABI))
+	require.NoError(t, err)
+	require.NoError(t, gas.AddABI(parsed))
+	// registering the same errors again is fine
+	require.NoError(t, gas.AddABI(parsed))
+
+	panicData := func(code int64) []byte {
+		return append([]byte{0x4e, 0x48, 0x7b, 0x71}, abiWord(big.NewInt(code))...)
+	}
+	customData := func(t *testing.T, name string, args ...interface{}) []byte {
+		e := parsed.Errors[name]
+		b, err := e.Inputs.Pack(args...)
+		require.NoError(t, err)
+		return append(e.ID[:4:4], b...)
+	}
+
+	t.Run("decodes Error(string)", func(t *testing.T) {
+		data := hexutil.MustDecode(encodeErrorRevert(t, "order already filled"))
+		decoded, err := gas.DecodeRevert(data)
+		require.NoError(t, err)
+		assert.Equal(t, "Error", decoded.Name)
+		assert.Equal(t, [4]byte{0x08, 0xc3, 0x79, 0xa0}, decoded.Selector)
+		assert.Equal(t, []interface{}{"order already filled"}, decoded.Args)
+		assert.Equal(t, data, decoded.Data)
+		assert.EqualError(t, decoded, "execution reverted: order already filled")
+	})
+
+	t.Run("decodes Panic(uint256) with the reason of the panic code", func(t *testing.T) {
+		decoded, err := gas.DecodeRevert(panicData(0x11))
+		require.NoError(t, err)
+		assert.Equal(t, "Panic", decoded.Name)
+		assert.Equal(t, []interface{}{big.NewInt(0x11)}, decoded.Args)
+		assert.Equal(t, "panic code 0x11 (arithmetic underflow or overflow)", decoded.Reason())
+
+		decoded, err = gas.DecodeRevert(panicData(0x12))
+		require.NoError(t, err)
+		assert.Equal(t, "panic code 0x12 (division or modulo by zero)", decoded.Reason())
+
+		decoded, err = gas.DecodeRevert(panicData(0x99))
+		require.NoError(t, err)
+		assert.Equal(t, "panic code 0x99", decoded.Reason())
+	})
+
+	t.Run("decodes registered custom errors", func(t *testing.T) {
+		decoded, err := gas.DecodeRevert(customData(t, "RevertTestInsufficientBalance", big.NewInt(10), big.NewInt(42)))
+		require.NoError(t, err)
+		assert.Equal(t, "RevertTestInsufficientBalance", decoded.Name)
+		assert.Equal(t, []interface{}{big.NewInt(10), big.NewInt(42)}, decoded.Args)
+		assert.Equal(t, "RevertTestInsufficientBalance(10, 42)", decoded.Reason())
+	})
+
+	t.Run("decodes nested Panic(0x11) revert data", func(t *testing.T) {
+		target := testutils.NewAddress()
+		decoded, err := gas.DecodeRevert(customData(t, "RevertTestCallFailed", target, panicData(0x11)))
+		require.NoError(t, err)
+		assert.Equal(t, "RevertTestCallFailed("+target.String()+", panic code 0x11 (arithmetic underflow or overflow))", decoded.Reason())
+
+		// bytes that aren't revert data are shown as hex
+		decoded, err = gas.DecodeRevert(customData(t, "RevertTestCallFailed", target, []byte{0x01, 0x02}))
+		require.NoError(t, err)
+		assert.Equal(t, "RevertTestCallFailed("+target.String()+", 0x0102)", decoded.Reason())
+	})
+
+	t.Run("fails for truncated data", func(t *testing.T) {
+		_, err := gas.DecodeRevert(nil)
+		assert.EqualError(t, err, "revert data of 0 bytes is too short for a selector")
+		_, err = gas.DecodeRevert([]byte{0x08, 0xc3, 0x79})
+		assert.EqualError(t, err, "revert data of 3 bytes is too short for a selector")
+
+		errorData := hexutil.MustDecode(encodeErrorRevert(t, "order already filled"))
+		_, err = gas.DecodeRevert(errorData[:len(errorData)-40])
+		assert.ErrorContains(t, err, "failed to decode Error(string) revert")
+
+		_, err = gas.DecodeRevert(panicData(0x11)[:20])
+		assert.EqualError(t, err, "Panic(uint256) revert data must be 36 bytes, got 20")
+
+		custom := customData(t, "RevertTestInsufficientBalance", big.NewInt(10), big.NewInt(42))
+		_, err = gas.DecodeRevert(custom[:40])
+		assert.ErrorContains(t, err, "failed to decode RevertTestInsufficientBalance(uint256,uint256) revert")
+	})
+
+	t.Run("fails for unknown selectors", func(t *testing.T) {
+		_, err := gas.DecodeRevert([]byte{0xde, 0xad, 0xbe, 0xef, 0x01})
+		assert.EqualError(t, err, "unknown revert selector 0xdeadbeef")
+	})
+
+	t.Run("AddABI rejects selectors of other errors", func(t *testing.T) {
+		// declaring Error(string) as a custom error would shadow the built-in one
+		clash, err := abi.JSON(strings.NewReader(`[{"type":"error","name":"Error","inputs":[{"name":"reason","type":"string"}]}]`))
+		require.NoError(t, err)
+		assert.EqualError(t, gas.AddABI(clash), "custom error Error(string) has the selector of a built-in error")
+	})
+
+	t.Run("wraps estimation failures that revert", func(t *testing.T) {
+		call := gas.EstimateGasCall{From: testutils.NewAddress(), To: testutils.NewAddress(), Data: []byte{0x01}}
+		data := customData(t, "RevertTestInsufficientBalance", big.NewInt(10), big.NewInt(42))
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "zks_estimateFee", mock.Anything).
+			Return(&evmclient.JsonError{Code: 3, Message: "execution reverted", Data: hexutil.Encode(data)}).Once()
+		cfg := gas.NewMockConfig()
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		e := gas.NewZkSyncEstimator(logger.TestLogger(t), cfg, client, *big.NewInt(324))
+		require.NoError(t, e.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, e.Close()) })
+
+		_, _, err := e.GetDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), 100_000, assets.GWei(100))
+		requireEstimationError(t, err, "ZkSync", gas.ErrRPCFailure, "zks_estimateFee failed: execution reverted: RevertTestInsufficientBalance(10, 42)")
+		var decoded *gas.DecodedRevertError
+		require.ErrorAs(t, err, &decoded)
+		assert.Equal(t, data, decoded.Data)
+		// the node's error is still available
+		var jErr *evmclient.JsonError
+		require.ErrorAs(t, err, &jErr)
+		assert.Equal(t, 3, jErr.Code)
+	})
+}
+
+// abiWord left-pads i to a 32 byte ABI word
+func abiWord(i *big.Int) []byte {
+	b := make([]byte, 32)
+	return i.FillBytes(b)
+}
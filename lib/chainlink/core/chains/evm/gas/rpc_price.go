@@ -0,0 +1,97 @@
+package gas
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// rpcPrice is a price in a result of an RPC call. Besides the hex quantities
+// of the JSON-RPC spec, it accepts the encodings that some chains' nodes return
+// instead: hex with leading zeros, decimal strings and JSON numbers.
+type rpcPrice big.Int
+
+// PriceDecodeError is returned, wrapped by the RPC client, when a price
+// returned by the node can't be decoded
+type PriceDecodeError struct {
+	// Input is the JSON value returned by the node
+	Input string
+	Err   error
+}
+
+func (e *PriceDecodeError) Error() string {
+	return "failed to decode price " + e.Input + ": " + e.Err.Error()
+}
+
+func (e *PriceDecodeError) Unwrap() error { return e.Err }
+
+// isPriceDecodeError returns true if err is or wraps a PriceDecodeError
+func isPriceDecodeError(err error) bool {
+	var dErr *PriceDecodeError
+	return errors.As(err, &dErr)
+}
+
+func (p *rpcPrice) UnmarshalJSON(input []byte) error {
+	i, err := decodePrice(bytes.TrimSpace(input))
+	if err != nil {
+		return &PriceDecodeError{Input: string(input), Err: err}
+	}
+	*p = rpcPrice(*i)
+	return nil
+}
+
+// decodePrice decodes a JSON string of a hex or decimal integer, or a JSON
+// number of an integer
+func decodePrice(input []byte) (*big.Int, error) {
+	if len(input) == 0 || bytes.Equal(input, []byte("null")) {
+		return nil, errors.New("price is missing")
+	}
+	var s string
+	if input[0] == '"' {
+		if err := json.Unmarshal(input, &s); err != nil {
+			return nil, err
+		}
+		s = strings.TrimSpace(s)
+	} else {
+		// a JSON number must be an integer, without a fraction or exponent
+		var n json.Number
+		if err := json.Unmarshal(input, &n); err != nil {
+			return nil, err
+		}
+		s = n.String()
+	}
+
+	if s == "" {
+		return nil, errors.New("price is empty")
+	}
+	base, digits := 10, s
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		base, digits = 16, s[2:]
+		if digits == "" {
+			return nil, errors.New("hex price has no digits")
+		}
+	}
+	i, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		return nil, errors.Errorf("price must be a non-negative integer, got %q", s)
+	}
+	if i.BitLen() > 256 {
+		return nil, errors.Errorf("price %s overflows uint256", s)
+	}
+	return i, nil
+}
+
+// ToInt returns the price as a *big.Int
+func (p *rpcPrice) ToInt() *big.Int {
+	return (*big.Int)(p)
+}
+
+// Wei returns the price as a *assets.Wei
+func (p *rpcPrice) Wei() *assets.Wei {
+	return (*assets.Wei)(p)
+}
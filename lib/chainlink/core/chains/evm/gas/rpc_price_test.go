@@ -0,0 +1,125 @@
+package gas_test
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestDecodeRPCPrice(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		input string
+		exp   int64
+	}{
+		{`"0x0"`, 0},
+		{`"0x01"`, 1},
+		{`"0x3B9ACA00"`, 1_000_000_000},
+		{`"1000000000"`, 1_000_000_000},
+		{`1000000000`, 1_000_000_000},
+		{`" 0x2a "`, 42},
+	} {
+		tc := tc
+		t.Run(tc.input, func(t *testing.T) {
+			price, err := gas.DecodeRPCPrice(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, big.NewInt(tc.exp), price)
+		})
+	}
+
+	for _, input := range []string{
+		`""`,
+		`"0x"`,
+		`null`,
+		`"-1"`,
+		`"0x-1"`,
+		`-1`,
+		`1.5`,
+		`1e9`,
+		`"gwei"`,
+		`"0x10000000000000000000000000000000000000000000000000000000000000000"`,
+		`{}`,
+	} {
+		input := input
+		t.Run(input, func(t *testing.T) {
+			_, err := gas.DecodeRPCPrice(input)
+			var dErr *gas.PriceDecodeError
+			require.ErrorAs(t, err, &dErr)
+			assert.Equal(t, input, dErr.Input)
+		})
+	}
+}
+
+func TestL2SuggestedEstimator_DecodesPrices(t *testing.T) {
+	t.Parallel()
+
+	mockGasPrice := func(client *mocks.RPCClient, raw string) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(func(_ context.Context, result interface{}, _ string, _ ...interface{}) error {
+			return json.Unmarshal([]byte(raw), result)
+		})
+	}
+
+	t.Run("accepts the encodings of nodes", func(t *testing.T) {
+		for raw, exp := range map[string]*assets.Wei{
+			`"0x0"`:        assets.NewWeiI(0),
+			`"0x01"`:       assets.NewWeiI(1),
+			`"1000000000"`: assets.GWei(1),
+			`1000000000`:   assets.GWei(1),
+		} {
+			raw, exp := raw, exp
+			t.Run(raw, func(t *testing.T) {
+				client := mocks.NewRPCClient(t)
+				mockGasPrice(client, raw).Once()
+				o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *testutils.NewRandomEVMChainID())
+				require.NoError(t, o.Start(testutils.Context(t)))
+				t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+				gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), nil, 21_000, assets.GWei(100))
+				require.NoError(t, err)
+				assert.Equal(t, exp, gasPrice)
+			})
+		}
+	})
+
+	t.Run("keeps the previous price on a decode error", func(t *testing.T) {
+		chainID := testutils.NewRandomEVMChainID()
+		labels := []string{chainID.String(), "L2Suggested"}
+		client := mocks.NewRPCClient(t)
+		mockGasPrice(client, `"0x2a"`).Once()
+		o := gas.NewL2SuggestedPriceEstimator(logger.TestLogger(t), gas.NewMockConfig(), client, *chainID)
+		require.NoError(t, o.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, o.Close()) })
+
+		mockGasPrice(client, `""`).Once()
+		err := o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t))
+		var dErr *gas.PriceDecodeError
+		require.ErrorAs(t, err, &dErr)
+		assert.Equal(t, `""`, dErr.Input)
+		assert.Equal(t, float64(1), promtestutil.ToFloat64(gas.PromGasEstimatorDecodeErrorCount.WithLabelValues(labels...)))
+		assert.Equal(t, float64(0), promtestutil.ToFloat64(gas.PromGasEstimatorRPCErrorCount.WithLabelValues(labels...)))
+
+		gasPrice, _, err := o.GetLegacyGas(testutils.Context(t), nil, 21_000, assets.GWei(100))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(42), gasPrice)
+
+		// the next refresh updates the price
+		mockGasPrice(client, `"43"`).Once()
+		require.NoError(t, o.(gas.ForceRefresher).ForceRefresh(testutils.Context(t)))
+		gasPrice, _, err = o.GetLegacyGas(testutils.Context(t), nil, 21_000, assets.GWei(100))
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(43), gasPrice)
+	})
+}
@@ -0,0 +1,97 @@
+package gas
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+)
+
+// RPCTimeoutError is returned when an RPC call of an estimator doesn't return
+// within EVM.GasEstimator.RPCCallTimeout. Unlike the expiry of the caller's
+// context, it only fails the call that timed out, so callers should check for
+// it before checking for context.DeadlineExceeded.
+type RPCTimeoutError struct {
+	Method  string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *RPCTimeoutError) Error() string {
+	return fmt.Sprintf("RPC call %s timed out after %s: %s", e.Method, e.Timeout, e.Err)
+}
+
+func (e *RPCTimeoutError) Unwrap() error { return e.Err }
+
+// isRPCTimeout returns true if err is or wraps an RPCTimeoutError
+func isRPCTimeout(err error) bool {
+	var tErr *RPCTimeoutError
+	return errors.As(err, &tErr)
+}
+
+// timeoutRPCClient bounds each call of an rpcClient by
+// EVM.GasEstimator.RPCCallTimeout, so that a hung connection fails the call
+// instead of stalling the loop that made it until the connection gives up
+type timeoutRPCClient struct {
+	client  rpcClient
+	timeout time.Duration
+}
+
+func (c timeoutRPCClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	return c.wrap(ctx, callCtx, method, c.client.CallContext(callCtx, result, method, args...))
+}
+
+// BatchCallContext bounds the whole batch by the timeout. The elements that
+// the client decoded before the timeout keep their results.
+func (c timeoutRPCClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	callCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	method := "batch"
+	if len(b) > 0 {
+		method = fmt.Sprintf("batch of %d %s", len(b), b[0].Method)
+	}
+	return c.wrap(ctx, callCtx, method, c.client.BatchCallContext(callCtx, b))
+}
+
+// wrap returns err as an RPCTimeoutError if the call failed because it timed
+// out, rather than because ctx expired
+func (c timeoutRPCClient) wrap(ctx, callCtx context.Context, method string, err error) error {
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return &RPCTimeoutError{Method: method, Timeout: c.timeout, Err: err}
+	}
+	return err
+}
+
+// timeoutClient applies EVM.GasEstimator.RPCCallTimeout to the calls of an
+// evmclient.Client made by the estimators
+type timeoutClient struct {
+	evmclient.Client
+	rpc timeoutRPCClient
+}
+
+func newTimeoutClient(client evmclient.Client, timeout time.Duration) evmclient.Client {
+	return timeoutClient{Client: client, rpc: timeoutRPCClient{client: client, timeout: timeout}}
+}
+
+func (c timeoutClient) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return c.rpc.CallContext(ctx, result, method, args...)
+}
+
+func (c timeoutClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.rpc.BatchCallContext(ctx, b)
+}
+
+func (c timeoutClient) HeadByNumber(ctx context.Context, number *big.Int) (*evmtypes.Head, error) {
+	callCtx, cancel := context.WithTimeout(ctx, c.rpc.timeout)
+	defer cancel()
+	head, err := c.Client.HeadByNumber(callCtx, number)
+	return head, c.rpc.wrap(ctx, callCtx, "eth_getBlockByNumber", err)
+}
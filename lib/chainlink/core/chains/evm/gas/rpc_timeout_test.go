@@ -0,0 +1,171 @@
+package gas_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/pkg/errors"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils/evmtest"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+func TestTimeoutRPCClient(t *testing.T) {
+	t.Parallel()
+
+	const timeout = 50 * time.Millisecond
+	// hang blocks the call until its context is done
+	hang := func(ctx context.Context, _ interface{}, _ string, _ ...interface{}) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	t.Run("fails hung calls after the timeout", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(hang).Once()
+
+		start := time.Now()
+		var res interface{}
+		err := gas.NewTimeoutRPCClient(client, timeout).CallContext(testutils.Context(t), &res, "eth_gasPrice")
+		assert.Less(t, time.Since(start), testutils.WaitTimeout(t))
+
+		var tErr *gas.RPCTimeoutError
+		require.ErrorAs(t, err, &tErr)
+		assert.Equal(t, "eth_gasPrice", tErr.Method)
+		assert.Equal(t, timeout, tErr.Timeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.EqualError(t, err, "RPC call eth_gasPrice timed out after 50ms: context deadline exceeded")
+	})
+
+	t.Run("does not report the expiry of the caller's context as a timeout", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Return(hang).Once()
+
+		ctx, cancel := context.WithTimeout(testutils.Context(t), time.Millisecond)
+		defer cancel()
+		var res interface{}
+		err := gas.NewTimeoutRPCClient(client, time.Minute).CallContext(ctx, &res, "eth_gasPrice")
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		var tErr *gas.RPCTimeoutError
+		assert.False(t, errors.As(err, &tErr))
+	})
+
+	t.Run("bounds batches", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("BatchCallContext", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.DeadlineExceeded).Once()
+
+		err := gas.NewTimeoutRPCClient(client, timeout).BatchCallContext(testutils.Context(t), []rpc.BatchElem{{Method: "eth_getBlockByNumber"}})
+		assert.EqualError(t, err, "RPC call batch of 1 eth_getBlockByNumber timed out after 50ms: context deadline exceeded")
+	})
+}
+
+func TestRPCCallTimeout_L2SuggestedEstimatorRecovers(t *testing.T) {
+	t.Parallel()
+
+	cfg := gas.NewMockConfig()
+	cfg.GasEstimatorModeF = "L2Suggested"
+	cfg.EvmGasRPCCallTimeoutF = 100 * time.Millisecond
+	cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+
+	ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+	// the initial refresh hangs, without the timeout it would block Start
+	// until evmclient.ContextWithDefaultTimeout
+	ethClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(context.DeadlineExceeded).Once()
+	ethClient.On("CallContext", mock.Anything, mock.Anything, "eth_gasPrice").Run(func(args mock.Arguments) {
+		gas.SetRPCPrice(args.Get(1), 42)
+	}).Return(nil)
+
+	estimator := gas.NewEstimator(logger.TestLogger(t), ethClient, cfg, nil)
+	timeouts := gas.PromGasEstimatorRPCTimeoutCount.WithLabelValues(testutils.FixtureChainID.String(), "L2Suggested")
+	before := promtestutil.ToFloat64(timeouts)
+
+	started := make(chan error)
+	go func() { started <- estimator.Start(testutils.Context(t)) }()
+	select {
+	case err := <-started:
+		require.NoError(t, err)
+	case <-time.After(testutils.WaitTimeout(t)):
+		t.Fatal("timed out waiting for the estimator to start")
+	}
+	t.Cleanup(func() { assert.NoError(t, estimator.Close()) })
+	assert.Equal(t, before+1, promtestutil.ToFloat64(timeouts))
+
+	fee, _, err := estimator.GetFee(testutils.Context(t), nil, 21000, nil, txmgrtypes.OptForceRefetch)
+	require.NoError(t, err)
+	assert.Equal(t, assets.NewWeiI(42), fee.Legacy)
+}
+
+func TestRPCCallTimeout_BlockHistoryEstimatorKeepsPartialBatch(t *testing.T) {
+	t.Parallel()
+
+	cfg := gas.NewMockConfig()
+	cfg.BlockHistoryEstimatorBlockHistorySizeF = 2
+	cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+
+	ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+	block42 := evmtypes.Block{Number: 42, Hash: utils.NewHash()}
+	// the node returns block 42 and then hangs before returning block 41
+	ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+		return len(b) == 2 && b[0].Args[0] == gas.Int64ToHex(42) && b[1].Args[0] == gas.Int64ToHex(41)
+	})).Run(func(args mock.Arguments) {
+		elems := args.Get(1).([]rpc.BatchElem)
+		*elems[0].Result.(*evmtypes.Block) = block42
+		<-args.Get(0).(context.Context).Done()
+	}).Return(context.DeadlineExceeded).Once()
+
+	bhe := newBlockHistoryEstimator(t, gas.NewTimeoutClient(ethClient, 100*time.Millisecond), cfg)
+	head := &evmtypes.Head{Hash: block42.Hash, Number: 42}
+	require.NoError(t, bhe.FetchBlocks(testutils.Context(t), head))
+
+	blocks := gas.GetRollingBlockHistory(bhe)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, block42, blocks[0])
+}
+
+func TestRPCCallTimeout_BlockHistoryEstimatorSkipsTimedOutBatch(t *testing.T) {
+	t.Parallel()
+
+	cfg := gas.NewMockConfig()
+	cfg.BlockHistoryEstimatorBlockHistorySizeF = 2
+	cfg.BlockHistoryEstimatorBatchSizeF = 1
+	cfg.BlockHistoryEstimatorTransactionPercentileF = 50
+
+	ethClient := evmtest.NewEthClientMockWithDefaultChain(t)
+	block41 := evmtypes.Block{Number: 41, Hash: utils.NewHash()}
+	// the node hangs on the batch of block 42, but returns the next batch
+	ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+		return len(b) == 1 && b[0].Args[0] == gas.Int64ToHex(42)
+	})).Run(func(args mock.Arguments) {
+		<-args.Get(0).(context.Context).Done()
+	}).Return(context.DeadlineExceeded).Once()
+	ethClient.On("BatchCallContext", mock.Anything, mock.MatchedBy(func(b []rpc.BatchElem) bool {
+		return len(b) == 1 && b[0].Args[0] == gas.Int64ToHex(41)
+	})).Run(func(args mock.Arguments) {
+		*args.Get(1).([]rpc.BatchElem)[0].Result.(*evmtypes.Block) = block41
+	}).Return(nil).Once()
+
+	bhe := newBlockHistoryEstimator(t, gas.NewTimeoutClient(ethClient, 100*time.Millisecond), cfg)
+	head := &evmtypes.Head{Hash: utils.NewHash(), Number: 42}
+	require.NoError(t, bhe.FetchBlocks(testutils.Context(t), head))
+
+	blocks := gas.GetRollingBlockHistory(bhe)
+	require.Len(t, blocks, 1)
+	assert.Equal(t, block41, blocks[0])
+}
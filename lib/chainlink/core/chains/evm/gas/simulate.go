@@ -0,0 +1,103 @@
+package gas
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+)
+
+// defaultSimulateBeforeBumpTimeout bounds the simulation of a transaction
+// before bumping it, so that a slow node delays the bump by at most this long
+const defaultSimulateBeforeBumpTimeout = 2 * time.Second
+
+var (
+	// errorSelector is the selector of the Error(string) revert of require and revert
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0}
+	// panicSelector is the selector of the Panic(uint256) revert of failed
+	// asserts, arithmetic overflows etc.
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71}
+)
+
+// RevertError is the error of a simulated call that reverted
+type RevertError struct {
+	// Data is the revert data returned by the node, nil if it returned none
+	Data []byte
+	// Reason is the reason decoded from Data, or the error message of the
+	// node if Data can't be decoded
+	Reason string
+	// Decoded is the decoded Data, nil if it can't be decoded
+	Decoded *DecodedRevertError
+}
+
+func (e *RevertError) Error() string {
+	return "execution reverted: " + e.Reason
+}
+
+func (e *RevertError) Unwrap() error {
+	if e.Decoded == nil {
+		return nil
+	}
+	return e.Decoded
+}
+
+// simulateBeforeBump simulates the call in ctx at the latest block if
+// EVM.GasEstimator.SimulateBeforeBump is enabled, and returns an
+// ErrWouldRevert error if it reverts. Any other failure, e.g. a timeout or a
+// rate limit, is only logged so that it doesn't block the bump.
+func (e WrappedEvmEstimator) simulateBeforeBump(ctx context.Context) error {
+	call, ok := estimateGasCallFromContext(ctx)
+	if !ok || !e.cfg.EvmGasSimulateBeforeBump() {
+		return nil
+	}
+	if e.client == nil {
+		e.lggr.Warn("SimulateBeforeBump is enabled but the estimator has no client; bumping without simulating")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.simulateTimeout)
+	defer cancel()
+	var result hexutil.Bytes
+	err := e.client.CallContext(ctx, &result, "eth_call", call.args(), "latest")
+	if err == nil {
+		return nil
+	}
+	revertErr, ok := asRevertError(err)
+	if !ok {
+		e.lggr.Warnw("Failed to simulate transaction before bumping, bumping anyway", "err", err, "from", call.From, "to", call.To)
+		return nil
+	}
+	e.lggr.Infow("Transaction would revert, not bumping", "reason", revertErr.Reason, "from", call.From, "to", call.To)
+	return &EstimationError{Reason: ErrWouldRevert, Err: errors.Wrap(revertErr, "transaction would revert")}
+}
+
+// asRevertError returns the RevertError of an eth_call error, or false if the
+// call failed for another reason than a revert
+func asRevertError(err error) (*RevertError, bool) {
+	jErr := evmclient.ExtractRPCErrorOrNil(err)
+	if jErr == nil {
+		return nil, false
+	}
+	// Geth returns code 3 for reverts with data. Other clients, and geth for
+	// reverts without data, only say so in the message.
+	dataStr, _ := jErr.Data.(string)
+	if jErr.Code != 3 && !strings.Contains(strings.ToLower(jErr.Message), "revert") && !strings.Contains(strings.ToLower(dataStr), "revert") {
+		return nil, false
+	}
+	revertErr := &RevertError{Reason: jErr.Message}
+	// Parity prefixes the data with "Reverted "
+	if i := strings.Index(dataStr, "0x"); i >= 0 {
+		if data, decodeErr := hexutil.Decode(dataStr[i:]); decodeErr == nil {
+			revertErr.Data = data
+			if decoded, decodeErr := DecodeRevert(data); decodeErr == nil {
+				revertErr.Decoded = decoded
+				revertErr.Reason = decoded.Reason()
+			}
+		}
+	}
+	return revertErr, true
+}
@@ -0,0 +1,155 @@
+package gas_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmclient "github.com/smartcontractkit/chainlink/v2/core/chains/evm/client"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+// encodeErrorRevert returns the revert data of a revert with the given reason
+func encodeErrorRevert(t *testing.T, reason string) string {
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	b, err := abi.Arguments{{Type: stringType}}.Pack(reason)
+	require.NoError(t, err)
+	return hexutil.Encode(append([]byte{0x08, 0xc3, 0x79, 0xa0}, b...))
+}
+
+func TestWrappedEvmEstimator_SimulateBeforeBump(t *testing.T) {
+	t.Parallel()
+
+	const feeLimit uint32 = 100_000
+	call := gas.EstimateGasCall{
+		From: testutils.NewAddress(),
+		To:   testutils.NewAddress(),
+		Data: []byte{0x01, 0x02, 0x03},
+	}
+	originalFee := gas.EvmFee{Legacy: assets.GWei(10)}
+	bumpedPrice := assets.GWei(12)
+
+	newConfig := func(simulate bool) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasSimulateBeforeBumpF = simulate
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		return cfg
+	}
+	newEstimator := func(t *testing.T, bumps bool) *mocks.EvmEstimator {
+		e := mocks.NewEvmEstimator(t)
+		if bumps {
+			e.On("BumpLegacyGas", mock.Anything, originalFee.Legacy, feeLimit, mock.Anything, mock.Anything).Return(bumpedPrice, feeLimit, nil).Once()
+		}
+		return e
+	}
+	mockCall := func(client *mocks.RPCClient, err error) *mock.Call {
+		return client.On("CallContext", mock.Anything, mock.Anything, "eth_call", mock.MatchedBy(func(args map[string]interface{}) bool {
+			return args["from"] == call.From && args["to"] == call.To && assert.ObjectsAreEqual(hexutil.Bytes(call.Data), args["data"])
+		}), "latest").Return(err).Once()
+	}
+
+	t.Run("bumps if the transaction succeeds", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCall(client, nil)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, true), newConfig(true), client)
+
+		fee, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bumpedPrice, fee.Legacy)
+	})
+
+	t.Run("returns ErrWouldRevert with the decoded reason if the transaction reverts", func(t *testing.T) {
+		data := encodeErrorRevert(t, "order already filled")
+		client := mocks.NewRPCClient(t)
+		mockCall(client, &evmclient.JsonError{Code: 3, Message: "execution reverted: order already filled", Data: data})
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, false), newConfig(true), client)
+
+		_, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.ErrorIs(t, err, gas.ErrWouldRevert)
+		assert.True(t, gas.IsBumpErr(err))
+		assert.EqualError(t, err, "transaction would revert: execution reverted: order already filled")
+		var revertErr *gas.RevertError
+		require.ErrorAs(t, err, &revertErr)
+		assert.Equal(t, hexutil.MustDecode(data), revertErr.Data)
+	})
+
+	t.Run("decodes Panic and Parity-style revert data", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		panicData := "0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011"
+		mockCall(client, &evmclient.JsonError{Code: -32015, Message: "VM execution error.", Data: "Reverted " + panicData})
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, false), newConfig(true), client)
+
+		_, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.ErrorIs(t, err, gas.ErrWouldRevert)
+		assert.EqualError(t, err, "transaction would revert: execution reverted: panic code 0x11 (arithmetic underflow or overflow)")
+	})
+
+	t.Run("uses the node's message if the revert data can't be decoded", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCall(client, &evmclient.JsonError{Code: 3, Message: "execution reverted", Data: "0xdeadbeef"})
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, false), newConfig(true), client)
+
+		_, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.ErrorIs(t, err, gas.ErrWouldRevert)
+		var revertErr *gas.RevertError
+		require.ErrorAs(t, err, &revertErr)
+		assert.Equal(t, "execution reverted", revertErr.Reason)
+		assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, revertErr.Data)
+	})
+
+	t.Run("bumps if the simulation fails for another reason", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCall(client, &evmclient.JsonError{Code: -32005, Message: "rate limit exceeded"})
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, true), newConfig(true), client)
+
+		fee, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bumpedPrice, fee.Legacy)
+	})
+
+	t.Run("bumps if the simulation times out", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCall(client, nil).Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).Return(context.DeadlineExceeded)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, true), newConfig(true), client)
+		gas.SetSimulateBeforeBumpTimeout(estimator, 50*time.Millisecond)
+
+		start := time.Now()
+		fee, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, bumpedPrice, fee.Legacy)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("does not simulate without a call or if disabled", func(t *testing.T) {
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, true), newConfig(true), mocks.NewRPCClient(t))
+		_, _, err := estimator.BumpFee(testutils.Context(t), originalFee, feeLimit, nil, nil)
+		require.NoError(t, err)
+
+		estimator = gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, true), newConfig(false), mocks.NewRPCClient(t))
+		_, _, err = estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("bumps if a non-RPC error is returned", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockCall(client, errors.New("connection refused"))
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, true), newConfig(true), client)
+
+		_, _, err := estimator.BumpFee(gas.WithEstimateGasCall(testutils.Context(t), call), originalFee, feeLimit, nil, nil)
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,134 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/pkg/errors"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+)
+
+// txCost returns the most the transaction can cost: its fee cap, or gas price
+// for legacy transactions, times its gas limit
+func txCost(fee EvmFee, gasLimit uint32) *assets.Wei {
+	price := fee.Legacy
+	if fee.ValidDynamic() {
+		price = fee.DynamicFeeCap
+	}
+	return price.Mul(big.NewInt(int64(gasLimit)))
+}
+
+// maxCostPrice returns the highest gas price, or fee cap, at which the
+// transaction costs no more than EVM.GasEstimator.MaxTxCost, nor the balance
+// given with WithAvailableBalance, with the given gas limit. It returns nil if
+// neither applies.
+func (e WrappedEvmEstimator) maxCostPrice(ctx context.Context, gasLimit uint32) *assets.Wei {
+	if gasLimit == 0 {
+		return nil
+	}
+	var price *assets.Wei
+	limit := func(maxCost *assets.Wei) {
+		p := assets.NewWei(new(big.Int).Div(maxCost.ToInt(), big.NewInt(int64(gasLimit))))
+		if price == nil || p.Cmp(price) < 0 {
+			price = p
+		}
+	}
+	if budget, ok := e.txCostBudget(); ok {
+		limit(budget.maxCost)
+	}
+	if balance, ok := availableBalanceLimit(ctx); ok {
+		limit(balance.maxCost)
+	}
+	return price
+}
+
+// costLimit is a limit on what a transaction may cost, i.e.
+// EVM.GasEstimator.MaxTxCost or the balance given with WithAvailableBalance
+type costLimit struct {
+	maxCost *assets.Wei
+	// reason is the error of the fees that exceed maxCost
+	reason error
+	// name describes maxCost in logs and errors
+	name string
+}
+
+// lowerFeeToCost lowers fee so that the transaction costs no more than the
+// limit with the given gas limit, and returns whether it had to. The gas
+// price, or fee cap, is lowered to the max cost divided by the gas limit, and
+// the tip cap along with it, but not below minViableFee, as the transaction
+// couldn't be included below the base fee. If even the minimum viable fee
+// exceeds the limit, an error with its reason is returned.
+func (e WrappedEvmEstimator) lowerFeeToCost(fee EvmFee, gasLimit uint32, limit costLimit) (EvmFee, bool, error) {
+	cost := txCost(fee, gasLimit)
+	if cost.Cmp(limit.maxCost) <= 0 {
+		return fee, false, nil
+	}
+
+	dynamic := fee.ValidDynamic()
+	floor, tipFloor := e.minViableFee(dynamic)
+	minCost := floor.Mul(big.NewInt(int64(gasLimit)))
+	if limit.maxCost.Cmp(minCost) < 0 {
+		return fee, false, &EstimationError{Price: minCost, Limit: limit.maxCost, Reason: limit.reason,
+			Err: errors.Wrapf(limit.reason, "transaction would cost at least %s with gas limit %d at the minimum viable price of %s (estimated cost %s), which exceeds %s of %s",
+				minCost, gasLimit, floor, cost, limit.name, limit.maxCost)}
+	}
+
+	price := assets.NewWei(new(big.Int).Div(limit.maxCost.ToInt(), big.NewInt(int64(gasLimit))))
+	e.lggr.Warnw("Estimated fee exceeds "+limit.name+", lowering it", "fee", fee, "gasLimit", gasLimit, "cost", cost, "maxCost", limit.maxCost, "price", price)
+	if dynamic {
+		// keep the tip cap within what the fee cap leaves over the base fee
+		tipCap := assets.WeiMin(fee.DynamicTipCap, price.Sub(floor).Add(tipFloor))
+		fee.DynamicFeeCap = price
+		fee.DynamicTipCap = assets.WeiMax(tipCap, tipFloor)
+	} else {
+		fee.Legacy = price
+	}
+	return fee, true, nil
+}
+
+// checkFeeCost returns an error with the reason of the limit if the bumped
+// fee would make the transaction cost more than it with the given gas limit.
+// Bumps aren't lowered to the limit like estimates, as a lowered bump would
+// no longer replace the original transaction.
+func checkFeeCost(bumpedFee EvmFee, gasLimit uint32, limit costLimit) error {
+	if cost := txCost(bumpedFee, gasLimit); cost.Cmp(limit.maxCost) > 0 {
+		return &EstimationError{Price: cost, Limit: limit.maxCost, Reason: limit.reason,
+			Err: errors.Wrapf(limit.reason, "bumped fee %s would make the transaction cost %s with gas limit %d, which exceeds %s of %s",
+				bumpedFee, cost, gasLimit, limit.name, limit.maxCost)}
+	}
+	return nil
+}
+
+// txCostBudget returns the limit of EVM.GasEstimator.MaxTxCost, if set
+func (e WrappedEvmEstimator) txCostBudget() (costLimit, bool) {
+	budget := e.cfg.EvmGasMaxTxCost()
+	if budget == nil || budget.IsZero() {
+		return costLimit{}, false
+	}
+	return costLimit{maxCost: budget, reason: ErrTxCostExceedsBudget, name: "the configured max transaction cost"}, true
+}
+
+// applyTxCostBudget lowers fee so that the transaction costs no more than
+// EVM.GasEstimator.MaxTxCost with the given gas limit, see lowerFeeToCost. If
+// even the minimum viable fee is over budget, an ErrTxCostExceedsBudget error
+// is returned.
+func (e WrappedEvmEstimator) applyTxCostBudget(fee EvmFee, gasLimit uint32) (EvmFee, error) {
+	limit, ok := e.txCostBudget()
+	if !ok {
+		return fee, nil
+	}
+	fee, _, err := e.lowerFeeToCost(fee, gasLimit, limit)
+	return fee, err
+}
+
+// checkTxCostBudget returns an ErrTxCostExceedsBudget error if the bumped fee
+// would make the transaction cost more than EVM.GasEstimator.MaxTxCost with
+// the given gas limit
+func (e WrappedEvmEstimator) checkTxCostBudget(bumpedFee EvmFee, gasLimit uint32) error {
+	limit, ok := e.txCostBudget()
+	if !ok {
+		return nil
+	}
+	return checkFeeCost(bumpedFee, gasLimit, limit)
+}
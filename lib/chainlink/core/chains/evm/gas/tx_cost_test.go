@@ -0,0 +1,160 @@
+package gas_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestWrappedEvmEstimator_MaxTxCost(t *testing.T) {
+	t.Parallel()
+
+	const gasLimit uint32 = 100_000
+	// 10 gwei for 100k gas
+	cost := assets.NewWeiI(1_000_000_000_000_000)
+
+	newConfig := func(eip1559 bool, budget *assets.Wei) *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmEIP1559DynamicFeesF = eip1559
+		cfg.EvmGasMaxTxCostF = budget
+		cfg.EvmMaxGasPriceWeiF = assets.GWei(100)
+		cfg.EvmMinGasPriceWeiF = assets.GWei(5)
+		cfg.EvmGasTipCapMinimumF = assets.GWei(1)
+		return cfg
+	}
+	newLegacyEstimator := func(t *testing.T, cfg *gas.MockConfig) (gas.EvmFeeEstimator, *mocks.EvmEstimator) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetLegacyGas", mock.Anything, mock.Anything, gasLimit, mock.Anything).Return(assets.GWei(10), gasLimit, nil).Maybe()
+		return gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, cfg, nil), e
+	}
+	requireBudgetError := func(t *testing.T, err error, cost, budget *assets.Wei) {
+		t.Helper()
+		require.ErrorIs(t, err, gas.ErrTxCostExceedsBudget)
+		var eErr *gas.EstimationError
+		require.ErrorAs(t, err, &eErr)
+		assert.Equal(t, cost, eErr.Price)
+		assert.Equal(t, budget, eErr.Limit)
+		assert.True(t, gas.IsBumpErr(err))
+	}
+
+	t.Run("keeps fees that cost exactly the budget", func(t *testing.T) {
+		estimator, _ := newLegacyEstimator(t, newConfig(false, cost))
+
+		fee, limit, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, limit)
+		assert.Equal(t, assets.GWei(10), fee.Legacy)
+	})
+
+	t.Run("lowers the gas price of fees over budget", func(t *testing.T) {
+		budget := cost.Sub(assets.NewWeiI(1))
+		estimator, _ := newLegacyEstimator(t, newConfig(false, budget))
+
+		fee, limit, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, gasLimit, limit)
+		assert.Equal(t, assets.NewWeiI(9_999_999_999), fee.Legacy)
+		assert.True(t, fee.Legacy.Mul(big.NewInt(int64(limit))).Cmp(budget) <= 0)
+	})
+
+	t.Run("lowers the fee cap and tip cap of dynamic fees over budget", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		e.On("GetDynamicFee", mock.Anything, gasLimit, mock.Anything).Return(gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(8)}, gasLimit, nil)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(true, assets.NewWeiI(600_000_000_000_000)), nil)
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(6), fee.DynamicFeeCap)
+		assert.Equal(t, assets.GWei(6), fee.DynamicTipCap)
+	})
+
+	t.Run("fails if even the minimum price is over budget", func(t *testing.T) {
+		// PriceMin of 5 gwei costs 0.0005 ether
+		budget := assets.NewWeiI(400_000_000_000_000)
+		estimator, _ := newLegacyEstimator(t, newConfig(false, budget))
+
+		_, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		requireBudgetError(t, err, assets.NewWeiI(500_000_000_000_000), budget)
+	})
+
+	t.Run("does not lower dynamic fees below the base fee", func(t *testing.T) {
+		newEstimator := func(t *testing.T, budget *assets.Wei) gas.EvmFeeEstimator {
+			e := mocks.NewEvmEstimator(t)
+			e.On("GetDynamicFee", mock.Anything, gasLimit, mock.Anything).Return(gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(8)}, gasLimit, nil)
+			e.On("OnNewLongestChain", mock.Anything, mock.Anything).Return()
+			estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(true, budget), nil)
+			estimator.OnNewLongestChain(testutils.Context(t), &evmtypes.Head{Number: 1, BaseFeePerGas: assets.GWei(5)})
+			return estimator
+		}
+
+		// the tip cap is lowered to what the fee cap leaves over the base fee
+		fee, _, err := newEstimator(t, assets.NewWeiI(700_000_000_000_000)).GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(7), fee.DynamicFeeCap)
+		assert.Equal(t, assets.GWei(2), fee.DynamicTipCap)
+
+		// a fee cap of 5 gwei would be over TipCapMin but not over the base fee
+		budget := assets.NewWeiI(500_000_000_000_000)
+		_, _, err = newEstimator(t, budget).GetFee(testutils.Context(t), nil, gasLimit, nil)
+		requireBudgetError(t, err, assets.NewWeiI(600_000_000_000_000), budget)
+	})
+
+	t.Run("allows bumps that cost exactly the budget", func(t *testing.T) {
+		estimator, e := newLegacyEstimator(t, newConfig(false, cost))
+		e.On("BumpLegacyGas", mock.Anything, assets.GWei(8), gasLimit, mock.Anything, mock.Anything).Return(assets.GWei(10), gasLimit, nil).Once()
+
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(8)}, gasLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(10), bumped.Legacy)
+	})
+
+	t.Run("refuses bumps over budget", func(t *testing.T) {
+		estimator, e := newLegacyEstimator(t, newConfig(false, cost))
+		e.On("BumpLegacyGas", mock.Anything, assets.GWei(10), gasLimit, mock.Anything, mock.Anything).Return(assets.GWei(12), gasLimit, nil).Once()
+
+		_, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(10)}, gasLimit, nil, nil)
+		requireBudgetError(t, err, assets.NewWeiI(1_200_000_000_000_000), cost)
+	})
+
+	t.Run("refuses dynamic bumps over budget", func(t *testing.T) {
+		e := mocks.NewEvmEstimator(t)
+		original := gas.DynamicFee{FeeCap: assets.GWei(10), TipCap: assets.GWei(2)}
+		e.On("BumpDynamicFee", mock.Anything, original, gasLimit, mock.Anything, mock.Anything).
+			Return(gas.DynamicFee{FeeCap: assets.GWei(11), TipCap: assets.GWei(3)}, gasLimit, nil).Once()
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), e, newConfig(true, cost), nil)
+
+		_, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{DynamicFeeCap: original.FeeCap, DynamicTipCap: original.TipCap}, gasLimit, nil, nil)
+		requireBudgetError(t, err, assets.NewWeiI(1_100_000_000_000_000), cost)
+	})
+
+	t.Run("has no budget if zero", func(t *testing.T) {
+		estimator, e := newLegacyEstimator(t, newConfig(false, assets.NewWeiI(0)))
+		e.On("BumpLegacyGas", mock.Anything, assets.GWei(10), gasLimit, mock.Anything, mock.Anything).Return(assets.GWei(50), gasLimit, nil).Once()
+
+		fee, _, err := estimator.GetFee(testutils.Context(t), nil, gasLimit, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(10), fee.Legacy)
+		bumped, _, err := estimator.BumpFee(testutils.Context(t), fee, gasLimit, nil, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.GWei(50), bumped.Legacy)
+	})
+
+	t.Run("does not refuse bumps that fail", func(t *testing.T) {
+		estimator, e := newLegacyEstimator(t, newConfig(false, cost))
+		e.On("BumpLegacyGas", mock.Anything, assets.GWei(10), gasLimit, mock.Anything, mock.Anything).Return(nil, uint32(0), errors.New("kaboom")).Once()
+
+		_, _, err := estimator.BumpFee(testutils.Context(t), gas.EvmFee{Legacy: assets.GWei(10)}, gasLimit, nil, nil)
+		assert.EqualError(t, err, "kaboom")
+	})
+}
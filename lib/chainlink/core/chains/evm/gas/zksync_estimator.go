@@ -0,0 +1,224 @@
+package gas
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+
+	txmgrtypes "github.com/smartcontractkit/chainlink/v2/common/txmgr/types"
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	evmtypes "github.com/smartcontractkit/chainlink/v2/core/chains/evm/types"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+	"github.com/smartcontractkit/chainlink/v2/core/utils"
+)
+
+// ZkSyncConfig is the config needed by the ZkSync estimator
+type ZkSyncConfig interface {
+	EvmGasBumpPercent() uint16
+	EvmGasBumpStrategy() string
+	EvmGasBumpWei() *assets.Wei
+	EvmGasLimitMax() uint32
+	EvmGasTipCapMinimum() *assets.Wei
+	EvmMaxGasPriceWei() *assets.Wei
+}
+
+var _ EvmEstimator = (*zkSyncEstimator)(nil)
+
+// zkSyncEstimator estimates fees on zkSync Era with zks_estimateFee, which
+// unlike eth_estimateGas accounts for the pubdata the transaction publishes
+// to L1. Since the estimate depends on the call, the ctx passed to GetFee and
+// BumpFee must carry it, see WithEstimateGasCall.
+//
+// zkSync refunds unused fees, so bumping re-estimates the fee instead of
+// multiplying the original one.
+type zkSyncEstimator struct {
+	utils.StartStopOnce
+
+	cfg     ZkSyncConfig
+	client  rpcClient
+	lggr    logger.SugaredLogger
+	chainID big.Int
+	metrics *estimatorMetrics
+}
+
+// NewZkSyncEstimator returns a new "ZkSync" estimator
+func NewZkSyncEstimator(lggr logger.Logger, cfg ZkSyncConfig, client rpcClient, chainID big.Int) EvmEstimator {
+	return &zkSyncEstimator{
+		cfg:     cfg,
+		client:  client,
+		lggr:    logger.Sugared(lggr.Named("ZkSyncEstimator")),
+		chainID: chainID,
+		metrics: newEstimatorMetrics(chainID, "ZkSync"),
+	}
+}
+
+func (z *zkSyncEstimator) Name() string {
+	return z.lggr.Name()
+}
+
+func (z *zkSyncEstimator) Start(context.Context) error {
+	return z.StartOnce("ZkSyncEstimator", func() error { return nil })
+}
+
+func (z *zkSyncEstimator) Close() error {
+	return z.StopOnce("ZkSyncEstimator", func() error { return nil })
+}
+
+func (z *zkSyncEstimator) HealthReport() map[string]error {
+	return map[string]error{z.Name(): z.Healthy()}
+}
+
+func (z *zkSyncEstimator) OnNewLongestChain(context.Context, *evmtypes.Head) {}
+
+// callSpecific implements callSpecificEstimator, since zks_estimateFee prices
+// the call itself
+func (z *zkSyncEstimator) callSpecific() {}
+
+// zkSyncFee is the result of zks_estimateFee
+type zkSyncFee struct {
+	GasLimit             *hexutil.Big `json:"gas_limit"`
+	MaxFeePerGas         *rpcPrice    `json:"max_fee_per_gas"`
+	MaxPriorityFeePerGas *rpcPrice    `json:"max_priority_fee_per_gas"`
+	GasPerPubdataLimit   *hexutil.Big `json:"gas_per_pubdata_limit"`
+}
+
+// estimateFee calls zks_estimateFee for the call in ctx and checks the
+// estimate against the configured limits
+func (z *zkSyncEstimator) estimateFee(ctx context.Context, maxGasPriceWei *assets.Wei) (fee DynamicFee, gasLimit uint32, err error) {
+	if !z.IfStarted(func() {}) {
+		return fee, 0, errors.New("estimator is not started")
+	}
+	call, ok := estimateGasCallFromContext(ctx)
+	if !ok {
+		return fee, 0, errors.New("zks_estimateFee requires the call to estimate, but none was given")
+	}
+
+	args := map[string]interface{}{
+		"from": call.From,
+		"to":   call.To,
+		"data": hexutil.Bytes(call.Data),
+	}
+	if call.Value != nil && call.Value.Sign() > 0 {
+		args["value"] = (*hexutil.Big)(call.Value)
+	}
+	var res zkSyncFee
+	if err = z.client.CallContext(ctx, &res, "zks_estimateFee", args); err != nil {
+		z.metrics.recordRPCError(err)
+		return fee, 0, &EstimationError{Reason: ErrRPCFailure, Err: errors.Wrap(withDecodedRevert(err), "zks_estimateFee failed")}
+	}
+	if res.GasLimit == nil || res.MaxFeePerGas == nil || res.GasPerPubdataLimit == nil {
+		return fee, 0, errors.Errorf("zks_estimateFee returned an incomplete estimate: %+v", res)
+	}
+
+	limit := res.GasLimit.ToInt()
+	if !limit.IsUint64() || limit.Uint64() > uint64(z.cfg.EvmGasLimitMax()) {
+		return fee, 0, errors.Errorf("zks_estimateFee gas limit of %s exceeds the configured max of %d", limit, z.cfg.EvmGasLimitMax())
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, z.cfg.EvmMaxGasPriceWei())
+	feeCap := assets.NewWei(res.MaxFeePerGas.ToInt())
+	if feeCap.Cmp(maxGasPrice) > 0 {
+		z.metrics.recordCap(feeCap, maxGasPrice)
+		return fee, 0, &EstimationError{Price: feeCap, Limit: maxGasPrice,
+			Err: errors.Errorf("estimated fee cap of %s is greater than the maximum gas price configured: %s", feeCap, maxGasPrice)}
+	}
+	tipCap := assets.NewWeiI(0)
+	if res.MaxPriorityFeePerGas != nil {
+		tipCap = assets.WeiMin(assets.NewWei(res.MaxPriorityFeePerGas.ToInt()), feeCap)
+	}
+
+	z.metrics.setGasPrice(feeCap)
+	z.metrics.setTipCap(tipCap)
+	z.lggr.Debugw("Estimated zkSync fee", "gasLimit", limit, "feeCap", feeCap, "tipCap", tipCap, "gasPerPubdataLimit", res.GasPerPubdataLimit.ToInt(), "to", call.To)
+	return DynamicFee{
+		FeeCap:             feeCap,
+		TipCap:             tipCap,
+		GasPerPubdataLimit: res.GasPerPubdataLimit.ToInt(),
+	}, uint32(limit.Uint64()), nil
+}
+
+// GetLegacyGas returns the fee cap estimated by zks_estimateFee as the gas price
+func (z *zkSyncEstimator) GetLegacyGas(ctx context.Context, _ []byte, _ uint32, maxGasPriceWei *assets.Wei, _ ...txmgrtypes.Opt) (gasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &z.chainID, "ZkSync") }()
+	fee, chainSpecificGasLimit, err := z.estimateFee(ctx, maxGasPriceWei)
+	if err != nil {
+		return nil, 0, err
+	}
+	return fee.FeeCap, chainSpecificGasLimit, nil
+}
+
+// BumpLegacyGas re-estimates the gas price. The bumped price is still at
+// least the configured bump above the original one, since nodes reject
+// replacements that don't pay more.
+func (z *zkSyncEstimator) BumpLegacyGas(ctx context.Context, originalGasPrice *assets.Wei, _ uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumpedGasPrice *assets.Wei, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &z.chainID, "ZkSync") }()
+	fee, chainSpecificGasLimit, err := z.estimateFee(ctx, maxGasPriceWei)
+	if err != nil {
+		return nil, 0, err
+	}
+	bumpedGasPrice, err = z.atLeastBumped(fee.FeeCap, originalGasPrice, maxGasPriceWei, "gas price")
+	z.metrics.recordLegacyBump(err)
+	return bumpedGasPrice, chainSpecificGasLimit, err
+}
+
+// GetDynamicFee returns the fee estimated by zks_estimateFee, including the
+// gas per pubdata limit
+func (z *zkSyncEstimator) GetDynamicFee(ctx context.Context, _ uint32, maxGasPriceWei *assets.Wei) (fee DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &z.chainID, "ZkSync") }()
+	fee, chainSpecificGasLimit, err = z.estimateFee(ctx, maxGasPriceWei)
+	if err != nil {
+		return fee, 0, err
+	}
+	if fee, err = applyTipCapMin(z.cfg, fee, getMaxGasPrice(maxGasPriceWei, z.cfg.EvmMaxGasPriceWei())); err != nil {
+		return fee, 0, err
+	}
+	return fee, chainSpecificGasLimit, nil
+}
+
+// BumpDynamicFee re-estimates the fee, with the fee and tip caps at least the
+// configured bump above the original ones
+func (z *zkSyncEstimator) BumpDynamicFee(ctx context.Context, original DynamicFee, _ uint32, maxGasPriceWei *assets.Wei, _ []EvmPriorAttempt) (bumped DynamicFee, chainSpecificGasLimit uint32, err error) {
+	defer func() { err = annotateError(err, &z.chainID, "ZkSync") }()
+	bumped, chainSpecificGasLimit, err = z.estimateFee(ctx, maxGasPriceWei)
+	if err != nil {
+		return bumped, 0, err
+	}
+	bumped.FeeCap, err = z.atLeastBumped(bumped.FeeCap, original.FeeCap, maxGasPriceWei, "fee cap")
+	z.metrics.recordDynamicBump(err)
+	if err != nil {
+		return DynamicFee{}, 0, err
+	}
+	// The tip is usually zero on zkSync, in which case there is nothing to bump
+	if original.TipCap != nil && !original.TipCap.IsZero() && original.TipCap.Cmp(bumped.TipCap) >= 0 {
+		bumpedTipCap, err := bumpFeePriceWithStrategy(z.cfg, original.TipCap)
+		if err != nil {
+			return DynamicFee{}, 0, err
+		}
+		bumped.TipCap = assets.WeiMin(bumpedTipCap, bumped.FeeCap)
+	}
+	if bumped, err = applyTipCapMin(z.cfg, bumped, getMaxGasPrice(maxGasPriceWei, z.cfg.EvmMaxGasPriceWei())); err != nil {
+		return DynamicFee{}, 0, err
+	}
+	return bumped, chainSpecificGasLimit, nil
+}
+
+// atLeastBumped returns the estimated price, or the original price bumped by
+// EVM.GasEstimator.BumpStrategy if the estimate is lower than that
+func (z *zkSyncEstimator) atLeastBumped(estimated, original, maxGasPriceWei *assets.Wei, name string) (*assets.Wei, error) {
+	if original == nil {
+		return estimated, nil
+	}
+	maxGasPrice := getMaxGasPrice(maxGasPriceWei, z.cfg.EvmMaxGasPriceWei())
+	bumpedOriginal, err := bumpFeePriceWithStrategy(z.cfg, original)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to bump %s", name)
+	}
+	price := assets.WeiMax(estimated, bumpedOriginal)
+	if price.Cmp(maxGasPrice) > 0 {
+		return nil, &EstimationError{Price: price, Limit: maxGasPrice, Reason: ErrBumpLimitExceeded,
+			Err: errors.Wrapf(ErrBumpGasExceedsLimit, "bumped %s of %s would exceed configured max gas price of %s (original %s was %s)",
+				name, price, maxGasPrice, name, original)}
+	}
+	return price, nil
+}
@@ -0,0 +1,186 @@
+package gas_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/smartcontractkit/chainlink/v2/core/assets"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas"
+	"github.com/smartcontractkit/chainlink/v2/core/chains/evm/gas/mocks"
+	"github.com/smartcontractkit/chainlink/v2/core/internal/testutils"
+	"github.com/smartcontractkit/chainlink/v2/core/logger"
+)
+
+func TestZkSyncEstimator(t *testing.T) {
+	t.Parallel()
+
+	maxGasPrice := assets.GWei(100)
+	calldata := []byte{0x01, 0x02, 0x03}
+	call := gas.EstimateGasCall{
+		From:  testutils.NewAddress(),
+		To:    testutils.NewAddress(),
+		Value: big.NewInt(42),
+		Data:  calldata,
+	}
+	chainID := big.NewInt(324)
+
+	newConfig := func() *gas.MockConfig {
+		cfg := gas.NewMockConfig()
+		cfg.EvmGasLimitMaxF = 10_000_000
+		cfg.EvmMaxGasPriceWeiF = maxGasPrice
+		cfg.EvmGasBumpPercentF = 10
+		cfg.EvmGasBumpWeiF = assets.NewWeiI(1)
+		return cfg
+	}
+	mockEstimateFee := func(client *mocks.RPCClient, gasLimit, feeCap, tipCap, pubdata int64) {
+		client.On("CallContext", mock.Anything, mock.Anything, "zks_estimateFee", mock.MatchedBy(func(args map[string]interface{}) bool {
+			return args["from"] == call.From && args["to"] == call.To &&
+				assert.ObjectsAreEqual(hexutil.Bytes(calldata), args["data"]) &&
+				assert.ObjectsAreEqual((*hexutil.Big)(call.Value), args["value"])
+		})).Run(func(args mock.Arguments) {
+			require.NoError(t, json.Unmarshal([]byte(fmt.Sprintf(
+				`{"gas_limit":"%s","max_fee_per_gas":"%s","max_priority_fee_per_gas":"%s","gas_per_pubdata_limit":"%s"}`,
+				hexutil.EncodeBig(big.NewInt(gasLimit)), hexutil.EncodeBig(big.NewInt(feeCap)), hexutil.EncodeBig(big.NewInt(tipCap)), hexutil.EncodeBig(big.NewInt(pubdata)))),
+				args.Get(1)))
+		}).Return(nil).Once()
+	}
+	newEstimator := func(t *testing.T, cfg *gas.MockConfig, client *mocks.RPCClient) gas.EvmEstimator {
+		e := gas.NewZkSyncEstimator(logger.TestLogger(t), cfg, client, *chainID)
+		require.NoError(t, e.Start(testutils.Context(t)))
+		t.Cleanup(func() { assert.NoError(t, e.Close()) })
+		return e
+	}
+
+	t.Run("calling GetDynamicFee returns the zks_estimateFee estimate", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 2_000_000, 250_000_000, 0, 50_000)
+		e := newEstimator(t, newConfig(), client)
+
+		fee, gasLimit, err := e.GetDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), 100_000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2_000_000), gasLimit)
+		assert.Equal(t, assets.NewWeiI(250_000_000), fee.FeeCap)
+		assert.True(t, fee.TipCap.IsZero())
+		assert.Equal(t, big.NewInt(50_000), fee.GasPerPubdataLimit)
+	})
+
+	t.Run("calling GetLegacyGas returns the fee cap as the gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 2_000_000, 250_000_000, 0, 50_000)
+		e := newEstimator(t, newConfig(), client)
+
+		gasPrice, gasLimit, err := e.GetLegacyGas(gas.WithEstimateGasCall(testutils.Context(t), call), calldata, 100_000, maxGasPrice)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(2_000_000), gasLimit)
+		assert.Equal(t, assets.NewWeiI(250_000_000), gasPrice)
+	})
+
+	t.Run("fails without a call", func(t *testing.T) {
+		e := newEstimator(t, newConfig(), mocks.NewRPCClient(t))
+
+		_, _, err := e.GetDynamicFee(testutils.Context(t), 100_000, maxGasPrice)
+		requireEstimationError(t, err, "ZkSync", nil, "zks_estimateFee requires the call to estimate, but none was given")
+	})
+
+	t.Run("fails if not started", func(t *testing.T) {
+		e := gas.NewZkSyncEstimator(logger.TestLogger(t), newConfig(), mocks.NewRPCClient(t), *chainID)
+
+		_, _, err := e.GetDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), 100_000, maxGasPrice)
+		requireEstimationError(t, err, "ZkSync", nil, "estimator is not started")
+	})
+
+	t.Run("fails if the RPC call fails", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		client.On("CallContext", mock.Anything, mock.Anything, "zks_estimateFee", mock.Anything).Return(errors.New("kaboom")).Once()
+		e := newEstimator(t, newConfig(), client)
+
+		_, _, err := e.GetDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), 100_000, maxGasPrice)
+		requireEstimationError(t, err, "ZkSync", gas.ErrRPCFailure, "zks_estimateFee failed: kaboom")
+	})
+
+	t.Run("fails if the gas limit exceeds LimitMax", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 20_000_000, 250_000_000, 0, 50_000)
+		e := newEstimator(t, newConfig(), client)
+
+		_, _, err := e.GetDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), 100_000, maxGasPrice)
+		requireEstimationError(t, err, "ZkSync", nil, "zks_estimateFee gas limit of 20000000 exceeds the configured max of 10000000")
+	})
+
+	t.Run("fails if the fee cap exceeds the max gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 2_000_000, 250_000_000, 0, 50_000)
+		e := newEstimator(t, newConfig(), client)
+
+		_, _, err := e.GetDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), 100_000, assets.NewWeiI(1000))
+		requireEstimationError(t, err, "ZkSync", nil, "estimated fee cap of 250 mwei is greater than the maximum gas price configured: 1 kwei")
+	})
+
+	t.Run("bumping re-estimates the fee", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 3_000_000, 500_000_000, 0, 60_000)
+		e := newEstimator(t, newConfig(), client)
+
+		original := gas.DynamicFee{FeeCap: assets.NewWeiI(250_000_000), TipCap: assets.NewWeiI(0), GasPerPubdataLimit: big.NewInt(50_000)}
+		bumped, gasLimit, err := e.BumpDynamicFee(gas.WithEstimateGasCall(testutils.Context(t), call), original, 2_000_000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, uint32(3_000_000), gasLimit)
+		assert.Equal(t, assets.NewWeiI(500_000_000), bumped.FeeCap)
+		assert.True(t, bumped.TipCap.IsZero())
+		assert.Equal(t, big.NewInt(60_000), bumped.GasPerPubdataLimit)
+	})
+
+	t.Run("bumping never returns less than the bumped original", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 2_000_000, 200_000_000, 0, 50_000)
+		mockEstimateFee(client, 2_000_000, 200_000_000, 0, 50_000)
+		e := newEstimator(t, newConfig(), client)
+		ctx := gas.WithEstimateGasCall(testutils.Context(t), call)
+
+		original := gas.DynamicFee{FeeCap: assets.NewWeiI(250_000_000), TipCap: assets.NewWeiI(100)}
+		bumped, _, err := e.BumpDynamicFee(ctx, original, 2_000_000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(275_000_000), bumped.FeeCap)
+		assert.Equal(t, assets.NewWeiI(110), bumped.TipCap)
+
+		gasPrice, _, err := e.BumpLegacyGas(ctx, assets.NewWeiI(250_000_000), 2_000_000, maxGasPrice, nil)
+		require.NoError(t, err)
+		assert.Equal(t, assets.NewWeiI(275_000_000), gasPrice)
+	})
+
+	t.Run("bumping fails if the bumped price exceeds the max gas price", func(t *testing.T) {
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 2_000_000, 900, 0, 50_000)
+		e := newEstimator(t, newConfig(), client)
+
+		_, _, err := e.BumpLegacyGas(gas.WithEstimateGasCall(testutils.Context(t), call), assets.NewWeiI(950), 2_000_000, assets.NewWeiI(1000), nil)
+		requireEstimationError(t, err, "ZkSync", gas.ErrBumpLimitExceeded, "bumped gas price of 1.045 kwei would exceed configured max gas price of 1 kwei (original gas price was 950 wei): gas bump exceeds limit")
+	})
+
+	t.Run("through the wrapper passes the calldata and pubdata limit, and is never cached", func(t *testing.T) {
+		cfg := newConfig()
+		cfg.EvmEIP1559DynamicFeesF = true
+		cfg.EvmGasFeeCacheTTLF = time.Minute
+		client := mocks.NewRPCClient(t)
+		mockEstimateFee(client, 2_000_000, 250_000_000, 0, 50_000)
+		mockEstimateFee(client, 2_000_000, 250_000_000, 0, 50_000)
+		estimator := gas.NewWrappedEvmEstimator(logger.TestLogger(t), newEstimator(t, cfg, client), cfg, client)
+		ctx := gas.WithEstimateGasCall(testutils.Context(t), gas.EstimateGasCall{From: call.From, To: call.To, Value: call.Value})
+
+		for i := 0; i < 2; i++ {
+			fee, gasLimit, err := estimator.GetFee(ctx, calldata, 100_000, nil)
+			require.NoError(t, err)
+			assert.Equal(t, uint32(2_000_000), gasLimit)
+			assert.Equal(t, big.NewInt(50_000), fee.GasPerPubdataLimit)
+		}
+	})
+}
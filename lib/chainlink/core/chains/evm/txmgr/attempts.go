@@ -48,6 +48,7 @@ func (c *evmTxAttemptBuilder) NewTxAttempt(ctx context.Context, etx EvmTx, lggr
 // used for L2 re-estimation on broadcasting (note EIP1559 must be disabled otherwise this will fail with mismatched fees + tx type)
 func (c *evmTxAttemptBuilder) NewTxAttemptWithType(ctx context.Context, etx EvmTx, lggr logger.Logger, txType int, opts ...txmgrtypes.Opt) (attempt EvmTxAttempt, fee gas.EvmFee, feeLimit uint32, retryable bool, err error) {
 	keySpecificMaxGasPriceWei := c.config.KeySpecificMaxGasPriceWei(etx.FromAddress)
+	ctx = gas.WithEstimateGasCall(ctx, gas.EstimateGasCall{From: etx.FromAddress, To: etx.ToAddress, Value: &etx.Value, Data: etx.EncodedPayload})
 	fee, feeLimit, err = c.EvmFeeEstimator.GetFee(ctx, etx.EncodedPayload, etx.FeeLimit, keySpecificMaxGasPriceWei, opts...)
 	if err != nil {
 		return attempt, fee, feeLimit, true, errors.Wrap(err, "failed to get fee") // estimator errors are retryable
@@ -59,9 +60,16 @@ func (c *evmTxAttemptBuilder) NewTxAttemptWithType(ctx context.Context, etx EvmT
 
 // NewBumpTxAttempt builds a new attempt with a bumped fee - based on the previous attempt tx type
 // used in the txm broadcaster + confirmer when tx ix rejected for too low fee or is not included in a timely manner
+// The new attempt keeps the fee limit of the previous attempt, which may have been estimated, and never has a lower one.
 func (c *evmTxAttemptBuilder) NewBumpTxAttempt(ctx context.Context, etx EvmTx, previousAttempt EvmTxAttempt, priorAttempts []EvmPriorAttempt, lggr logger.Logger) (attempt EvmTxAttempt, bumpedFee gas.EvmFee, bumpedFeeLimit uint32, retryable bool, err error) {
 	keySpecificMaxGasPriceWei := c.config.KeySpecificMaxGasPriceWei(etx.FromAddress)
-	bumpedFee, bumpedFeeLimit, err = c.EvmFeeEstimator.BumpFee(ctx, previousAttempt.Fee(), etx.FeeLimit, keySpecificMaxGasPriceWei, priorAttempts)
+	ctx = gas.WithEstimateGasCall(ctx, gas.EstimateGasCall{From: etx.FromAddress, To: etx.ToAddress, Value: &etx.Value, Data: etx.EncodedPayload})
+	ctx = gas.WithOriginalFeeLimit(ctx, previousAttempt.ChainSpecificFeeLimit)
+	previousFee, err := attemptFee(previousAttempt)
+	if err != nil {
+		return attempt, bumpedFee, bumpedFeeLimit, false, err
+	}
+	bumpedFee, bumpedFeeLimit, err = c.EvmFeeEstimator.BumpFee(ctx, previousFee, etx.FeeLimit, keySpecificMaxGasPriceWei, priorAttempts)
 	if err != nil {
 		return attempt, bumpedFee, bumpedFeeLimit, true, errors.Wrap(err, "failed to bump fee") // estimator errors are retryable
 	}
@@ -70,8 +78,28 @@ func (c *evmTxAttemptBuilder) NewBumpTxAttempt(ctx context.Context, etx EvmTx, p
 	return attempt, bumpedFee, bumpedFeeLimit, retryable, err
 }
 
+// attemptFee returns the fee of the attempt, along with the access list it was
+// sent with, which attempts loaded from the database don't carry in their fee
+// but in their signed transaction
+func attemptFee(attempt EvmTxAttempt) (gas.EvmFee, error) {
+	fee := attempt.Fee()
+	if attempt.TxType != 0x2 || fee.AccessList != nil || len(attempt.SignedRawTx) == 0 {
+		return fee, nil
+	}
+	tx, err := GetGethSignedTx(attempt.SignedRawTx)
+	if err != nil {
+		return fee, errors.Wrapf(err, "failed to decode the signed transaction of attempt %v", attempt.ID)
+	}
+	fee.AccessList = tx.AccessList()
+	return fee, nil
+}
+
 // NewCustomTxAttempt is the lowest level func where the fee parameters + tx type must be passed in
 // used in the txm for force rebroadcast where fees and tx type are pre-determined without an estimator
+//
+// The access list of the fee, which the gas limit may have been estimated with,
+// is attached to dynamic fee transactions in place of the transaction's own.
+// Legacy transactions can't carry one.
 func (c *evmTxAttemptBuilder) NewCustomTxAttempt(etx EvmTx, fee gas.EvmFee, gasLimit uint32, txType int, lggr logger.Logger) (attempt EvmTxAttempt, retryable bool, err error) {
 	switch txType {
 	case 0x0: // legacy
@@ -80,6 +108,11 @@ func (c *evmTxAttemptBuilder) NewCustomTxAttempt(etx EvmTx, fee gas.EvmFee, gasL
 			logger.Sugared(lggr).AssumptionViolation(err.Error())
 			return attempt, false, err // not retryable
 		}
+		if len(fee.AccessList) > 0 {
+			err = errors.Errorf("Attempt %v is a type 0 transaction but estimator returned an access list, which it cannot carry", attempt.ID)
+			logger.Sugared(lggr).AssumptionViolation(err.Error())
+			return attempt, false, err // not retryable
+		}
 		attempt, err = c.newLegacyAttempt(etx, fee.Legacy, gasLimit)
 		return attempt, true, err
 	case 0x2: // dynamic, EIP1559
@@ -91,7 +124,7 @@ func (c *evmTxAttemptBuilder) NewCustomTxAttempt(etx EvmTx, fee gas.EvmFee, gasL
 		attempt, err = c.newDynamicFeeAttempt(etx, gas.DynamicFee{
 			FeeCap: fee.DynamicFeeCap,
 			TipCap: fee.DynamicTipCap,
-		}, gasLimit)
+		}, fee.AccessList, gasLimit)
 		return attempt, true, err
 	default:
 		err = errors.Errorf("invariant violation: Attempt %v had unrecognised transaction type %v"+
@@ -123,13 +156,13 @@ func (c *evmTxAttemptBuilder) NewEmptyTxAttempt(nonce evmtypes.Nonce, feeLimit u
 
 }
 
-func (c *evmTxAttemptBuilder) newDynamicFeeAttempt(etx EvmTx, fee gas.DynamicFee, gasLimit uint32) (attempt EvmTxAttempt, err error) {
+func (c *evmTxAttemptBuilder) newDynamicFeeAttempt(etx EvmTx, fee gas.DynamicFee, accessList types.AccessList, gasLimit uint32) (attempt EvmTxAttempt, err error) {
 	if err = validateDynamicFeeGas(c.config, fee, gasLimit, etx); err != nil {
 		return attempt, errors.Wrap(err, "error validating gas")
 	}
 
-	var al types.AccessList
-	if etx.AdditionalParameters.Valid {
+	al := accessList
+	if al == nil && etx.AdditionalParameters.Valid {
 		al = etx.AdditionalParameters.AccessList
 	}
 	d := newDynamicFeeTransaction(
@@ -151,6 +184,7 @@ func (c *evmTxAttemptBuilder) newDynamicFeeAttempt(etx EvmTx, fee gas.DynamicFee
 	attempt.TxFee = gas.EvmFee{
 		DynamicFeeCap: fee.FeeCap,
 		DynamicTipCap: fee.TipCap,
+		AccessList:    accessList,
 	}
 	attempt.ChainSpecificFeeLimit = gasLimit
 	attempt.TxType = 2
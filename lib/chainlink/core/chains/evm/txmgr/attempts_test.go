@@ -1,6 +1,7 @@
 package txmgr_test
 
 import (
+	"bytes"
 	"fmt"
 	"math/big"
 	"testing"
@@ -96,6 +97,30 @@ func TestTxm_NewDynamicFeeTx(t *testing.T) {
 		assert.Equal(t, assets.GWei(200).String(), a.TxFee.DynamicFeeCap.String())
 	})
 
+	t.Run("attaches the access list of the fee in place of the transaction's", func(t *testing.T) {
+		accessList := types.AccessList{{Address: testutils.NewAddress(), StorageKeys: []gethcommon.Hash{{1}}}}
+		kst := ksmocks.NewEth(t)
+		var signed *types.Transaction
+		kst.On("SignTx", addr, mock.Anything, big.NewInt(1)).Run(func(args mock.Arguments) {
+			signed = args.Get(1).(*types.Transaction)
+		}).Return(tx, nil).Once()
+		gcfg := configtest.NewGeneralConfig(t, nil)
+		cfg := evmtest.NewChainScopedConfig(t, gcfg)
+		cks := txmgr.NewEvmTxAttemptBuilder(*big.NewInt(1), cfg, kst, nil)
+		etx := txmgr.EvmTx{Sequence: &n, FromAddress: addr}
+		etx.AdditionalParameters.Valid = true
+		etx.AdditionalParameters.AccessList = types.AccessList{{Address: testutils.NewAddress()}}
+
+		a, _, err := cks.NewCustomTxAttempt(etx, gas.EvmFee{
+			DynamicTipCap: assets.GWei(100),
+			DynamicFeeCap: assets.GWei(200),
+			AccessList:    accessList,
+		}, 100, 0x2, lggr)
+		require.NoError(t, err)
+		assert.Equal(t, accessList, signed.AccessList())
+		assert.Equal(t, accessList, a.TxFee.AccessList)
+	})
+
 	t.Run("verifies gas tip and fees", func(t *testing.T) {
 		tests := []struct {
 			name        string
@@ -195,6 +220,15 @@ func TestTxm_NewCustomTxAttempt_NonRetryableErrors(t *testing.T) {
 		assert.False(t, retryable)
 	})
 
+	t.Run("access list with legacy tx type", func(t *testing.T) {
+		_, retryable, err := cks.NewCustomTxAttempt(txmgr.EvmTx{}, gas.EvmFee{
+			Legacy:     legacyFee,
+			AccessList: types.AccessList{{Address: testutils.NewAddress()}},
+		}, 100, 0x0, lggr)
+		require.Error(t, err)
+		assert.False(t, retryable)
+	})
+
 	t.Run("invalid type", func(t *testing.T) {
 		_, retryable, err := cks.NewCustomTxAttempt(txmgr.EvmTx{}, gas.EvmFee{}, 100, 0xA, lggr)
 		require.Error(t, err)
@@ -235,3 +269,33 @@ func TestTxm_EvmTxAttemptBuilder_RetryableEstimatorError(t *testing.T) {
 		assert.True(t, retryable)
 	})
 }
+
+func TestTxm_EvmTxAttemptBuilder_BumpKeepsAccessList(t *testing.T) {
+	t.Parallel()
+
+	addr := NewEvmAddress()
+	accessList := types.AccessList{{Address: testutils.NewAddress(), StorageKeys: []gethcommon.Hash{{1}}}}
+	previousTx := types.NewTx(&types.DynamicFeeTx{ChainID: big.NewInt(1), GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(2), AccessList: accessList})
+	signedRawTx := new(bytes.Buffer)
+	require.NoError(t, previousTx.EncodeRLP(signedRawTx))
+	// attempts loaded from the database only have the access list in their
+	// signed transaction
+	previousAttempt := txmgr.EvmTxAttempt{
+		TxType:                0x2,
+		TxFee:                 gas.EvmFee{DynamicFeeCap: assets.NewWeiI(2), DynamicTipCap: assets.NewWeiI(1)},
+		ChainSpecificFeeLimit: 100,
+		SignedRawTx:           signedRawTx.Bytes(),
+	}
+
+	est := txmgrmocks.NewFeeEstimator[*evmtypes.Head, gas.EvmFee, *assets.Wei, gethcommon.Hash](t)
+	est.On("BumpFee", mock.Anything, mock.MatchedBy(func(fee gas.EvmFee) bool {
+		return assert.ObjectsAreEqual(accessList, fee.AccessList)
+	}), mock.Anything, mock.Anything, mock.Anything).Return(gas.EvmFee{}, uint32(0), errors.New("fail")).Once()
+
+	cfg := txmmocks.NewConfig(t)
+	cfg.On("KeySpecificMaxGasPriceWei", mock.Anything).Return(assets.NewWeiI(100))
+	cks := txmgr.NewEvmTxAttemptBuilder(*big.NewInt(1), cfg, ksmocks.NewEth(t), est)
+
+	_, _, _, _, err := cks.NewBumpTxAttempt(testutils.Context(t), txmgr.EvmTx{FromAddress: addr}, previousAttempt, nil, logger.TestLogger(t))
+	require.ErrorContains(t, err, "failed to bump fee")
+}
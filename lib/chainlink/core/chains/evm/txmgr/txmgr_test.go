@@ -54,7 +54,7 @@ func makeTestEvmTxm(
 	)
 
 	// build estimator from factory
-	estimator := gas.NewEstimator(lggr, ethClient, cfg)
+	estimator := gas.NewEstimator(lggr, ethClient, cfg, nil)
 
 	return builder.NewTxm(
 		db,
@@ -16,6 +16,8 @@ type BlockInternal struct {
 	BaseFeePerGas *hexutil.Big          `json:"baseFeePerGas"`
 	Timestamp     hexutil.Uint64        `json:"timestamp"`
 	Transactions  []TransactionInternal `json:"transactions"`
+	BlobGasUsed   *hexutil.Uint64       `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas *hexutil.Uint64       `json:"excessBlobGas,omitempty"`
 }
 
 func (bi BlockInternal) Empty() bool {
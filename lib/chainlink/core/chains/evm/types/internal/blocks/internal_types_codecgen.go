@@ -661,21 +661,25 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 		_ = yy2arr2
 		const yyr2 bool = false // struct tag has 'toArray'
 		var yyn6 bool = x.BaseFeePerGas == nil
+		var yyn9 bool = x.BlobGasUsed == nil
+		var yyn10 bool = x.ExcessBlobGas == nil
+		var yyq2 = [8]bool{ // should field at this index be written?
+			true,                   // number
+			true,                   // hash
+			true,                   // parentHash
+			true,                   // baseFeePerGas
+			true,                   // timestamp
+			true,                   // transactions
+			x.BlobGasUsed != nil,   // blobGasUsed
+			x.ExcessBlobGas != nil, // excessBlobGas
+		}
+		_ = yyq2
 		if yyr2 || yy2arr2 {
-			z.EncWriteArrayStart(6)
+			z.EncWriteArrayStart(8)
 			z.EncWriteArrayElem()
 			r.EncodeString(string(x.Number))
 			z.EncWriteArrayElem()
-			yy10 := &x.Hash
-			if yyxt11 := z.Extension(yy10); yyxt11 != nil {
-				z.EncExtension(yy10, yyxt11)
-			} else if !z.EncBinary() {
-				z.EncTextMarshal(*yy10)
-			} else {
-				z.F.EncSliceUint8V(([]uint8)(yy10[:]), e)
-			}
-			z.EncWriteArrayElem()
-			yy12 := &x.ParentHash
+			yy12 := &x.Hash
 			if yyxt13 := z.Extension(yy12); yyxt13 != nil {
 				z.EncExtension(yy12, yyxt13)
 			} else if !z.EncBinary() {
@@ -683,13 +687,22 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 			} else {
 				z.F.EncSliceUint8V(([]uint8)(yy12[:]), e)
 			}
+			z.EncWriteArrayElem()
+			yy14 := &x.ParentHash
+			if yyxt15 := z.Extension(yy14); yyxt15 != nil {
+				z.EncExtension(yy14, yyxt15)
+			} else if !z.EncBinary() {
+				z.EncTextMarshal(*yy14)
+			} else {
+				z.F.EncSliceUint8V(([]uint8)(yy14[:]), e)
+			}
 			if yyn6 {
 				z.EncWriteArrayElem()
 				r.EncodeNil()
 			} else {
 				z.EncWriteArrayElem()
-				if yyxt14 := z.Extension(x.BaseFeePerGas); yyxt14 != nil {
-					z.EncExtension(x.BaseFeePerGas, yyxt14)
+				if yyxt16 := z.Extension(x.BaseFeePerGas); yyxt16 != nil {
+					z.EncExtension(x.BaseFeePerGas, yyxt16)
 				} else if !z.EncBinary() {
 					z.EncTextMarshal(*x.BaseFeePerGas)
 				} else {
@@ -697,8 +710,8 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				}
 			}
 			z.EncWriteArrayElem()
-			if yyxt15 := z.Extension(x.Timestamp); yyxt15 != nil {
-				z.EncExtension(x.Timestamp, yyxt15)
+			if yyxt17 := z.Extension(x.Timestamp); yyxt17 != nil {
+				z.EncExtension(x.Timestamp, yyxt17)
 			} else if !z.EncBinary() {
 				z.EncTextMarshal(x.Timestamp)
 			} else {
@@ -710,9 +723,52 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 			} else {
 				h.encSliceTransactionInternal(([]TransactionInternal)(x.Transactions), e)
 			} // end block: if x.Transactions slice == nil
+			if yyn9 {
+				z.EncWriteArrayElem()
+				r.EncodeNil()
+			} else {
+				z.EncWriteArrayElem()
+				if yyq2[6] {
+					yy19 := *x.BlobGasUsed
+					if yyxt20 := z.Extension(yy19); yyxt20 != nil {
+						z.EncExtension(yy19, yyxt20)
+					} else if !z.EncBinary() {
+						z.EncTextMarshal(yy19)
+					} else {
+						r.EncodeUint(uint64(yy19))
+					}
+				} else {
+					r.EncodeNil()
+				}
+			}
+			if yyn10 {
+				z.EncWriteArrayElem()
+				r.EncodeNil()
+			} else {
+				z.EncWriteArrayElem()
+				if yyq2[7] {
+					yy21 := *x.ExcessBlobGas
+					if yyxt22 := z.Extension(yy21); yyxt22 != nil {
+						z.EncExtension(yy21, yyxt22)
+					} else if !z.EncBinary() {
+						z.EncTextMarshal(yy21)
+					} else {
+						r.EncodeUint(uint64(yy21))
+					}
+				} else {
+					r.EncodeNil()
+				}
+			}
 			z.EncWriteArrayEnd()
 		} else {
-			z.EncWriteMapStart(6)
+			var yynn2 int
+			for _, b := range yyq2 {
+				if b {
+					yynn2++
+				}
+			}
+			z.EncWriteMapStart(yynn2)
+			yynn2 = 0
 			if z.EncBasicHandle().Canonical {
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"baseFeePerGas\"")
@@ -720,24 +776,58 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				if yyn6 {
 					r.EncodeNil()
 				} else {
-					if yyxt17 := z.Extension(x.BaseFeePerGas); yyxt17 != nil {
-						z.EncExtension(x.BaseFeePerGas, yyxt17)
+					if yyxt23 := z.Extension(x.BaseFeePerGas); yyxt23 != nil {
+						z.EncExtension(x.BaseFeePerGas, yyxt23)
 					} else if !z.EncBinary() {
 						z.EncTextMarshal(*x.BaseFeePerGas)
 					} else {
 						z.EncFallback(x.BaseFeePerGas)
 					}
 				}
+				if yyq2[6] {
+					z.EncWriteMapElemKey()
+					z.EncWr().WriteStr("\"blobGasUsed\"")
+					z.EncWriteMapElemValue()
+					if yyn9 {
+						r.EncodeNil()
+					} else {
+						yy24 := *x.BlobGasUsed
+						if yyxt25 := z.Extension(yy24); yyxt25 != nil {
+							z.EncExtension(yy24, yyxt25)
+						} else if !z.EncBinary() {
+							z.EncTextMarshal(yy24)
+						} else {
+							r.EncodeUint(uint64(yy24))
+						}
+					}
+				}
+				if yyq2[7] {
+					z.EncWriteMapElemKey()
+					z.EncWr().WriteStr("\"excessBlobGas\"")
+					z.EncWriteMapElemValue()
+					if yyn10 {
+						r.EncodeNil()
+					} else {
+						yy26 := *x.ExcessBlobGas
+						if yyxt27 := z.Extension(yy26); yyxt27 != nil {
+							z.EncExtension(yy26, yyxt27)
+						} else if !z.EncBinary() {
+							z.EncTextMarshal(yy26)
+						} else {
+							r.EncodeUint(uint64(yy26))
+						}
+					}
+				}
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"hash\"")
 				z.EncWriteMapElemValue()
-				yy18 := &x.Hash
-				if yyxt19 := z.Extension(yy18); yyxt19 != nil {
-					z.EncExtension(yy18, yyxt19)
+				yy28 := &x.Hash
+				if yyxt29 := z.Extension(yy28); yyxt29 != nil {
+					z.EncExtension(yy28, yyxt29)
 				} else if !z.EncBinary() {
-					z.EncTextMarshal(*yy18)
+					z.EncTextMarshal(*yy28)
 				} else {
-					z.F.EncSliceUint8V(([]uint8)(yy18[:]), e)
+					z.F.EncSliceUint8V(([]uint8)(yy28[:]), e)
 				}
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"number\"")
@@ -746,19 +836,19 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"parentHash\"")
 				z.EncWriteMapElemValue()
-				yy21 := &x.ParentHash
-				if yyxt22 := z.Extension(yy21); yyxt22 != nil {
-					z.EncExtension(yy21, yyxt22)
+				yy31 := &x.ParentHash
+				if yyxt32 := z.Extension(yy31); yyxt32 != nil {
+					z.EncExtension(yy31, yyxt32)
 				} else if !z.EncBinary() {
-					z.EncTextMarshal(*yy21)
+					z.EncTextMarshal(*yy31)
 				} else {
-					z.F.EncSliceUint8V(([]uint8)(yy21[:]), e)
+					z.F.EncSliceUint8V(([]uint8)(yy31[:]), e)
 				}
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"timestamp\"")
 				z.EncWriteMapElemValue()
-				if yyxt23 := z.Extension(x.Timestamp); yyxt23 != nil {
-					z.EncExtension(x.Timestamp, yyxt23)
+				if yyxt33 := z.Extension(x.Timestamp); yyxt33 != nil {
+					z.EncExtension(x.Timestamp, yyxt33)
 				} else if !z.EncBinary() {
 					z.EncTextMarshal(x.Timestamp)
 				} else {
@@ -780,24 +870,24 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"hash\"")
 				z.EncWriteMapElemValue()
-				yy26 := &x.Hash
-				if yyxt27 := z.Extension(yy26); yyxt27 != nil {
-					z.EncExtension(yy26, yyxt27)
+				yy36 := &x.Hash
+				if yyxt37 := z.Extension(yy36); yyxt37 != nil {
+					z.EncExtension(yy36, yyxt37)
 				} else if !z.EncBinary() {
-					z.EncTextMarshal(*yy26)
+					z.EncTextMarshal(*yy36)
 				} else {
-					z.F.EncSliceUint8V(([]uint8)(yy26[:]), e)
+					z.F.EncSliceUint8V(([]uint8)(yy36[:]), e)
 				}
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"parentHash\"")
 				z.EncWriteMapElemValue()
-				yy28 := &x.ParentHash
-				if yyxt29 := z.Extension(yy28); yyxt29 != nil {
-					z.EncExtension(yy28, yyxt29)
+				yy38 := &x.ParentHash
+				if yyxt39 := z.Extension(yy38); yyxt39 != nil {
+					z.EncExtension(yy38, yyxt39)
 				} else if !z.EncBinary() {
-					z.EncTextMarshal(*yy28)
+					z.EncTextMarshal(*yy38)
 				} else {
-					z.F.EncSliceUint8V(([]uint8)(yy28[:]), e)
+					z.F.EncSliceUint8V(([]uint8)(yy38[:]), e)
 				}
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"baseFeePerGas\"")
@@ -805,8 +895,8 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				if yyn6 {
 					r.EncodeNil()
 				} else {
-					if yyxt30 := z.Extension(x.BaseFeePerGas); yyxt30 != nil {
-						z.EncExtension(x.BaseFeePerGas, yyxt30)
+					if yyxt40 := z.Extension(x.BaseFeePerGas); yyxt40 != nil {
+						z.EncExtension(x.BaseFeePerGas, yyxt40)
 					} else if !z.EncBinary() {
 						z.EncTextMarshal(*x.BaseFeePerGas)
 					} else {
@@ -816,8 +906,8 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				z.EncWriteMapElemKey()
 				z.EncWr().WriteStr("\"timestamp\"")
 				z.EncWriteMapElemValue()
-				if yyxt31 := z.Extension(x.Timestamp); yyxt31 != nil {
-					z.EncExtension(x.Timestamp, yyxt31)
+				if yyxt41 := z.Extension(x.Timestamp); yyxt41 != nil {
+					z.EncExtension(x.Timestamp, yyxt41)
 				} else if !z.EncBinary() {
 					z.EncTextMarshal(x.Timestamp)
 				} else {
@@ -831,6 +921,40 @@ func (x *BlockInternal) CodecEncodeSelf(e *codec1978.Encoder) {
 				} else {
 					h.encSliceTransactionInternal(([]TransactionInternal)(x.Transactions), e)
 				} // end block: if x.Transactions slice == nil
+				if yyq2[6] {
+					z.EncWriteMapElemKey()
+					z.EncWr().WriteStr("\"blobGasUsed\"")
+					z.EncWriteMapElemValue()
+					if yyn9 {
+						r.EncodeNil()
+					} else {
+						yy43 := *x.BlobGasUsed
+						if yyxt44 := z.Extension(yy43); yyxt44 != nil {
+							z.EncExtension(yy43, yyxt44)
+						} else if !z.EncBinary() {
+							z.EncTextMarshal(yy43)
+						} else {
+							r.EncodeUint(uint64(yy43))
+						}
+					}
+				}
+				if yyq2[7] {
+					z.EncWriteMapElemKey()
+					z.EncWr().WriteStr("\"excessBlobGas\"")
+					z.EncWriteMapElemValue()
+					if yyn10 {
+						r.EncodeNil()
+					} else {
+						yy45 := *x.ExcessBlobGas
+						if yyxt46 := z.Extension(yy45); yyxt46 != nil {
+							z.EncExtension(yy45, yyxt46)
+						} else if !z.EncBinary() {
+							z.EncTextMarshal(yy45)
+						} else {
+							r.EncodeUint(uint64(yy45))
+						}
+					}
+				}
 			}
 			z.EncWriteMapEnd()
 		}
@@ -917,6 +1041,40 @@ func (x *BlockInternal) codecDecodeSelfFromMap(l int, d *codec1978.Decoder) {
 			}
 		case "transactions":
 			h.decSliceTransactionInternal((*[]TransactionInternal)(&x.Transactions), d)
+		case "blobGasUsed":
+			if r.TryNil() {
+				if x.BlobGasUsed != nil { // remove the if-true
+					x.BlobGasUsed = nil
+				}
+			} else {
+				if x.BlobGasUsed == nil {
+					x.BlobGasUsed = new(pkg1_hexutil.Uint64)
+				}
+				if yyxt16 := z.Extension(x.BlobGasUsed); yyxt16 != nil {
+					z.DecExtension(x.BlobGasUsed, yyxt16)
+				} else if !z.DecBinary() && z.IsJSONHandle() {
+					z.DecJSONUnmarshal(x.BlobGasUsed)
+				} else {
+					*x.BlobGasUsed = (pkg1_hexutil.Uint64)(r.DecodeUint64())
+				}
+			}
+		case "excessBlobGas":
+			if r.TryNil() {
+				if x.ExcessBlobGas != nil { // remove the if-true
+					x.ExcessBlobGas = nil
+				}
+			} else {
+				if x.ExcessBlobGas == nil {
+					x.ExcessBlobGas = new(pkg1_hexutil.Uint64)
+				}
+				if yyxt18 := z.Extension(x.ExcessBlobGas); yyxt18 != nil {
+					z.DecExtension(x.ExcessBlobGas, yyxt18)
+				} else if !z.DecBinary() && z.IsJSONHandle() {
+					z.DecJSONUnmarshal(x.ExcessBlobGas)
+				} else {
+					*x.ExcessBlobGas = (pkg1_hexutil.Uint64)(r.DecodeUint64())
+				}
+			}
 		default:
 			z.DecStructFieldNotFound(-1, string(yys3))
 		} // end switch yys3
@@ -927,47 +1085,47 @@ func (x *BlockInternal) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
 	var h codecSelfer1709
 	z, r := codec1978.GenHelper().Decoder(d)
 	_, _, _ = h, z, r
-	var yyj15 int
-	var yyb15 bool
-	var yyhl15 bool = l >= 0
-	yyb15 = !z.DecContainerNext(yyj15, l, yyhl15)
-	if yyb15 {
+	var yyj19 int
+	var yyb19 bool
+	var yyhl19 bool = l >= 0
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
 		z.DecReadArrayEnd()
 		return
 	}
 	z.DecReadArrayElem()
 	x.Number = (string)(z.DecStringZC(r.DecodeStringAsBytes()))
-	yyj15++
-	yyb15 = !z.DecContainerNext(yyj15, l, yyhl15)
-	if yyb15 {
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
 		z.DecReadArrayEnd()
 		return
 	}
 	z.DecReadArrayElem()
-	if yyxt18 := z.Extension(x.Hash); yyxt18 != nil {
-		z.DecExtension(&x.Hash, yyxt18)
+	if yyxt22 := z.Extension(x.Hash); yyxt22 != nil {
+		z.DecExtension(&x.Hash, yyxt22)
 	} else if !z.DecBinary() && z.IsJSONHandle() {
 		z.DecJSONUnmarshal(&x.Hash)
 	} else {
 		z.F.DecSliceUint8N(([]uint8)(x.Hash[:]), d)
 	}
-	yyj15++
-	yyb15 = !z.DecContainerNext(yyj15, l, yyhl15)
-	if yyb15 {
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
 		z.DecReadArrayEnd()
 		return
 	}
 	z.DecReadArrayElem()
-	if yyxt20 := z.Extension(x.ParentHash); yyxt20 != nil {
-		z.DecExtension(&x.ParentHash, yyxt20)
+	if yyxt24 := z.Extension(x.ParentHash); yyxt24 != nil {
+		z.DecExtension(&x.ParentHash, yyxt24)
 	} else if !z.DecBinary() && z.IsJSONHandle() {
 		z.DecJSONUnmarshal(&x.ParentHash)
 	} else {
 		z.F.DecSliceUint8N(([]uint8)(x.ParentHash[:]), d)
 	}
-	yyj15++
-	yyb15 = !z.DecContainerNext(yyj15, l, yyhl15)
-	if yyb15 {
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
 		z.DecReadArrayEnd()
 		return
 	}
@@ -980,40 +1138,86 @@ func (x *BlockInternal) codecDecodeSelfFromArray(l int, d *codec1978.Decoder) {
 		if x.BaseFeePerGas == nil {
 			x.BaseFeePerGas = new(pkg1_hexutil.Big)
 		}
-		if yyxt22 := z.Extension(x.BaseFeePerGas); yyxt22 != nil {
-			z.DecExtension(x.BaseFeePerGas, yyxt22)
+		if yyxt26 := z.Extension(x.BaseFeePerGas); yyxt26 != nil {
+			z.DecExtension(x.BaseFeePerGas, yyxt26)
 		} else if !z.DecBinary() && z.IsJSONHandle() {
 			z.DecJSONUnmarshal(x.BaseFeePerGas)
 		} else {
 			z.DecFallback(x.BaseFeePerGas, false)
 		}
 	}
-	yyj15++
-	yyb15 = !z.DecContainerNext(yyj15, l, yyhl15)
-	if yyb15 {
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
 		z.DecReadArrayEnd()
 		return
 	}
 	z.DecReadArrayElem()
-	if yyxt24 := z.Extension(x.Timestamp); yyxt24 != nil {
-		z.DecExtension(&x.Timestamp, yyxt24)
+	if yyxt28 := z.Extension(x.Timestamp); yyxt28 != nil {
+		z.DecExtension(&x.Timestamp, yyxt28)
 	} else if !z.DecBinary() && z.IsJSONHandle() {
 		z.DecJSONUnmarshal(&x.Timestamp)
 	} else {
 		x.Timestamp = (pkg1_hexutil.Uint64)(r.DecodeUint64())
 	}
-	yyj15++
-	yyb15 = !z.DecContainerNext(yyj15, l, yyhl15)
-	if yyb15 {
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
 		z.DecReadArrayEnd()
 		return
 	}
 	z.DecReadArrayElem()
 	h.decSliceTransactionInternal((*[]TransactionInternal)(&x.Transactions), d)
-	yyj15++
-	for ; z.DecContainerNext(yyj15, l, yyhl15); yyj15++ {
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
+		z.DecReadArrayEnd()
+		return
+	}
+	z.DecReadArrayElem()
+	if r.TryNil() {
+		if x.BlobGasUsed != nil { // remove the if-true
+			x.BlobGasUsed = nil
+		}
+	} else {
+		if x.BlobGasUsed == nil {
+			x.BlobGasUsed = new(pkg1_hexutil.Uint64)
+		}
+		if yyxt32 := z.Extension(x.BlobGasUsed); yyxt32 != nil {
+			z.DecExtension(x.BlobGasUsed, yyxt32)
+		} else if !z.DecBinary() && z.IsJSONHandle() {
+			z.DecJSONUnmarshal(x.BlobGasUsed)
+		} else {
+			*x.BlobGasUsed = (pkg1_hexutil.Uint64)(r.DecodeUint64())
+		}
+	}
+	yyj19++
+	yyb19 = !z.DecContainerNext(yyj19, l, yyhl19)
+	if yyb19 {
+		z.DecReadArrayEnd()
+		return
+	}
+	z.DecReadArrayElem()
+	if r.TryNil() {
+		if x.ExcessBlobGas != nil { // remove the if-true
+			x.ExcessBlobGas = nil
+		}
+	} else {
+		if x.ExcessBlobGas == nil {
+			x.ExcessBlobGas = new(pkg1_hexutil.Uint64)
+		}
+		if yyxt34 := z.Extension(x.ExcessBlobGas); yyxt34 != nil {
+			z.DecExtension(x.ExcessBlobGas, yyxt34)
+		} else if !z.DecBinary() && z.IsJSONHandle() {
+			z.DecJSONUnmarshal(x.ExcessBlobGas)
+		} else {
+			*x.ExcessBlobGas = (pkg1_hexutil.Uint64)(r.DecodeUint64())
+		}
+	}
+	yyj19++
+	for ; z.DecContainerNext(yyj19, l, yyhl19); yyj19++ {
 		z.DecReadArrayElem()
-		z.DecStructFieldNotFound(yyj15-1, "")
+		z.DecStructFieldNotFound(yyj19-1, "")
 	}
 }
 
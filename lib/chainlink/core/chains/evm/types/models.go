@@ -295,6 +295,9 @@ type Block struct {
 	BaseFeePerGas *assets.Wei
 	Timestamp     time.Time
 	Transactions  []Transaction
+	// BlobGasUsed and ExcessBlobGas are only present on post-Cancun (EIP-4844) blocks
+	BlobGasUsed   *uint64
+	ExcessBlobGas *uint64
 }
 
 // MarshalJSON implements json marshalling for Block
@@ -306,6 +309,8 @@ func (b Block) MarshalJSON() ([]byte, error) {
 		BaseFeePerGas: (*hexutil.Big)(b.BaseFeePerGas),
 		Timestamp:     (hexutil.Uint64)(uint64(b.Timestamp.Unix())),
 		Transactions:  toInternalTxnSlice(b.Transactions),
+		BlobGasUsed:   (*hexutil.Uint64)(b.BlobGasUsed),
+		ExcessBlobGas: (*hexutil.Uint64)(b.ExcessBlobGas),
 	}
 
 	buf := bytes.NewBuffer(make([]byte, 0, 1024))
@@ -346,6 +351,8 @@ func (b *Block) UnmarshalJSON(data []byte) error {
 		BaseFeePerGas: (*assets.Wei)(bi.BaseFeePerGas),
 		Timestamp:     time.Unix((int64((uint64)(bi.Timestamp))), 0),
 		Transactions:  fromInternalTxnSlice(bi.Transactions),
+		BlobGasUsed:   (*uint64)(bi.BlobGasUsed),
+		ExcessBlobGas: (*uint64)(bi.ExcessBlobGas),
 	}
 	return nil
 }
@@ -715,6 +715,25 @@ func TestBlock_UnmarshalJSON(t *testing.T) {
 		assert.Equal(t, assets.NewWeiI(39678999761), b.BaseFeePerGas)
 		assert.Equal(t, int64(1656603143), b.Timestamp.Unix())
 		assert.Len(t, b.Transactions, 7)
+		assert.Nil(t, b.BlobGasUsed)
+		assert.Nil(t, b.ExcessBlobGas)
+	})
+	t.Run("unmarshals blob gas fields of post-Cancun block", func(t *testing.T) {
+		b := new(evmtypes.Block)
+		err := b.UnmarshalJSON([]byte(`{"number":"0x1","hash":"0x45eb0a650b6b0b9fd1ee676b870e43fa7614f1034f7404070327a332faed05c0","baseFeePerGas":"0x7","timestamp":"0x62c2b6c7","transactions":[],"blobGasUsed":"0x40000","excessBlobGas":"0x4b0000"}`))
+		require.NoError(t, err)
+
+		require.NotNil(t, b.BlobGasUsed)
+		require.NotNil(t, b.ExcessBlobGas)
+		assert.Equal(t, uint64(0x40000), *b.BlobGasUsed)
+		assert.Equal(t, uint64(0x4b0000), *b.ExcessBlobGas)
+
+		bs, err := b.MarshalJSON()
+		require.NoError(t, err)
+		b2 := new(evmtypes.Block)
+		require.NoError(t, b2.UnmarshalJSON(bs))
+		assert.Equal(t, b.BlobGasUsed, b2.BlobGasUsed)
+		assert.Equal(t, b.ExcessBlobGas, b2.ExcessBlobGas)
 	})
 	t.Run("handles empty result", func(t *testing.T) {
 		b := new(evmtypes.Block)
@@ -441,22 +441,50 @@ func TestConfig_Marshal(t *testing.T) {
 				FlagsContractAddress: mustAddress("0xae4E781a6218A8031764928E88d457937A954fC3"),
 
 				GasEstimator: evmcfg.GasEstimator{
-					Mode:               ptr("L2Suggested"),
-					EIP1559DynamicFees: ptr(true),
-					BumpPercent:        ptr[uint16](10),
-					BumpThreshold:      ptr[uint32](6),
-					BumpTxDepth:        ptr[uint32](6),
-					BumpMin:            assets.NewWeiI(100),
-					FeeCapDefault:      assets.NewWeiI(math.MaxInt64),
-					LimitDefault:       ptr[uint32](12),
-					LimitMax:           ptr[uint32](17),
-					LimitMultiplier:    mustDecimal("1.234"),
-					LimitTransfer:      ptr[uint32](100),
-					TipCapDefault:      assets.NewWeiI(2),
-					TipCapMin:          assets.NewWeiI(1),
-					PriceDefault:       assets.NewWeiI(math.MaxInt64),
-					PriceMax:           assets.NewWei(utils.HexToBig("FFFFFFFFFFFF")),
-					PriceMin:           assets.NewWeiI(13),
+					Mode:                            ptr("L2Suggested"),
+					EIP1559DynamicFees:              ptr(true),
+					BumpPercent:                     ptr[uint16](10),
+					BumpThreshold:                   ptr[uint32](6),
+					BumpTxDepth:                     ptr[uint32](6),
+					BumpMin:                         assets.NewWeiI(100),
+					FeeCapDefault:                   assets.NewWeiI(math.MaxInt64),
+					LimitDefault:                    ptr[uint32](12),
+					LimitMax:                        ptr[uint32](17),
+					LimitMultiplier:                 mustDecimal("1.234"),
+					LimitTransfer:                   ptr[uint32](100),
+					TipCapDefault:                   assets.NewWeiI(2),
+					TipCapMin:                       assets.NewWeiI(1),
+					PriceDefault:                    assets.NewWeiI(math.MaxInt64),
+					PriceMax:                        assets.NewWei(utils.HexToBig("FFFFFFFFFFFF")),
+					PriceMin:                        assets.NewWeiI(13),
+					PriceMaxBlob:                    assets.GWei(1),
+					PriceStaleThreshold:             models.MustNewDuration(time.Minute),
+					SuggestedPriceConnectivityCheck: ptr(false),
+					FeeCacheTTL:                     models.MustNewDuration(time.Second),
+					EstimateGasLimit:                ptr(true),
+					LimitMin:                        ptr[uint32](22000),
+					BumpStrategy:                    ptr("Rebase"),
+					SimulateBeforeBump:              ptr(true),
+					RPCRateLimit:                    ptr[uint32](20),
+					RPCRateLimitBurst:               ptr[uint32](40),
+					FallbackModes:                   &[]string{"BlockHistory", "L2Suggested"},
+					BatchTipIncrement:               assets.NewWeiI(10),
+					EstimateAccessList:              ptr(true),
+					PriceUpdateThreshold:            ptr[uint16](5),
+					MaxTxCost:                       assets.Ether(1),
+					FeeAnomalyFactor:                mustDecimal("10"),
+					FeeAnomalyHalfLife:              models.MustNewDuration(30 * time.Minute),
+					FeeAnomalyCooldown:              models.MustNewDuration(time.Hour),
+					NodeMinPriceSync:                ptr(true),
+					NodeMinPriceMethod:              ptr("eth_minGasPrice"),
+					BumpFeeCapFromBaseFee:           ptr(true),
+					RPCCallTimeout:                  models.MustNewDuration(3 * time.Second),
+					FeeCurrency:                     mustAddress("0x765DE816845861e75A25fCA122bb6898B8B1282a"),
+					DecisionLogSampleRate:           ptr[uint32](100),
+					DecisionLogAlways:               &[]string{"Capped", "Bumped", "Anomaly"},
+					DrainTimeout:                    models.MustNewDuration(10 * time.Second),
+					FeeRounding:                     assets.GWei(1),
+					BaseFeeBufferMultiplier:         mustDecimal("1.5"),
 
 					LimitJobType: evmcfg.GasLimitJobType{
 						OCR:    ptr[uint32](1001),
@@ -473,6 +501,28 @@ func TestConfig_Marshal(t *testing.T) {
 						CheckInclusionPercentile:  ptr[uint16](19),
 						EIP1559FeeCapBufferBlocks: ptr[uint16](13),
 						TransactionPercentile:     ptr[uint16](15),
+						TipCapTrimPercentile:      ptr[uint16](5),
+						BaseFeeLookaheadBlocks:    ptr[uint16](2),
+						MaxReorgDepth:             ptr[uint16](30),
+						InclusionPercentiles:      &[]string{"1:99", "10:50"},
+						HistoryDuration:           models.MustNewDuration(2 * time.Minute),
+						HistorySizeMin:            ptr[uint16](6),
+						HistorySizeMax:            ptr[uint16](300),
+						ExcludedTxTypes:           &[]uint8{126},
+					},
+					ExternalAPI: evmcfg.ExternalAPIEstimator{
+						URL:                  models.MustParseURL("https://api.etherscan.io/api?module=gastracker&action=gasoracle"),
+						Speed:                ptr("Fast"),
+						SafeLowPath:          ptr("result.SafeGasPrice"),
+						StandardPath:         ptr("result.ProposeGasPrice"),
+						FastPath:             ptr("result.FastGasPrice"),
+						BaseFeePath:          ptr("result.suggestBaseFee"),
+						Unit:                 ptr("gwei"),
+						PricesIncludeBaseFee: ptr(true),
+					},
+					FeeHistory: evmcfg.FeeHistoryEstimator{
+						BlockCount:       ptr[uint16](12),
+						RewardPercentile: ptr[uint16](45),
 					},
 				},
 
@@ -872,6 +922,34 @@ EIP1559DynamicFees = true
 FeeCapDefault = '9.223372036854775807 ether'
 TipCapDefault = '2 wei'
 TipCapMin = '1 wei'
+PriceMaxBlob = '1 gwei'
+PriceStaleThreshold = '1m0s'
+SuggestedPriceConnectivityCheck = false
+FeeCacheTTL = '1s'
+EstimateGasLimit = true
+LimitMin = 22000
+BumpStrategy = 'Rebase'
+SimulateBeforeBump = true
+RPCRateLimit = 20
+RPCRateLimitBurst = 40
+FallbackModes = ['BlockHistory', 'L2Suggested']
+BatchTipIncrement = '10 wei'
+EstimateAccessList = true
+PriceUpdateThreshold = 5
+MaxTxCost = '1 ether'
+FeeAnomalyFactor = '10'
+FeeAnomalyHalfLife = '30m0s'
+FeeAnomalyCooldown = '1h0m0s'
+NodeMinPriceSync = true
+NodeMinPriceMethod = 'eth_minGasPrice'
+BumpFeeCapFromBaseFee = true
+RPCCallTimeout = '3s'
+FeeCurrency = '0x765DE816845861e75A25fCA122bb6898B8B1282a'
+DecisionLogSampleRate = 100
+DecisionLogAlways = ['Capped', 'Bumped', 'Anomaly']
+DrainTimeout = '10s'
+FeeRounding = '1 gwei'
+BaseFeeBufferMultiplier = '1.5'
 
 [EVM.GasEstimator.LimitJobType]
 OCR = 1001
@@ -887,6 +965,28 @@ CheckInclusionBlocks = 18
 CheckInclusionPercentile = 19
 EIP1559FeeCapBufferBlocks = 13
 TransactionPercentile = 15
+TipCapTrimPercentile = 5
+BaseFeeLookaheadBlocks = 2
+MaxReorgDepth = 30
+InclusionPercentiles = ['1:99', '10:50']
+HistoryDuration = '2m0s'
+HistorySizeMin = 6
+HistorySizeMax = 300
+ExcludedTxTypes = [126]
+
+[EVM.GasEstimator.ExternalAPI]
+URL = 'https://api.etherscan.io/api?module=gastracker&action=gasoracle'
+Speed = 'Fast'
+SafeLowPath = 'result.SafeGasPrice'
+StandardPath = 'result.ProposeGasPrice'
+FastPath = 'result.FastGasPrice'
+BaseFeePath = 'result.suggestBaseFee'
+Unit = 'gwei'
+PricesIncludeBaseFee = true
+
+[EVM.GasEstimator.FeeHistory]
+BlockCount = 12
+RewardPercentile = 45
 
 [EVM.HeadTracker]
 HistoryDepth = 15
@@ -1053,13 +1153,22 @@ func TestConfig_Validate(t *testing.T) {
 		- 3.Nodes.4.WSURL: invalid value (ws://dupe.com): duplicate - must be unique
 		- 0: 3 errors:
 			- GasEstimator.BumpTxDepth: invalid value (11): must be less than or equal to Transactions.MaxInFlight
-			- GasEstimator: 6 errors:
+			- GasEstimator: 15 errors:
 				- BumpPercent: invalid value (1): may not be less than Geth's default of 10
+				- BumpStrategy: invalid value (Foo): must be one of Percent, Additive or Rebase
 				- TipCapDefault: invalid value (3 wei): must be greater than or equal to TipCapMinimum
 				- FeeCapDefault: invalid value (3 wei): must be greater than or equal to TipCapDefault
 				- PriceMin: invalid value (10 gwei): must be less than or equal to PriceDefault
 				- PriceMax: invalid value (10 gwei): must be greater than or equal to PriceDefault
+				- LimitMin: invalid value (600000): must be less than or equal to LimitMax
+				- RPCRateLimitBurst: invalid value (0): must be greater than or equal to 1 with RPCRateLimit
+				- FallbackModes: invalid value ([BlockHistory Fallback Foo]): must not include Fallback
+				- FallbackModes: invalid value (Foo): must be a built-in or registered estimator mode
 				- BlockHistory.BlockHistorySize: invalid value (0): must be greater than or equal to 1 with BlockHistory Mode
+				- BlockHistory.TipCapTrimPercentile: invalid value (50): must be less than 50
+				- BlockHistory.InclusionPercentiles: invalid value ([5:50 2:90]): blocks must be at least 1 and increasing
+				- BaseFeeBufferMultiplier: invalid value (0.5): must be at least 1, so that fee caps cover the base fee
+				- FeeCurrency: invalid value (0x765DE816845861e75A25fCA122bb6898B8B1282a): is not supported yet, as transactions are sent paying fees in the native currency
 			- Nodes: 2 errors:
 				- 0: 2 errors:
 					- WSURL: missing: required for primary nodes
@@ -0,0 +1,12 @@
+-- +goose Up
+CREATE TABLE evm_gas_estimator_blocks (
+    evm_chain_id numeric(78,0) NOT NULL REFERENCES evm_chains (id) ON DELETE CASCADE DEFERRABLE,
+    number bigint NOT NULL,
+    hash bytea NOT NULL,
+    block jsonb NOT NULL,
+    created_at timestamp with time zone NOT NULL,
+    PRIMARY KEY (evm_chain_id, number)
+);
+
+-- +goose Down
+DROP TABLE evm_gas_estimator_blocks;